@@ -0,0 +1,41 @@
+package blob
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/application/ports"
+)
+
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.Put("a/b/file", strings.NewReader("first")))
+	assert.NoError(t, store.Put("a/b/file", strings.NewReader("second")))
+
+	rc, err := store.Get("a/b/file")
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "second", string(data))
+
+	assert.NoError(t, store.Delete("a/b/file"))
+	assert.NoError(t, store.Delete("a/b/file"))
+	_, err = store.Get("a/b/file")
+	assert.ErrorIs(t, err, ports.ErrBlobNotFound)
+}
+
+func TestLocalStore_RejectsEscapingKeys(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "a/../../b", "a//b", `a\b`} {
+		assert.Error(t, store.Put(key, strings.NewReader("x")), key)
+		_, err := store.Get(key)
+		assert.Error(t, err, key)
+	}
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+// AdminHandler serves the /admin route group. Access control happens in
+// the router; handlers here assume the caller is an admin.
+type AdminHandler struct {
+	userService  ports.UserService
+	adminService ports.AdminService
+}
+
+func NewAdminHandler(userService ports.UserService, adminService ports.AdminService) *AdminHandler {
+	return &AdminHandler{userService: userService, adminService: adminService}
+}
+
+type setRoleRequest struct {
+	Role domain.Role `json:"role"`
+}
+
+// adminUserResponse is the admin view of an account. It leaves out
+// credentials and second-factor material.
+type adminUserResponse struct {
+	ID                    string      `json:"id"`
+	Username              string      `json:"username"`
+	Role                  domain.Role `json:"role"`
+	Disabled              bool        `json:"disabled"`
+	PasswordResetRequired bool        `json:"password_reset_required"`
+	TOTPEnabled           bool        `json:"totp_enabled"`
+	IsBot                 bool        `json:"is_bot"`
+	OwnerID               string      `json:"owner_id,omitempty"`
+}
+
+func (h *AdminHandler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	var req setRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userService.SetRole(mux.Vars(r)["id"], req.Role); err != nil {
+		switch {
+		case errors.Is(err, application.ErrInvalidRole):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, application.ErrUserNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "failed to set role", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	users, err := h.adminService.ListUsers(r.URL.Query().Get("q"), limit, offset)
+	if err != nil {
+		http.Error(w, "failed to list users", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]adminUserResponse, 0, len(users))
+	for _, u := range users {
+		resp = append(resp, adminUserResponse{
+			ID:                    u.ID.String(),
+			Username:              u.Username,
+			Role:                  u.Role,
+			Disabled:              u.Disabled,
+			PasswordResetRequired: u.PasswordResetRequired,
+			TOTPEnabled:           u.TOTPEnabled,
+			IsBot:                 u.IsBot,
+			OwnerID:               u.OwnerID,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *AdminHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || actorID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.adminService.DisableUser(actorID, mux.Vars(r)["id"]); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	if err := h.adminService.EnableUser(mux.Vars(r)["id"]); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) ForcePasswordReset(w http.ResponseWriter, r *http.Request) {
+	if err := h.adminService.ForcePasswordReset(mux.Vars(r)["id"]); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) DeleteMessage(w http.ResponseWriter, r *http.Request) {
+	if err := h.adminService.DeleteMessage(mux.Vars(r)["id"]); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) DeleteConversation(w http.ResponseWriter, r *http.Request) {
+	if err := h.adminService.DeleteConversation(mux.Vars(r)["id"]); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrCannotDisableSelf):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, application.ErrUserNotFound),
+		errors.Is(err, application.ErrMessageNotFound),
+		errors.Is(err, application.ErrConversationNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, "admin action failed", http.StatusInternalServerError)
+	}
+}
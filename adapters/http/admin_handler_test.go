@@ -0,0 +1,277 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/domain"
+)
+
+type mockAdminService struct {
+	mock.Mock
+}
+
+func (m *mockAdminService) ListUsers(query string, limit, offset int) ([]*domain.User, error) {
+	args := m.Called(query, limit, offset)
+	users, _ := args.Get(0).([]*domain.User)
+	return users, args.Error(1)
+}
+
+func (m *mockAdminService) DisableUser(actorID, userID string) error {
+	return m.Called(actorID, userID).Error(0)
+}
+
+func (m *mockAdminService) EnableUser(userID string) error {
+	return m.Called(userID).Error(0)
+}
+
+func (m *mockAdminService) ForcePasswordReset(userID string) error {
+	return m.Called(userID).Error(0)
+}
+
+func (m *mockAdminService) DeleteMessage(messageID string) error {
+	return m.Called(messageID).Error(0)
+}
+
+func (m *mockAdminService) DeleteConversation(conversationID string) error {
+	return m.Called(conversationID).Error(0)
+}
+
+func TestAdminHandler_SetUserRole(t *testing.T) {
+	service := new(mocks.MockUserService)
+	handler := NewAdminHandler(service, new(mockAdminService))
+
+	tests := []struct {
+		name         string
+		role         domain.Role
+		mockSetup    func()
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			role: domain.RoleModerator,
+			mockSetup: func() {
+				service.On("SetRole", "u1", domain.RoleModerator).Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name: "unknown role",
+			role: "superuser",
+			mockSetup: func() {
+				service.On("SetRole", "u1", domain.Role("superuser")).Return(application.ErrInvalidRole)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "unknown user",
+			role: domain.RoleAdmin,
+			mockSetup: func() {
+				service.On("SetRole", "u1", domain.RoleAdmin).Return(application.ErrUserNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tc.mockSetup()
+
+			body, _ := json.Marshal(setRoleRequest{Role: tc.role})
+			req := httptest.NewRequest(http.MethodPut, "/admin/users/u1/role", bytes.NewReader(body))
+			req = mux.SetURLVars(req, map[string]string{"id": "u1"})
+			rr := httptest.NewRecorder()
+
+			handler.SetUserRole(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_ListUsers(t *testing.T) {
+	service := new(mockAdminService)
+	handler := NewAdminHandler(new(mocks.MockUserService), service)
+
+	tests := []struct {
+		name         string
+		query        string
+		mockSetup    func()
+		expectedCode int
+		expectedLen  int
+	}{
+		{
+			name:  "search with pagination",
+			query: "?q=al&limit=5&offset=5",
+			mockSetup: func() {
+				service.On("ListUsers", "al", 5, 5).Return([]*domain.User{
+					{ID: uuid.New(), Username: "alice", PasswordHash: "secret", Disabled: true},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedLen:  1,
+		},
+		{
+			name:         "invalid limit",
+			query:        "?limit=0",
+			mockSetup:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tc.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users"+tc.query, nil)
+			rr := httptest.NewRecorder()
+
+			handler.ListUsers(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusOK {
+				assert.NotContains(t, rr.Body.String(), "secret")
+				var resp []adminUserResponse
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+				assert.Len(t, resp, tc.expectedLen)
+				assert.True(t, resp[0].Disabled)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_DisableUser(t *testing.T) {
+	service := new(mockAdminService)
+	handler := NewAdminHandler(new(mocks.MockUserService), service)
+
+	tests := []struct {
+		name         string
+		ctxUserID    string
+		mockSetup    func()
+		expectedCode int
+	}{
+		{
+			name:      "valid",
+			ctxUserID: "admin1",
+			mockSetup: func() {
+				service.On("DisableUser", "admin1", "u1").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:      "self",
+			ctxUserID: "u1",
+			mockSetup: func() {
+				service.On("DisableUser", "u1", "u1").Return(application.ErrCannotDisableSelf)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:      "unknown user",
+			ctxUserID: "admin1",
+			mockSetup: func() {
+				service.On("DisableUser", "admin1", "u1").Return(application.ErrUserNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "unauthorized",
+			mockSetup:    func() {},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tc.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/u1/disable", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "u1"})
+			if tc.ctxUserID != "" {
+				req = req.WithContext(contextWithUserID(req.Context(), tc.ctxUserID))
+			}
+			rr := httptest.NewRecorder()
+
+			handler.DisableUser(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminHandler_DeleteContent(t *testing.T) {
+	service := new(mockAdminService)
+	handler := NewAdminHandler(new(mocks.MockUserService), service)
+
+	tests := []struct {
+		name         string
+		call         func(w http.ResponseWriter, r *http.Request)
+		mockSetup    func()
+		expectedCode int
+	}{
+		{
+			name: "delete message",
+			call: handler.DeleteMessage,
+			mockSetup: func() {
+				service.On("DeleteMessage", "x1").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name: "unknown message",
+			call: handler.DeleteMessage,
+			mockSetup: func() {
+				service.On("DeleteMessage", "x1").Return(application.ErrMessageNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "delete conversation",
+			call: handler.DeleteConversation,
+			mockSetup: func() {
+				service.On("DeleteConversation", "x1").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name: "unknown conversation",
+			call: handler.DeleteConversation,
+			mockSetup: func() {
+				service.On("DeleteConversation", "x1").Return(application.ErrConversationNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tc.mockSetup()
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/x/x1", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "x1"})
+			rr := httptest.NewRecorder()
+
+			tc.call(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
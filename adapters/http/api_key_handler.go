@@ -0,0 +1,161 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+type APIKeyHandler struct {
+	svc ports.APIKeyService
+}
+
+func NewAPIKeyHandler(svc ports.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{svc: svc}
+}
+
+type createBotRequest struct {
+	Username string `json:"username"`
+}
+
+type botResponse struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	OwnerID  string `json:"owner_id"`
+}
+
+type createAPIKeyRequest struct {
+	Name   string         `json:"name"`
+	Scopes []domain.Scope `json:"scopes"`
+}
+
+type createAPIKeyResponse struct {
+	// Key is the plaintext secret. It is never returned again.
+	Key    string         `json:"key"`
+	APIKey *domain.APIKey `json:"api_key"`
+}
+
+func (h *APIKeyHandler) CreateBot(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req createBotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	bot, err := h.svc.CreateBot(userID, req.Username)
+	if err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(botResponse{ID: bot.ID.String(), Username: bot.Username, OwnerID: bot.OwnerID})
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req createAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	raw, key, err := h.svc.CreateAPIKey(userID, targetUserID(r, userID), req.Name, req.Scopes)
+	if err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(createAPIKeyResponse{Key: raw, APIKey: key})
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	keys, err := h.svc.ListAPIKeys(userID, targetUserID(r, userID))
+	if err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(keys)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.svc.RevokeAPIKey(userID, mux.Vars(r)["id"]); err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// targetUserID resolves the {id} path variable, where "me" is the caller.
+func targetUserID(r *http.Request, callerID string) string {
+	id := mux.Vars(r)["id"]
+	if id == "" || id == "me" {
+		return callerID
+	}
+	return id
+}
+
+func writeAPIKeyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrUsernameRequired),
+		errors.Is(err, application.ErrUsernameTaken),
+		errors.Is(err, application.ErrAPIKeyNameRequired),
+		errors.Is(err, application.ErrAPIKeyScopeRequired),
+		errors.Is(err, application.ErrInvalidScope):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, application.ErrNotKeyOwner):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, application.ErrAPIKeyNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, "API key request failed", http.StatusInternalServerError)
+	}
+}
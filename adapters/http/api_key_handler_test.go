@@ -0,0 +1,169 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/domain"
+)
+
+// mockAPIKeyService is a testify/mock for ports.APIKeyService.
+type mockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *mockAPIKeyService) CreateBot(ownerID, username string) (*domain.User, error) {
+	args := m.Called(ownerID, username)
+	u, _ := args.Get(0).(*domain.User)
+	return u, args.Error(1)
+}
+
+func (m *mockAPIKeyService) CreateAPIKey(callerID, userID, name string, scopes []domain.Scope) (string, *domain.APIKey, error) {
+	args := m.Called(callerID, userID, name, scopes)
+	k, _ := args.Get(1).(*domain.APIKey)
+	return args.String(0), k, args.Error(2)
+}
+
+func (m *mockAPIKeyService) ListAPIKeys(callerID, userID string) ([]*domain.APIKey, error) {
+	args := m.Called(callerID, userID)
+	k, _ := args.Get(0).([]*domain.APIKey)
+	return k, args.Error(1)
+}
+
+func (m *mockAPIKeyService) RevokeAPIKey(callerID, keyID string) error {
+	return m.Called(callerID, keyID).Error(0)
+}
+
+func TestAPIKeyHandler_CreateBot(t *testing.T) {
+	service := new(mockAPIKeyService)
+	handler := NewAPIKeyHandler(service)
+
+	bot := &domain.User{ID: uuid.New(), Username: "ci-bot", PasswordHash: "", IsBot: true, OwnerID: "alice"}
+	service.On("CreateBot", "alice", "ci-bot").Return(bot, nil)
+	service.On("CreateBot", "alice", "taken").Return(nil, application.ErrUsernameTaken)
+
+	body, _ := json.Marshal(createBotRequest{Username: "ci-bot"})
+	req := httptest.NewRequest(http.MethodPost, "/bots", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "alice"))
+	rr := httptest.NewRecorder()
+	handler.CreateBot(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var got botResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Equal(t, bot.ID.String(), got.ID)
+	assert.Equal(t, "alice", got.OwnerID)
+
+	body, _ = json.Marshal(createBotRequest{Username: "taken"})
+	req = httptest.NewRequest(http.MethodPost, "/bots", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "alice"))
+	rr = httptest.NewRecorder()
+	handler.CreateBot(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	service.AssertExpectations(t)
+}
+
+func TestAPIKeyHandler_CreateAPIKey(t *testing.T) {
+	service := new(mockAPIKeyService)
+	handler := NewAPIKeyHandler(service)
+
+	scopes := []domain.Scope{domain.ScopeMessagesWrite}
+	key := &domain.APIKey{ID: uuid.New(), UserID: "bot-1", Name: "ci", Prefix: "abcd", Hash: "secret-hash", Scopes: scopes, CreatedAt: time.Now()}
+
+	tests := []struct {
+		name         string
+		pathID       string
+		mockSetup    func()
+		expectedCode int
+	}{
+		{
+			name:   "for bot",
+			pathID: "bot-1",
+			mockSetup: func() {
+				service.On("CreateAPIKey", "alice", "bot-1", "ci", scopes).Return("chk_abcd_xyz", key, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "for self",
+			pathID: "me",
+			mockSetup: func() {
+				service.On("CreateAPIKey", "alice", "alice", "ci", scopes).Return("chk_abcd_xyz", key, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "not owner",
+			pathID: "bot-2",
+			mockSetup: func() {
+				service.On("CreateAPIKey", "alice", "bot-2", "ci", scopes).Return("", nil, application.ErrNotKeyOwner)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tc.mockSetup()
+
+			body, _ := json.Marshal(createAPIKeyRequest{Name: "ci", Scopes: scopes})
+			req := httptest.NewRequest(http.MethodPost, "/users/"+tc.pathID+"/api-keys", bytes.NewReader(body))
+			req = mux.SetURLVars(req, map[string]string{"id": tc.pathID})
+			req = req.WithContext(contextWithUserID(req.Context(), "alice"))
+			rr := httptest.NewRecorder()
+
+			handler.CreateAPIKey(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusCreated {
+				assert.Contains(t, rr.Body.String(), `"key":"chk_abcd_xyz"`)
+				assert.NotContains(t, rr.Body.String(), "secret-hash")
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIKeyHandler_ListAndRevoke(t *testing.T) {
+	service := new(mockAPIKeyService)
+	handler := NewAPIKeyHandler(service)
+
+	keys := []*domain.APIKey{{ID: uuid.New(), UserID: "alice", Name: "script"}}
+	service.On("ListAPIKeys", "alice", "alice").Return(keys, nil)
+	service.On("RevokeAPIKey", "alice", "k1").Return(nil)
+	service.On("RevokeAPIKey", "alice", "k2").Return(application.ErrAPIKeyNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me/api-keys", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "me"})
+	req = req.WithContext(contextWithUserID(req.Context(), "alice"))
+	rr := httptest.NewRecorder()
+	handler.ListAPIKeys(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var got []domain.APIKey
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Len(t, got, 1)
+
+	for id, code := range map[string]int{"k1": http.StatusNoContent, "k2": http.StatusNotFound} {
+		req = httptest.NewRequest(http.MethodDelete, "/api-keys/"+id, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		req = req.WithContext(contextWithUserID(req.Context(), "alice"))
+		rr = httptest.NewRecorder()
+		handler.RevokeAPIKey(rr, req)
+		assert.Equal(t, code, rr.Code)
+	}
+
+	service.AssertExpectations(t)
+}
@@ -0,0 +1,144 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+// multipartOverhead allows for form boundaries and headers on top of the
+// file itself.
+const multipartOverhead = 64 << 10
+
+type AttachmentHandler struct {
+	attachmentService ports.AttachmentService
+	maxUploadSize     int64
+}
+
+// NewAttachmentHandler rejects request bodies larger than maxUploadSize
+// before they reach the service, which enforces the exact limit.
+func NewAttachmentHandler(attachmentService ports.AttachmentService, maxUploadSize int64) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService, maxUploadSize: maxUploadSize}
+}
+
+// Upload accepts a multipart/form-data body with the file in a "file"
+// field and returns the stored attachment's metadata.
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadSize+multipartOverhead)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "expected a multipart/form-data body", http.StatusBadRequest)
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			http.Error(w, "missing 'file' field", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			writeAttachmentError(w, err)
+			return
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		attachment, err := h.attachmentService.Upload(userID, part.FileName(), part)
+		if err != nil {
+			writeAttachmentError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		err = json.NewEncoder(w).Encode(attachment)
+		if err != nil {
+			http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		}
+		return
+	}
+}
+
+// Download streams an attachment as a file download.
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	attachment, body, err := h.attachmentService.Open(userID, mux.Vars(r)["id"])
+	if err != nil {
+		writeAttachmentError(w, err)
+		return
+	}
+	defer body.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})
+	w.Header().Set("Content-Disposition", disposition)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	serveBlob(w, attachment.ContentType, body)
+}
+
+// Thumbnail serves the PNG preview of an image attachment.
+func (h *AttachmentHandler) Thumbnail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	_, body, err := h.attachmentService.OpenThumbnail(userID, mux.Vars(r)["id"])
+	if err != nil {
+		writeAttachmentError(w, err)
+		return
+	}
+	defer body.Close()
+
+	serveBlob(w, "image/png", body)
+}
+
+// serveBlob writes stored bytes with their sniffed type, telling
+// browsers not to guess a more dangerous one.
+func serveBlob(w http.ResponseWriter, contentType string, body io.Reader) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private")
+	_, _ = io.Copy(w, body)
+}
+
+func writeAttachmentError(w http.ResponseWriter, err error) {
+	var bodyTooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &bodyTooLarge):
+		http.Error(w, application.ErrAttachmentTooLarge.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, application.ErrAttachmentEmpty):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, application.ErrAttachmentNotFound),
+		errors.Is(err, application.ErrNoThumbnail):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, application.ErrAttachmentTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, application.ErrUnsupportedAttachmentType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	default:
+		http.Error(w, "failed to process attachment", http.StatusInternalServerError)
+	}
+}
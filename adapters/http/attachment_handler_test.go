@@ -0,0 +1,165 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/domain"
+)
+
+type mockAttachmentService struct {
+	mock.Mock
+}
+
+// Upload drains r like the real service so body limits take effect.
+func (m *mockAttachmentService) Upload(uploaderID, name string, r io.Reader) (*domain.Attachment, error) {
+	if _, err := io.ReadAll(r); err != nil {
+		return nil, err
+	}
+	args := m.Called(uploaderID, name)
+	a, _ := args.Get(0).(*domain.Attachment)
+	return a, args.Error(1)
+}
+
+func (m *mockAttachmentService) Open(userID, attachmentID string) (*domain.Attachment, io.ReadCloser, error) {
+	args := m.Called(userID, attachmentID)
+	a, _ := args.Get(0).(*domain.Attachment)
+	rc, _ := args.Get(1).(io.ReadCloser)
+	return a, rc, args.Error(2)
+}
+
+func (m *mockAttachmentService) OpenThumbnail(userID, attachmentID string) (*domain.Attachment, io.ReadCloser, error) {
+	args := m.Called(userID, attachmentID)
+	a, _ := args.Get(0).(*domain.Attachment)
+	rc, _ := args.Get(1).(io.ReadCloser)
+	return a, rc, args.Error(2)
+}
+
+func multipartBody(t *testing.T, field, filename, content string) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile(field, filename)
+	assert.NoError(t, err)
+	_, _ = fw.Write([]byte(content))
+	assert.NoError(t, mw.Close())
+	return &buf, mw.FormDataContentType()
+}
+
+func TestAttachmentHandler_Upload(t *testing.T) {
+	service := new(mockAttachmentService)
+	handler := NewAttachmentHandler(service, 1024)
+
+	tests := []struct {
+		name         string
+		field        string
+		content      string
+		mockSetup    func()
+		expectedCode int
+	}{
+		{
+			name:    "stored",
+			field:   "file",
+			content: "hello",
+			mockSetup: func() {
+				service.On("Upload", "alice", "notes.txt").Return(&domain.Attachment{ID: uuid.New(), Name: "notes.txt"}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "missing file field",
+			field:        "other",
+			content:      "hello",
+			mockSetup:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "body over the limit",
+			field:        "file",
+			content:      strings.Repeat("x", 1024+multipartOverhead),
+			mockSetup:    func() {},
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:    "type not allowed",
+			field:   "file",
+			content: "MZ",
+			mockSetup: func() {
+				service.On("Upload", "alice", "notes.txt").Return(nil, application.ErrUnsupportedAttachmentType)
+			},
+			expectedCode: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tt.mockSetup()
+
+			body, contentType := multipartBody(t, tt.field, "notes.txt", tt.content)
+			req := httptest.NewRequest(http.MethodPost, "/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			req = req.WithContext(contextWithUserID(req.Context(), "alice"))
+			rr := httptest.NewRecorder()
+
+			handler.Upload(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestAttachmentHandler_Download(t *testing.T) {
+	service := new(mockAttachmentService)
+	handler := NewAttachmentHandler(service, 1024)
+
+	id := uuid.New()
+	attachment := &domain.Attachment{ID: id, Name: "report.pdf", Size: 4, ContentType: "application/pdf"}
+	service.On("Open", "bob", id.String()).Return(attachment, io.NopCloser(strings.NewReader("%PDF")), nil)
+	service.On("Open", "mallory", id.String()).Return(nil, nil, application.ErrAttachmentNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/attachments/"+id.String(), nil)
+	req = mux.SetURLVars(req, map[string]string{"id": id.String()})
+	rr := httptest.NewRecorder()
+	handler.Download(rr, req.WithContext(contextWithUserID(req.Context(), "bob")))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/pdf", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, `attachment; filename=report.pdf`, rr.Header().Get("Content-Disposition"))
+	assert.Equal(t, "%PDF", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	handler.Download(rr, req.WithContext(contextWithUserID(req.Context(), "mallory")))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.Download(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestAttachmentHandler_Thumbnail(t *testing.T) {
+	service := new(mockAttachmentService)
+	handler := NewAttachmentHandler(service, 1024)
+
+	id := uuid.New().String()
+	service.On("OpenThumbnail", "bob", id).Return(nil, nil, application.ErrNoThumbnail)
+
+	req := httptest.NewRequest(http.MethodGet, "/attachments/"+id+"/thumbnail", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": id})
+	rr := httptest.NewRecorder()
+	handler.Thumbnail(rr, req.WithContext(contextWithUserID(req.Context(), "bob")))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
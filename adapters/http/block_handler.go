@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+type BlockHandler struct {
+	blockService ports.BlockService
+}
+
+func NewBlockHandler(svc ports.BlockService) *BlockHandler {
+	return &BlockHandler{blockService: svc}
+}
+
+type blockRequest struct {
+	UserID string `json:"user_id"`
+}
+
+func (h *BlockHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req blockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == "" {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	block, err := h.blockService.BlockUser(userID, req.UserID)
+	if err != nil {
+		writeBlockError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(block)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *BlockHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.blockService.UnblockUser(userID, mux.Vars(r)["id"]); err != nil {
+		writeBlockError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BlockHandler) GetBlocks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	blocks, err := h.blockService.GetBlocks(userID)
+	if err != nil {
+		writeBlockError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(blocks)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func writeBlockError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrCannotBlockSelf):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, application.ErrUserNotFound),
+		errors.Is(err, application.ErrBlockNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, "failed to update blocks", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestBlockHandler(t *testing.T) {
+	service := mocks.NewMockBlockService(t)
+	handler := NewBlockHandler(service)
+
+	t.Run("block", func(t *testing.T) {
+		service.On("BlockUser", "u1", "u2").Return(&domain.Block{BlockerID: "u1", BlockedID: "u2"}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/users/me/blocks", bytes.NewBufferString(`{"user_id":"u2"}`))
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.BlockUser(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		var got domain.Block
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.Equal(t, "u2", got.BlockedID)
+	})
+
+	t.Run("block self", func(t *testing.T) {
+		service.On("BlockUser", "u1", "u1").Return(nil, application.ErrCannotBlockSelf).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/users/me/blocks", bytes.NewBufferString(`{"user_id":"u1"}`))
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.BlockUser(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("missing user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/users/me/blocks", bytes.NewBufferString(`{}`))
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.BlockUser(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		service.On("GetBlocks", "u1").Return([]*domain.Block{{BlockerID: "u1", BlockedID: "u2"}}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/users/me/blocks", nil)
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.GetBlocks(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var got []domain.Block
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.Len(t, got, 1)
+	})
+
+	t.Run("unblock", func(t *testing.T) {
+		service.On("UnblockUser", "u1", "u2").Return(nil).Once()
+		service.On("UnblockUser", "u1", "u3").Return(application.ErrBlockNotFound).Once()
+
+		for id, want := range map[string]int{"u2": http.StatusNoContent, "u3": http.StatusNotFound} {
+			req := httptest.NewRequest(http.MethodDelete, "/users/me/blocks/"+id, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": id})
+			req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+			rr := httptest.NewRecorder()
+			handler.UnblockUser(rr, req)
+
+			assert.Equal(t, want, rr.Code, id)
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+type ContactHandler struct {
+	contactService ports.ContactService
+}
+
+func NewContactHandler(svc ports.ContactService) *ContactHandler {
+	return &ContactHandler{contactService: svc}
+}
+
+type contactRequestRequest struct {
+	UserID string `json:"user_id"`
+}
+
+type contactSettingsRequest struct {
+	ContactsOnly bool `json:"contacts_only"`
+}
+
+// SendRequest asks another user to become a contact. If they had already
+// asked the caller, the response is their request, now accepted.
+func (h *ContactHandler) SendRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req contactRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == "" {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	request, err := h.contactService.SendContactRequest(userID, req.UserID)
+	if err != nil {
+		writeContactError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(request)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetRequests lists the caller's pending requests, incoming and outgoing.
+func (h *ContactHandler) GetRequests(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	requests, err := h.contactService.GetContactRequests(userID)
+	if err != nil {
+		writeContactError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(requests)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *ContactHandler) AcceptRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	request, err := h.contactService.AcceptContactRequest(userID, mux.Vars(r)["id"])
+	if err != nil {
+		writeContactError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(request)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *ContactHandler) DeclineRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.contactService.DeclineContactRequest(userID, mux.Vars(r)["id"]); err != nil {
+		writeContactError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ContactHandler) CancelRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.contactService.CancelContactRequest(userID, mux.Vars(r)["id"]); err != nil {
+		writeContactError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ContactHandler) GetContacts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	contacts, err := h.contactService.GetContacts(userID)
+	if err != nil {
+		writeContactError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(contacts)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *ContactHandler) RemoveContact(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.contactService.RemoveContact(userID, mux.Vars(r)["id"]); err != nil {
+		writeContactError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetSettings serves PUT /users/me/contact-settings with
+// {"contacts_only": true} to refuse direct messages from non-contacts.
+func (h *ContactHandler) SetSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req contactSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.contactService.SetContactsOnly(userID, req.ContactsOnly); err != nil {
+		writeContactError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeContactError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrCannotAddSelf):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, application.ErrUserNotFound),
+		errors.Is(err, application.ErrContactRequestNotFound),
+		errors.Is(err, application.ErrContactNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, application.ErrBlocked):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, application.ErrAlreadyContacts),
+		errors.Is(err, application.ErrContactRequestNotPending):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "failed to update contacts", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestContactHandler(t *testing.T) {
+	service := mocks.NewMockContactService(t)
+	handler := NewContactHandler(service)
+	requestID := uuid.NewString()
+
+	t.Run("send", func(t *testing.T) {
+		service.On("SendContactRequest", "u1", "u2").
+			Return(&domain.ContactRequest{FromID: "u1", ToID: "u2", Status: domain.ContactRequestPending}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/users/me/contact-requests", bytes.NewBufferString(`{"user_id":"u2"}`))
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.SendRequest(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		var got domain.ContactRequest
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.Equal(t, domain.ContactRequestPending, got.Status)
+	})
+
+	t.Run("send to a contact", func(t *testing.T) {
+		service.On("SendContactRequest", "u1", "u3").Return(nil, application.ErrAlreadyContacts).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/users/me/contact-requests", bytes.NewBufferString(`{"user_id":"u3"}`))
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.SendRequest(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("accept", func(t *testing.T) {
+		service.On("AcceptContactRequest", "u2", requestID).
+			Return(&domain.ContactRequest{Status: domain.ContactRequestAccepted}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/contact-requests/"+requestID+"/accept", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": requestID})
+		req = req.WithContext(contextWithUserID(req.Context(), "u2"))
+		rr := httptest.NewRecorder()
+		handler.AcceptRequest(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("decline someone else's request", func(t *testing.T) {
+		service.On("DeclineContactRequest", "u3", requestID).Return(application.ErrContactRequestNotFound).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/contact-requests/"+requestID+"/decline", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": requestID})
+		req = req.WithContext(contextWithUserID(req.Context(), "u3"))
+		rr := httptest.NewRecorder()
+		handler.DeclineRequest(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		service.On("CancelContactRequest", "u1", requestID).Return(nil).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/contact-requests/"+requestID, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": requestID})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.CancelRequest(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("contacts", func(t *testing.T) {
+		service.On("GetContacts", "u1").Return([]*domain.Contact{{UserID: "u1", ContactID: "u2"}}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/users/me/contacts", nil)
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.GetContacts(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `[{"contact_id":"u2","since":"0001-01-01T00:00:00Z"}]`, rr.Body.String())
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		service.On("SetContactsOnly", "u1", true).Return(nil).Once()
+
+		req := httptest.NewRequest(http.MethodPut, "/users/me/contact-settings", bytes.NewBufferString(`{"contacts_only":true}`))
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.SetSettings(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+}
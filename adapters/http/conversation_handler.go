@@ -2,8 +2,10 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/chrikar/chatheon/application"
 	"github.com/chrikar/chatheon/application/ports"
 	"github.com/chrikar/chatheon/internal/auth"
 )
@@ -48,6 +50,10 @@ func (h *ConversationHandler) CreateConversation(w http.ResponseWriter, r *http.
 
 	// Call service
 	conv, err := h.svc.CreateConversation(req.ParticipantIDs)
+	if errors.Is(err, application.ErrBlocked) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
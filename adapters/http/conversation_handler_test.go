@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/chrikar/chatheon/application"
 	"github.com/chrikar/chatheon/domain"
 )
 
@@ -108,3 +109,21 @@ func TestConversationHandler_GetConversations_Success(t *testing.T) {
 
 	service.AssertExpectations(t)
 }
+
+func TestConversationHandler_CreateConversation_Blocked(t *testing.T) {
+	service := new(mockConversationService)
+	handler := NewConversationHandler(service)
+
+	ids := []string{"bob", "alice"}
+	service.On("CreateConversation", ids).Return((*domain.Conversation)(nil), application.ErrBlocked)
+
+	payload, _ := json.Marshal(createConversationRequest{ParticipantIDs: []string{"bob"}})
+	req := httptest.NewRequest(http.MethodPost, "/conversations", bytes.NewReader(payload))
+	req = req.WithContext(contextWithUserID(req.Context(), "alice"))
+	rr := httptest.NewRecorder()
+
+	handler.CreateConversation(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	service.AssertExpectations(t)
+}
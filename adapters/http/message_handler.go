@@ -2,11 +2,14 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/chrikar/chatheon/application"
 	"github.com/chrikar/chatheon/application/ports"
 	"github.com/chrikar/chatheon/domain"
 	"github.com/chrikar/chatheon/internal/auth"
@@ -21,8 +24,36 @@ func NewMessageHandler(messageService ports.MessageService) *MessageHandler {
 }
 
 type createMessageRequest struct {
-	ReceiverID string `json:"receiver_id"`
-	Content    string `json:"content"`
+	ReceiverID     string   `json:"receiver_id,omitempty"`
+	ConversationID string   `json:"conversation_id,omitempty"`
+	ReplyToID      string   `json:"reply_to,omitempty"`
+	QuoteID        string   `json:"quote_id,omitempty"`
+	Content        string   `json:"content"`
+	Format         string   `json:"format,omitempty"`
+	AttachmentIDs  []string `json:"attachment_ids,omitempty"`
+	// SendAt schedules the message instead of sending it now.
+	SendAt *time.Time `json:"send_at,omitempty"`
+}
+
+type rescheduleRequest struct {
+	SendAt time.Time `json:"send_at"`
+}
+
+type forwardRequest struct {
+	ReceiverID     string `json:"receiver_id,omitempty"`
+	ConversationID string `json:"conversation_id,omitempty"`
+}
+
+type messageTTLRequest struct {
+	TTLSeconds int64 `json:"ttl_seconds"`
+}
+
+type reactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+type editMessageRequest struct {
+	Content string `json:"content"`
 }
 
 func (h *MessageHandler) CreateMessage(w http.ResponseWriter, r *http.Request) {
@@ -38,12 +69,177 @@ func (h *MessageHandler) CreateMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.messageService.CreateMessage(senderID, req.ReceiverID, req.Content); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	draft := ports.MessageDraft{
+		ReceiverID:     req.ReceiverID,
+		ConversationID: req.ConversationID,
+		ReplyToID:      req.ReplyToID,
+		QuoteID:        req.QuoteID,
+		Content:        req.Content,
+		Format:         domain.MessageFormat(req.Format),
+		AttachmentIDs:  req.AttachmentIDs,
+	}
+	if req.SendAt != nil {
+		h.scheduleMessage(w, senderID, draft, *req.SendAt)
 		return
 	}
 
+	msg, err := h.messageService.CreateMessage(senderID, draft)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(msg)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// scheduleMessage answers 202 Accepted: the message exists only as a
+// scheduled message until send_at.
+func (h *MessageHandler) scheduleMessage(w http.ResponseWriter, senderID string, draft ports.MessageDraft, sendAt time.Time) {
+	scheduled, err := h.messageService.ScheduleMessage(senderID, draft, sendAt)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	err = json.NewEncoder(w).Encode(scheduled)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetScheduledMessages lists the caller's pending scheduled messages,
+// soonest first.
+func (h *MessageHandler) GetScheduledMessages(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, offset, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	scheduled, err := h.messageService.GetScheduledMessages(userID, limit, offset)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(scheduled)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *MessageHandler) RescheduleMessage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req rescheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	scheduled, err := h.messageService.RescheduleMessage(userID, mux.Vars(r)["id"], req.SendAt)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(scheduled)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *MessageHandler) CancelScheduledMessage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.messageService.CancelScheduledMessage(userID, mux.Vars(r)["id"]); err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetMessageTTL sets a conversation's disappearing-message timer. A
+// ttl_seconds of 0 turns it off.
+func (h *MessageHandler) SetMessageTTL(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req messageTTLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TTLSeconds < 0 {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	conv, err := h.messageService.SetMessageTTL(userID, mux.Vars(r)["id"], time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(conv)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetConversationMessages serves a conversation's timeline, oldest first.
+func (h *MessageHandler) GetConversationMessages(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, offset, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	msgs, err := h.messageService.GetConversationMessages(userID, mux.Vars(r)["id"], limit, offset)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(msgs)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
 }
 
 func (h *MessageHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
@@ -73,9 +269,390 @@ func (h *MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	limit, offset, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	msgs, err := h.messageService.GetMessagesByReceiver(userID, limit, offset)
+	if err != nil {
+		http.Error(w, "failed to fetch messages", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(msgs)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *MessageHandler) EditMessage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req editMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	msg, err := h.messageService.EditMessage(userID, mux.Vars(r)["id"], req.Content)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(msg)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *MessageHandler) GetMessageHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	revisions, err := h.messageService.GetMessageHistory(userID, mux.Vars(r)["id"])
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(revisions)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteMessage deletes for the caller only, or for everyone with
+// ?for=everyone.
+func (h *MessageHandler) DeleteMessage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	var err error
+	switch r.URL.Query().Get("for") {
+	case "", "me":
+		err = h.messageService.DeleteMessageForMe(userID, id)
+	case "everyone":
+		err = h.messageService.DeleteMessageForEveryone(userID, id)
+	default:
+		http.Error(w, "invalid 'for' parameter: must be 'me' or 'everyone'", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetThread serves a thread root and a page of its replies.
+func (h *MessageHandler) GetThread(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, offset, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	thread, err := h.messageService.GetThread(userID, mux.Vars(r)["id"], limit, offset)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(thread)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *MessageHandler) MarkThreadRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.messageService.MarkThreadRead(userID, mux.Vars(r)["id"]); err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetMentions serves the caller's mentions inbox, newest first.
+func (h *MessageHandler) GetMentions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, offset, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	msgs, err := h.messageService.GetMentions(userID, limit, offset)
+	if err != nil {
+		http.Error(w, "failed to fetch mentions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(msgs)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SearchMessages serves GET /search?q=... with optional conversation_id,
+// sender_id and RFC 3339 since/until filters, newest first.
+func (h *MessageHandler) SearchMessages(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, offset, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	since, ok := parseTimeParam(w, r, "since")
+	if !ok {
+		return
+	}
+	until, ok := parseTimeParam(w, r, "until")
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	search := ports.MessageSearch{
+		Query:          query.Get("q"),
+		ConversationID: query.Get("conversation_id"),
+		SenderID:       query.Get("sender_id"),
+		Since:          since,
+		Until:          until,
+		Limit:          limit,
+		Offset:         offset,
+	}
+
+	msgs, err := h.messageService.SearchMessages(userID, search)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(msgs)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *MessageHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req reactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.messageService.AddReaction(userID, mux.Vars(r)["id"], req.Emoji); err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *MessageHandler) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	if err := h.messageService.RemoveReaction(userID, vars["id"], vars["emoji"]); err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *MessageHandler) PinMessage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	pin, err := h.messageService.PinMessage(userID, mux.Vars(r)["id"])
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(pin)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *MessageHandler) UnpinMessage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.messageService.UnpinMessage(userID, mux.Vars(r)["id"]); err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ForwardMessage copies a message into another chat, crediting its
+// original sender.
+func (h *MessageHandler) ForwardMessage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req forwardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	target := ports.ForwardTarget{ReceiverID: req.ReceiverID, ConversationID: req.ConversationID}
+	msg, err := h.messageService.ForwardMessage(userID, mux.Vars(r)["id"], target)
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(msg)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetPins lists a conversation's pinned messages, most recently pinned
+// first.
+func (h *MessageHandler) GetPins(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	pins, err := h.messageService.GetPins(userID, mux.Vars(r)["id"])
+	if err != nil {
+		writeMessageError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(pins)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func writeMessageError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrMessageContentRequired),
+		errors.Is(err, application.ErrMessageTooLong),
+		errors.Is(err, application.ErrRecipientRequired),
+		errors.Is(err, application.ErrInvalidReaction),
+		errors.Is(err, application.ErrInvalidReply),
+		errors.Is(err, application.ErrTooManyAttachments),
+		errors.Is(err, application.ErrSearchQueryRequired),
+		errors.Is(err, application.ErrSendAtInPast),
+		errors.Is(err, application.ErrInvalidMessageTTL),
+		errors.Is(err, application.ErrNotPinnable),
+		errors.Is(err, application.ErrNotForwardable),
+		errors.Is(err, application.ErrInvalidQuote),
+		errors.Is(err, application.ErrInvalidFormat):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, application.ErrMessageNotFound),
+		errors.Is(err, application.ErrConversationNotFound),
+		errors.Is(err, application.ErrReactionNotFound),
+		errors.Is(err, application.ErrAttachmentNotFound),
+		errors.Is(err, application.ErrScheduledMessageNotFound),
+		errors.Is(err, application.ErrPinNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, application.ErrNotMessageSender),
+		errors.Is(err, application.ErrPinForbidden),
+		errors.Is(err, application.ErrBlocked),
+		errors.Is(err, application.ErrContactsOnly):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, application.ErrEditWindowExpired),
+		errors.Is(err, application.ErrDeleteWindowExpired),
+		errors.Is(err, application.ErrMessageDeleted),
+		errors.Is(err, application.ErrAttachmentAlreadySent),
+		errors.Is(err, application.ErrScheduledMessageNotPending),
+		errors.Is(err, application.ErrTooManyPins):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "failed to process message", http.StatusInternalServerError)
+	}
+}
+
+// parsePagination reads the limit and offset query parameters, writing a
+// 400 and returning ok=false when either is malformed.
+func parsePagination(w http.ResponseWriter, r *http.Request) (limit, offset int, ok bool) {
 	// default pagination
-	limit := 10
-	offset := 0
+	limit = 10
+	offset = 0
 
 	// parse limit
 	if l := r.URL.Query().Get("limit"); l != "" {
@@ -85,7 +662,7 @@ func (h *MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 				"invalid 'limit' parameter: must be a positive integer",
 				http.StatusBadRequest,
 			)
-			return
+			return 0, 0, false
 		}
 		limit = n
 	}
@@ -98,20 +675,28 @@ func (h *MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
 				"invalid 'offset' parameter: must be a non-negative integer",
 				http.StatusBadRequest,
 			)
-			return
+			return 0, 0, false
 		}
 		offset = n
 	}
 
-	msgs, err := h.messageService.GetMessagesByReceiver(userID, limit, offset)
-	if err != nil {
-		http.Error(w, "failed to fetch messages", http.StatusInternalServerError)
-		return
+	return limit, offset, true
+}
+
+// parseTimeParam reads an optional RFC 3339 query parameter, writing a 400
+// and returning ok=false when it is malformed.
+func parseTimeParam(w http.ResponseWriter, r *http.Request, name string) (t time.Time, ok bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return time.Time{}, true
 	}
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(msgs)
+	t, err := time.Parse(time.RFC3339, v)
 	if err != nil {
-		http.Error(w, "failed to encode response", http.StatusInternalServerError)
-		return
+		http.Error(w,
+			"invalid '"+name+"' parameter: must be an RFC 3339 timestamp",
+			http.StatusBadRequest,
+		)
+		return time.Time{}, false
 	}
+	return t, true
 }
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -9,10 +10,13 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/application/ports"
 	"github.com/chrikar/chatheon/domain"
 	"github.com/chrikar/chatheon/internal/auth"
 )
@@ -22,9 +26,96 @@ type mockMessageService struct {
 	mock.Mock
 }
 
-func (m *mockMessageService) CreateMessage(senderID, receiverID, content string) error {
-	args := m.Called(senderID, receiverID, content)
-	return args.Error(0)
+func (m *mockMessageService) CreateMessage(senderID string, draft ports.MessageDraft) (*domain.Message, error) {
+	args := m.Called(senderID, draft)
+	msg, _ := args.Get(0).(*domain.Message)
+	return msg, args.Error(1)
+}
+
+func (m *mockMessageService) GetConversationMessages(userID, conversationID string, limit, offset int) ([]*domain.Message, error) {
+	args := m.Called(userID, conversationID, limit, offset)
+	msgs, _ := args.Get(0).([]*domain.Message)
+	return msgs, args.Error(1)
+}
+
+func (m *mockMessageService) GetThread(userID, messageID string, limit, offset int) (*ports.Thread, error) {
+	args := m.Called(userID, messageID, limit, offset)
+	thread, _ := args.Get(0).(*ports.Thread)
+	return thread, args.Error(1)
+}
+
+func (m *mockMessageService) MarkThreadRead(userID, messageID string) error {
+	return m.Called(userID, messageID).Error(0)
+}
+
+func (m *mockMessageService) GetMentions(userID string, limit, offset int) ([]*domain.Message, error) {
+	args := m.Called(userID, limit, offset)
+	msgs, _ := args.Get(0).([]*domain.Message)
+	return msgs, args.Error(1)
+}
+
+func (m *mockMessageService) SearchMessages(userID string, search ports.MessageSearch) ([]*domain.Message, error) {
+	args := m.Called(userID, search)
+	msgs, _ := args.Get(0).([]*domain.Message)
+	return msgs, args.Error(1)
+}
+
+func (m *mockMessageService) ScheduleMessage(senderID string, draft ports.MessageDraft, sendAt time.Time) (*domain.ScheduledMessage, error) {
+	args := m.Called(senderID, draft, sendAt)
+	sm, _ := args.Get(0).(*domain.ScheduledMessage)
+	return sm, args.Error(1)
+}
+
+func (m *mockMessageService) GetScheduledMessages(userID string, limit, offset int) ([]*domain.ScheduledMessage, error) {
+	args := m.Called(userID, limit, offset)
+	sms, _ := args.Get(0).([]*domain.ScheduledMessage)
+	return sms, args.Error(1)
+}
+
+func (m *mockMessageService) RescheduleMessage(userID, scheduledID string, sendAt time.Time) (*domain.ScheduledMessage, error) {
+	args := m.Called(userID, scheduledID, sendAt)
+	sm, _ := args.Get(0).(*domain.ScheduledMessage)
+	return sm, args.Error(1)
+}
+
+func (m *mockMessageService) CancelScheduledMessage(userID, scheduledID string) error {
+	return m.Called(userID, scheduledID).Error(0)
+}
+
+func (m *mockMessageService) SetMessageTTL(userID, conversationID string, ttl time.Duration) (*domain.Conversation, error) {
+	args := m.Called(userID, conversationID, ttl)
+	conv, _ := args.Get(0).(*domain.Conversation)
+	return conv, args.Error(1)
+}
+
+func (m *mockMessageService) PinMessage(userID, messageID string) (*domain.Pin, error) {
+	args := m.Called(userID, messageID)
+	pin, _ := args.Get(0).(*domain.Pin)
+	return pin, args.Error(1)
+}
+
+func (m *mockMessageService) UnpinMessage(userID, messageID string) error {
+	return m.Called(userID, messageID).Error(0)
+}
+
+func (m *mockMessageService) ForwardMessage(userID, messageID string, target ports.ForwardTarget) (*domain.Message, error) {
+	args := m.Called(userID, messageID, target)
+	msg, _ := args.Get(0).(*domain.Message)
+	return msg, args.Error(1)
+}
+
+func (m *mockMessageService) GetPins(userID, conversationID string) ([]*domain.Pin, error) {
+	args := m.Called(userID, conversationID)
+	pins, _ := args.Get(0).([]*domain.Pin)
+	return pins, args.Error(1)
+}
+
+func (m *mockMessageService) AddReaction(userID, messageID, emoji string) error {
+	return m.Called(userID, messageID, emoji).Error(0)
+}
+
+func (m *mockMessageService) RemoveReaction(userID, messageID, emoji string) error {
+	return m.Called(userID, messageID, emoji).Error(0)
 }
 
 func (m *mockMessageService) GetMessagesByReceiver(receiverID string, limit, offset int) ([]*domain.Message, error) {
@@ -37,6 +128,26 @@ func (m *mockMessageService) SetMessageStatus(messageID string, status domain.Me
 	return args.Error(0)
 }
 
+func (m *mockMessageService) EditMessage(editorID, messageID, content string) (*domain.Message, error) {
+	args := m.Called(editorID, messageID, content)
+	msg, _ := args.Get(0).(*domain.Message)
+	return msg, args.Error(1)
+}
+
+func (m *mockMessageService) DeleteMessageForMe(userID, messageID string) error {
+	return m.Called(userID, messageID).Error(0)
+}
+
+func (m *mockMessageService) DeleteMessageForEveryone(userID, messageID string) error {
+	return m.Called(userID, messageID).Error(0)
+}
+
+func (m *mockMessageService) GetMessageHistory(callerID, messageID string) ([]*domain.MessageRevision, error) {
+	args := m.Called(callerID, messageID)
+	revisions, _ := args.Get(0).([]*domain.MessageRevision)
+	return revisions, args.Error(1)
+}
+
 // helper to inject user ID into request context
 func contextWithUserID(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, auth.ContextUserIDKey, userID)
@@ -81,3 +192,664 @@ func TestMessageHandler_GetMessages_Success(t *testing.T) {
 
 	service.AssertExpectations(t)
 }
+
+func TestMessageHandler_EditMessage(t *testing.T) {
+	service := new(mockMessageService)
+	handler := NewMessageHandler(service)
+	edited := &domain.Message{ID: uuid.New(), SenderID: "user-1", Content: "fixed"}
+
+	tests := []struct {
+		name         string
+		mockSetup    func()
+		expectedCode int
+	}{
+		{
+			name: "valid",
+			mockSetup: func() {
+				service.On("EditMessage", "user-1", "m1", "fixed").Return(edited, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "not sender",
+			mockSetup: func() {
+				service.On("EditMessage", "user-1", "m1", "fixed").Return(nil, application.ErrNotMessageSender)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "window expired",
+			mockSetup: func() {
+				service.On("EditMessage", "user-1", "m1", "fixed").Return(nil, application.ErrEditWindowExpired)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name: "unknown message",
+			mockSetup: func() {
+				service.On("EditMessage", "user-1", "m1", "fixed").Return(nil, application.ErrMessageNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tc.mockSetup()
+
+			body, _ := json.Marshal(editMessageRequest{Content: "fixed"})
+			req := httptest.NewRequest(http.MethodPatch, "/messages/m1", bytes.NewReader(body))
+			req = mux.SetURLVars(req, map[string]string{"id": "m1"})
+			req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+			rr := httptest.NewRecorder()
+
+			handler.EditMessage(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageHandler_GetMessageHistory(t *testing.T) {
+	service := mocks.NewMockMessageService(t)
+	revisions := []*domain.MessageRevision{{MessageID: uuid.New(), Content: "tpyo", CreatedAt: time.Now()}}
+	service.On("GetMessageHistory", "user-1", "m1").Return(revisions, nil)
+
+	handler := NewMessageHandler(service)
+	req := httptest.NewRequest(http.MethodGet, "/messages/m1/history", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "m1"})
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+	rr := httptest.NewRecorder()
+
+	handler.GetMessageHistory(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var got []domain.MessageRevision
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Len(t, got, 1)
+	assert.Equal(t, "tpyo", got[0].Content)
+}
+
+func TestMessageHandler_DeleteMessage(t *testing.T) {
+	service := new(mockMessageService)
+	handler := NewMessageHandler(service)
+
+	tests := []struct {
+		name         string
+		query        string
+		mockSetup    func()
+		expectedCode int
+	}{
+		{
+			name:  "for me by default",
+			query: "",
+			mockSetup: func() {
+				service.On("DeleteMessageForMe", "user-1", "m1").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:  "for everyone",
+			query: "?for=everyone",
+			mockSetup: func() {
+				service.On("DeleteMessageForEveryone", "user-1", "m1").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:  "for everyone too late",
+			query: "?for=everyone",
+			mockSetup: func() {
+				service.On("DeleteMessageForEveryone", "user-1", "m1").Return(application.ErrDeleteWindowExpired)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "invalid target",
+			query:        "?for=them",
+			mockSetup:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tc.mockSetup()
+
+			req := httptest.NewRequest(http.MethodDelete, "/messages/m1"+tc.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "m1"})
+			req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+			rr := httptest.NewRecorder()
+
+			handler.DeleteMessage(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageHandler_CreateMessage(t *testing.T) {
+	service := new(mockMessageService)
+	handler := NewMessageHandler(service)
+	created := &domain.Message{ID: uuid.New(), SenderID: "user-1", ConversationID: uuid.New(), Content: "hi"}
+
+	tests := []struct {
+		name         string
+		payload      createMessageRequest
+		mockSetup    func()
+		expectedCode int
+	}{
+		{
+			name:    "to conversation",
+			payload: createMessageRequest{ConversationID: "c1", Content: "hi"},
+			mockSetup: func() {
+				service.On("CreateMessage", "user-1", ports.MessageDraft{ConversationID: "c1", Content: "hi"}).
+					Return(created, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:    "unknown conversation",
+			payload: createMessageRequest{ConversationID: "c1", Content: "hi"},
+			mockSetup: func() {
+				service.On("CreateMessage", "user-1", ports.MessageDraft{ConversationID: "c1", Content: "hi"}).
+					Return(nil, application.ErrConversationNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:    "no recipient",
+			payload: createMessageRequest{Content: "hi"},
+			mockSetup: func() {
+				service.On("CreateMessage", "user-1", ports.MessageDraft{Content: "hi"}).
+					Return(nil, application.ErrRecipientRequired)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:    "unknown format",
+			payload: createMessageRequest{ConversationID: "c1", Content: "hi", Format: "html"},
+			mockSetup: func() {
+				service.On("CreateMessage", "user-1", ports.MessageDraft{ConversationID: "c1", Content: "hi", Format: "html"}).
+					Return(nil, application.ErrInvalidFormat)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tc.mockSetup()
+
+			body, _ := json.Marshal(tc.payload)
+			req := httptest.NewRequest(http.MethodPost, "/messages", bytes.NewReader(body))
+			req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+			rr := httptest.NewRecorder()
+
+			handler.CreateMessage(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusCreated {
+				var got domain.Message
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, created.ID, got.ID)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageHandler_GetConversationMessages(t *testing.T) {
+	service := mocks.NewMockMessageService(t)
+	msgs := []*domain.Message{{
+		ID:        uuid.New(),
+		Content:   "hi",
+		Reactions: []domain.ReactionSummary{{Emoji: "👍", Count: 1, UserIDs: []string{"user-2"}}},
+	}}
+	service.On("GetConversationMessages", "user-1", "c1", 10, 0).Return(msgs, nil)
+
+	handler := NewMessageHandler(service)
+	req := httptest.NewRequest(http.MethodGet, "/conversations/c1/messages", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "c1"})
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+	rr := httptest.NewRecorder()
+
+	handler.GetConversationMessages(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var got []domain.Message
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Len(t, got, 1)
+	assert.Equal(t, msgs[0].Reactions, got[0].Reactions)
+}
+
+func TestMessageHandler_Reactions(t *testing.T) {
+	service := new(mockMessageService)
+	handler := NewMessageHandler(service)
+
+	t.Run("add", func(t *testing.T) {
+		service.ExpectedCalls = nil // reset calls
+		service.On("AddReaction", "user-1", "m1", "👍").Return(nil)
+
+		body, _ := json.Marshal(reactionRequest{Emoji: "👍"})
+		req := httptest.NewRequest(http.MethodPost, "/messages/m1/reactions", bytes.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": "m1"})
+		req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+		rr := httptest.NewRecorder()
+
+		handler.AddReaction(rr, req)
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("add invalid", func(t *testing.T) {
+		service.ExpectedCalls = nil // reset calls
+		service.On("AddReaction", "user-1", "m1", "lol").Return(application.ErrInvalidReaction)
+
+		body, _ := json.Marshal(reactionRequest{Emoji: "lol"})
+		req := httptest.NewRequest(http.MethodPost, "/messages/m1/reactions", bytes.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": "m1"})
+		req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+		rr := httptest.NewRecorder()
+
+		handler.AddReaction(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("remove missing", func(t *testing.T) {
+		service.ExpectedCalls = nil // reset calls
+		service.On("RemoveReaction", "user-1", "m1", ":tada:").Return(application.ErrReactionNotFound)
+
+		req := httptest.NewRequest(http.MethodDelete, "/messages/m1/reactions/:tada:", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "m1", "emoji": ":tada:"})
+		req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+		rr := httptest.NewRecorder()
+
+		handler.RemoveReaction(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		service.AssertExpectations(t)
+	})
+}
+
+func TestMessageHandler_GetThread(t *testing.T) {
+	service := new(mockMessageService)
+	handler := NewMessageHandler(service)
+	rootID := uuid.New()
+	thread := &ports.Thread{
+		Root:    &domain.Message{ID: rootID, Content: "lunch?", Thread: &domain.ThreadSummary{ReplyCount: 1}},
+		Replies: []*domain.Message{{ID: uuid.New(), Content: "yes", ThreadRootID: &rootID, ReplyToID: &rootID}},
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		mockSetup    func()
+		expectedCode int
+	}{
+		{
+			name:  "valid",
+			query: "?limit=20",
+			mockSetup: func() {
+				service.On("GetThread", "user-1", "m1", 20, 0).Return(thread, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:  "unknown message",
+			query: "",
+			mockSetup: func() {
+				service.On("GetThread", "user-1", "m1", 10, 0).Return(nil, application.ErrMessageNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "invalid pagination",
+			query:        "?offset=-1",
+			mockSetup:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tc.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/messages/m1/thread"+tc.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "m1"})
+			req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+			rr := httptest.NewRecorder()
+
+			handler.GetThread(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			if tc.expectedCode == http.StatusOK {
+				var got ports.Thread
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+				assert.Equal(t, 1, got.Root.Thread.ReplyCount)
+				assert.Len(t, got.Replies, 1)
+				assert.Equal(t, rootID, *got.Replies[0].ThreadRootID)
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageHandler_GetMentions(t *testing.T) {
+	service := mocks.NewMockMessageService(t)
+	msgs := []*domain.Message{{
+		ID:       uuid.New(),
+		Content:  "@user-1 ping",
+		Mentions: []domain.Mention{{UserID: "u1", Username: "user-1", Offset: 0, Length: 7}},
+	}}
+	service.On("GetMentions", "u1", 5, 0).Return(msgs, nil)
+
+	handler := NewMessageHandler(service)
+	req := httptest.NewRequest(http.MethodGet, "/users/me/mentions?limit=5", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+	rr := httptest.NewRecorder()
+
+	handler.GetMentions(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var got []domain.Message
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Len(t, got, 1)
+	assert.Equal(t, msgs[0].Mentions, got[0].Mentions)
+}
+
+func TestMessageHandler_SearchMessages(t *testing.T) {
+	service := mocks.NewMockMessageService(t)
+	handler := NewMessageHandler(service)
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		query        string
+		mockSetup    func()
+		expectedCode int
+	}{
+		{
+			name:  "with filters",
+			query: "?q=%22release+notes%22&sender_id=u2&conversation_id=c1&since=2026-01-01T00:00:00Z&limit=5",
+			mockSetup: func() {
+				service.On("SearchMessages", "u1", ports.MessageSearch{
+					Query: `"release notes"`, ConversationID: "c1", SenderID: "u2", Since: since, Limit: 5,
+				}).Return([]*domain.Message{{ID: uuid.New()}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:  "empty query",
+			query: "?q=",
+			mockSetup: func() {
+				service.On("SearchMessages", "u1", ports.MessageSearch{Limit: 10}).Return(nil, application.ErrSearchQueryRequired)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "not a participant",
+			query: "?q=x&conversation_id=c2",
+			mockSetup: func() {
+				service.On("SearchMessages", "u1", ports.MessageSearch{Query: "x", ConversationID: "c2", Limit: 10}).
+					Return(nil, application.ErrConversationNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "bad date",
+			query:        "?q=x&until=yesterday",
+			mockSetup:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/search"+tt.query, nil)
+			req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+			rr := httptest.NewRecorder()
+
+			handler.SearchMessages(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestMessageHandler_ScheduledMessages(t *testing.T) {
+	service := mocks.NewMockMessageService(t)
+	handler := NewMessageHandler(service)
+	sendAt := time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC)
+	id := uuid.New()
+
+	t.Run("create with send_at schedules", func(t *testing.T) {
+		service.On("ScheduleMessage", "u1", ports.MessageDraft{ReceiverID: "u2", Content: "later"}, sendAt).
+			Return(&domain.ScheduledMessage{ID: id, SendAt: sendAt, Status: domain.SchedulePending}, nil).Once()
+
+		body := `{"receiver_id":"u2","content":"later","send_at":"2026-06-01T08:00:00Z"}`
+		req := httptest.NewRequest(http.MethodPost, "/messages", bytes.NewBufferString(body))
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.CreateMessage(rr, req)
+
+		assert.Equal(t, http.StatusAccepted, rr.Code)
+		var got domain.ScheduledMessage
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.Equal(t, id, got.ID)
+	})
+
+	t.Run("reschedule after sending conflicts", func(t *testing.T) {
+		service.On("RescheduleMessage", "u1", id.String(), sendAt).Return(nil, application.ErrScheduledMessageNotPending).Once()
+
+		req := httptest.NewRequest(http.MethodPatch, "/scheduled-messages/"+id.String(), bytes.NewBufferString(`{"send_at":"2026-06-01T08:00:00Z"}`))
+		req = mux.SetURLVars(req, map[string]string{"id": id.String()})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.RescheduleMessage(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		service.On("CancelScheduledMessage", "u1", id.String()).Return(nil).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/scheduled-messages/"+id.String(), nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id.String()})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.CancelScheduledMessage(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		service.On("GetScheduledMessages", "u1", 10, 0).Return([]*domain.ScheduledMessage{{ID: id}}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/scheduled-messages", nil)
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.GetScheduledMessages(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+}
+
+func TestMessageHandler_SetMessageTTL(t *testing.T) {
+	service := mocks.NewMockMessageService(t)
+	handler := NewMessageHandler(service)
+	convID := uuid.New()
+
+	tests := []struct {
+		name         string
+		body         string
+		setup        func()
+		expectedCode int
+	}{
+		{
+			name: "set",
+			body: `{"ttl_seconds":3600}`,
+			setup: func() {
+				service.On("SetMessageTTL", "u1", convID.String(), time.Hour).
+					Return(&domain.Conversation{ID: convID, MessageTTL: time.Hour}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "out of range",
+			body: `{"ttl_seconds":5}`,
+			setup: func() {
+				service.On("SetMessageTTL", "u1", convID.String(), 5*time.Second).
+					Return(nil, application.ErrInvalidMessageTTL).Once()
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "negative",
+			body:         `{"ttl_seconds":-1}`,
+			setup:        func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "not a participant",
+			body: `{"ttl_seconds":0}`,
+			setup: func() {
+				service.On("SetMessageTTL", "u1", convID.String(), time.Duration(0)).
+					Return(nil, application.ErrConversationNotFound).Once()
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPut, "/conversations/"+convID.String()+"/ttl", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": convID.String()})
+			req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+			rr := httptest.NewRecorder()
+			handler.SetMessageTTL(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if rr.Code == http.StatusOK {
+				assert.Contains(t, rr.Body.String(), `"message_ttl_seconds":3600`)
+			}
+		})
+	}
+}
+
+func TestMessageHandler_Pins(t *testing.T) {
+	service := mocks.NewMockMessageService(t)
+	handler := NewMessageHandler(service)
+	msgID, convID := uuid.New(), uuid.New()
+
+	t.Run("pin", func(t *testing.T) {
+		service.On("PinMessage", "u1", msgID.String()).
+			Return(&domain.Pin{ConversationID: convID, MessageID: msgID, PinnedBy: "u1"}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/messages/"+msgID.String()+"/pin", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": msgID.String()})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.PinMessage(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var got domain.Pin
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.Equal(t, msgID, got.MessageID)
+	})
+
+	t.Run("pin over the cap", func(t *testing.T) {
+		service.On("PinMessage", "u1", msgID.String()).Return(nil, application.ErrTooManyPins).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/messages/"+msgID.String()+"/pin", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": msgID.String()})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.PinMessage(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("unpin without permission", func(t *testing.T) {
+		service.On("UnpinMessage", "u1", msgID.String()).Return(application.ErrPinForbidden).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/messages/"+msgID.String()+"/pin", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": msgID.String()})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.UnpinMessage(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		service.On("GetPins", "u1", convID.String()).Return([]*domain.Pin{{MessageID: msgID}}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/conversations/"+convID.String()+"/pins", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": convID.String()})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.GetPins(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var got []domain.Pin
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.Len(t, got, 1)
+	})
+}
+
+func TestMessageHandler_ForwardMessage(t *testing.T) {
+	service := mocks.NewMockMessageService(t)
+	handler := NewMessageHandler(service)
+	msgID, convID := uuid.New(), uuid.New()
+	target := ports.ForwardTarget{ConversationID: convID.String()}
+
+	t.Run("success", func(t *testing.T) {
+		forwarded := &domain.Message{
+			ID:             uuid.New(),
+			SenderID:       "u1",
+			ConversationID: convID,
+			Content:        "hello",
+			ForwardedFrom:  &domain.Forward{MessageID: msgID, SenderID: "u2"},
+		}
+		service.On("ForwardMessage", "u1", msgID.String(), target).Return(forwarded, nil).Once()
+
+		body := `{"conversation_id":"` + convID.String() + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/messages/"+msgID.String()+"/forward", bytes.NewBufferString(body))
+		req = mux.SetURLVars(req, map[string]string{"id": msgID.String()})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.ForwardMessage(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		var got domain.Message
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		if assert.NotNil(t, got.ForwardedFrom) {
+			assert.Equal(t, "u2", got.ForwardedFrom.SenderID)
+		}
+	})
+
+	t.Run("nothing to forward", func(t *testing.T) {
+		service.On("ForwardMessage", "u1", msgID.String(), target).Return(nil, application.ErrNotForwardable).Once()
+
+		body := `{"conversation_id":"` + convID.String() + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/messages/"+msgID.String()+"/forward", bytes.NewBufferString(body))
+		req = mux.SetURLVars(req, map[string]string{"id": msgID.String()})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.ForwardMessage(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+type NotificationPreferenceHandler struct {
+	prefService ports.NotificationPreferenceService
+}
+
+func NewNotificationPreferenceHandler(svc ports.NotificationPreferenceService) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{prefService: svc}
+}
+
+type muteRequest struct {
+	Until *time.Time `json:"until"`
+}
+
+func (h *NotificationPreferenceHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	settings, err := h.prefService.GetNotificationSettings(userID)
+	if err != nil {
+		writeNotificationPreferenceError(w, err)
+		return
+	}
+	writeNotificationSettings(w, settings)
+}
+
+// UpdateSettings replaces the caller's settings with the request body.
+func (h *NotificationPreferenceHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.NotificationSettings
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.prefService.UpdateNotificationSettings(userID, req)
+	if err != nil {
+		writeNotificationPreferenceError(w, err)
+		return
+	}
+	writeNotificationSettings(w, settings)
+}
+
+// MuteConversation mutes a conversation for the caller, until the
+// optional "until" time or until unmuted.
+func (h *NotificationPreferenceHandler) MuteConversation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req muteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	mute, err := h.prefService.MuteConversation(userID, mux.Vars(r)["id"], req.Until)
+	if err != nil {
+		writeNotificationPreferenceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(mute)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *NotificationPreferenceHandler) UnmuteConversation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.prefService.UnmuteConversation(userID, mux.Vars(r)["id"]); err != nil {
+		writeNotificationPreferenceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *NotificationPreferenceHandler) GetMutes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	mutes, err := h.prefService.GetMutes(userID)
+	if err != nil {
+		writeNotificationPreferenceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(mutes)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func writeNotificationSettings(w http.ResponseWriter, settings *domain.NotificationSettings) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(settings)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func writeNotificationPreferenceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrInvalidNotificationLevel),
+		errors.Is(err, application.ErrInvalidTimeZone),
+		errors.Is(err, application.ErrInvalidDoNotDisturb),
+		errors.Is(err, application.ErrMuteExpired):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, application.ErrConversationNotFound),
+		errors.Is(err, application.ErrMuteNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, "failed to update notification preferences", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestNotificationPreferenceHandler_Settings(t *testing.T) {
+	service := mocks.NewMockNotificationPreferenceService(t)
+	handler := NewNotificationPreferenceHandler(service)
+
+	want := domain.NotificationSettings{
+		Level:        domain.NotifyMentions,
+		TimeZone:     "Europe/Athens",
+		DoNotDisturb: &domain.DoNotDisturb{Start: "22:00", End: "07:00"},
+	}
+	service.On("UpdateNotificationSettings", "u1", want).Return(&want, nil).Once()
+
+	body := `{"level":"mentions","time_zone":"Europe/Athens","do_not_disturb":{"start":"22:00","end":"07:00"}}`
+	req := httptest.NewRequest(http.MethodPut, "/users/me/notification-settings", bytes.NewBufferString(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+	rr := httptest.NewRecorder()
+	handler.UpdateSettings(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, body, rr.Body.String())
+
+	service.On("UpdateNotificationSettings", "u1", mock.Anything).Return(nil, application.ErrInvalidTimeZone).Once()
+	req = httptest.NewRequest(http.MethodPut, "/users/me/notification-settings", bytes.NewBufferString(`{"time_zone":"Nowhere"}`))
+	req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+	rr = httptest.NewRecorder()
+	handler.UpdateSettings(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestNotificationPreferenceHandler_Mute(t *testing.T) {
+	service := mocks.NewMockNotificationPreferenceService(t)
+	handler := NewNotificationPreferenceHandler(service)
+	convID := uuid.New()
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("until", func(t *testing.T) {
+		service.On("MuteConversation", "u1", convID.String(), mock.MatchedBy(func(u *time.Time) bool {
+			return u != nil && u.Equal(until)
+		})).Return(&domain.ConversationMute{ConversationID: convID, Until: &until}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPut, "/conversations/"+convID.String()+"/mute", bytes.NewBufferString(`{"until":"2030-01-01T00:00:00Z"}`))
+		req = mux.SetURLVars(req, map[string]string{"id": convID.String()})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.MuteConversation(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var got domain.ConversationMute
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.Equal(t, convID, got.ConversationID)
+	})
+
+	t.Run("indefinitely", func(t *testing.T) {
+		service.On("MuteConversation", "u1", convID.String(), (*time.Time)(nil)).
+			Return(&domain.ConversationMute{ConversationID: convID}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPut, "/conversations/"+convID.String()+"/mute", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": convID.String()})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.MuteConversation(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("unmute", func(t *testing.T) {
+		service.On("UnmuteConversation", "u1", convID.String()).Return(application.ErrMuteNotFound).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/conversations/"+convID.String()+"/mute", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": convID.String()})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.UnmuteConversation(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
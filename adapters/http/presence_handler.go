@@ -0,0 +1,133 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+type PresenceHandler struct {
+	presenceService ports.PresenceService
+}
+
+func NewPresenceHandler(svc ports.PresenceService) *PresenceHandler {
+	return &PresenceHandler{presenceService: svc}
+}
+
+type heartbeatRequest struct {
+	Away bool `json:"away"`
+}
+
+type privacyRequest struct {
+	HideLastSeen bool `json:"hide_last_seen"`
+}
+
+// Heartbeat keeps the caller's presence alive. The body is optional;
+// {"away": true} shows the caller as away until the next heartbeat.
+func (h *PresenceHandler) Heartbeat(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req heartbeatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.presenceService.Heartbeat(userID, req.Away); err != nil {
+		writePresenceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPresence returns a user's presence; "me" stands for the caller.
+func (h *PresenceHandler) GetPresence(w http.ResponseWriter, r *http.Request) {
+	viewerID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || viewerID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID := mux.Vars(r)["id"]
+	if userID == "me" {
+		userID = viewerID
+	}
+	presence, err := h.presenceService.GetPresence(viewerID, userID)
+	if err != nil {
+		writePresenceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(presence)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetConversationPresence returns the presence of every participant of a
+// conversation.
+func (h *PresenceHandler) GetConversationPresence(w http.ResponseWriter, r *http.Request) {
+	viewerID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || viewerID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	presence, err := h.presenceService.GetConversationPresence(viewerID, mux.Vars(r)["id"])
+	if err != nil {
+		writePresenceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(presence)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *PresenceHandler) SetPrivacy(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req privacyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.presenceService.SetLastSeenHidden(userID, req.HideLastSeen); err != nil {
+		writePresenceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writePresenceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrUserNotFound),
+		errors.Is(err, application.ErrConversationNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, "failed to process presence", http.StatusInternalServerError)
+	}
+}
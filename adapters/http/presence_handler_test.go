@@ -0,0 +1,100 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestPresenceHandler(t *testing.T) {
+	service := mocks.NewMockPresenceService(t)
+	handler := NewPresenceHandler(service)
+
+	t.Run("heartbeat without a body", func(t *testing.T) {
+		service.On("Heartbeat", "u1", false).Return(nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/users/me/presence", nil)
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.Heartbeat(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("heartbeat while away", func(t *testing.T) {
+		service.On("Heartbeat", "u1", true).Return(nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/users/me/presence", bytes.NewBufferString(`{"away":true}`))
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.Heartbeat(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		service.On("GetPresence", "u1", "u2").
+			Return(&domain.Presence{UserID: "u2", Status: domain.PresenceOnline}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/users/u2/presence", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "u2"})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.GetPresence(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var got domain.Presence
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.Equal(t, domain.PresenceOnline, got.Status)
+	})
+
+	t.Run("get unknown user", func(t *testing.T) {
+		service.On("GetPresence", "u1", "u1").Return(nil, application.ErrUserNotFound).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/users/me/presence", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "me"})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.GetPresence(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("conversation", func(t *testing.T) {
+		convID := uuid.NewString()
+		service.On("GetConversationPresence", "u1", convID).
+			Return([]domain.Presence{{UserID: "u1"}, {UserID: "u2"}}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/conversations/"+convID+"/presence", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": convID})
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.GetConversationPresence(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var got []domain.Presence
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.Len(t, got, 2)
+	})
+
+	t.Run("privacy", func(t *testing.T) {
+		service.On("SetLastSeenHidden", "u1", true).Return(nil).Once()
+
+		req := httptest.NewRequest(http.MethodPut, "/users/me/privacy", bytes.NewBufferString(`{"hide_last_seen":true}`))
+		req = req.WithContext(contextWithUserID(req.Context(), "u1"))
+		rr := httptest.NewRecorder()
+		handler.SetPrivacy(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+}
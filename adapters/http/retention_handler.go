@@ -0,0 +1,100 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+// RetentionHandler serves the retention routes under /admin. Access
+// control happens in the router.
+type RetentionHandler struct {
+	svc ports.RetentionService
+}
+
+func NewRetentionHandler(svc ports.RetentionService) *RetentionHandler {
+	return &RetentionHandler{svc: svc}
+}
+
+type retentionRequest struct {
+	Days int `json:"days"`
+}
+
+func (h *RetentionHandler) GetPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.svc.GetRetentionPolicies()
+	if err != nil {
+		http.Error(w, "failed to fetch retention policies", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(policies)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SetConversationRetention sets a conversation's retention in days; 0
+// reverts it to the server-wide policy.
+func (h *RetentionHandler) SetConversationRetention(w http.ResponseWriter, r *http.Request) {
+	var req retentionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Days < 0 {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	conv, err := h.svc.SetConversationRetention(mux.Vars(r)["id"], time.Duration(req.Days)*domain.Day)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrInvalidRetention):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, application.ErrConversationNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "failed to set retention", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(conv)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Purge runs the retention purge now and returns its report. With
+// ?dry_run=true nothing is deleted.
+func (h *RetentionHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "invalid 'dry_run' parameter: must be a boolean", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := h.svc.Purge(dryRun)
+	if err != nil {
+		http.Error(w, "purge failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(report)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestRetentionHandler_SetConversationRetention(t *testing.T) {
+	service := mocks.NewMockRetentionService(t)
+	handler := NewRetentionHandler(service)
+	convID := uuid.New()
+
+	tests := []struct {
+		name         string
+		body         string
+		setup        func()
+		expectedCode int
+	}{
+		{
+			name: "set",
+			body: `{"days":90}`,
+			setup: func() {
+				service.On("SetConversationRetention", convID.String(), 90*domain.Day).
+					Return(&domain.Conversation{ID: convID, Retention: 90 * domain.Day}, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "negative",
+			body:         `{"days":-1}`,
+			setup:        func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "unknown conversation",
+			body: `{"days":0}`,
+			setup: func() {
+				service.On("SetConversationRetention", convID.String(), time.Duration(0)).
+					Return(nil, application.ErrConversationNotFound).Once()
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/conversations/"+convID.String()+"/retention", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": convID.String()})
+			rr := httptest.NewRecorder()
+			handler.SetConversationRetention(rr, req)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+			if rr.Code == http.StatusOK {
+				assert.Contains(t, rr.Body.String(), `"retention_days":90`)
+			}
+		})
+	}
+}
+
+func TestRetentionHandler_Purge(t *testing.T) {
+	service := mocks.NewMockRetentionService(t)
+	handler := NewRetentionHandler(service)
+
+	t.Run("dry run", func(t *testing.T) {
+		service.On("Purge", true).Return(&domain.PurgeReport{DryRun: true, PurgeCounts: domain.PurgeCounts{Messages: 12}}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/retention/purge?dry_run=true", nil)
+		rr := httptest.NewRecorder()
+		handler.Purge(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var got domain.PurgeReport
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.True(t, got.DryRun)
+		assert.Equal(t, 12, got.Messages)
+	})
+
+	t.Run("bad dry_run", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/retention/purge?dry_run=maybe", nil)
+		rr := httptest.NewRecorder()
+		handler.Purge(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		service.On("Purge", false).Return(nil, errors.New("db down")).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/retention/purge", nil)
+		rr := httptest.NewRecorder()
+		handler.Purge(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+type SessionHandler struct {
+	svc ports.SessionService
+}
+
+func NewSessionHandler(svc ports.SessionService) *SessionHandler {
+	return &SessionHandler{svc: svc}
+}
+
+type sessionResponse struct {
+	*domain.Session
+	Current bool `json:"current"`
+}
+
+func (h *SessionHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	currentID, _ := r.Context().Value(auth.ContextSessionIDKey).(string)
+
+	sessions, err := h.svc.ListSessions(userID)
+	if err != nil {
+		http.Error(w, "failed to fetch sessions", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]sessionResponse, len(sessions))
+	for i, s := range sessions {
+		resp[i] = sessionResponse{Session: s, Current: s.ID.String() == currentID}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *SessionHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.svc.RevokeSession(userID, mux.Vars(r)["id"]); err != nil {
+		if errors.Is(err, application.ErrSessionNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, "failed to revoke session", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
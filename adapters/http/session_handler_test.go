@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/domain"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+// mockSessionService is a testify/mock for ports.SessionService.
+type mockSessionService struct {
+	mock.Mock
+}
+
+func (m *mockSessionService) ListSessions(userID string) ([]*domain.Session, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]*domain.Session), args.Error(1)
+}
+
+func (m *mockSessionService) RevokeSession(userID, sessionID string) error {
+	return m.Called(userID, sessionID).Error(0)
+}
+
+func TestSessionHandler_ListSessions(t *testing.T) {
+	service := new(mockSessionService)
+	handler := NewSessionHandler(service)
+
+	now := time.Now()
+	current := &domain.Session{ID: uuid.New(), UserID: "alice", DeviceName: "laptop", CreatedAt: now, LastSeenAt: now}
+	other := &domain.Session{ID: uuid.New(), UserID: "alice", DeviceName: "phone", CreatedAt: now, LastSeenAt: now}
+	service.On("ListSessions", "alice").Return([]*domain.Session{current, other}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me/sessions", nil)
+	ctx := contextWithUserID(req.Context(), "alice")
+	ctx = context.WithValue(ctx, auth.ContextSessionIDKey, current.ID.String())
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	handler.ListSessions(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var got []struct {
+		ID         uuid.UUID `json:"id"`
+		DeviceName string    `json:"device_name"`
+		Current    bool      `json:"current"`
+	}
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Len(t, got, 2)
+	assert.Equal(t, current.ID, got[0].ID)
+	assert.True(t, got[0].Current)
+	assert.Equal(t, "phone", got[1].DeviceName)
+	assert.False(t, got[1].Current)
+
+	service.AssertExpectations(t)
+}
+
+func TestSessionHandler_RevokeSession(t *testing.T) {
+	service := new(mockSessionService)
+	handler := NewSessionHandler(service)
+
+	tests := []struct {
+		name         string
+		sessionID    string
+		mockSetup    func()
+		expectedCode int
+	}{
+		{
+			name:      "revoked",
+			sessionID: "s1",
+			mockSetup: func() {
+				service.On("RevokeSession", "alice", "s1").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:      "not found",
+			sessionID: "s2",
+			mockSetup: func() {
+				service.On("RevokeSession", "alice", "s2").Return(application.ErrSessionNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tc.mockSetup()
+
+			req := httptest.NewRequest(http.MethodDelete, "/users/me/sessions/"+tc.sessionID, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.sessionID})
+			req = req.WithContext(contextWithUserID(req.Context(), "alice"))
+			rr := httptest.NewRecorder()
+
+			handler.RevokeSession(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
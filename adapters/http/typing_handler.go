@@ -0,0 +1,89 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+type TypingHandler struct {
+	typingService ports.TypingService
+}
+
+func NewTypingHandler(svc ports.TypingService) *TypingHandler {
+	return &TypingHandler{typingService: svc}
+}
+
+// StartTyping marks the caller as typing. The response says when the
+// indicator lapses; clients post again before then while typing goes on.
+func (h *TypingHandler) StartTyping(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	typer, err := h.typingService.StartTyping(userID, mux.Vars(r)["id"])
+	if err != nil {
+		writeTypingError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(typer)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *TypingHandler) StopTyping(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.typingService.StopTyping(userID, mux.Vars(r)["id"]); err != nil {
+		writeTypingError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTypers lists the other participants typing in a conversation.
+func (h *TypingHandler) GetTypers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	typers, err := h.typingService.GetTypers(userID, mux.Vars(r)["id"])
+	if err != nil {
+		writeTypingError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(typers)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func writeTypingError(w http.ResponseWriter, err error) {
+	if errors.Is(err, application.ErrConversationNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, "failed to update typing status", http.StatusInternalServerError)
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestTypingHandler(t *testing.T) {
+	service := mocks.NewMockTypingService(t)
+	handler := NewTypingHandler(service)
+	convID := uuid.New().String()
+
+	newRequest := func(method string) *http.Request {
+		req := httptest.NewRequest(method, "/conversations/"+convID+"/typing", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": convID})
+		return req.WithContext(contextWithUserID(req.Context(), "u1"))
+	}
+
+	t.Run("start", func(t *testing.T) {
+		expires := time.Now().Add(5 * time.Second).UTC()
+		service.On("StartTyping", "u1", convID).Return(&domain.Typer{UserID: "u1", ExpiresAt: expires}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.StartTyping(rr, newRequest(http.MethodPost))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var got domain.Typer
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.True(t, expires.Equal(got.ExpiresAt))
+	})
+
+	t.Run("start outside the conversation", func(t *testing.T) {
+		service.On("StartTyping", "u1", convID).Return(nil, application.ErrConversationNotFound).Once()
+
+		rr := httptest.NewRecorder()
+		handler.StartTyping(rr, newRequest(http.MethodPost))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		service.On("StopTyping", "u1", convID).Return(nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.StopTyping(rr, newRequest(http.MethodDelete))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		service.On("GetTypers", "u1", convID).Return([]domain.Typer{{UserID: "u2"}}, nil).Once()
+
+		rr := httptest.NewRecorder()
+		handler.GetTypers(rr, newRequest(http.MethodGet))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var got []domain.Typer
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+		assert.Equal(t, []domain.Typer{{UserID: "u2"}}, got)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/conversations/"+convID+"/typing", nil)
+		rr := httptest.NewRecorder()
+		handler.StartTyping(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
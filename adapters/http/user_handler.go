@@ -2,10 +2,13 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
 
 	"github.com/chrikar/chatheon/application"
 	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/internal/auth"
 )
 
 type UserHandler struct {
@@ -27,7 +30,46 @@ type loginRequest struct {
 }
 
 type loginResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token,omitempty"`
+	TOTPRequired bool   `json:"totp_required,omitempty"`
+	Challenge    string `json:"challenge,omitempty"`
+}
+
+type changePasswordRequest struct {
+	Username    string `json:"username"`
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+	// Code is required when two-factor authentication is on.
+	Code string `json:"code"`
+}
+
+type totpLoginRequest struct {
+	Challenge string `json:"challenge"`
+	Code      string `json:"code"`
+}
+
+type totpCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type totpEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type emailRequest struct {
+	Email string `json:"email"`
+}
+
+// directoryUserResponse is what any user may see of another.
+type directoryUserResponse struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	IsBot    bool   `json:"is_bot"`
 }
 
 func (h *UserHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
@@ -63,9 +105,37 @@ func (h *UserHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.userService.Login(req.Username, req.Password)
+	result, err := h.userService.Login(req.Username, req.Password, clientInfo(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		writeLoginError(w, err)
+		return
+	}
+
+	resp := loginResponse{Token: result.Token}
+	if result.Challenge != "" {
+		resp = loginResponse{TOTPRequired: true, Challenge: result.Challenge}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// CompleteTOTPLogin exchanges a login challenge and a second-factor code
+// for an access token.
+func (h *UserHandler) CompleteTOTPLogin(w http.ResponseWriter, r *http.Request) {
+	var req totpLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.userService.CompleteTOTPLogin(req.Challenge, req.Code, clientInfo(r))
+	if err != nil {
+		writeLoginError(w, err)
 		return
 	}
 
@@ -76,3 +146,211 @@ func (h *UserHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// ChangePassword is public so that users whose password was reset by an
+// admin can set a new one without a token.
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req changePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := h.userService.ChangePassword(req.Username, req.OldPassword, req.NewPassword, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrPasswordRequired):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, application.ErrAccountDisabled):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, application.ErrInvalidCredentials),
+			errors.Is(err, application.ErrInvalidTOTPCode):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case errors.Is(err, application.ErrTooManyAttempts):
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		default:
+			http.Error(w, "failed to change password", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	enrollment, err := h.userService.EnrollTOTP(userID)
+	if err != nil {
+		writeTOTPError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(totpEnrollmentResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	})
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *UserHandler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req totpCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	codes, err := h.userService.ConfirmTOTP(userID, req.Code)
+	if err != nil {
+		writeTOTPError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(recoveryCodesResponse{RecoveryCodes: codes})
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *UserHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req totpCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userService.DisableTOTP(userID, req.Code); err != nil {
+		writeTOTPError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SearchUsers serves the user directory: GET /users?q=... with the usual
+// limit and offset.
+func (h *UserHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	limit, offset, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	users, err := h.userService.SearchUsers(userID, r.URL.Query().Get("q"), limit, offset)
+	if err != nil {
+		http.Error(w, "failed to search users", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]directoryUserResponse, 0, len(users))
+	for _, u := range users {
+		resp = append(resp, directoryUserResponse{ID: u.ID.String(), Username: u.Username, IsBot: u.IsBot})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(resp)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// clientInfo describes the caller for the session a login creates. Clients
+// may name themselves through the X-Device-Name header.
+func clientInfo(r *http.Request) ports.ClientInfo {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return ports.ClientInfo{
+		DeviceName: r.Header.Get("X-Device-Name"),
+		UserAgent:  r.UserAgent(),
+		IP:         ip,
+	}
+}
+
+// SetEmail sets the address the user gets email notifications at; an
+// empty email turns them off.
+func (h *UserHandler) SetEmail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(auth.ContextUserIDKey).(string)
+	if !ok || userID == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req emailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := h.userService.SetEmail(userID, req.Email)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrInvalidEmail):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, application.ErrUserNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			http.Error(w, "failed to set email", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeTOTPError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrTOTPAlreadyEnabled),
+		errors.Is(err, application.ErrTOTPNotEnrolled),
+		errors.Is(err, application.ErrTOTPNotEnabled):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, application.ErrInvalidTOTPCode):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, application.ErrTooManyAttempts):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	default:
+		http.Error(w, "two-factor request failed", http.StatusInternalServerError)
+	}
+}
+
+// writeLoginError reports account-state errors as 403 so clients can tell
+// them apart from bad credentials.
+func writeLoginError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrAccountDisabled),
+		errors.Is(err, application.ErrPasswordResetRequired):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, application.ErrTooManyAttempts):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	default:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	}
+}
@@ -7,10 +7,14 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/chrikar/chatheon/adapters/mocks"
 	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
 )
 
 func TestUserHandler_RegisterUser(t *testing.T) {
@@ -73,7 +77,7 @@ func TestUserHandler_LoginUser(t *testing.T) {
 			name:    "valid",
 			payload: loginRequest{"user", "pass"},
 			mockSetup: func() {
-				service.On("Login", "user", "pass").Return("mock-token", nil)
+				service.On("Login", "user", "pass", mock.Anything).Return(&ports.LoginResult{Token: "mock-token"}, nil)
 			},
 			expectedCode: http.StatusOK,
 			expectToken:  true,
@@ -82,11 +86,20 @@ func TestUserHandler_LoginUser(t *testing.T) {
 			name:    "invalid credentials",
 			payload: loginRequest{"user", "wrongpass"},
 			mockSetup: func() {
-				service.On("Login", "user", "wrongpass").Return("", application.ErrInvalidCredentials)
+				service.On("Login", "user", "wrongpass", mock.Anything).Return(nil, application.ErrInvalidCredentials)
 			},
 			expectedCode: http.StatusUnauthorized,
 			expectToken:  false,
 		},
+		{
+			name:    "disabled account",
+			payload: loginRequest{"user", "pass"},
+			mockSetup: func() {
+				service.On("Login", "user", "pass", mock.Anything).Return(nil, application.ErrAccountDisabled)
+			},
+			expectedCode: http.StatusForbidden,
+			expectToken:  false,
+		},
 	}
 
 	for _, tc := range tests {
@@ -134,3 +147,183 @@ func TestUserHandler_RegisterUser_EmptyFields(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	}
 }
+
+func TestUserHandler_LoginUser_TOTPChallenge(t *testing.T) {
+	service := mocks.NewMockUserService(t)
+	handler := NewUserHandler(service)
+
+	service.On("Login", "user", "pass", mock.Anything).Return(&ports.LoginResult{Challenge: "challenge-token"}, nil)
+
+	body, _ := json.Marshal(loginRequest{"user", "pass"})
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	handler.LoginUser(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp loginResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Empty(t, resp.Token)
+	assert.True(t, resp.TOTPRequired)
+	assert.Equal(t, "challenge-token", resp.Challenge)
+}
+
+func TestUserHandler_CompleteTOTPLogin(t *testing.T) {
+	service := new(mocks.MockUserService)
+	handler := NewUserHandler(service)
+
+	tests := []struct {
+		name         string
+		payload      totpLoginRequest
+		mockSetup    func()
+		expectedCode int
+	}{
+		{
+			name:    "valid",
+			payload: totpLoginRequest{Challenge: "c", Code: "123456"},
+			mockSetup: func() {
+				service.On("CompleteTOTPLogin", "c", "123456", mock.Anything).Return("mock-token", nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:    "invalid code",
+			payload: totpLoginRequest{Challenge: "c", Code: "000000"},
+			mockSetup: func() {
+				service.On("CompleteTOTPLogin", "c", "000000", mock.Anything).Return("", application.ErrInvalidTOTPCode)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service.ExpectedCalls = nil // reset calls
+			tc.mockSetup()
+
+			body, _ := json.Marshal(tc.payload)
+			req := httptest.NewRequest(http.MethodPost, "/login/totp", bytes.NewReader(body))
+			rr := httptest.NewRecorder()
+
+			handler.CompleteTOTPLogin(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_EnrollAndConfirmTOTP(t *testing.T) {
+	service := mocks.NewMockUserService(t)
+	handler := NewUserHandler(service)
+
+	service.On("EnrollTOTP", "user-1").Return(&ports.TOTPEnrollment{
+		Secret:          "SECRET",
+		ProvisioningURI: "otpauth://totp/Chatheon:alice?secret=SECRET",
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/me/totp", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+	rr := httptest.NewRecorder()
+	handler.EnrollTOTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var enrollment totpEnrollmentResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&enrollment))
+	assert.Equal(t, "SECRET", enrollment.Secret)
+
+	service.On("ConfirmTOTP", "user-1", "123456").Return([]string{"aaaaa-bbbbb"}, nil)
+	service.On("ConfirmTOTP", "user-1", "000000").Return(nil, application.ErrInvalidTOTPCode)
+
+	body, _ := json.Marshal(totpCodeRequest{Code: "123456"})
+	req = httptest.NewRequest(http.MethodPost, "/users/me/totp/confirm", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+	rr = httptest.NewRecorder()
+	handler.ConfirmTOTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var codes recoveryCodesResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&codes))
+	assert.Equal(t, []string{"aaaaa-bbbbb"}, codes.RecoveryCodes)
+
+	body, _ = json.Marshal(totpCodeRequest{Code: "000000"})
+	req = httptest.NewRequest(http.MethodPost, "/users/me/totp/confirm", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+	rr = httptest.NewRecorder()
+	handler.ConfirmTOTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUserHandler_DisableTOTP(t *testing.T) {
+	service := mocks.NewMockUserService(t)
+	handler := NewUserHandler(service)
+
+	service.On("DisableTOTP", "user-1", "123456").Return(application.ErrTOTPNotEnabled)
+
+	body, _ := json.Marshal(totpCodeRequest{Code: "123456"})
+	req := httptest.NewRequest(http.MethodDelete, "/users/me/totp", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+	rr := httptest.NewRecorder()
+	handler.DisableTOTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	// no user in context
+	req = httptest.NewRequest(http.MethodDelete, "/users/me/totp", bytes.NewReader(body))
+	rr = httptest.NewRecorder()
+	handler.DisableTOTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestUserHandler_SearchUsers(t *testing.T) {
+	service := mocks.NewMockUserService(t)
+	handler := NewUserHandler(service)
+
+	bob := &domain.User{ID: uuid.New(), Username: "bob", PasswordHash: "secret"}
+	service.On("SearchUsers", "user-1", "bo", 5, 0).Return([]*domain.User{bob}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/users?q=bo&limit=5", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
+	rr := httptest.NewRecorder()
+	handler.SearchUsers(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "secret")
+	var got []directoryUserResponse
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&got))
+	assert.Equal(t, []directoryUserResponse{{ID: bob.ID.String(), Username: "bob"}}, got)
+}
+
+func TestUserHandler_SetEmail(t *testing.T) {
+	service := mocks.NewMockUserService(t)
+	handler := NewUserHandler(service)
+
+	tests := []struct {
+		name         string
+		body         string
+		err          error
+		expectedCode int
+	}{
+		{name: "set", body: `{"email":"alice@example.com"}`, expectedCode: http.StatusNoContent},
+		{name: "invalid", body: `{"email":"alice"}`, err: application.ErrInvalidEmail, expectedCode: http.StatusBadRequest},
+		{name: "bad body", body: `{`, expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req emailRequest
+			if json.Unmarshal([]byte(tt.body), &req) == nil {
+				service.On("SetEmail", "user-1", req.Email).Return(tt.err).Once()
+			}
+
+			r := httptest.NewRequest(http.MethodPut, "/users/me/email", bytes.NewBufferString(tt.body))
+			r = r.WithContext(contextWithUserID(r.Context(), "user-1"))
+			rr := httptest.NewRecorder()
+			handler.SetEmail(rr, r)
+
+			assert.Equal(t, tt.expectedCode, rr.Code)
+		})
+	}
+}
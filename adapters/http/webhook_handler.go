@@ -0,0 +1,32 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/chrikar/chatheon/application/ports"
+)
+
+// WebhookHandler serves the webhook delivery log under /admin. Access
+// control happens in the router.
+type WebhookHandler struct {
+	log ports.WebhookDeliveryLog
+}
+
+func NewWebhookHandler(log ports.WebhookDeliveryLog) *WebhookHandler {
+	return &WebhookHandler{log: log}
+}
+
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := parsePagination(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(h.log.Deliveries(limit, offset))
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application/ports"
+)
+
+func TestWebhookHandler_GetDeliveries(t *testing.T) {
+	deliveryLog := mocks.NewMockWebhookDeliveryLog(t)
+	handler := NewWebhookHandler(deliveryLog)
+
+	deliveryLog.On("Deliveries", 5, 10).Return([]ports.WebhookDelivery{
+		{URL: "https://example.com/hook", Status: ports.DeliveryFailed, Attempts: 5, StatusCode: 503},
+	}).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/webhooks/deliveries?limit=5&offset=10", nil)
+	rr := httptest.NewRecorder()
+	handler.GetDeliveries(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var got []ports.WebhookDelivery
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, ports.DeliveryFailed, got[0].Status)
+		assert.Equal(t, 503, got[0].StatusCode)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/webhooks/deliveries?limit=0", nil)
+	rr = httptest.NewRecorder()
+	handler.GetDeliveries(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
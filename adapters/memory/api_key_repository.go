@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// APIKeyRepository is an in‑memory implementation of ports.APIKeyRepository.
+// Like SessionRepository it hands out copies, since keys are touched on
+// every request they authenticate.
+type APIKeyRepository struct {
+	mu   sync.RWMutex
+	keys []*domain.APIKey
+}
+
+// NewAPIKeyRepository constructs an in‑memory repo.
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		keys: make([]*domain.APIKey, 0),
+	}
+}
+
+// Create appends a new key. Prefixes must be unique.
+func (r *APIKeyRepository) Create(key *domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.Prefix == key.Prefix {
+			return errors.New("API key prefix already exists")
+		}
+	}
+	c := *key
+	r.keys = append(r.keys, &c)
+	return nil
+}
+
+// FindByID looks up a key by ID.
+func (r *APIKeyRepository) FindByID(id uuid.UUID) (*domain.APIKey, error) {
+	return r.find(func(k *domain.APIKey) bool { return k.ID == id })
+}
+
+// FindByPrefix looks up a key by its public prefix.
+func (r *APIKeyRepository) FindByPrefix(prefix string) (*domain.APIKey, error) {
+	return r.find(func(k *domain.APIKey) bool { return k.Prefix == prefix })
+}
+
+// FindByUser filters keys by userID.
+func (r *APIKeyRepository) FindByUser(userID string) ([]*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*domain.APIKey
+	for _, k := range r.keys {
+		if k.UserID == userID {
+			c := *k
+			result = append(result, &c)
+		}
+	}
+	return result, nil
+}
+
+// Update replaces the stored key with the same ID.
+func (r *APIKeyRepository) Update(key *domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, k := range r.keys {
+		if k.ID == key.ID {
+			c := *key
+			r.keys[i] = &c
+			return nil
+		}
+	}
+	return errors.New("API key not found")
+}
+
+func (r *APIKeyRepository) find(match func(*domain.APIKey) bool) (*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, k := range r.keys {
+		if match(k) {
+			c := *k
+			return &c, nil
+		}
+	}
+	return nil, errors.New("API key not found")
+}
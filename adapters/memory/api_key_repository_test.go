@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestAPIKeyRepository_CreateAndFind(t *testing.T) {
+	t.Parallel()
+
+	repo := NewAPIKeyRepository()
+	now := time.Now()
+
+	k1 := &domain.APIKey{ID: uuid.New(), UserID: "bot-1", Name: "ci", Prefix: "aaaa", Hash: "h1", CreatedAt: now}
+	k2 := &domain.APIKey{ID: uuid.New(), UserID: "bot-1", Name: "deploy", Prefix: "bbbb", Hash: "h2", CreatedAt: now}
+	assert.NoError(t, repo.Create(k1))
+	assert.NoError(t, repo.Create(k2))
+
+	// prefixes are unique
+	assert.Error(t, repo.Create(&domain.APIKey{ID: uuid.New(), Prefix: "aaaa"}))
+
+	found, err := repo.FindByPrefix("bbbb")
+	assert.NoError(t, err)
+	assert.Equal(t, k2.ID, found.ID)
+
+	found, err = repo.FindByID(k1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "ci", found.Name)
+
+	_, err = repo.FindByPrefix("zzzz")
+	assert.Error(t, err)
+
+	keys, err := repo.FindByUser("bot-1")
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	none, err := repo.FindByUser("someone")
+	assert.NoError(t, err)
+	assert.Empty(t, none)
+
+	found.RevokedAt = &now
+	assert.NoError(t, repo.Update(found))
+	found, _ = repo.FindByID(k1.ID)
+	assert.False(t, found.Active())
+
+	assert.Error(t, repo.Update(&domain.APIKey{ID: uuid.New()}))
+}
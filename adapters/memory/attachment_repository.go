@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+// AttachmentRepository is an in-memory implementation of
+// ports.AttachmentRepository.
+type AttachmentRepository struct {
+	mu          sync.RWMutex
+	attachments []*domain.Attachment
+}
+
+func NewAttachmentRepository() *AttachmentRepository {
+	return &AttachmentRepository{}
+}
+
+func (r *AttachmentRepository) Create(attachment *domain.Attachment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *attachment
+	r.attachments = append(r.attachments, &stored)
+	return nil
+}
+
+func (r *AttachmentRepository) FindByID(id uuid.UUID) (*domain.Attachment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, a := range r.attachments {
+		if a.ID == id {
+			c := *a
+			return &c, nil
+		}
+	}
+	return nil, errors.New("attachment not found")
+}
+
+func (r *AttachmentRepository) FindByMessages(messageIDs []uuid.UUID) ([]*domain.Attachment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		wanted[id] = true
+	}
+	var result []*domain.Attachment
+	for _, a := range r.attachments {
+		if a.MessageID != nil && wanted[*a.MessageID] {
+			c := *a
+			result = append(result, &c)
+		}
+	}
+	return result, nil
+}
+
+func (r *AttachmentRepository) AttachToMessage(ids []uuid.UUID, messageID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	claimed := make([]*domain.Attachment, 0, len(ids))
+	for _, id := range ids {
+		a := r.find(id)
+		if a == nil || a.MessageID != nil {
+			return ports.ErrAttachmentSent
+		}
+		claimed = append(claimed, a)
+	}
+	for _, a := range claimed {
+		m := messageID
+		a.MessageID = &m
+	}
+	return nil
+}
+
+// find returns the stored attachment with the given ID. Callers hold r.mu.
+func (r *AttachmentRepository) find(id uuid.UUID) *domain.Attachment {
+	for _, a := range r.attachments {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+func (r *AttachmentRepository) DeleteByMessages(messageIDs []uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	doomed := make(map[uuid.UUID]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		doomed[id] = true
+	}
+	kept := r.attachments[:0]
+	for _, a := range r.attachments {
+		if a.MessageID == nil || !doomed[*a.MessageID] {
+			kept = append(kept, a)
+		}
+	}
+	r.attachments = kept
+	return nil
+}
+
+func (r *AttachmentRepository) DeleteUnsent(before time.Time, keep []uuid.UUID, limit int) ([]*domain.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var removed []*domain.Attachment
+	kept := r.attachments[:0]
+	for _, a := range r.attachments {
+		if len(removed) < limit && a.MessageID == nil && a.CreatedAt.Before(before) && !slices.Contains(keep, a.ID) {
+			removed = append(removed, a)
+			continue
+		}
+		kept = append(kept, a)
+	}
+	r.attachments = kept
+	return removed, nil
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestAttachmentRepository_AttachToMessage(t *testing.T) {
+	t.Parallel()
+
+	repo := NewAttachmentRepository()
+	a1 := &domain.Attachment{ID: uuid.New(), UploaderID: "alice", Name: "a.txt"}
+	a2 := &domain.Attachment{ID: uuid.New(), UploaderID: "alice", Name: "b.txt"}
+	assert.NoError(t, repo.Create(a1))
+	assert.NoError(t, repo.Create(a2))
+
+	msgID := uuid.New()
+	found, err := repo.FindByMessages([]uuid.UUID{msgID})
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+
+	assert.NoError(t, repo.AttachToMessage([]uuid.UUID{a2.ID}, msgID))
+	found, err = repo.FindByMessages([]uuid.UUID{msgID})
+	assert.NoError(t, err)
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, "b.txt", found[0].Name)
+	}
+
+	// Claims are all or nothing.
+	assert.ErrorIs(t, repo.AttachToMessage([]uuid.UUID{a1.ID, a2.ID}, uuid.New()), ports.ErrAttachmentSent)
+	assert.ErrorIs(t, repo.AttachToMessage([]uuid.UUID{a1.ID, uuid.New()}, uuid.New()), ports.ErrAttachmentSent)
+
+	got, err := repo.FindByID(a1.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, got.MessageID)
+	_, err = repo.FindByID(uuid.New())
+	assert.Error(t, err)
+}
+
+func TestAttachmentRepository_DeleteUnsent(t *testing.T) {
+	t.Parallel()
+
+	repo := NewAttachmentRepository()
+	now := time.Now()
+	msgID := uuid.New()
+	old := &domain.Attachment{ID: uuid.New(), CreatedAt: now.Add(-2 * time.Hour)}
+	sent := &domain.Attachment{ID: uuid.New(), MessageID: &msgID, CreatedAt: now.Add(-2 * time.Hour)}
+	fresh := &domain.Attachment{ID: uuid.New(), CreatedAt: now}
+	held := &domain.Attachment{ID: uuid.New(), CreatedAt: now.Add(-2 * time.Hour)}
+	for _, a := range []*domain.Attachment{old, sent, fresh, held} {
+		assert.NoError(t, repo.Create(a))
+	}
+
+	removed, err := repo.DeleteUnsent(now.Add(-time.Hour), []uuid.UUID{held.ID}, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, removed, 1) {
+		assert.Equal(t, old.ID, removed[0].ID)
+	}
+	_, err = repo.FindByID(old.ID)
+	assert.Error(t, err)
+	_, err = repo.FindByID(sent.ID)
+	assert.NoError(t, err)
+	_, err = repo.FindByID(fresh.ID)
+	assert.NoError(t, err)
+	_, err = repo.FindByID(held.ID)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/chrikar/chatheon/application/ports"
+)
+
+// BlobStore is an in-memory implementation of ports.BlobStore.
+type BlobStore struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+func NewBlobStore() *BlobStore {
+	return &BlobStore{blobs: make(map[string][]byte)}
+}
+
+func (s *BlobStore) Put(key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = data
+	return nil
+}
+
+func (s *BlobStore) Get(key string) (io.ReadCloser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, ports.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *BlobStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.blobs, key)
+	return nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// BlockRepository is an in-memory implementation of ports.BlockRepository.
+type BlockRepository struct {
+	mu     sync.RWMutex
+	blocks []*domain.Block
+}
+
+func NewBlockRepository() *BlockRepository {
+	return &BlockRepository{}
+}
+
+func (r *BlockRepository) Add(block *domain.Block) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index(block.BlockerID, block.BlockedID) >= 0 {
+		return nil
+	}
+	stored := *block
+	r.blocks = append(r.blocks, &stored)
+	return nil
+}
+
+func (r *BlockRepository) Remove(blockerID, blockedID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(blockerID, blockedID)
+	if i < 0 {
+		return errors.New("block not found")
+	}
+	r.blocks = append(r.blocks[:i], r.blocks[i+1:]...)
+	return nil
+}
+
+func (r *BlockRepository) Exists(blockerID, blockedID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.index(blockerID, blockedID) >= 0, nil
+}
+
+func (r *BlockRepository) FindByBlocker(blockerID string) ([]*domain.Block, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*domain.Block
+	for i := len(r.blocks) - 1; i >= 0; i-- {
+		if r.blocks[i].BlockerID == blockerID {
+			c := *r.blocks[i]
+			result = append(result, &c)
+		}
+	}
+	return result, nil
+}
+
+func (r *BlockRepository) index(blockerID, blockedID string) int {
+	for i, block := range r.blocks {
+		if block.BlockerID == blockerID && block.BlockedID == blockedID {
+			return i
+		}
+	}
+	return -1
+}
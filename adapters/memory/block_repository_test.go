@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestBlockRepository(t *testing.T) {
+	t.Parallel()
+
+	repo := NewBlockRepository()
+	assert.NoError(t, repo.Add(&domain.Block{BlockerID: "a", BlockedID: "b"}))
+	assert.NoError(t, repo.Add(&domain.Block{BlockerID: "a", BlockedID: "b"}))
+	assert.NoError(t, repo.Add(&domain.Block{BlockerID: "a", BlockedID: "c"}))
+	assert.NoError(t, repo.Add(&domain.Block{BlockerID: "c", BlockedID: "a"}))
+
+	blocked, err := repo.Exists("a", "b")
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+	blocked, err = repo.Exists("b", "a")
+	assert.NoError(t, err)
+	assert.False(t, blocked, "blocks are one-way")
+
+	blocks, err := repo.FindByBlocker("a")
+	assert.NoError(t, err)
+	if assert.Len(t, blocks, 2) {
+		assert.Equal(t, "c", blocks[0].BlockedID, "newest first")
+	}
+
+	assert.NoError(t, repo.Remove("a", "b"))
+	assert.Error(t, repo.Remove("a", "b"))
+	blocked, _ = repo.Exists("a", "b")
+	assert.False(t, blocked)
+}
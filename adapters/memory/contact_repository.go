@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// ContactRepository is an in-memory implementation of
+// ports.ContactRepository.
+type ContactRepository struct {
+	mu       sync.RWMutex
+	requests []*domain.ContactRequest
+	contacts []*domain.Contact
+}
+
+func NewContactRepository() *ContactRepository {
+	return &ContactRepository{}
+}
+
+func (r *ContactRepository) CreateRequest(request *domain.ContactRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if request.Status == domain.ContactRequestPending && r.pending(request.FromID, request.ToID) != nil {
+		return errors.New("a request between these users is already pending")
+	}
+	stored := *request
+	r.requests = append(r.requests, &stored)
+	return nil
+}
+
+func (r *ContactRepository) FindRequest(id uuid.UUID) (*domain.ContactRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, req := range r.requests {
+		if req.ID == id {
+			c := *req
+			return &c, nil
+		}
+	}
+	return nil, errors.New("contact request not found")
+}
+
+func (r *ContactRepository) FindPendingRequest(userA, userB string) (*domain.ContactRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if req := r.pending(userA, userB); req != nil {
+		c := *req
+		return &c, nil
+	}
+	return nil, errors.New("contact request not found")
+}
+
+func (r *ContactRepository) FindPendingRequests(userID string) ([]*domain.ContactRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*domain.ContactRequest
+	for i := len(r.requests) - 1; i >= 0; i-- {
+		req := r.requests[i]
+		if req.Status == domain.ContactRequestPending && (req.FromID == userID || req.ToID == userID) {
+			c := *req
+			result = append(result, &c)
+		}
+	}
+	return result, nil
+}
+
+func (r *ContactRepository) UpdateRequest(request *domain.ContactRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, req := range r.requests {
+		if req.ID == request.ID {
+			stored := *request
+			r.requests[i] = &stored
+			return nil
+		}
+	}
+	return errors.New("contact request not found")
+}
+
+func (r *ContactRepository) AddContact(userA, userB string, since time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.contactIndex(userA, userB) >= 0 {
+		return nil
+	}
+	r.contacts = append(r.contacts,
+		&domain.Contact{UserID: userA, ContactID: userB, Since: since},
+		&domain.Contact{UserID: userB, ContactID: userA, Since: since})
+	return nil
+}
+
+func (r *ContactRepository) RemoveContact(userA, userB string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.contactIndex(userA, userB) < 0 {
+		return errors.New("contact not found")
+	}
+	kept := r.contacts[:0]
+	for _, c := range r.contacts {
+		if !(c.UserID == userA && c.ContactID == userB) && !(c.UserID == userB && c.ContactID == userA) {
+			kept = append(kept, c)
+		}
+	}
+	r.contacts = kept
+	return nil
+}
+
+func (r *ContactRepository) IsContact(userID, contactID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.contactIndex(userID, contactID) >= 0, nil
+}
+
+func (r *ContactRepository) FindContacts(userID string) ([]*domain.Contact, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*domain.Contact
+	for i := len(r.contacts) - 1; i >= 0; i-- {
+		if r.contacts[i].UserID == userID {
+			c := *r.contacts[i]
+			result = append(result, &c)
+		}
+	}
+	return result, nil
+}
+
+// pending returns the pending request between two users. Callers hold
+// r.mu.
+func (r *ContactRepository) pending(userA, userB string) *domain.ContactRequest {
+	for _, req := range r.requests {
+		if req.Status != domain.ContactRequestPending {
+			continue
+		}
+		if (req.FromID == userA && req.ToID == userB) || (req.FromID == userB && req.ToID == userA) {
+			return req
+		}
+	}
+	return nil
+}
+
+// contactIndex locates userID's entry for contactID. Callers hold r.mu.
+func (r *ContactRepository) contactIndex(userID, contactID string) int {
+	for i, c := range r.contacts {
+		if c.UserID == userID && c.ContactID == contactID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestContactRepository_Requests(t *testing.T) {
+	t.Parallel()
+
+	repo := NewContactRepository()
+	req := &domain.ContactRequest{ID: uuid.New(), FromID: "a", ToID: "b", Status: domain.ContactRequestPending}
+	assert.NoError(t, repo.CreateRequest(req))
+	assert.Error(t, repo.CreateRequest(&domain.ContactRequest{ID: uuid.New(), FromID: "b", ToID: "a", Status: domain.ContactRequestPending}),
+		"only one pending request either way")
+
+	found, err := repo.FindPendingRequest("b", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, req.ID, found.ID)
+
+	pending, err := repo.FindPendingRequests("b")
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+
+	found.Status = domain.ContactRequestDeclined
+	assert.NoError(t, repo.UpdateRequest(found))
+	_, err = repo.FindPendingRequest("a", "b")
+	assert.Error(t, err)
+	stored, err := repo.FindRequest(req.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ContactRequestDeclined, stored.Status)
+	assert.NoError(t, repo.CreateRequest(&domain.ContactRequest{ID: uuid.New(), FromID: "b", ToID: "a", Status: domain.ContactRequestPending}))
+}
+
+func TestContactRepository_Contacts(t *testing.T) {
+	t.Parallel()
+
+	repo := NewContactRepository()
+	now := time.Now()
+	assert.NoError(t, repo.AddContact("a", "b", now))
+	assert.NoError(t, repo.AddContact("b", "a", now.Add(time.Hour)))
+	assert.NoError(t, repo.AddContact("a", "c", now.Add(time.Minute)))
+
+	for _, pair := range [][2]string{{"a", "b"}, {"b", "a"}, {"c", "a"}} {
+		ok, err := repo.IsContact(pair[0], pair[1])
+		assert.NoError(t, err)
+		assert.True(t, ok, pair)
+	}
+
+	contacts, err := repo.FindContacts("a")
+	assert.NoError(t, err)
+	if assert.Len(t, contacts, 2) {
+		assert.Equal(t, "c", contacts[0].ContactID, "newest first")
+		assert.Equal(t, now, contacts[1].Since, "adding again keeps the first date")
+	}
+
+	assert.NoError(t, repo.RemoveContact("b", "a"))
+	assert.Error(t, repo.RemoveContact("a", "b"))
+	ok, _ := repo.IsContact("a", "b")
+	assert.False(t, ok)
+	contacts, _ = repo.FindContacts("b")
+	assert.Empty(t, contacts)
+}
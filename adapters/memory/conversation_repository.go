@@ -1,8 +1,11 @@
 package memory
 
 import (
+	"errors"
 	"sync"
 
+	"github.com/google/uuid"
+
 	"github.com/chrikar/chatheon/domain"
 )
 
@@ -43,3 +46,56 @@ func (r *ConversationRepository) FindByParticipant(userID string) ([]*domain.Con
 	}
 	return result, nil
 }
+
+// FindByID looks up a conversation by ID.
+func (r *ConversationRepository) FindByID(id uuid.UUID) (*domain.Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, c := range r.conversations {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, errors.New("conversation not found")
+}
+
+// FindWithRetention returns conversations with their own retention
+// period.
+func (r *ConversationRepository) FindWithRetention() ([]*domain.Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*domain.Conversation
+	for _, c := range r.conversations {
+		if c.Retention > 0 {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+// Update replaces a stored conversation.
+func (r *ConversationRepository) Update(conv *domain.Conversation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, c := range r.conversations {
+		if c.ID == conv.ID {
+			r.conversations[i] = conv
+			return nil
+		}
+	}
+	return errors.New("conversation not found")
+}
+
+// Delete removes a conversation.
+func (r *ConversationRepository) Delete(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, c := range r.conversations {
+		if c.ID == id {
+			r.conversations = append(r.conversations[:i], r.conversations[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("conversation not found")
+}
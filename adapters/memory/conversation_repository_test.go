@@ -54,3 +54,18 @@ func TestConversationRepository_CreateAndFindByParticipant(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Empty(t, unk)
 }
+
+func TestConversationRepository_Update(t *testing.T) {
+	repo := NewConversationRepository()
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, repo.Create(conv))
+
+	updated := *conv
+	updated.MessageTTL = time.Hour
+	assert.NoError(t, repo.Update(&updated))
+	found, err := repo.FindByID(conv.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, found.MessageTTL)
+
+	assert.Error(t, repo.Update(&domain.Conversation{ID: uuid.New()}))
+}
@@ -1,23 +1,32 @@
 package memory
 
 import (
-	"fmt"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/chrikar/chatheon/application/ports"
 	"github.com/chrikar/chatheon/domain"
 )
 
 type MessageRepository struct {
-	mu       sync.RWMutex
-	messages []*domain.Message
+	mu        sync.RWMutex
+	messages  []*domain.Message
+	revisions map[uuid.UUID][]*domain.MessageRevision
+	hidden    map[uuid.UUID]map[string]bool
+	// threadReads holds when each user last read each thread.
+	threadReads map[uuid.UUID]map[string]time.Time
 }
 
 func NewMessageRepository() *MessageRepository {
 	return &MessageRepository{
-		messages: make([]*domain.Message, 0),
+		messages:  make([]*domain.Message, 0),
+		revisions: make(map[uuid.UUID][]*domain.MessageRevision),
+		hidden:    make(map[uuid.UUID]map[string]bool),
+
+		threadReads: make(map[uuid.UUID]map[string]time.Time),
 	}
 }
 
@@ -27,7 +36,8 @@ func (r *MessageRepository) Create(message *domain.Message) error {
 
 	message.CreatedAt = time.Now()
 	message.Status = domain.StatusSent
-	r.messages = append(r.messages, message)
+	stored := *message
+	r.messages = append(r.messages, &stored)
 	return nil
 }
 
@@ -37,8 +47,8 @@ func (r *MessageRepository) GetMessagesBySender(senderID string) ([]*domain.Mess
 
 	var result []*domain.Message
 	for _, msg := range r.messages {
-		if msg.SenderID == senderID {
-			result = append(result, msg)
+		if msg.SenderID == senderID && r.visible(msg, senderID) {
+			result = append(result, clone(msg))
 		}
 	}
 	return result, nil
@@ -50,22 +60,97 @@ func (r *MessageRepository) GetMessagesByReceiver(receiverID string, limit, offs
 
 	var result []*domain.Message
 	for _, msg := range r.messages {
-		if msg.ReceiverID == receiverID {
-			result = append(result, msg)
+		if msg.ReceiverID == receiverID && r.visible(msg, receiverID) {
+			result = append(result, clone(msg))
 		}
 	}
 
-	// Apply pagination
-	start := offset
-	if start > len(result) {
-		start = len(result)
+	return paginate(result, limit, offset), nil
+}
+
+func (r *MessageRepository) GetMessagesByConversation(conversationID uuid.UUID, userID string, limit, offset int) ([]*domain.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*domain.Message
+	for _, msg := range r.messages {
+		if msg.ConversationID == conversationID && msg.ThreadRootID == nil && r.visible(msg, userID) {
+			result = append(result, clone(msg))
+		}
 	}
-	end := start + limit
-	if end > len(result) {
-		end = len(result)
+	return paginate(result, limit, offset), nil
+}
+
+func (r *MessageRepository) GetThreadReplies(rootID uuid.UUID, userID string, limit, offset int) ([]*domain.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*domain.Message
+	for _, msg := range r.messages {
+		if msg.ThreadRootID != nil && *msg.ThreadRootID == rootID && r.visible(msg, userID) {
+			result = append(result, clone(msg))
+		}
+	}
+	return paginate(result, limit, offset), nil
+}
+
+func (r *MessageRepository) ThreadSummaries(rootIDs []uuid.UUID, userID string) (map[uuid.UUID]*domain.ThreadSummary, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(rootIDs))
+	for _, id := range rootIDs {
+		wanted[id] = true
+	}
+	result := make(map[uuid.UUID]*domain.ThreadSummary)
+	for _, msg := range r.messages {
+		if msg.ThreadRootID == nil || !wanted[*msg.ThreadRootID] || msg.Expired(time.Now()) {
+			continue
+		}
+		root := *msg.ThreadRootID
+		sum := result[root]
+		if sum == nil {
+			sum = &domain.ThreadSummary{}
+			result[root] = sum
+		}
+		sum.ReplyCount++
+		if !msg.CreatedAt.Before(sum.LastReplyAt) {
+			sum.LastReplyID = msg.ID
+			sum.LastReplyAt = msg.CreatedAt
+		}
+		if !slices.Contains(sum.ParticipantIDs, msg.SenderID) {
+			sum.ParticipantIDs = append(sum.ParticipantIDs, msg.SenderID)
+		}
+		readAt, ok := r.threadReads[root][userID]
+		if msg.SenderID != userID && (!ok || msg.CreatedAt.After(readAt)) {
+			sum.UnreadCount++
+		}
+	}
+	return result, nil
+}
+
+func (r *MessageRepository) MarkThreadRead(rootID uuid.UUID, userID string, readAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.threadReads[rootID] == nil {
+		r.threadReads[rootID] = make(map[string]time.Time)
 	}
+	r.threadReads[rootID][userID] = readAt
+	return nil
+}
 
-	return result[start:end], nil
+func (r *MessageRepository) GetMentions(userID string, limit, offset int) ([]*domain.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*domain.Message
+	for i := len(r.messages) - 1; i >= 0; i-- {
+		msg := r.messages[i]
+		if msg.Mentioned(userID) && r.visible(msg, userID) {
+			result = append(result, clone(msg))
+		}
+	}
+	return paginate(result, limit, offset), nil
 }
 
 func (r *MessageRepository) SetMessageStatus(id uuid.UUID, status domain.MessageStatus) error {
@@ -77,5 +162,231 @@ func (r *MessageRepository) SetMessageStatus(id uuid.UUID, status domain.Message
 			return nil
 		}
 	}
-	return fmt.Errorf("message not found")
+	return ports.ErrMessageNotFound
+}
+
+func (r *MessageRepository) FindByID(id uuid.UUID) (*domain.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, msg := range r.messages {
+		if msg.ID == id && !msg.Expired(time.Now()) {
+			return clone(msg), nil
+		}
+	}
+	return nil, ports.ErrMessageNotFound
+}
+
+func (r *MessageRepository) Update(message *domain.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, msg := range r.messages {
+		if msg.ID == message.ID {
+			stored := *message
+			r.messages[i] = &stored
+			return nil
+		}
+	}
+	return ports.ErrMessageNotFound
+}
+
+// AddRevision records a superseded version of a message.
+func (r *MessageRepository) AddRevision(revision *domain.MessageRevision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.revisions[revision.MessageID] = append(r.revisions[revision.MessageID], revision)
+	return nil
+}
+
+// FindRevisions returns the revisions of a message, oldest first.
+func (r *MessageRepository) FindRevisions(messageID uuid.UUID) ([]*domain.MessageRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]*domain.MessageRevision(nil), r.revisions[messageID]...), nil
+}
+
+func (r *MessageRepository) HideForUser(messageID uuid.UUID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.find(messageID) == nil {
+		return ports.ErrMessageNotFound
+	}
+	if r.hidden[messageID] == nil {
+		r.hidden[messageID] = make(map[string]bool)
+	}
+	r.hidden[messageID][userID] = true
+	return nil
+}
+
+func (r *MessageRepository) DeleteForEveryone(messageID uuid.UUID, deletedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	msg := r.find(messageID)
+	if msg == nil {
+		return ports.ErrMessageNotFound
+	}
+	msg.Content = ""
+	msg.Mentions = nil
+	msg.Entities = nil
+	msg.Quote = nil
+	msg.ForwardedFrom = nil
+	msg.DeletedAt = &deletedAt
+	delete(r.revisions, messageID)
+	return nil
+}
+
+// clone copies a stored message, so callers can't change it behind the
+// lock.
+func clone(msg *domain.Message) *domain.Message {
+	c := *msg
+	return &c
+}
+
+// find returns the stored message with the given ID. Callers hold r.mu.
+func (r *MessageRepository) find(id uuid.UUID) *domain.Message {
+	for _, msg := range r.messages {
+		if msg.ID == id {
+			return msg
+		}
+	}
+	return nil
+}
+
+// Delete removes a message along with its thread replies.
+func (r *MessageRepository) Delete(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.find(id) == nil {
+		return ports.ErrMessageNotFound
+	}
+	r.removeWhere(func(msg *domain.Message) bool {
+		return msg.ID == id || (msg.ThreadRootID != nil && *msg.ThreadRootID == id)
+	})
+	return nil
+}
+
+func (r *MessageRepository) DeleteByConversation(conversationID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removeWhere(func(msg *domain.Message) bool { return msg.ConversationID == conversationID })
+	return nil
+}
+
+func (r *MessageRepository) FindThreadIDs(rootID uuid.UUID) ([]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.find(rootID) == nil {
+		return nil, nil
+	}
+	ids := []uuid.UUID{rootID}
+	for _, msg := range r.messages {
+		if msg.ThreadRootID != nil && *msg.ThreadRootID == rootID {
+			ids = append(ids, msg.ID)
+		}
+	}
+	return ids, nil
+}
+
+// FindExpired returns up to limit expired messages along with their
+// thread replies.
+func (r *MessageRepository) FindExpired(now time.Time, limit int) ([]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	expired := make(map[uuid.UUID]bool)
+	for _, msg := range r.messages {
+		if len(expired) == limit {
+			break
+		}
+		if msg.Expired(now) {
+			expired[msg.ID] = true
+		}
+	}
+	var ids []uuid.UUID
+	for _, msg := range r.messages {
+		if expired[msg.ID] || (msg.ThreadRootID != nil && expired[*msg.ThreadRootID]) {
+			ids = append(ids, msg.ID)
+		}
+	}
+	return ids, nil
+}
+
+// FindPastRetention returns a page of messages matching filter together
+// with their thread replies.
+func (r *MessageRepository) FindPastRetention(filter ports.RetentionFilter, limit, offset int) ([]*domain.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []*domain.Message
+	for _, msg := range r.messages {
+		if msg.CreatedAt.Before(filter.Before) && retentionCovers(filter, msg) {
+			matched = append(matched, msg)
+		}
+	}
+	page := make(map[uuid.UUID]bool)
+	for _, msg := range paginate(matched, limit, offset) {
+		page[msg.ID] = true
+	}
+	var result []*domain.Message
+	for _, msg := range r.messages {
+		if page[msg.ID] || (msg.ThreadRootID != nil && page[*msg.ThreadRootID]) {
+			result = append(result, clone(msg))
+		}
+	}
+	return result, nil
+}
+
+func (r *MessageRepository) DeleteMany(messageIDs []uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	doomed := make(map[uuid.UUID]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		doomed[id] = true
+	}
+	r.removeWhere(func(msg *domain.Message) bool { return doomed[msg.ID] })
+	return nil
+}
+
+func retentionCovers(filter ports.RetentionFilter, msg *domain.Message) bool {
+	if filter.ConversationID != uuid.Nil {
+		return msg.ConversationID == filter.ConversationID
+	}
+	return msg.ConversationID == uuid.Nil || !slices.Contains(filter.Exclude, msg.ConversationID)
+}
+
+// visible reports whether msg shows up in userID's listings. Callers hold
+// r.mu.
+func (r *MessageRepository) visible(msg *domain.Message, userID string) bool {
+	return !r.hidden[msg.ID][userID] && !msg.Expired(time.Now())
+}
+
+// removeWhere drops matching messages and everything stored about them,
+// returning their IDs. Callers hold r.mu.
+func (r *MessageRepository) removeWhere(match func(*domain.Message) bool) []uuid.UUID {
+	var removed []uuid.UUID
+	kept := r.messages[:0]
+	for _, msg := range r.messages {
+		if !match(msg) {
+			kept = append(kept, msg)
+			continue
+		}
+		removed = append(removed, msg.ID)
+		delete(r.revisions, msg.ID)
+		delete(r.hidden, msg.ID)
+		delete(r.threadReads, msg.ID)
+	}
+	r.messages = kept
+	return removed
+}
+
+func paginate[T any](items []T, limit, offset int) []T {
+	start := offset
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
 }
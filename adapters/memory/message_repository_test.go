@@ -3,10 +3,12 @@ package memory
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/chrikar/chatheon/application/ports"
 	"github.com/chrikar/chatheon/domain"
 )
 
@@ -113,3 +115,154 @@ func TestMessageRepository_Pagination(t *testing.T) {
 		assert.Len(t, messages, tc.expectedCount)
 	}
 }
+
+func TestMessageRepository_Revisions(t *testing.T) {
+	t.Parallel()
+
+	repo := NewMessageRepository()
+	msg := &domain.Message{ID: uuid.New(), SenderID: "user-1", Content: "v3"}
+	assert.NoError(t, repo.Create(msg))
+
+	for _, content := range []string{"v1", "v2"} {
+		assert.NoError(t, repo.AddRevision(&domain.MessageRevision{MessageID: msg.ID, Content: content}))
+	}
+	revisions, err := repo.FindRevisions(msg.ID)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, "v1", revisions[0].Content)
+
+	assert.NoError(t, repo.Delete(msg.ID))
+	revisions, err = repo.FindRevisions(msg.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, revisions)
+}
+
+func TestMessageRepository_HideForUser(t *testing.T) {
+	t.Parallel()
+
+	repo := NewMessageRepository()
+	for i := 0; i < 3; i++ {
+		msg := &domain.Message{ID: uuid.New(), SenderID: "user-1", ReceiverID: "user-2", Content: fmt.Sprint(i)}
+		assert.NoError(t, repo.Create(msg))
+		if i == 0 {
+			assert.NoError(t, repo.HideForUser(msg.ID, "user-2"))
+		}
+	}
+	assert.Error(t, repo.HideForUser(uuid.New(), "user-2"))
+
+	// Pagination counts only visible messages.
+	page, err := repo.GetMessagesByReceiver("user-2", 1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, page, 1)
+	assert.Equal(t, "2", page[0].Content)
+
+	sent, err := repo.GetMessagesBySender("user-1")
+	assert.NoError(t, err)
+	assert.Len(t, sent, 3)
+}
+
+func TestMessageRepository_DeleteRootRemovesReplies(t *testing.T) {
+	t.Parallel()
+
+	repo := NewMessageRepository()
+	root := &domain.Message{ID: uuid.New(), SenderID: "user-1", ReceiverID: "user-2"}
+	reply := &domain.Message{ID: uuid.New(), SenderID: "user-2", ReceiverID: "user-1", ThreadRootID: &root.ID}
+	assert.NoError(t, repo.Create(root))
+	assert.NoError(t, repo.Create(reply))
+
+	assert.NoError(t, repo.Delete(root.ID))
+	_, err := repo.FindByID(reply.ID)
+	assert.Error(t, err)
+	assert.Error(t, repo.Delete(root.ID))
+}
+
+func TestMessageRepository_Expiry(t *testing.T) {
+	t.Parallel()
+
+	repo := NewMessageRepository()
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	expired := &domain.Message{ID: uuid.New(), SenderID: "user-1", ReceiverID: "user-2", Content: "gone", ExpiresAt: &past}
+	reply := &domain.Message{ID: uuid.New(), SenderID: "user-2", ReceiverID: "user-1", ThreadRootID: &expired.ID, ExpiresAt: &future}
+	live := &domain.Message{ID: uuid.New(), SenderID: "user-1", ReceiverID: "user-2", Content: "still here", ExpiresAt: &future}
+	for _, msg := range []*domain.Message{expired, reply, live} {
+		assert.NoError(t, repo.Create(msg))
+	}
+
+	// Expired messages are unreadable before they are purged.
+	_, err := repo.FindByID(expired.ID)
+	assert.Error(t, err)
+	received, err := repo.GetMessagesByReceiver("user-2", 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Message{live}, received)
+
+	ids, err := repo.FindExpired(time.Now(), 10)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{expired.ID, reply.ID}, ids)
+	assert.NoError(t, repo.DeleteMany(ids))
+	_, err = repo.FindByID(reply.ID)
+	assert.Error(t, err)
+	_, err = repo.FindByID(live.ID)
+	assert.NoError(t, err)
+
+	ids, err = repo.FindExpired(time.Now(), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestMessageRepository_FindPastRetention(t *testing.T) {
+	t.Parallel()
+
+	repo := NewMessageRepository()
+	kept := uuid.New()
+	purged := uuid.New()
+	root := &domain.Message{ID: uuid.New(), SenderID: "user-1", ConversationID: purged}
+	reply := &domain.Message{ID: uuid.New(), SenderID: "user-2", ConversationID: purged, ThreadRootID: &root.ID}
+	direct := &domain.Message{ID: uuid.New(), SenderID: "user-1", ReceiverID: "user-2"}
+	excluded := &domain.Message{ID: uuid.New(), SenderID: "user-1", ConversationID: kept}
+	for _, msg := range []*domain.Message{root, direct, excluded} {
+		assert.NoError(t, repo.Create(msg))
+	}
+	cutoff := time.Now().Add(time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+	// The reply is newer than the cutoff but goes with its root.
+	assert.NoError(t, repo.Create(reply))
+
+	filter := ports.RetentionFilter{Before: cutoff, Exclude: []uuid.UUID{kept}}
+	page, err := repo.FindPastRetention(filter, 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Message{root, reply}, page)
+	page, err = repo.FindPastRetention(filter, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Message{direct}, page)
+
+	page, err = repo.FindPastRetention(ports.RetentionFilter{Before: cutoff, ConversationID: kept}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Message{excluded}, page)
+
+	assert.NoError(t, repo.DeleteMany([]uuid.UUID{root.ID, reply.ID}))
+	_, err = repo.FindByID(reply.ID)
+	assert.Error(t, err)
+	_, err = repo.FindByID(direct.ID)
+	assert.NoError(t, err)
+}
+
+func TestMessageRepository_ReturnsCopies(t *testing.T) {
+	t.Parallel()
+
+	repo := NewMessageRepository()
+	msg := &domain.Message{ID: uuid.New(), SenderID: "user-1", ReceiverID: "user-2", Content: "original"}
+	assert.NoError(t, repo.Create(msg))
+	msg.Content = "changed by the caller"
+
+	found, err := repo.FindByID(msg.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "original", found.Content)
+	found.Content = "changed without Update"
+
+	received, err := repo.GetMessagesByReceiver("user-2", 10, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, "original", received[0].Content)
+	}
+}
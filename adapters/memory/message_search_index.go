@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+// searchDoc is the indexed form of a message.
+type searchDoc struct {
+	id             uuid.UUID
+	conversationID uuid.UUID
+	senderID       string
+	receiverID     string
+	createdAt      time.Time
+	tokens         []string
+	hiddenFor      map[string]bool
+}
+
+// MessageSearchIndex is an in-memory inverted index implementing
+// ports.MessageSearchIndex. Postings keep word positions so phrases can
+// be matched.
+type MessageSearchIndex struct {
+	mu       sync.RWMutex
+	docs     map[uuid.UUID]*searchDoc
+	postings map[string]map[uuid.UUID][]int
+}
+
+func NewMessageSearchIndex() *MessageSearchIndex {
+	return &MessageSearchIndex{
+		docs:     make(map[uuid.UUID]*searchDoc),
+		postings: make(map[string]map[uuid.UUID][]int),
+	}
+}
+
+func (x *MessageSearchIndex) Index(msg *domain.Message) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	hidden := map[string]bool{}
+	if old, ok := x.docs[msg.ID]; ok {
+		hidden = old.hiddenFor
+		x.unindex(old)
+	}
+	doc := &searchDoc{
+		id:             msg.ID,
+		conversationID: msg.ConversationID,
+		senderID:       msg.SenderID,
+		receiverID:     msg.ReceiverID,
+		createdAt:      msg.CreatedAt,
+		tokens:         domain.Tokenize(msg.Content),
+		hiddenFor:      hidden,
+	}
+	x.docs[msg.ID] = doc
+	for pos, token := range doc.tokens {
+		if x.postings[token] == nil {
+			x.postings[token] = make(map[uuid.UUID][]int)
+		}
+		x.postings[token][doc.id] = append(x.postings[token][doc.id], pos)
+	}
+	return nil
+}
+
+func (x *MessageSearchIndex) Remove(messageID uuid.UUID) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if doc, ok := x.docs[messageID]; ok {
+		x.unindex(doc)
+		delete(x.docs, messageID)
+	}
+	return nil
+}
+
+func (x *MessageSearchIndex) Hide(messageID uuid.UUID, userID string) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if doc, ok := x.docs[messageID]; ok {
+		doc.hiddenFor[userID] = true
+	}
+	return nil
+}
+
+func (x *MessageSearchIndex) Search(q ports.SearchQuery) ([]uuid.UUID, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	if len(q.Terms) == 0 {
+		return nil, nil
+	}
+	// Start from the first term's matches and narrow with the rest.
+	candidates := x.match(q.Terms[0])
+	for _, term := range q.Terms[1:] {
+		next := x.match(term)
+		for id := range candidates {
+			if _, ok := next[id]; !ok {
+				delete(candidates, id)
+			}
+		}
+	}
+
+	var hits []*searchDoc
+	for id := range candidates {
+		if doc := x.docs[id]; x.visible(doc, q) {
+			hits = append(hits, doc)
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool { return hits[i].createdAt.After(hits[j].createdAt) })
+
+	hits = paginate(hits, q.Limit, q.Offset)
+	ids := make([]uuid.UUID, len(hits))
+	for i, doc := range hits {
+		ids[i] = doc.id
+	}
+	return ids, nil
+}
+
+// match returns the documents satisfying one term. Callers hold x.mu.
+func (x *MessageSearchIndex) match(term domain.SearchTerm) map[uuid.UUID]struct{} {
+	out := make(map[uuid.UUID]struct{})
+	if !term.Phrase() {
+		for _, postings := range x.postingsFor(term.Words[0], term.Prefix) {
+			for id := range postings {
+				out[id] = struct{}{}
+			}
+		}
+		return out
+	}
+	// For phrases, check word order on the documents holding the first
+	// word.
+	for _, postings := range x.postingsFor(term.Words[0], false) {
+		for id, positions := range postings {
+			tokens := x.docs[id].tokens
+			for _, pos := range positions {
+				if phraseAt(tokens, pos, term) {
+					out[id] = struct{}{}
+					break
+				}
+			}
+		}
+	}
+	return out
+}
+
+// postingsFor returns the postings of word, or of every indexed word
+// starting with it when prefix is set.
+func (x *MessageSearchIndex) postingsFor(word string, prefix bool) []map[uuid.UUID][]int {
+	if !prefix {
+		if postings, ok := x.postings[word]; ok {
+			return []map[uuid.UUID][]int{postings}
+		}
+		return nil
+	}
+	var out []map[uuid.UUID][]int
+	for token, postings := range x.postings {
+		if strings.HasPrefix(token, word) {
+			out = append(out, postings)
+		}
+	}
+	return out
+}
+
+// phraseAt reports whether term's words appear in tokens starting at pos.
+func phraseAt(tokens []string, pos int, term domain.SearchTerm) bool {
+	if pos+len(term.Words) > len(tokens) {
+		return false
+	}
+	last := len(term.Words) - 1
+	for i, word := range term.Words {
+		token := tokens[pos+i]
+		if i == last && term.Prefix {
+			if !strings.HasPrefix(token, word) {
+				return false
+			}
+		} else if token != word {
+			return false
+		}
+	}
+	return true
+}
+
+// visible applies the query's scope and filters to doc.
+func (x *MessageSearchIndex) visible(doc *searchDoc, q ports.SearchQuery) bool {
+	if doc.hiddenFor[q.ParticipantID] {
+		return false
+	}
+	if doc.conversationID != uuid.Nil {
+		if !slices.Contains(q.ConversationIDs, doc.conversationID) {
+			return false
+		}
+	} else if doc.senderID != q.ParticipantID && doc.receiverID != q.ParticipantID {
+		return false
+	}
+	if q.ConversationID != uuid.Nil && doc.conversationID != q.ConversationID {
+		return false
+	}
+	if q.SenderID != "" && doc.senderID != q.SenderID {
+		return false
+	}
+	if !q.Since.IsZero() && doc.createdAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !doc.createdAt.Before(q.Until) {
+		return false
+	}
+	return true
+}
+
+// unindex drops doc's postings. Callers hold x.mu.
+func (x *MessageSearchIndex) unindex(doc *searchDoc) {
+	for _, token := range doc.tokens {
+		delete(x.postings[token], doc.id)
+		if len(x.postings[token]) == 0 {
+			delete(x.postings, token)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestMessageSearchIndex_Search(t *testing.T) {
+	t.Parallel()
+
+	idx := NewMessageSearchIndex()
+	conv, other := uuid.New(), uuid.New()
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	add := func(content, sender string, convID uuid.UUID, receiver string, age int) uuid.UUID {
+		msg := &domain.Message{
+			ID: uuid.New(), SenderID: sender, ReceiverID: receiver, ConversationID: convID,
+			Content: content, CreatedAt: base.Add(time.Duration(age) * time.Hour),
+		}
+		assert.NoError(t, idx.Index(msg))
+		return msg.ID
+	}
+	notes := add("The release notes are ready", "alice", conv, "", 0)
+	deploy := add("Deploying the release tonight", "bob", conv, "", 1)
+	dm := add("release party?", "carol", uuid.Nil, "alice", 2)
+	secret := add("secret release plans", "mallory", other, "", 3)
+	add("release for someone else", "carol", uuid.Nil, "dave", 4)
+
+	search := func(text string, mod func(*ports.SearchQuery)) []uuid.UUID {
+		q := ports.SearchQuery{
+			Terms:           domain.ParseSearchQuery(text),
+			ParticipantID:   "alice",
+			ConversationIDs: []uuid.UUID{conv},
+			Limit:           10,
+		}
+		if mod != nil {
+			mod(&q)
+		}
+		ids, err := idx.Search(q)
+		assert.NoError(t, err)
+		return ids
+	}
+
+	assert.Equal(t, []uuid.UUID{dm, deploy, notes}, search("release", nil))
+	assert.Equal(t, []uuid.UUID{notes}, search(`"release notes"`, nil))
+	assert.Empty(t, search(`"notes release"`, nil))
+	assert.Equal(t, []uuid.UUID{deploy}, search("deploy*", nil))
+	assert.Equal(t, []uuid.UUID{notes}, search(`"release no*"`, nil))
+	assert.Equal(t, []uuid.UUID{deploy}, search("release tonight", nil))
+	assert.Empty(t, search("secret", nil))
+	assert.Empty(t, search("", nil))
+
+	assert.Equal(t, []uuid.UUID{deploy}, search("release", func(q *ports.SearchQuery) { q.SenderID = "bob" }))
+	assert.Equal(t, []uuid.UUID{deploy, notes}, search("release", func(q *ports.SearchQuery) { q.ConversationID = conv }))
+	assert.Equal(t, []uuid.UUID{deploy}, search("release", func(q *ports.SearchQuery) {
+		q.Since = base.Add(time.Hour)
+		q.Until = base.Add(2 * time.Hour)
+	}))
+	assert.Equal(t, []uuid.UUID{deploy}, search("release", func(q *ports.SearchQuery) { q.Limit, q.Offset = 1, 1 }))
+
+	// Edits replace the indexed words; hiding and removal drop results.
+	assert.NoError(t, idx.Index(&domain.Message{ID: deploy, SenderID: "bob", ConversationID: conv, Content: "ship it", CreatedAt: base}))
+	assert.Equal(t, []uuid.UUID{deploy}, search("ship", nil))
+	assert.Equal(t, []uuid.UUID{dm, notes}, search("release", nil))
+	assert.NoError(t, idx.Hide(dm, "alice"))
+	assert.Equal(t, []uuid.UUID{notes}, search("release", nil))
+	assert.NoError(t, idx.Remove(notes))
+	assert.Empty(t, search("release", nil))
+	assert.Equal(t, []uuid.UUID{secret}, search("secret", func(q *ports.SearchQuery) { q.ConversationIDs = []uuid.UUID{other} }))
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// NotificationPreferenceRepository is an in-memory implementation of
+// ports.NotificationPreferenceRepository.
+type NotificationPreferenceRepository struct {
+	mu       sync.RWMutex
+	settings map[string]domain.NotificationSettings
+	mutes    map[string]map[uuid.UUID]domain.ConversationMute
+}
+
+func NewNotificationPreferenceRepository() *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		settings: make(map[string]domain.NotificationSettings),
+		mutes:    make(map[string]map[uuid.UUID]domain.ConversationMute),
+	}
+}
+
+func (r *NotificationPreferenceRepository) FindSettings(userID string) (*domain.NotificationSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	settings, ok := r.settings[userID]
+	if !ok {
+		return &domain.NotificationSettings{UserID: userID}, nil
+	}
+	if settings.DoNotDisturb != nil {
+		dnd := *settings.DoNotDisturb
+		settings.DoNotDisturb = &dnd
+	}
+	return &settings, nil
+}
+
+func (r *NotificationPreferenceRepository) SaveSettings(settings *domain.NotificationSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *settings
+	if stored.DoNotDisturb != nil {
+		dnd := *stored.DoNotDisturb
+		stored.DoNotDisturb = &dnd
+	}
+	r.settings[settings.UserID] = stored
+	return nil
+}
+
+func (r *NotificationPreferenceRepository) Mute(mute *domain.ConversationMute) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mutes[mute.UserID] == nil {
+		r.mutes[mute.UserID] = make(map[uuid.UUID]domain.ConversationMute)
+	}
+	r.mutes[mute.UserID][mute.ConversationID] = *mute
+	return nil
+}
+
+func (r *NotificationPreferenceRepository) Unmute(userID string, conversationID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.mutes[userID][conversationID]; !ok {
+		return errors.New("mute not found")
+	}
+	delete(r.mutes[userID], conversationID)
+	return nil
+}
+
+func (r *NotificationPreferenceRepository) FindMute(userID string, conversationID uuid.UUID) (*domain.ConversationMute, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	mute, ok := r.mutes[userID][conversationID]
+	if !ok {
+		return nil, nil
+	}
+	return &mute, nil
+}
+
+func (r *NotificationPreferenceRepository) FindMutes(userID string) ([]*domain.ConversationMute, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*domain.ConversationMute
+	for _, mute := range r.mutes[userID] {
+		m := mute
+		result = append(result, &m)
+	}
+	slices.SortFunc(result, func(a, b *domain.ConversationMute) int {
+		return strings.Compare(a.ConversationID.String(), b.ConversationID.String())
+	})
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestNotificationPreferenceRepository_Settings(t *testing.T) {
+	t.Parallel()
+
+	repo := NewNotificationPreferenceRepository()
+	settings, err := repo.FindSettings("a")
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.NotificationSettings{UserID: "a"}, settings)
+
+	saved := &domain.NotificationSettings{UserID: "a", Level: domain.NotifyMentions, DoNotDisturb: &domain.DoNotDisturb{Start: "22:00", End: "07:00"}}
+	assert.NoError(t, repo.SaveSettings(saved))
+	saved.DoNotDisturb.Start = "23:00"
+
+	settings, err = repo.FindSettings("a")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NotifyMentions, settings.Level)
+	assert.Equal(t, "22:00", settings.DoNotDisturb.Start, "stored settings are copies")
+}
+
+func TestNotificationPreferenceRepository_Mutes(t *testing.T) {
+	t.Parallel()
+
+	repo := NewNotificationPreferenceRepository()
+	conv := uuid.New()
+	until := time.Now().Add(time.Hour)
+
+	mute, err := repo.FindMute("a", conv)
+	assert.NoError(t, err)
+	assert.Nil(t, mute)
+
+	assert.NoError(t, repo.Mute(&domain.ConversationMute{UserID: "a", ConversationID: conv}))
+	assert.NoError(t, repo.Mute(&domain.ConversationMute{UserID: "a", ConversationID: conv, Until: &until}))
+	assert.NoError(t, repo.Mute(&domain.ConversationMute{UserID: "a", ConversationID: uuid.New()}))
+
+	mute, err = repo.FindMute("a", conv)
+	assert.NoError(t, err)
+	assert.Equal(t, &until, mute.Until, "muting again replaces the mute")
+
+	mutes, err := repo.FindMutes("a")
+	assert.NoError(t, err)
+	assert.Len(t, mutes, 2)
+	mutes, err = repo.FindMutes("b")
+	assert.NoError(t, err)
+	assert.Empty(t, mutes)
+
+	assert.NoError(t, repo.Unmute("a", conv))
+	assert.Error(t, repo.Unmute("a", conv))
+	mute, _ = repo.FindMute("a", conv)
+	assert.Nil(t, mute)
+}
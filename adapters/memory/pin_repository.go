@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+// PinRepository is an in-memory implementation of ports.PinRepository.
+type PinRepository struct {
+	mu   sync.RWMutex
+	pins []*domain.Pin
+}
+
+func NewPinRepository() *PinRepository {
+	return &PinRepository{}
+}
+
+func (r *PinRepository) Add(pin *domain.Pin, limit int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index(pin.ConversationID, pin.MessageID) >= 0 {
+		return nil
+	}
+	count := 0
+	for _, p := range r.pins {
+		if p.ConversationID == pin.ConversationID {
+			count++
+		}
+	}
+	if count >= limit {
+		return ports.ErrPinLimit
+	}
+	stored := *pin
+	stored.Message = nil
+	r.pins = append(r.pins, &stored)
+	return nil
+}
+
+func (r *PinRepository) Remove(conversationID, messageID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(conversationID, messageID)
+	if i < 0 {
+		return errors.New("pin not found")
+	}
+	r.pins = append(r.pins[:i], r.pins[i+1:]...)
+	return nil
+}
+
+func (r *PinRepository) FindByConversation(conversationID uuid.UUID) ([]*domain.Pin, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*domain.Pin
+	for i := len(r.pins) - 1; i >= 0; i-- {
+		if r.pins[i].ConversationID == conversationID {
+			c := *r.pins[i]
+			result = append(result, &c)
+		}
+	}
+	return result, nil
+}
+
+func (r *PinRepository) DeleteByMessages(messageIDs []uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	doomed := make(map[uuid.UUID]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		doomed[id] = true
+	}
+	kept := r.pins[:0]
+	for _, pin := range r.pins {
+		if !doomed[pin.MessageID] {
+			kept = append(kept, pin)
+		}
+	}
+	r.pins = kept
+	return nil
+}
+
+func (r *PinRepository) index(conversationID, messageID uuid.UUID) int {
+	for i, pin := range r.pins {
+		if pin.ConversationID == conversationID && pin.MessageID == messageID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestPinRepository_AddRemove(t *testing.T) {
+	t.Parallel()
+
+	repo := NewPinRepository()
+	conv, other := uuid.New(), uuid.New()
+	m1, m2, m3 := uuid.New(), uuid.New(), uuid.New()
+
+	assert.NoError(t, repo.Add(&domain.Pin{ConversationID: conv, MessageID: m1, PinnedBy: "a"}, 10))
+	assert.NoError(t, repo.Add(&domain.Pin{ConversationID: conv, MessageID: m1, PinnedBy: "b"}, 10))
+	assert.NoError(t, repo.Add(&domain.Pin{ConversationID: conv, MessageID: m2, PinnedBy: "b"}, 10))
+	assert.NoError(t, repo.Add(&domain.Pin{ConversationID: other, MessageID: m3, PinnedBy: "a"}, 10))
+
+	pins, err := repo.FindByConversation(conv)
+	assert.NoError(t, err)
+	if assert.Len(t, pins, 2) {
+		assert.Equal(t, m2, pins[0].MessageID, "newest first")
+		assert.Equal(t, "a", pins[1].PinnedBy, "pinning again keeps the first pin")
+	}
+
+	assert.NoError(t, repo.Remove(conv, m1))
+	assert.Error(t, repo.Remove(conv, m1))
+	assert.Error(t, repo.Remove(conv, m3))
+
+	pins, err = repo.FindByConversation(conv)
+	assert.NoError(t, err)
+	assert.Len(t, pins, 1)
+}
+
+func TestPinRepository_Limit(t *testing.T) {
+	t.Parallel()
+
+	repo := NewPinRepository()
+	conv := uuid.New()
+	m1, m2 := uuid.New(), uuid.New()
+	assert.NoError(t, repo.Add(&domain.Pin{ConversationID: conv, MessageID: m1}, 1))
+	assert.NoError(t, repo.Add(&domain.Pin{ConversationID: conv, MessageID: m1}, 1), "pinning again is a no-op")
+	assert.ErrorIs(t, repo.Add(&domain.Pin{ConversationID: conv, MessageID: m2}, 1), ports.ErrPinLimit)
+	assert.NoError(t, repo.Add(&domain.Pin{ConversationID: uuid.New(), MessageID: m2}, 1))
+}
+
+func TestPinRepository_DeleteByMessages(t *testing.T) {
+	t.Parallel()
+
+	repo := NewPinRepository()
+	conv, other := uuid.New(), uuid.New()
+	m1, m2 := uuid.New(), uuid.New()
+	assert.NoError(t, repo.Add(&domain.Pin{ConversationID: conv, MessageID: m1}, 10))
+	assert.NoError(t, repo.Add(&domain.Pin{ConversationID: conv, MessageID: m2}, 10))
+	assert.NoError(t, repo.Add(&domain.Pin{ConversationID: other, MessageID: m1}, 10))
+
+	assert.NoError(t, repo.DeleteByMessages([]uuid.UUID{m1}))
+
+	pins, err := repo.FindByConversation(conv)
+	assert.NoError(t, err)
+	if assert.Len(t, pins, 1) {
+		assert.Equal(t, m2, pins[0].MessageID)
+	}
+	pins, err = repo.FindByConversation(other)
+	assert.NoError(t, err)
+	assert.Empty(t, pins)
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// PresenceStore is an in-memory implementation of ports.PresenceStore.
+// It starts empty, so everyone is offline until heard from after a
+// restart.
+type PresenceStore struct {
+	mu       sync.RWMutex
+	activity map[string]domain.Activity
+}
+
+func NewPresenceStore() *PresenceStore {
+	return &PresenceStore{activity: make(map[string]domain.Activity)}
+}
+
+func (s *PresenceStore) Seen(userID string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.activity[userID]
+	if at.After(a.LastSeenAt) {
+		a.LastSeenAt = at
+	}
+	s.activity[userID] = a
+	return nil
+}
+
+func (s *PresenceStore) Heartbeat(userID string, away bool, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.activity[userID]
+	if at.After(a.LastSeenAt) {
+		a.LastSeenAt = at
+	}
+	a.Away = away
+	s.activity[userID] = a
+	return nil
+}
+
+func (s *PresenceStore) Find(userIDs []string) (map[string]domain.Activity, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make(map[string]domain.Activity, len(userIDs))
+	for _, id := range userIDs {
+		if a, ok := s.activity[id]; ok {
+			result[id] = a
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestPresenceStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewPresenceStore()
+	now := time.Now()
+
+	assert.NoError(t, store.Heartbeat("alice", true, now))
+	assert.NoError(t, store.Seen("alice", now.Add(time.Second)))
+	assert.NoError(t, store.Seen("alice", now), "late reports don't move last seen back")
+	assert.NoError(t, store.Seen("bob", now))
+
+	activity, err := store.Find([]string{"alice", "bob", "carol"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]domain.Activity{
+		"alice": {LastSeenAt: now.Add(time.Second), Away: true},
+		"bob":   {LastSeenAt: now},
+	}, activity)
+
+	assert.NoError(t, store.Heartbeat("alice", false, now.Add(2*time.Second)))
+	activity, err = store.Find([]string{"alice"})
+	assert.NoError(t, err)
+	assert.False(t, activity["alice"].Away)
+}
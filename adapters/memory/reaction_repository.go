@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// ReactionRepository is an in-memory implementation of ports.ReactionRepository.
+type ReactionRepository struct {
+	mu        sync.RWMutex
+	reactions []*domain.Reaction
+}
+
+func NewReactionRepository() *ReactionRepository {
+	return &ReactionRepository{}
+}
+
+func (r *ReactionRepository) Add(reaction *domain.Reaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.index(reaction.MessageID, reaction.UserID, reaction.Emoji) >= 0 {
+		return nil
+	}
+	stored := *reaction
+	r.reactions = append(r.reactions, &stored)
+	return nil
+}
+
+func (r *ReactionRepository) Remove(messageID uuid.UUID, userID, emoji string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(messageID, userID, emoji)
+	if i < 0 {
+		return errors.New("reaction not found")
+	}
+	r.reactions = append(r.reactions[:i], r.reactions[i+1:]...)
+	return nil
+}
+
+func (r *ReactionRepository) FindByMessages(messageIDs []uuid.UUID) ([]*domain.Reaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		wanted[id] = true
+	}
+	var result []*domain.Reaction
+	for _, reaction := range r.reactions {
+		if wanted[reaction.MessageID] {
+			c := *reaction
+			result = append(result, &c)
+		}
+	}
+	return result, nil
+}
+
+func (r *ReactionRepository) DeleteByMessages(messageIDs []uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	doomed := make(map[uuid.UUID]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		doomed[id] = true
+	}
+	kept := r.reactions[:0]
+	for _, reaction := range r.reactions {
+		if !doomed[reaction.MessageID] {
+			kept = append(kept, reaction)
+		}
+	}
+	r.reactions = kept
+	return nil
+}
+
+func (r *ReactionRepository) index(messageID uuid.UUID, userID, emoji string) int {
+	for i, reaction := range r.reactions {
+		if reaction.MessageID == messageID && reaction.UserID == userID && reaction.Emoji == emoji {
+			return i
+		}
+	}
+	return -1
+}
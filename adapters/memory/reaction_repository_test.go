@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestReactionRepository_AddRemove(t *testing.T) {
+	t.Parallel()
+
+	repo := NewReactionRepository()
+	m1, m2 := uuid.New(), uuid.New()
+
+	assert.NoError(t, repo.Add(&domain.Reaction{MessageID: m1, UserID: "a", Emoji: "👍"}))
+	assert.NoError(t, repo.Add(&domain.Reaction{MessageID: m1, UserID: "a", Emoji: "👍"}))
+	assert.NoError(t, repo.Add(&domain.Reaction{MessageID: m1, UserID: "b", Emoji: "👍"}))
+	assert.NoError(t, repo.Add(&domain.Reaction{MessageID: m2, UserID: "a", Emoji: ":tada:"}))
+
+	found, err := repo.FindByMessages([]uuid.UUID{m1})
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	assert.NoError(t, repo.Remove(m1, "a", "👍"))
+	assert.Error(t, repo.Remove(m1, "a", "👍"))
+
+	found, err = repo.FindByMessages([]uuid.UUID{m1, m2})
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+}
@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+// ScheduledMessageRepository is an in-memory implementation of
+// ports.ScheduledMessageRepository.
+type ScheduledMessageRepository struct {
+	mu       sync.RWMutex
+	messages map[uuid.UUID]*domain.ScheduledMessage
+}
+
+func NewScheduledMessageRepository() *ScheduledMessageRepository {
+	return &ScheduledMessageRepository{messages: make(map[uuid.UUID]*domain.ScheduledMessage)}
+}
+
+func (r *ScheduledMessageRepository) Create(message *domain.ScheduledMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *message
+	r.messages[message.ID] = &stored
+	return nil
+}
+
+func (r *ScheduledMessageRepository) FindByID(id uuid.UUID) (*domain.ScheduledMessage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sm, ok := r.messages[id]
+	if !ok {
+		return nil, errors.New("scheduled message not found")
+	}
+	c := *sm
+	return &c, nil
+}
+
+func (r *ScheduledMessageRepository) ListPending(senderID string, limit, offset int) ([]*domain.ScheduledMessage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*domain.ScheduledMessage
+	for _, sm := range r.messages {
+		if sm.SenderID == senderID && sm.Status == domain.SchedulePending {
+			c := *sm
+			result = append(result, &c)
+		}
+	}
+	sortBySendAt(result)
+	return paginate(result, limit, offset), nil
+}
+
+func (r *ScheduledMessageRepository) Reschedule(id uuid.UUID, sendAt, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sm, err := r.unclaimed(id, now)
+	if err != nil {
+		return err
+	}
+	sm.SendAt = sendAt
+	return nil
+}
+
+func (r *ScheduledMessageRepository) Cancel(id uuid.UUID, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sm, err := r.unclaimed(id, now)
+	if err != nil {
+		return err
+	}
+	sm.Status = domain.ScheduleCanceled
+	return nil
+}
+
+func (r *ScheduledMessageRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]*domain.ScheduledMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*domain.ScheduledMessage
+	for _, sm := range r.messages {
+		if sm.Status == domain.SchedulePending && !sm.SendAt.After(now) && !claimed(sm, now) {
+			due = append(due, sm)
+		}
+	}
+	sortBySendAt(due)
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	until := now.Add(lease)
+	result := make([]*domain.ScheduledMessage, len(due))
+	for i, sm := range due {
+		sm.ClaimedUntil = &until
+		c := *sm
+		result[i] = &c
+	}
+	return result, nil
+}
+
+func (r *ScheduledMessageRepository) PendingAttachmentIDs() ([]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var ids []uuid.UUID
+	for _, sm := range r.messages {
+		if sm.Status == domain.SchedulePending {
+			ids = append(ids, sm.AttachmentIDs...)
+		}
+	}
+	return ids, nil
+}
+
+func (r *ScheduledMessageRepository) MarkSent(id uuid.UUID) error {
+	return r.resolve(id, domain.ScheduleSent, "")
+}
+
+func (r *ScheduledMessageRepository) MarkFailed(id uuid.UUID, reason string) error {
+	return r.resolve(id, domain.ScheduleFailed, reason)
+}
+
+func (r *ScheduledMessageRepository) resolve(id uuid.UUID, status domain.ScheduleStatus, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sm, ok := r.messages[id]
+	if !ok {
+		return errors.New("scheduled message not found")
+	}
+	sm.Status = status
+	sm.Failure = reason
+	sm.ClaimedUntil = nil
+	return nil
+}
+
+// unclaimed returns the stored message if it can still be changed.
+// Callers hold r.mu.
+func (r *ScheduledMessageRepository) unclaimed(id uuid.UUID, now time.Time) (*domain.ScheduledMessage, error) {
+	sm, ok := r.messages[id]
+	if !ok {
+		return nil, errors.New("scheduled message not found")
+	}
+	if sm.Status != domain.SchedulePending || claimed(sm, now) {
+		return nil, ports.ErrNotPending
+	}
+	return sm, nil
+}
+
+func claimed(sm *domain.ScheduledMessage, now time.Time) bool {
+	return sm.ClaimedUntil != nil && sm.ClaimedUntil.After(now)
+}
+
+func sortBySendAt(msgs []*domain.ScheduledMessage) {
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].SendAt.Before(msgs[j].SendAt) })
+}
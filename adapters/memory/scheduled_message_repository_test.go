@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestScheduledMessageRepository_ClaimDue(t *testing.T) {
+	t.Parallel()
+
+	repo := NewScheduledMessageRepository()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	schedule := func(sendAt time.Time) uuid.UUID {
+		sm := &domain.ScheduledMessage{ID: uuid.New(), SenderID: "alice", SendAt: sendAt, Status: domain.SchedulePending}
+		assert.NoError(t, repo.Create(sm))
+		return sm.ID
+	}
+	first := schedule(now.Add(-2 * time.Minute))
+	second := schedule(now.Add(-time.Minute))
+	later := schedule(now.Add(time.Hour))
+
+	claimed, err := repo.ClaimDue(now, time.Minute, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, claimed, 1) {
+		assert.Equal(t, first, claimed[0].ID)
+	}
+	// A claimed message is neither handed out again nor changeable.
+	claimed, err = repo.ClaimDue(now, time.Minute, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, claimed, 1) {
+		assert.Equal(t, second, claimed[0].ID)
+	}
+	assert.ErrorIs(t, repo.Cancel(first, now), ports.ErrNotPending)
+
+	// Once the lease lapses an unresolved claim is retried.
+	assert.NoError(t, repo.MarkSent(second))
+	claimed, err = repo.ClaimDue(now.Add(2*time.Minute), time.Minute, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, claimed, 1) {
+		assert.Equal(t, first, claimed[0].ID)
+	}
+
+	pending, err := repo.ListPending("alice", 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+
+	assert.NoError(t, repo.Reschedule(later, now.Add(2*time.Hour), now))
+	assert.NoError(t, repo.Cancel(later, now))
+	assert.ErrorIs(t, repo.Reschedule(later, now, now), ports.ErrNotPending)
+	got, err := repo.FindByID(later)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ScheduleCanceled, got.Status)
+	assert.Equal(t, now.Add(2*time.Hour), got.SendAt)
+}
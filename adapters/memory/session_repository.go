@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// SessionRepository is an in‑memory implementation of ports.SessionRepository.
+// Sessions are touched on every authenticated request, so it hands out
+// copies rather than the stored pointers.
+type SessionRepository struct {
+	mu       sync.RWMutex
+	sessions []*domain.Session
+}
+
+// NewSessionRepository constructs an in‑memory repo.
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{
+		sessions: make([]*domain.Session, 0),
+	}
+}
+
+// Create appends a new session.
+func (r *SessionRepository) Create(session *domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := *session
+	r.sessions = append(r.sessions, &s)
+	return nil
+}
+
+// FindByID looks up a session by ID.
+func (r *SessionRepository) FindByID(id uuid.UUID) (*domain.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, s := range r.sessions {
+		if s.ID == id {
+			c := *s
+			return &c, nil
+		}
+	}
+	return nil, errors.New("session not found")
+}
+
+// FindByUser filters sessions by userID.
+func (r *SessionRepository) FindByUser(userID string) ([]*domain.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*domain.Session
+	for _, s := range r.sessions {
+		if s.UserID == userID {
+			c := *s
+			result = append(result, &c)
+		}
+	}
+	return result, nil
+}
+
+// TouchSession moves LastSeenAt of an active session forward.
+func (r *SessionRepository) TouchSession(id uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.ID == id {
+			if s.RevokedAt == nil {
+				s.LastSeenAt = at
+			}
+			return nil
+		}
+	}
+	return errors.New("session not found")
+}
+
+// Update replaces the stored session with the same ID.
+func (r *SessionRepository) Update(session *domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, s := range r.sessions {
+		if s.ID == session.ID {
+			c := *session
+			r.sessions[i] = &c
+			return nil
+		}
+	}
+	return errors.New("session not found")
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestSessionRepository_CreateFindUpdate(t *testing.T) {
+	t.Parallel()
+
+	repo := NewSessionRepository()
+	now := time.Now()
+
+	s1 := &domain.Session{ID: uuid.New(), UserID: "alice", DeviceName: "laptop", CreatedAt: now, LastSeenAt: now}
+	s2 := &domain.Session{ID: uuid.New(), UserID: "alice", DeviceName: "phone", CreatedAt: now, LastSeenAt: now}
+	s3 := &domain.Session{ID: uuid.New(), UserID: "bob", CreatedAt: now, LastSeenAt: now}
+	assert.NoError(t, repo.Create(s1))
+	assert.NoError(t, repo.Create(s2))
+	assert.NoError(t, repo.Create(s3))
+
+	found, err := repo.FindByID(s2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "phone", found.DeviceName)
+
+	_, err = repo.FindByID(uuid.New())
+	assert.Error(t, err)
+
+	alice, err := repo.FindByUser("alice")
+	assert.NoError(t, err)
+	assert.Len(t, alice, 2)
+	assert.Equal(t, s1.ID, alice[0].ID)
+
+	// mutating a returned session doesn't touch the store until Update
+	found.DeviceName = "tablet"
+	again, _ := repo.FindByID(s2.ID)
+	assert.Equal(t, "phone", again.DeviceName)
+
+	revoked := now.Add(time.Minute)
+	found.RevokedAt = &revoked
+	assert.NoError(t, repo.Update(found))
+	again, _ = repo.FindByID(s2.ID)
+	assert.Equal(t, "tablet", again.DeviceName)
+	assert.False(t, again.Active())
+
+	assert.Error(t, repo.Update(&domain.Session{ID: uuid.New()}))
+}
+
+func TestSessionRepository_TouchSession(t *testing.T) {
+	t.Parallel()
+
+	repo := NewSessionRepository()
+	now := time.Now()
+	active := &domain.Session{ID: uuid.New(), UserID: "alice", CreatedAt: now, LastSeenAt: now}
+	revoked := &domain.Session{ID: uuid.New(), UserID: "alice", CreatedAt: now, LastSeenAt: now, RevokedAt: &now}
+	assert.NoError(t, repo.Create(active))
+	assert.NoError(t, repo.Create(revoked))
+
+	later := now.Add(time.Hour)
+	assert.NoError(t, repo.TouchSession(active.ID, later))
+	assert.NoError(t, repo.TouchSession(revoked.ID, later))
+	assert.Error(t, repo.TouchSession(uuid.New(), later))
+
+	found, _ := repo.FindByID(active.ID)
+	assert.Equal(t, later, found.LastSeenAt)
+	found, _ = repo.FindByID(revoked.ID)
+	assert.Equal(t, now, found.LastSeenAt)
+	assert.False(t, found.Active(), "a touch never brings a revoked session back")
+}
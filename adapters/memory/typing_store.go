@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// TypingStore is an in-memory implementation of ports.TypingStore.
+// Lapsed indicators are dropped whenever their conversation is touched
+// or read.
+type TypingStore struct {
+	mu     sync.Mutex
+	typing map[uuid.UUID]map[string]time.Time
+}
+
+func NewTypingStore() *TypingStore {
+	return &TypingStore{typing: make(map[uuid.UUID]map[string]time.Time)}
+}
+
+func (s *TypingStore) Touch(conversationID uuid.UUID, userID string, now, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(conversationID, now)
+	if s.typing[conversationID] == nil {
+		s.typing[conversationID] = make(map[string]time.Time)
+	}
+	_, already := s.typing[conversationID][userID]
+	s.typing[conversationID][userID] = expiresAt
+	return !already, nil
+}
+
+func (s *TypingStore) Clear(conversationID uuid.UUID, userID string, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(conversationID, now)
+	_, typing := s.typing[conversationID][userID]
+	delete(s.typing[conversationID], userID)
+	s.prune(conversationID, now)
+	return typing, nil
+}
+
+func (s *TypingStore) Typers(conversationID uuid.UUID, now time.Time) ([]domain.Typer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(conversationID, now)
+
+	var result []domain.Typer
+	for userID, expiresAt := range s.typing[conversationID] {
+		result = append(result, domain.Typer{UserID: userID, ExpiresAt: expiresAt})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserID < result[j].UserID })
+	return result, nil
+}
+
+// prune drops a conversation's lapsed indicators, and the conversation
+// once nobody is left. Callers hold s.mu.
+func (s *TypingStore) prune(conversationID uuid.UUID, now time.Time) {
+	typers := s.typing[conversationID]
+	for userID, expiresAt := range typers {
+		if !expiresAt.After(now) {
+			delete(typers, userID)
+		}
+	}
+	if len(typers) == 0 {
+		delete(s.typing, conversationID)
+	}
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestTypingStore_Expiry(t *testing.T) {
+	t.Parallel()
+
+	store := NewTypingStore()
+	conv := uuid.New()
+	now := time.Now()
+
+	started, err := store.Touch(conv, "bob", now, now.Add(5*time.Second))
+	assert.NoError(t, err)
+	assert.True(t, started)
+	started, err = store.Touch(conv, "bob", now.Add(time.Second), now.Add(6*time.Second))
+	assert.NoError(t, err)
+	assert.False(t, started, "a refresh isn't a new start")
+	_, err = store.Touch(conv, "alice", now, now.Add(2*time.Second))
+	assert.NoError(t, err)
+
+	typers, err := store.Typers(conv, now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Typer{
+		{UserID: "alice", ExpiresAt: now.Add(2 * time.Second)},
+		{UserID: "bob", ExpiresAt: now.Add(6 * time.Second)},
+	}, typers)
+
+	typers, err = store.Typers(conv, now.Add(3*time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, typers, 1, "alice's indicator lapsed")
+
+	stopped, err := store.Clear(conv, "bob", now.Add(3*time.Second))
+	assert.NoError(t, err)
+	assert.True(t, stopped)
+	stopped, err = store.Clear(conv, "alice", now.Add(3*time.Second))
+	assert.NoError(t, err)
+	assert.False(t, stopped)
+
+	typers, err = store.Typers(conv, now.Add(3*time.Second))
+	assert.NoError(t, err)
+	assert.Empty(t, typers)
+	assert.Empty(t, store.typing, "empty conversations are dropped")
+}
@@ -2,8 +2,14 @@ package memory
 
 import (
 	"errors"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
 	"github.com/chrikar/chatheon/domain"
 )
 
@@ -26,7 +32,7 @@ func (r *UserRepository) Create(user *domain.User) error {
 		return errors.New("user already exists")
 	}
 
-	r.users[user.Username] = user
+	r.users[user.Username] = cloneUser(user)
 	return nil
 }
 
@@ -38,5 +44,91 @@ func (r *UserRepository) FindByUsername(username string) (*domain.User, error) {
 	if !exists {
 		return nil, errors.New("user not found")
 	}
-	return user, nil
+	return cloneUser(user), nil
+}
+
+func (r *UserRepository) FindByID(id uuid.UUID) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if user := r.find(id); user != nil {
+		return cloneUser(user), nil
+	}
+	return nil, errors.New("user not found")
+}
+
+func (r *UserRepository) Update(user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.users[user.Username]; !exists {
+		return errors.New("user not found")
+	}
+
+	r.users[user.Username] = cloneUser(user)
+	return nil
+}
+
+func (r *UserRepository) UseTOTPCounter(id uuid.UUID, counter int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := r.find(id)
+	if user == nil {
+		return errors.New("user not found")
+	}
+	if counter <= user.TOTPLastCounter {
+		return ports.ErrCodeUsed
+	}
+	user.TOTPLastCounter = counter
+	return nil
+}
+
+func (r *UserRepository) UseRecoveryCode(id uuid.UUID, hash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := r.find(id)
+	if user == nil {
+		return errors.New("user not found")
+	}
+	i := slices.Index(user.RecoveryCodeHashes, hash)
+	if i < 0 {
+		return ports.ErrCodeUsed
+	}
+	user.RecoveryCodeHashes = slices.Delete(slices.Clone(user.RecoveryCodeHashes), i, i+1)
+	return nil
+}
+
+func (r *UserRepository) Search(query string, exclude []string, limit, offset int) ([]*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query = strings.ToLower(query)
+	var result []*domain.User
+	for _, user := range r.users {
+		if strings.Contains(strings.ToLower(user.Username), query) && !slices.Contains(exclude, user.ID.String()) {
+			result = append(result, cloneUser(user))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Username < result[j].Username })
+	return paginate(result, limit, offset), nil
+}
+
+// find returns the stored user with the given ID. Callers hold r.mu.
+func (r *UserRepository) find(id uuid.UUID) *domain.User {
+	for _, user := range r.users {
+		if user.ID == id {
+			return user
+		}
+	}
+	return nil
+}
+
+// cloneUser copies a user, so callers can't change the stored one behind
+// the lock.
+func cloneUser(user *domain.User) *domain.User {
+	c := *user
+	c.RecoveryCodeHashes = slices.Clone(user.RecoveryCodeHashes)
+	return &c
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/chrikar/chatheon/application/ports"
 	"github.com/chrikar/chatheon/domain"
 )
 
@@ -37,3 +38,99 @@ func TestUserRepository_CreateAndFind(t *testing.T) {
 	_, err = repo.FindByUsername("unknown")
 	assert.Error(t, err)
 }
+
+func TestUserRepository_FindByIDAndUpdate(t *testing.T) {
+	t.Parallel()
+
+	repo := NewUserRepository()
+
+	user := &domain.User{ID: uuid.New(), Username: "testuser", PasswordHash: "hash"}
+	assert.NoError(t, repo.Create(user))
+
+	found, err := repo.FindByID(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, user, found)
+
+	_, err = repo.FindByID(uuid.New())
+	assert.Error(t, err)
+
+	updated := &domain.User{ID: user.ID, Username: "testuser", PasswordHash: "hash", TOTPEnabled: true}
+	assert.NoError(t, repo.Update(updated))
+	found, err = repo.FindByUsername("testuser")
+	assert.NoError(t, err)
+	assert.True(t, found.TOTPEnabled)
+
+	err = repo.Update(&domain.User{ID: uuid.New(), Username: "ghost"})
+	assert.Error(t, err)
+}
+
+func TestUserRepository_Search(t *testing.T) {
+	t.Parallel()
+
+	repo := NewUserRepository()
+	for _, name := range []string{"carol", "Alice", "alina", "bob"} {
+		assert.NoError(t, repo.Create(&domain.User{ID: uuid.New(), Username: name}))
+	}
+
+	users, err := repo.Search("AL", nil, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "Alice", users[0].Username)
+	assert.Equal(t, "alina", users[1].Username)
+
+	users, err = repo.Search("", nil, 2, 1)
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "alina", users[0].Username)
+
+	users, err = repo.Search("", nil, 10, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+
+	alice, _ := repo.FindByUsername("Alice")
+	users, err = repo.Search("AL", []string{alice.ID.String()}, 10, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, "alina", users[0].Username)
+	}
+}
+
+func TestUserRepository_ReturnsCopies(t *testing.T) {
+	t.Parallel()
+
+	repo := NewUserRepository()
+	user := &domain.User{ID: uuid.New(), Username: "alice", RecoveryCodeHashes: []string{"a", "b"}}
+	assert.NoError(t, repo.Create(user))
+	user.Disabled = true
+
+	found, err := repo.FindByID(user.ID)
+	assert.NoError(t, err)
+	assert.False(t, found.Disabled)
+	found.Role = domain.RoleAdmin
+	found.RecoveryCodeHashes[0] = "changed"
+
+	found, err = repo.FindByUsername("alice")
+	assert.NoError(t, err)
+	assert.Empty(t, found.Role)
+	assert.Equal(t, []string{"a", "b"}, found.RecoveryCodeHashes)
+}
+
+func TestUserRepository_UseSecondFactor(t *testing.T) {
+	t.Parallel()
+
+	repo := NewUserRepository()
+	user := &domain.User{ID: uuid.New(), Username: "alice", TOTPLastCounter: 10, RecoveryCodeHashes: []string{"a", "b"}}
+	assert.NoError(t, repo.Create(user))
+
+	assert.ErrorIs(t, repo.UseTOTPCounter(user.ID, 10), ports.ErrCodeUsed)
+	assert.NoError(t, repo.UseTOTPCounter(user.ID, 11))
+	assert.ErrorIs(t, repo.UseTOTPCounter(user.ID, 11), ports.ErrCodeUsed)
+
+	assert.NoError(t, repo.UseRecoveryCode(user.ID, "a"))
+	assert.ErrorIs(t, repo.UseRecoveryCode(user.ID, "a"), ports.ErrCodeUsed)
+
+	found, err := repo.FindByID(user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), found.TOTPLastCounter)
+	assert.Equal(t, []string{"b"}, found.RecoveryCodeHashes)
+}
@@ -5,9 +5,11 @@
 package mocks
 
 import (
+	"github.com/chrikar/chatheon/application/ports"
 	"github.com/chrikar/chatheon/domain"
 	"github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
+	"time"
 )
 
 // NewMockMessageRepository creates a new instance of MockMessageRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -37,47 +39,673 @@ func (_m *MockMessageRepository) EXPECT() *MockMessageRepository_Expecter {
 	return &MockMessageRepository_Expecter{mock: &_m.Mock}
 }
 
+// AddRevision provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) AddRevision(revision *domain.MessageRevision) error {
+	ret := _mock.Called(revision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddRevision")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*domain.MessageRevision) error); ok {
+		r0 = returnFunc(revision)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageRepository_AddRevision_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddRevision'
+type MockMessageRepository_AddRevision_Call struct {
+	*mock.Call
+}
+
+// AddRevision is a helper method to define mock.On call
+//   - revision
+func (_e *MockMessageRepository_Expecter) AddRevision(revision interface{}) *MockMessageRepository_AddRevision_Call {
+	return &MockMessageRepository_AddRevision_Call{Call: _e.mock.On("AddRevision", revision)}
+}
+
+func (_c *MockMessageRepository_AddRevision_Call) Run(run func(revision *domain.MessageRevision)) *MockMessageRepository_AddRevision_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*domain.MessageRevision))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_AddRevision_Call) Return(err error) *MockMessageRepository_AddRevision_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageRepository_AddRevision_Call) RunAndReturn(run func(revision *domain.MessageRevision) error) *MockMessageRepository_AddRevision_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function for the type MockMessageRepository
 func (_mock *MockMessageRepository) Create(message *domain.Message) error {
 	ret := _mock.Called(message)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Create")
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*domain.Message) error); ok {
+		r0 = returnFunc(message)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockMessageRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - message
+func (_e *MockMessageRepository_Expecter) Create(message interface{}) *MockMessageRepository_Create_Call {
+	return &MockMessageRepository_Create_Call{Call: _e.mock.On("Create", message)}
+}
+
+func (_c *MockMessageRepository_Create_Call) Run(run func(message *domain.Message)) *MockMessageRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*domain.Message))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_Create_Call) Return(err error) *MockMessageRepository_Create_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageRepository_Create_Call) RunAndReturn(run func(message *domain.Message) error) *MockMessageRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) Delete(messageID uuid.UUID) error {
+	ret := _mock.Called(messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID) error); ok {
+		r0 = returnFunc(messageID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockMessageRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - messageID
+func (_e *MockMessageRepository_Expecter) Delete(messageID interface{}) *MockMessageRepository_Delete_Call {
+	return &MockMessageRepository_Delete_Call{Call: _e.mock.On("Delete", messageID)}
+}
+
+func (_c *MockMessageRepository_Delete_Call) Run(run func(messageID uuid.UUID)) *MockMessageRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_Delete_Call) Return(err error) *MockMessageRepository_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageRepository_Delete_Call) RunAndReturn(run func(messageID uuid.UUID) error) *MockMessageRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteByConversation provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) DeleteByConversation(conversationID uuid.UUID) error {
+	ret := _mock.Called(conversationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByConversation")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID) error); ok {
+		r0 = returnFunc(conversationID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageRepository_DeleteByConversation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteByConversation'
+type MockMessageRepository_DeleteByConversation_Call struct {
+	*mock.Call
+}
+
+// DeleteByConversation is a helper method to define mock.On call
+//   - conversationID
+func (_e *MockMessageRepository_Expecter) DeleteByConversation(conversationID interface{}) *MockMessageRepository_DeleteByConversation_Call {
+	return &MockMessageRepository_DeleteByConversation_Call{Call: _e.mock.On("DeleteByConversation", conversationID)}
+}
+
+func (_c *MockMessageRepository_DeleteByConversation_Call) Run(run func(conversationID uuid.UUID)) *MockMessageRepository_DeleteByConversation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_DeleteByConversation_Call) Return(err error) *MockMessageRepository_DeleteByConversation_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageRepository_DeleteByConversation_Call) RunAndReturn(run func(conversationID uuid.UUID) error) *MockMessageRepository_DeleteByConversation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteForEveryone provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) DeleteForEveryone(messageID uuid.UUID, deletedAt time.Time) error {
+	ret := _mock.Called(messageID, deletedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteForEveryone")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID, time.Time) error); ok {
+		r0 = returnFunc(messageID, deletedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageRepository_DeleteForEveryone_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteForEveryone'
+type MockMessageRepository_DeleteForEveryone_Call struct {
+	*mock.Call
+}
+
+// DeleteForEveryone is a helper method to define mock.On call
+//   - messageID
+//   - deletedAt
+func (_e *MockMessageRepository_Expecter) DeleteForEveryone(messageID interface{}, deletedAt interface{}) *MockMessageRepository_DeleteForEveryone_Call {
+	return &MockMessageRepository_DeleteForEveryone_Call{Call: _e.mock.On("DeleteForEveryone", messageID, deletedAt)}
+}
+
+func (_c *MockMessageRepository_DeleteForEveryone_Call) Run(run func(messageID uuid.UUID, deletedAt time.Time)) *MockMessageRepository_DeleteForEveryone_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_DeleteForEveryone_Call) Return(err error) *MockMessageRepository_DeleteForEveryone_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageRepository_DeleteForEveryone_Call) RunAndReturn(run func(messageID uuid.UUID, deletedAt time.Time) error) *MockMessageRepository_DeleteForEveryone_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteMany provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) DeleteMany(messageIDs []uuid.UUID) error {
+	ret := _mock.Called(messageIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMany")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func([]uuid.UUID) error); ok {
+		r0 = returnFunc(messageIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageRepository_DeleteMany_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMany'
+type MockMessageRepository_DeleteMany_Call struct {
+	*mock.Call
+}
+
+// DeleteMany is a helper method to define mock.On call
+//   - messageIDs
+func (_e *MockMessageRepository_Expecter) DeleteMany(messageIDs interface{}) *MockMessageRepository_DeleteMany_Call {
+	return &MockMessageRepository_DeleteMany_Call{Call: _e.mock.On("DeleteMany", messageIDs)}
+}
+
+func (_c *MockMessageRepository_DeleteMany_Call) Run(run func(messageIDs []uuid.UUID)) *MockMessageRepository_DeleteMany_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_DeleteMany_Call) Return(err error) *MockMessageRepository_DeleteMany_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageRepository_DeleteMany_Call) RunAndReturn(run func(messageIDs []uuid.UUID) error) *MockMessageRepository_DeleteMany_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByID provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) FindByID(messageID uuid.UUID) (*domain.Message, error) {
+	ret := _mock.Called(messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 *domain.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID) (*domain.Message, error)); ok {
+		return returnFunc(messageID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID) *domain.Message); ok {
+		r0 = returnFunc(messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(uuid.UUID) error); ok {
+		r1 = returnFunc(messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageRepository_FindByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByID'
+type MockMessageRepository_FindByID_Call struct {
+	*mock.Call
+}
+
+// FindByID is a helper method to define mock.On call
+//   - messageID
+func (_e *MockMessageRepository_Expecter) FindByID(messageID interface{}) *MockMessageRepository_FindByID_Call {
+	return &MockMessageRepository_FindByID_Call{Call: _e.mock.On("FindByID", messageID)}
+}
+
+func (_c *MockMessageRepository_FindByID_Call) Run(run func(messageID uuid.UUID)) *MockMessageRepository_FindByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_FindByID_Call) Return(message *domain.Message, err error) *MockMessageRepository_FindByID_Call {
+	_c.Call.Return(message, err)
+	return _c
+}
+
+func (_c *MockMessageRepository_FindByID_Call) RunAndReturn(run func(messageID uuid.UUID) (*domain.Message, error)) *MockMessageRepository_FindByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindExpired provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) FindExpired(now time.Time, limit int) ([]uuid.UUID, error) {
+	ret := _mock.Called(now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindExpired")
+	}
+
+	var r0 []uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(time.Time, int) ([]uuid.UUID, error)); ok {
+		return returnFunc(now, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(time.Time, int) []uuid.UUID); ok {
+		r0 = returnFunc(now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(time.Time, int) error); ok {
+		r1 = returnFunc(now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageRepository_FindExpired_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindExpired'
+type MockMessageRepository_FindExpired_Call struct {
+	*mock.Call
+}
+
+// FindExpired is a helper method to define mock.On call
+//   - now
+//   - limit
+func (_e *MockMessageRepository_Expecter) FindExpired(now interface{}, limit interface{}) *MockMessageRepository_FindExpired_Call {
+	return &MockMessageRepository_FindExpired_Call{Call: _e.mock.On("FindExpired", now, limit)}
+}
+
+func (_c *MockMessageRepository_FindExpired_Call) Run(run func(now time.Time, limit int)) *MockMessageRepository_FindExpired_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Time), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_FindExpired_Call) Return(uUIDs []uuid.UUID, err error) *MockMessageRepository_FindExpired_Call {
+	_c.Call.Return(uUIDs, err)
+	return _c
+}
+
+func (_c *MockMessageRepository_FindExpired_Call) RunAndReturn(run func(now time.Time, limit int) ([]uuid.UUID, error)) *MockMessageRepository_FindExpired_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPastRetention provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) FindPastRetention(filter ports.RetentionFilter, limit int, offset int) ([]*domain.Message, error) {
+	ret := _mock.Called(filter, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPastRetention")
+	}
+
+	var r0 []*domain.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(ports.RetentionFilter, int, int) ([]*domain.Message, error)); ok {
+		return returnFunc(filter, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(ports.RetentionFilter, int, int) []*domain.Message); ok {
+		r0 = returnFunc(filter, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(ports.RetentionFilter, int, int) error); ok {
+		r1 = returnFunc(filter, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageRepository_FindPastRetention_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPastRetention'
+type MockMessageRepository_FindPastRetention_Call struct {
+	*mock.Call
+}
+
+// FindPastRetention is a helper method to define mock.On call
+//   - filter
+//   - limit
+//   - offset
+func (_e *MockMessageRepository_Expecter) FindPastRetention(filter interface{}, limit interface{}, offset interface{}) *MockMessageRepository_FindPastRetention_Call {
+	return &MockMessageRepository_FindPastRetention_Call{Call: _e.mock.On("FindPastRetention", filter, limit, offset)}
+}
+
+func (_c *MockMessageRepository_FindPastRetention_Call) Run(run func(filter ports.RetentionFilter, limit int, offset int)) *MockMessageRepository_FindPastRetention_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(ports.RetentionFilter), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_FindPastRetention_Call) Return(messages []*domain.Message, err error) *MockMessageRepository_FindPastRetention_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockMessageRepository_FindPastRetention_Call) RunAndReturn(run func(filter ports.RetentionFilter, limit int, offset int) ([]*domain.Message, error)) *MockMessageRepository_FindPastRetention_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindRevisions provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) FindRevisions(messageID uuid.UUID) ([]*domain.MessageRevision, error) {
+	ret := _mock.Called(messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRevisions")
+	}
+
+	var r0 []*domain.MessageRevision
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID) ([]*domain.MessageRevision, error)); ok {
+		return returnFunc(messageID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID) []*domain.MessageRevision); ok {
+		r0 = returnFunc(messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.MessageRevision)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(uuid.UUID) error); ok {
+		r1 = returnFunc(messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageRepository_FindRevisions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindRevisions'
+type MockMessageRepository_FindRevisions_Call struct {
+	*mock.Call
+}
+
+// FindRevisions is a helper method to define mock.On call
+//   - messageID
+func (_e *MockMessageRepository_Expecter) FindRevisions(messageID interface{}) *MockMessageRepository_FindRevisions_Call {
+	return &MockMessageRepository_FindRevisions_Call{Call: _e.mock.On("FindRevisions", messageID)}
+}
+
+func (_c *MockMessageRepository_FindRevisions_Call) Run(run func(messageID uuid.UUID)) *MockMessageRepository_FindRevisions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_FindRevisions_Call) Return(messageRevisions []*domain.MessageRevision, err error) *MockMessageRepository_FindRevisions_Call {
+	_c.Call.Return(messageRevisions, err)
+	return _c
+}
+
+func (_c *MockMessageRepository_FindRevisions_Call) RunAndReturn(run func(messageID uuid.UUID) ([]*domain.MessageRevision, error)) *MockMessageRepository_FindRevisions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindThreadIDs provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) FindThreadIDs(rootID uuid.UUID) ([]uuid.UUID, error) {
+	ret := _mock.Called(rootID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindThreadIDs")
+	}
+
+	var r0 []uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID) ([]uuid.UUID, error)); ok {
+		return returnFunc(rootID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID) []uuid.UUID); ok {
+		r0 = returnFunc(rootID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(uuid.UUID) error); ok {
+		r1 = returnFunc(rootID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageRepository_FindThreadIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindThreadIDs'
+type MockMessageRepository_FindThreadIDs_Call struct {
+	*mock.Call
+}
+
+// FindThreadIDs is a helper method to define mock.On call
+//   - rootID
+func (_e *MockMessageRepository_Expecter) FindThreadIDs(rootID interface{}) *MockMessageRepository_FindThreadIDs_Call {
+	return &MockMessageRepository_FindThreadIDs_Call{Call: _e.mock.On("FindThreadIDs", rootID)}
+}
+
+func (_c *MockMessageRepository_FindThreadIDs_Call) Run(run func(rootID uuid.UUID)) *MockMessageRepository_FindThreadIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_FindThreadIDs_Call) Return(uUIDs []uuid.UUID, err error) *MockMessageRepository_FindThreadIDs_Call {
+	_c.Call.Return(uUIDs, err)
+	return _c
+}
+
+func (_c *MockMessageRepository_FindThreadIDs_Call) RunAndReturn(run func(rootID uuid.UUID) ([]uuid.UUID, error)) *MockMessageRepository_FindThreadIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMentions provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) GetMentions(userID string, limit int, offset int) ([]*domain.Message, error) {
+	ret := _mock.Called(userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMentions")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*domain.Message) error); ok {
-		r0 = returnFunc(message)
+	var r0 []*domain.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) ([]*domain.Message, error)); ok {
+		return returnFunc(userID, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) []*domain.Message); ok {
+		r0 = returnFunc(userID, limit, offset)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Message)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(string, int, int) error); ok {
+		r1 = returnFunc(userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockMessageRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
-type MockMessageRepository_Create_Call struct {
+// MockMessageRepository_GetMentions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMentions'
+type MockMessageRepository_GetMentions_Call struct {
 	*mock.Call
 }
 
-// Create is a helper method to define mock.On call
-//   - message
-func (_e *MockMessageRepository_Expecter) Create(message interface{}) *MockMessageRepository_Create_Call {
-	return &MockMessageRepository_Create_Call{Call: _e.mock.On("Create", message)}
+// GetMentions is a helper method to define mock.On call
+//   - userID
+//   - limit
+//   - offset
+func (_e *MockMessageRepository_Expecter) GetMentions(userID interface{}, limit interface{}, offset interface{}) *MockMessageRepository_GetMentions_Call {
+	return &MockMessageRepository_GetMentions_Call{Call: _e.mock.On("GetMentions", userID, limit, offset)}
 }
 
-func (_c *MockMessageRepository_Create_Call) Run(run func(message *domain.Message)) *MockMessageRepository_Create_Call {
+func (_c *MockMessageRepository_GetMentions_Call) Run(run func(userID string, limit int, offset int)) *MockMessageRepository_GetMentions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*domain.Message))
+		run(args[0].(string), args[1].(int), args[2].(int))
 	})
 	return _c
 }
 
-func (_c *MockMessageRepository_Create_Call) Return(err error) *MockMessageRepository_Create_Call {
-	_c.Call.Return(err)
+func (_c *MockMessageRepository_GetMentions_Call) Return(messages []*domain.Message, err error) *MockMessageRepository_GetMentions_Call {
+	_c.Call.Return(messages, err)
 	return _c
 }
 
-func (_c *MockMessageRepository_Create_Call) RunAndReturn(run func(message *domain.Message) error) *MockMessageRepository_Create_Call {
+func (_c *MockMessageRepository_GetMentions_Call) RunAndReturn(run func(userID string, limit int, offset int) ([]*domain.Message, error)) *MockMessageRepository_GetMentions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMessagesByConversation provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) GetMessagesByConversation(conversationID uuid.UUID, userID string, limit int, offset int) ([]*domain.Message, error) {
+	ret := _mock.Called(conversationID, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessagesByConversation")
+	}
+
+	var r0 []*domain.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID, string, int, int) ([]*domain.Message, error)); ok {
+		return returnFunc(conversationID, userID, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID, string, int, int) []*domain.Message); ok {
+		r0 = returnFunc(conversationID, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(uuid.UUID, string, int, int) error); ok {
+		r1 = returnFunc(conversationID, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageRepository_GetMessagesByConversation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessagesByConversation'
+type MockMessageRepository_GetMessagesByConversation_Call struct {
+	*mock.Call
+}
+
+// GetMessagesByConversation is a helper method to define mock.On call
+//   - conversationID
+//   - userID
+//   - limit
+//   - offset
+func (_e *MockMessageRepository_Expecter) GetMessagesByConversation(conversationID interface{}, userID interface{}, limit interface{}, offset interface{}) *MockMessageRepository_GetMessagesByConversation_Call {
+	return &MockMessageRepository_GetMessagesByConversation_Call{Call: _e.mock.On("GetMessagesByConversation", conversationID, userID, limit, offset)}
+}
+
+func (_c *MockMessageRepository_GetMessagesByConversation_Call) Run(run func(conversationID uuid.UUID, userID string, limit int, offset int)) *MockMessageRepository_GetMessagesByConversation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID), args[1].(string), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_GetMessagesByConversation_Call) Return(messages []*domain.Message, err error) *MockMessageRepository_GetMessagesByConversation_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockMessageRepository_GetMessagesByConversation_Call) RunAndReturn(run func(conversationID uuid.UUID, userID string, limit int, offset int) ([]*domain.Message, error)) *MockMessageRepository_GetMessagesByConversation_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -196,6 +824,158 @@ func (_c *MockMessageRepository_GetMessagesBySender_Call) RunAndReturn(run func(
 	return _c
 }
 
+// GetThreadReplies provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) GetThreadReplies(rootID uuid.UUID, userID string, limit int, offset int) ([]*domain.Message, error) {
+	ret := _mock.Called(rootID, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetThreadReplies")
+	}
+
+	var r0 []*domain.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID, string, int, int) ([]*domain.Message, error)); ok {
+		return returnFunc(rootID, userID, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID, string, int, int) []*domain.Message); ok {
+		r0 = returnFunc(rootID, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(uuid.UUID, string, int, int) error); ok {
+		r1 = returnFunc(rootID, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageRepository_GetThreadReplies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetThreadReplies'
+type MockMessageRepository_GetThreadReplies_Call struct {
+	*mock.Call
+}
+
+// GetThreadReplies is a helper method to define mock.On call
+//   - rootID
+//   - userID
+//   - limit
+//   - offset
+func (_e *MockMessageRepository_Expecter) GetThreadReplies(rootID interface{}, userID interface{}, limit interface{}, offset interface{}) *MockMessageRepository_GetThreadReplies_Call {
+	return &MockMessageRepository_GetThreadReplies_Call{Call: _e.mock.On("GetThreadReplies", rootID, userID, limit, offset)}
+}
+
+func (_c *MockMessageRepository_GetThreadReplies_Call) Run(run func(rootID uuid.UUID, userID string, limit int, offset int)) *MockMessageRepository_GetThreadReplies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID), args[1].(string), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_GetThreadReplies_Call) Return(messages []*domain.Message, err error) *MockMessageRepository_GetThreadReplies_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockMessageRepository_GetThreadReplies_Call) RunAndReturn(run func(rootID uuid.UUID, userID string, limit int, offset int) ([]*domain.Message, error)) *MockMessageRepository_GetThreadReplies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HideForUser provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) HideForUser(messageID uuid.UUID, userID string) error {
+	ret := _mock.Called(messageID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HideForUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID, string) error); ok {
+		r0 = returnFunc(messageID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageRepository_HideForUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HideForUser'
+type MockMessageRepository_HideForUser_Call struct {
+	*mock.Call
+}
+
+// HideForUser is a helper method to define mock.On call
+//   - messageID
+//   - userID
+func (_e *MockMessageRepository_Expecter) HideForUser(messageID interface{}, userID interface{}) *MockMessageRepository_HideForUser_Call {
+	return &MockMessageRepository_HideForUser_Call{Call: _e.mock.On("HideForUser", messageID, userID)}
+}
+
+func (_c *MockMessageRepository_HideForUser_Call) Run(run func(messageID uuid.UUID, userID string)) *MockMessageRepository_HideForUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_HideForUser_Call) Return(err error) *MockMessageRepository_HideForUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageRepository_HideForUser_Call) RunAndReturn(run func(messageID uuid.UUID, userID string) error) *MockMessageRepository_HideForUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkThreadRead provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) MarkThreadRead(rootID uuid.UUID, userID string, readAt time.Time) error {
+	ret := _mock.Called(rootID, userID, readAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkThreadRead")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID, string, time.Time) error); ok {
+		r0 = returnFunc(rootID, userID, readAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageRepository_MarkThreadRead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkThreadRead'
+type MockMessageRepository_MarkThreadRead_Call struct {
+	*mock.Call
+}
+
+// MarkThreadRead is a helper method to define mock.On call
+//   - rootID
+//   - userID
+//   - readAt
+func (_e *MockMessageRepository_Expecter) MarkThreadRead(rootID interface{}, userID interface{}, readAt interface{}) *MockMessageRepository_MarkThreadRead_Call {
+	return &MockMessageRepository_MarkThreadRead_Call{Call: _e.mock.On("MarkThreadRead", rootID, userID, readAt)}
+}
+
+func (_c *MockMessageRepository_MarkThreadRead_Call) Run(run func(rootID uuid.UUID, userID string, readAt time.Time)) *MockMessageRepository_MarkThreadRead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_MarkThreadRead_Call) Return(err error) *MockMessageRepository_MarkThreadRead_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageRepository_MarkThreadRead_Call) RunAndReturn(run func(rootID uuid.UUID, userID string, readAt time.Time) error) *MockMessageRepository_MarkThreadRead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetMessageStatus provides a mock function for the type MockMessageRepository
 func (_mock *MockMessageRepository) SetMessageStatus(messageID uuid.UUID, status domain.MessageStatus) error {
 	ret := _mock.Called(messageID, status)
@@ -241,3 +1021,105 @@ func (_c *MockMessageRepository_SetMessageStatus_Call) RunAndReturn(run func(mes
 	_c.Call.Return(run)
 	return _c
 }
+
+// ThreadSummaries provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) ThreadSummaries(rootIDs []uuid.UUID, userID string) (map[uuid.UUID]*domain.ThreadSummary, error) {
+	ret := _mock.Called(rootIDs, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ThreadSummaries")
+	}
+
+	var r0 map[uuid.UUID]*domain.ThreadSummary
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func([]uuid.UUID, string) (map[uuid.UUID]*domain.ThreadSummary, error)); ok {
+		return returnFunc(rootIDs, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func([]uuid.UUID, string) map[uuid.UUID]*domain.ThreadSummary); ok {
+		r0 = returnFunc(rootIDs, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uuid.UUID]*domain.ThreadSummary)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func([]uuid.UUID, string) error); ok {
+		r1 = returnFunc(rootIDs, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageRepository_ThreadSummaries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ThreadSummaries'
+type MockMessageRepository_ThreadSummaries_Call struct {
+	*mock.Call
+}
+
+// ThreadSummaries is a helper method to define mock.On call
+//   - rootIDs
+//   - userID
+func (_e *MockMessageRepository_Expecter) ThreadSummaries(rootIDs interface{}, userID interface{}) *MockMessageRepository_ThreadSummaries_Call {
+	return &MockMessageRepository_ThreadSummaries_Call{Call: _e.mock.On("ThreadSummaries", rootIDs, userID)}
+}
+
+func (_c *MockMessageRepository_ThreadSummaries_Call) Run(run func(rootIDs []uuid.UUID, userID string)) *MockMessageRepository_ThreadSummaries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].([]uuid.UUID), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_ThreadSummaries_Call) Return(mapParam map[uuid.UUID]*domain.ThreadSummary, err error) *MockMessageRepository_ThreadSummaries_Call {
+	_c.Call.Return(mapParam, err)
+	return _c
+}
+
+func (_c *MockMessageRepository_ThreadSummaries_Call) RunAndReturn(run func(rootIDs []uuid.UUID, userID string) (map[uuid.UUID]*domain.ThreadSummary, error)) *MockMessageRepository_ThreadSummaries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function for the type MockMessageRepository
+func (_mock *MockMessageRepository) Update(message *domain.Message) error {
+	ret := _mock.Called(message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*domain.Message) error); ok {
+		r0 = returnFunc(message)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockMessageRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - message
+func (_e *MockMessageRepository_Expecter) Update(message interface{}) *MockMessageRepository_Update_Call {
+	return &MockMessageRepository_Update_Call{Call: _e.mock.On("Update", message)}
+}
+
+func (_c *MockMessageRepository_Update_Call) Run(run func(message *domain.Message)) *MockMessageRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*domain.Message))
+	})
+	return _c
+}
+
+func (_c *MockMessageRepository_Update_Call) Return(err error) *MockMessageRepository_Update_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageRepository_Update_Call) RunAndReturn(run func(message *domain.Message) error) *MockMessageRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -5,8 +5,10 @@
 package mocks
 
 import (
+	"github.com/chrikar/chatheon/application/ports"
 	"github.com/chrikar/chatheon/domain"
 	mock "github.com/stretchr/testify/mock"
+	"time"
 )
 
 // NewMockMessageService creates a new instance of MockMessageService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -36,107 +38,1089 @@ func (_m *MockMessageService) EXPECT() *MockMessageService_Expecter {
 	return &MockMessageService_Expecter{mock: &_m.Mock}
 }
 
+// AddReaction provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) AddReaction(userID string, messageID string, emoji string) error {
+	ret := _mock.Called(userID, messageID, emoji)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddReaction")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = returnFunc(userID, messageID, emoji)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageService_AddReaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddReaction'
+type MockMessageService_AddReaction_Call struct {
+	*mock.Call
+}
+
+// AddReaction is a helper method to define mock.On call
+//   - userID
+//   - messageID
+//   - emoji
+func (_e *MockMessageService_Expecter) AddReaction(userID interface{}, messageID interface{}, emoji interface{}) *MockMessageService_AddReaction_Call {
+	return &MockMessageService_AddReaction_Call{Call: _e.mock.On("AddReaction", userID, messageID, emoji)}
+}
+
+func (_c *MockMessageService_AddReaction_Call) Run(run func(userID string, messageID string, emoji string)) *MockMessageService_AddReaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_AddReaction_Call) Return(err error) *MockMessageService_AddReaction_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageService_AddReaction_Call) RunAndReturn(run func(userID string, messageID string, emoji string) error) *MockMessageService_AddReaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CancelScheduledMessage provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) CancelScheduledMessage(userID string, scheduledID string) error {
+	ret := _mock.Called(userID, scheduledID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelScheduledMessage")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(userID, scheduledID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageService_CancelScheduledMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelScheduledMessage'
+type MockMessageService_CancelScheduledMessage_Call struct {
+	*mock.Call
+}
+
+// CancelScheduledMessage is a helper method to define mock.On call
+//   - userID
+//   - scheduledID
+func (_e *MockMessageService_Expecter) CancelScheduledMessage(userID interface{}, scheduledID interface{}) *MockMessageService_CancelScheduledMessage_Call {
+	return &MockMessageService_CancelScheduledMessage_Call{Call: _e.mock.On("CancelScheduledMessage", userID, scheduledID)}
+}
+
+func (_c *MockMessageService_CancelScheduledMessage_Call) Run(run func(userID string, scheduledID string)) *MockMessageService_CancelScheduledMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_CancelScheduledMessage_Call) Return(err error) *MockMessageService_CancelScheduledMessage_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageService_CancelScheduledMessage_Call) RunAndReturn(run func(userID string, scheduledID string) error) *MockMessageService_CancelScheduledMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateMessage provides a mock function for the type MockMessageService
-func (_mock *MockMessageService) CreateMessage(senderID string, receiverID string, content string) error {
-	ret := _mock.Called(senderID, receiverID, content)
+func (_mock *MockMessageService) CreateMessage(senderID string, draft ports.MessageDraft) (*domain.Message, error) {
+	ret := _mock.Called(senderID, draft)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMessage")
+	}
+
+	var r0 *domain.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, ports.MessageDraft) (*domain.Message, error)); ok {
+		return returnFunc(senderID, draft)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, ports.MessageDraft) *domain.Message); ok {
+		r0 = returnFunc(senderID, draft)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, ports.MessageDraft) error); ok {
+		r1 = returnFunc(senderID, draft)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_CreateMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateMessage'
+type MockMessageService_CreateMessage_Call struct {
+	*mock.Call
+}
+
+// CreateMessage is a helper method to define mock.On call
+//   - senderID
+//   - draft
+func (_e *MockMessageService_Expecter) CreateMessage(senderID interface{}, draft interface{}) *MockMessageService_CreateMessage_Call {
+	return &MockMessageService_CreateMessage_Call{Call: _e.mock.On("CreateMessage", senderID, draft)}
+}
+
+func (_c *MockMessageService_CreateMessage_Call) Run(run func(senderID string, draft ports.MessageDraft)) *MockMessageService_CreateMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(ports.MessageDraft))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_CreateMessage_Call) Return(message *domain.Message, err error) *MockMessageService_CreateMessage_Call {
+	_c.Call.Return(message, err)
+	return _c
+}
+
+func (_c *MockMessageService_CreateMessage_Call) RunAndReturn(run func(senderID string, draft ports.MessageDraft) (*domain.Message, error)) *MockMessageService_CreateMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteMessageForEveryone provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) DeleteMessageForEveryone(userID string, messageID string) error {
+	ret := _mock.Called(userID, messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMessageForEveryone")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(userID, messageID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageService_DeleteMessageForEveryone_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMessageForEveryone'
+type MockMessageService_DeleteMessageForEveryone_Call struct {
+	*mock.Call
+}
+
+// DeleteMessageForEveryone is a helper method to define mock.On call
+//   - userID
+//   - messageID
+func (_e *MockMessageService_Expecter) DeleteMessageForEveryone(userID interface{}, messageID interface{}) *MockMessageService_DeleteMessageForEveryone_Call {
+	return &MockMessageService_DeleteMessageForEveryone_Call{Call: _e.mock.On("DeleteMessageForEveryone", userID, messageID)}
+}
+
+func (_c *MockMessageService_DeleteMessageForEveryone_Call) Run(run func(userID string, messageID string)) *MockMessageService_DeleteMessageForEveryone_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_DeleteMessageForEveryone_Call) Return(err error) *MockMessageService_DeleteMessageForEveryone_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageService_DeleteMessageForEveryone_Call) RunAndReturn(run func(userID string, messageID string) error) *MockMessageService_DeleteMessageForEveryone_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteMessageForMe provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) DeleteMessageForMe(userID string, messageID string) error {
+	ret := _mock.Called(userID, messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMessageForMe")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(userID, messageID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageService_DeleteMessageForMe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMessageForMe'
+type MockMessageService_DeleteMessageForMe_Call struct {
+	*mock.Call
+}
+
+// DeleteMessageForMe is a helper method to define mock.On call
+//   - userID
+//   - messageID
+func (_e *MockMessageService_Expecter) DeleteMessageForMe(userID interface{}, messageID interface{}) *MockMessageService_DeleteMessageForMe_Call {
+	return &MockMessageService_DeleteMessageForMe_Call{Call: _e.mock.On("DeleteMessageForMe", userID, messageID)}
+}
+
+func (_c *MockMessageService_DeleteMessageForMe_Call) Run(run func(userID string, messageID string)) *MockMessageService_DeleteMessageForMe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_DeleteMessageForMe_Call) Return(err error) *MockMessageService_DeleteMessageForMe_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageService_DeleteMessageForMe_Call) RunAndReturn(run func(userID string, messageID string) error) *MockMessageService_DeleteMessageForMe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EditMessage provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) EditMessage(editorID string, messageID string, content string) (*domain.Message, error) {
+	ret := _mock.Called(editorID, messageID, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EditMessage")
+	}
+
+	var r0 *domain.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, string) (*domain.Message, error)); ok {
+		return returnFunc(editorID, messageID, content)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, string) *domain.Message); ok {
+		r0 = returnFunc(editorID, messageID, content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = returnFunc(editorID, messageID, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_EditMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EditMessage'
+type MockMessageService_EditMessage_Call struct {
+	*mock.Call
+}
+
+// EditMessage is a helper method to define mock.On call
+//   - editorID
+//   - messageID
+//   - content
+func (_e *MockMessageService_Expecter) EditMessage(editorID interface{}, messageID interface{}, content interface{}) *MockMessageService_EditMessage_Call {
+	return &MockMessageService_EditMessage_Call{Call: _e.mock.On("EditMessage", editorID, messageID, content)}
+}
+
+func (_c *MockMessageService_EditMessage_Call) Run(run func(editorID string, messageID string, content string)) *MockMessageService_EditMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_EditMessage_Call) Return(message *domain.Message, err error) *MockMessageService_EditMessage_Call {
+	_c.Call.Return(message, err)
+	return _c
+}
+
+func (_c *MockMessageService_EditMessage_Call) RunAndReturn(run func(editorID string, messageID string, content string) (*domain.Message, error)) *MockMessageService_EditMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ForwardMessage provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) ForwardMessage(userID string, messageID string, target ports.ForwardTarget) (*domain.Message, error) {
+	ret := _mock.Called(userID, messageID, target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForwardMessage")
+	}
+
+	var r0 *domain.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, ports.ForwardTarget) (*domain.Message, error)); ok {
+		return returnFunc(userID, messageID, target)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, ports.ForwardTarget) *domain.Message); ok {
+		r0 = returnFunc(userID, messageID, target)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, ports.ForwardTarget) error); ok {
+		r1 = returnFunc(userID, messageID, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_ForwardMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForwardMessage'
+type MockMessageService_ForwardMessage_Call struct {
+	*mock.Call
+}
+
+// ForwardMessage is a helper method to define mock.On call
+//   - userID
+//   - messageID
+//   - target
+func (_e *MockMessageService_Expecter) ForwardMessage(userID interface{}, messageID interface{}, target interface{}) *MockMessageService_ForwardMessage_Call {
+	return &MockMessageService_ForwardMessage_Call{Call: _e.mock.On("ForwardMessage", userID, messageID, target)}
+}
+
+func (_c *MockMessageService_ForwardMessage_Call) Run(run func(userID string, messageID string, target ports.ForwardTarget)) *MockMessageService_ForwardMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(ports.ForwardTarget))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_ForwardMessage_Call) Return(message *domain.Message, err error) *MockMessageService_ForwardMessage_Call {
+	_c.Call.Return(message, err)
+	return _c
+}
+
+func (_c *MockMessageService_ForwardMessage_Call) RunAndReturn(run func(userID string, messageID string, target ports.ForwardTarget) (*domain.Message, error)) *MockMessageService_ForwardMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetConversationMessages provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) GetConversationMessages(userID string, conversationID string, limit int, offset int) ([]*domain.Message, error) {
+	ret := _mock.Called(userID, conversationID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetConversationMessages")
+	}
+
+	var r0 []*domain.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, int, int) ([]*domain.Message, error)); ok {
+		return returnFunc(userID, conversationID, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, int, int) []*domain.Message); ok {
+		r0 = returnFunc(userID, conversationID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, int, int) error); ok {
+		r1 = returnFunc(userID, conversationID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_GetConversationMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetConversationMessages'
+type MockMessageService_GetConversationMessages_Call struct {
+	*mock.Call
+}
+
+// GetConversationMessages is a helper method to define mock.On call
+//   - userID
+//   - conversationID
+//   - limit
+//   - offset
+func (_e *MockMessageService_Expecter) GetConversationMessages(userID interface{}, conversationID interface{}, limit interface{}, offset interface{}) *MockMessageService_GetConversationMessages_Call {
+	return &MockMessageService_GetConversationMessages_Call{Call: _e.mock.On("GetConversationMessages", userID, conversationID, limit, offset)}
+}
+
+func (_c *MockMessageService_GetConversationMessages_Call) Run(run func(userID string, conversationID string, limit int, offset int)) *MockMessageService_GetConversationMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_GetConversationMessages_Call) Return(messages []*domain.Message, err error) *MockMessageService_GetConversationMessages_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockMessageService_GetConversationMessages_Call) RunAndReturn(run func(userID string, conversationID string, limit int, offset int) ([]*domain.Message, error)) *MockMessageService_GetConversationMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMentions provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) GetMentions(userID string, limit int, offset int) ([]*domain.Message, error) {
+	ret := _mock.Called(userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMentions")
+	}
+
+	var r0 []*domain.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) ([]*domain.Message, error)); ok {
+		return returnFunc(userID, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) []*domain.Message); ok {
+		r0 = returnFunc(userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, int, int) error); ok {
+		r1 = returnFunc(userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_GetMentions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMentions'
+type MockMessageService_GetMentions_Call struct {
+	*mock.Call
+}
+
+// GetMentions is a helper method to define mock.On call
+//   - userID
+//   - limit
+//   - offset
+func (_e *MockMessageService_Expecter) GetMentions(userID interface{}, limit interface{}, offset interface{}) *MockMessageService_GetMentions_Call {
+	return &MockMessageService_GetMentions_Call{Call: _e.mock.On("GetMentions", userID, limit, offset)}
+}
+
+func (_c *MockMessageService_GetMentions_Call) Run(run func(userID string, limit int, offset int)) *MockMessageService_GetMentions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_GetMentions_Call) Return(messages []*domain.Message, err error) *MockMessageService_GetMentions_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockMessageService_GetMentions_Call) RunAndReturn(run func(userID string, limit int, offset int) ([]*domain.Message, error)) *MockMessageService_GetMentions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMessageHistory provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) GetMessageHistory(callerID string, messageID string) ([]*domain.MessageRevision, error) {
+	ret := _mock.Called(callerID, messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessageHistory")
+	}
+
+	var r0 []*domain.MessageRevision
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) ([]*domain.MessageRevision, error)); ok {
+		return returnFunc(callerID, messageID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string) []*domain.MessageRevision); ok {
+		r0 = returnFunc(callerID, messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.MessageRevision)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = returnFunc(callerID, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_GetMessageHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessageHistory'
+type MockMessageService_GetMessageHistory_Call struct {
+	*mock.Call
+}
+
+// GetMessageHistory is a helper method to define mock.On call
+//   - callerID
+//   - messageID
+func (_e *MockMessageService_Expecter) GetMessageHistory(callerID interface{}, messageID interface{}) *MockMessageService_GetMessageHistory_Call {
+	return &MockMessageService_GetMessageHistory_Call{Call: _e.mock.On("GetMessageHistory", callerID, messageID)}
+}
+
+func (_c *MockMessageService_GetMessageHistory_Call) Run(run func(callerID string, messageID string)) *MockMessageService_GetMessageHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_GetMessageHistory_Call) Return(messageRevisions []*domain.MessageRevision, err error) *MockMessageService_GetMessageHistory_Call {
+	_c.Call.Return(messageRevisions, err)
+	return _c
+}
+
+func (_c *MockMessageService_GetMessageHistory_Call) RunAndReturn(run func(callerID string, messageID string) ([]*domain.MessageRevision, error)) *MockMessageService_GetMessageHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMessagesByReceiver provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) GetMessagesByReceiver(receiverID string, limit int, offset int) ([]*domain.Message, error) {
+	ret := _mock.Called(receiverID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessagesByReceiver")
+	}
+
+	var r0 []*domain.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) ([]*domain.Message, error)); ok {
+		return returnFunc(receiverID, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) []*domain.Message); ok {
+		r0 = returnFunc(receiverID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, int, int) error); ok {
+		r1 = returnFunc(receiverID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_GetMessagesByReceiver_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessagesByReceiver'
+type MockMessageService_GetMessagesByReceiver_Call struct {
+	*mock.Call
+}
+
+// GetMessagesByReceiver is a helper method to define mock.On call
+//   - receiverID
+//   - limit
+//   - offset
+func (_e *MockMessageService_Expecter) GetMessagesByReceiver(receiverID interface{}, limit interface{}, offset interface{}) *MockMessageService_GetMessagesByReceiver_Call {
+	return &MockMessageService_GetMessagesByReceiver_Call{Call: _e.mock.On("GetMessagesByReceiver", receiverID, limit, offset)}
+}
+
+func (_c *MockMessageService_GetMessagesByReceiver_Call) Run(run func(receiverID string, limit int, offset int)) *MockMessageService_GetMessagesByReceiver_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_GetMessagesByReceiver_Call) Return(messages []*domain.Message, err error) *MockMessageService_GetMessagesByReceiver_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockMessageService_GetMessagesByReceiver_Call) RunAndReturn(run func(receiverID string, limit int, offset int) ([]*domain.Message, error)) *MockMessageService_GetMessagesByReceiver_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPins provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) GetPins(userID string, conversationID string) ([]*domain.Pin, error) {
+	ret := _mock.Called(userID, conversationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPins")
+	}
+
+	var r0 []*domain.Pin
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) ([]*domain.Pin, error)); ok {
+		return returnFunc(userID, conversationID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string) []*domain.Pin); ok {
+		r0 = returnFunc(userID, conversationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.Pin)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = returnFunc(userID, conversationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_GetPins_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPins'
+type MockMessageService_GetPins_Call struct {
+	*mock.Call
+}
+
+// GetPins is a helper method to define mock.On call
+//   - userID
+//   - conversationID
+func (_e *MockMessageService_Expecter) GetPins(userID interface{}, conversationID interface{}) *MockMessageService_GetPins_Call {
+	return &MockMessageService_GetPins_Call{Call: _e.mock.On("GetPins", userID, conversationID)}
+}
+
+func (_c *MockMessageService_GetPins_Call) Run(run func(userID string, conversationID string)) *MockMessageService_GetPins_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_GetPins_Call) Return(pins []*domain.Pin, err error) *MockMessageService_GetPins_Call {
+	_c.Call.Return(pins, err)
+	return _c
+}
+
+func (_c *MockMessageService_GetPins_Call) RunAndReturn(run func(userID string, conversationID string) ([]*domain.Pin, error)) *MockMessageService_GetPins_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetScheduledMessages provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) GetScheduledMessages(userID string, limit int, offset int) ([]*domain.ScheduledMessage, error) {
+	ret := _mock.Called(userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetScheduledMessages")
+	}
+
+	var r0 []*domain.ScheduledMessage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) ([]*domain.ScheduledMessage, error)); ok {
+		return returnFunc(userID, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, int, int) []*domain.ScheduledMessage); ok {
+		r0 = returnFunc(userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ScheduledMessage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, int, int) error); ok {
+		r1 = returnFunc(userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_GetScheduledMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetScheduledMessages'
+type MockMessageService_GetScheduledMessages_Call struct {
+	*mock.Call
+}
+
+// GetScheduledMessages is a helper method to define mock.On call
+//   - userID
+//   - limit
+//   - offset
+func (_e *MockMessageService_Expecter) GetScheduledMessages(userID interface{}, limit interface{}, offset interface{}) *MockMessageService_GetScheduledMessages_Call {
+	return &MockMessageService_GetScheduledMessages_Call{Call: _e.mock.On("GetScheduledMessages", userID, limit, offset)}
+}
+
+func (_c *MockMessageService_GetScheduledMessages_Call) Run(run func(userID string, limit int, offset int)) *MockMessageService_GetScheduledMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_GetScheduledMessages_Call) Return(scheduledMessages []*domain.ScheduledMessage, err error) *MockMessageService_GetScheduledMessages_Call {
+	_c.Call.Return(scheduledMessages, err)
+	return _c
+}
+
+func (_c *MockMessageService_GetScheduledMessages_Call) RunAndReturn(run func(userID string, limit int, offset int) ([]*domain.ScheduledMessage, error)) *MockMessageService_GetScheduledMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetThread provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) GetThread(userID string, messageID string, limit int, offset int) (*ports.Thread, error) {
+	ret := _mock.Called(userID, messageID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetThread")
+	}
+
+	var r0 *ports.Thread
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, int, int) (*ports.Thread, error)); ok {
+		return returnFunc(userID, messageID, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, int, int) *ports.Thread); ok {
+		r0 = returnFunc(userID, messageID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ports.Thread)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, int, int) error); ok {
+		r1 = returnFunc(userID, messageID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_GetThread_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetThread'
+type MockMessageService_GetThread_Call struct {
+	*mock.Call
+}
+
+// GetThread is a helper method to define mock.On call
+//   - userID
+//   - messageID
+//   - limit
+//   - offset
+func (_e *MockMessageService_Expecter) GetThread(userID interface{}, messageID interface{}, limit interface{}, offset interface{}) *MockMessageService_GetThread_Call {
+	return &MockMessageService_GetThread_Call{Call: _e.mock.On("GetThread", userID, messageID, limit, offset)}
+}
+
+func (_c *MockMessageService_GetThread_Call) Run(run func(userID string, messageID string, limit int, offset int)) *MockMessageService_GetThread_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_GetThread_Call) Return(thread *ports.Thread, err error) *MockMessageService_GetThread_Call {
+	_c.Call.Return(thread, err)
+	return _c
+}
+
+func (_c *MockMessageService_GetThread_Call) RunAndReturn(run func(userID string, messageID string, limit int, offset int) (*ports.Thread, error)) *MockMessageService_GetThread_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkThreadRead provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) MarkThreadRead(userID string, messageID string) error {
+	ret := _mock.Called(userID, messageID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateMessage")
+		panic("no return value specified for MarkThreadRead")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(userID, messageID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageService_MarkThreadRead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkThreadRead'
+type MockMessageService_MarkThreadRead_Call struct {
+	*mock.Call
+}
+
+// MarkThreadRead is a helper method to define mock.On call
+//   - userID
+//   - messageID
+func (_e *MockMessageService_Expecter) MarkThreadRead(userID interface{}, messageID interface{}) *MockMessageService_MarkThreadRead_Call {
+	return &MockMessageService_MarkThreadRead_Call{Call: _e.mock.On("MarkThreadRead", userID, messageID)}
+}
+
+func (_c *MockMessageService_MarkThreadRead_Call) Run(run func(userID string, messageID string)) *MockMessageService_MarkThreadRead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_MarkThreadRead_Call) Return(err error) *MockMessageService_MarkThreadRead_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageService_MarkThreadRead_Call) RunAndReturn(run func(userID string, messageID string) error) *MockMessageService_MarkThreadRead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PinMessage provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) PinMessage(userID string, messageID string) (*domain.Pin, error) {
+	ret := _mock.Called(userID, messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PinMessage")
+	}
+
+	var r0 *domain.Pin
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) (*domain.Pin, error)); ok {
+		return returnFunc(userID, messageID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string) *domain.Pin); ok {
+		r0 = returnFunc(userID, messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Pin)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = returnFunc(userID, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_PinMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PinMessage'
+type MockMessageService_PinMessage_Call struct {
+	*mock.Call
+}
+
+// PinMessage is a helper method to define mock.On call
+//   - userID
+//   - messageID
+func (_e *MockMessageService_Expecter) PinMessage(userID interface{}, messageID interface{}) *MockMessageService_PinMessage_Call {
+	return &MockMessageService_PinMessage_Call{Call: _e.mock.On("PinMessage", userID, messageID)}
+}
+
+func (_c *MockMessageService_PinMessage_Call) Run(run func(userID string, messageID string)) *MockMessageService_PinMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_PinMessage_Call) Return(pin *domain.Pin, err error) *MockMessageService_PinMessage_Call {
+	_c.Call.Return(pin, err)
+	return _c
+}
+
+func (_c *MockMessageService_PinMessage_Call) RunAndReturn(run func(userID string, messageID string) (*domain.Pin, error)) *MockMessageService_PinMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveReaction provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) RemoveReaction(userID string, messageID string, emoji string) error {
+	ret := _mock.Called(userID, messageID, emoji)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveReaction")
 	}
 
 	var r0 error
 	if returnFunc, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = returnFunc(senderID, receiverID, content)
+		r0 = returnFunc(userID, messageID, emoji)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockMessageService_CreateMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateMessage'
-type MockMessageService_CreateMessage_Call struct {
+// MockMessageService_RemoveReaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveReaction'
+type MockMessageService_RemoveReaction_Call struct {
 	*mock.Call
 }
 
-// CreateMessage is a helper method to define mock.On call
-//   - senderID
-//   - receiverID
-//   - content
-func (_e *MockMessageService_Expecter) CreateMessage(senderID interface{}, receiverID interface{}, content interface{}) *MockMessageService_CreateMessage_Call {
-	return &MockMessageService_CreateMessage_Call{Call: _e.mock.On("CreateMessage", senderID, receiverID, content)}
+// RemoveReaction is a helper method to define mock.On call
+//   - userID
+//   - messageID
+//   - emoji
+func (_e *MockMessageService_Expecter) RemoveReaction(userID interface{}, messageID interface{}, emoji interface{}) *MockMessageService_RemoveReaction_Call {
+	return &MockMessageService_RemoveReaction_Call{Call: _e.mock.On("RemoveReaction", userID, messageID, emoji)}
 }
 
-func (_c *MockMessageService_CreateMessage_Call) Run(run func(senderID string, receiverID string, content string)) *MockMessageService_CreateMessage_Call {
+func (_c *MockMessageService_RemoveReaction_Call) Run(run func(userID string, messageID string, emoji string)) *MockMessageService_RemoveReaction_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(string), args[1].(string), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *MockMessageService_CreateMessage_Call) Return(err error) *MockMessageService_CreateMessage_Call {
+func (_c *MockMessageService_RemoveReaction_Call) Return(err error) *MockMessageService_RemoveReaction_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockMessageService_CreateMessage_Call) RunAndReturn(run func(senderID string, receiverID string, content string) error) *MockMessageService_CreateMessage_Call {
+func (_c *MockMessageService_RemoveReaction_Call) RunAndReturn(run func(userID string, messageID string, emoji string) error) *MockMessageService_RemoveReaction_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetMessagesByReceiver provides a mock function for the type MockMessageService
-func (_mock *MockMessageService) GetMessagesByReceiver(receiverID string, limit int, offset int) ([]*domain.Message, error) {
-	ret := _mock.Called(receiverID, limit, offset)
+// RescheduleMessage provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) RescheduleMessage(userID string, scheduledID string, sendAt time.Time) (*domain.ScheduledMessage, error) {
+	ret := _mock.Called(userID, scheduledID, sendAt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetMessagesByReceiver")
+		panic("no return value specified for RescheduleMessage")
+	}
+
+	var r0 *domain.ScheduledMessage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, time.Time) (*domain.ScheduledMessage, error)); ok {
+		return returnFunc(userID, scheduledID, sendAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, time.Time) *domain.ScheduledMessage); ok {
+		r0 = returnFunc(userID, scheduledID, sendAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ScheduledMessage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, time.Time) error); ok {
+		r1 = returnFunc(userID, scheduledID, sendAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_RescheduleMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RescheduleMessage'
+type MockMessageService_RescheduleMessage_Call struct {
+	*mock.Call
+}
+
+// RescheduleMessage is a helper method to define mock.On call
+//   - userID
+//   - scheduledID
+//   - sendAt
+func (_e *MockMessageService_Expecter) RescheduleMessage(userID interface{}, scheduledID interface{}, sendAt interface{}) *MockMessageService_RescheduleMessage_Call {
+	return &MockMessageService_RescheduleMessage_Call{Call: _e.mock.On("RescheduleMessage", userID, scheduledID, sendAt)}
+}
+
+func (_c *MockMessageService_RescheduleMessage_Call) Run(run func(userID string, scheduledID string, sendAt time.Time)) *MockMessageService_RescheduleMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_RescheduleMessage_Call) Return(scheduledMessage *domain.ScheduledMessage, err error) *MockMessageService_RescheduleMessage_Call {
+	_c.Call.Return(scheduledMessage, err)
+	return _c
+}
+
+func (_c *MockMessageService_RescheduleMessage_Call) RunAndReturn(run func(userID string, scheduledID string, sendAt time.Time) (*domain.ScheduledMessage, error)) *MockMessageService_RescheduleMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ScheduleMessage provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) ScheduleMessage(senderID string, draft ports.MessageDraft, sendAt time.Time) (*domain.ScheduledMessage, error) {
+	ret := _mock.Called(senderID, draft, sendAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScheduleMessage")
+	}
+
+	var r0 *domain.ScheduledMessage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, ports.MessageDraft, time.Time) (*domain.ScheduledMessage, error)); ok {
+		return returnFunc(senderID, draft, sendAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, ports.MessageDraft, time.Time) *domain.ScheduledMessage); ok {
+		r0 = returnFunc(senderID, draft, sendAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ScheduledMessage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, ports.MessageDraft, time.Time) error); ok {
+		r1 = returnFunc(senderID, draft, sendAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_ScheduleMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScheduleMessage'
+type MockMessageService_ScheduleMessage_Call struct {
+	*mock.Call
+}
+
+// ScheduleMessage is a helper method to define mock.On call
+//   - senderID
+//   - draft
+//   - sendAt
+func (_e *MockMessageService_Expecter) ScheduleMessage(senderID interface{}, draft interface{}, sendAt interface{}) *MockMessageService_ScheduleMessage_Call {
+	return &MockMessageService_ScheduleMessage_Call{Call: _e.mock.On("ScheduleMessage", senderID, draft, sendAt)}
+}
+
+func (_c *MockMessageService_ScheduleMessage_Call) Run(run func(senderID string, draft ports.MessageDraft, sendAt time.Time)) *MockMessageService_ScheduleMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(ports.MessageDraft), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_ScheduleMessage_Call) Return(scheduledMessage *domain.ScheduledMessage, err error) *MockMessageService_ScheduleMessage_Call {
+	_c.Call.Return(scheduledMessage, err)
+	return _c
+}
+
+func (_c *MockMessageService_ScheduleMessage_Call) RunAndReturn(run func(senderID string, draft ports.MessageDraft, sendAt time.Time) (*domain.ScheduledMessage, error)) *MockMessageService_ScheduleMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SearchMessages provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) SearchMessages(userID string, search ports.MessageSearch) ([]*domain.Message, error) {
+	ret := _mock.Called(userID, search)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchMessages")
 	}
 
 	var r0 []*domain.Message
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string, int, int) ([]*domain.Message, error)); ok {
-		return returnFunc(receiverID, limit, offset)
+	if returnFunc, ok := ret.Get(0).(func(string, ports.MessageSearch) ([]*domain.Message, error)); ok {
+		return returnFunc(userID, search)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string, int, int) []*domain.Message); ok {
-		r0 = returnFunc(receiverID, limit, offset)
+	if returnFunc, ok := ret.Get(0).(func(string, ports.MessageSearch) []*domain.Message); ok {
+		r0 = returnFunc(userID, search)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*domain.Message)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(string, int, int) error); ok {
-		r1 = returnFunc(receiverID, limit, offset)
+	if returnFunc, ok := ret.Get(1).(func(string, ports.MessageSearch) error); ok {
+		r1 = returnFunc(userID, search)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockMessageService_GetMessagesByReceiver_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessagesByReceiver'
-type MockMessageService_GetMessagesByReceiver_Call struct {
+// MockMessageService_SearchMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchMessages'
+type MockMessageService_SearchMessages_Call struct {
 	*mock.Call
 }
 
-// GetMessagesByReceiver is a helper method to define mock.On call
-//   - receiverID
-//   - limit
-//   - offset
-func (_e *MockMessageService_Expecter) GetMessagesByReceiver(receiverID interface{}, limit interface{}, offset interface{}) *MockMessageService_GetMessagesByReceiver_Call {
-	return &MockMessageService_GetMessagesByReceiver_Call{Call: _e.mock.On("GetMessagesByReceiver", receiverID, limit, offset)}
+// SearchMessages is a helper method to define mock.On call
+//   - userID
+//   - search
+func (_e *MockMessageService_Expecter) SearchMessages(userID interface{}, search interface{}) *MockMessageService_SearchMessages_Call {
+	return &MockMessageService_SearchMessages_Call{Call: _e.mock.On("SearchMessages", userID, search)}
 }
 
-func (_c *MockMessageService_GetMessagesByReceiver_Call) Run(run func(receiverID string, limit int, offset int)) *MockMessageService_GetMessagesByReceiver_Call {
+func (_c *MockMessageService_SearchMessages_Call) Run(run func(userID string, search ports.MessageSearch)) *MockMessageService_SearchMessages_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(int), args[2].(int))
+		run(args[0].(string), args[1].(ports.MessageSearch))
 	})
 	return _c
 }
 
-func (_c *MockMessageService_GetMessagesByReceiver_Call) Return(messages []*domain.Message, err error) *MockMessageService_GetMessagesByReceiver_Call {
+func (_c *MockMessageService_SearchMessages_Call) Return(messages []*domain.Message, err error) *MockMessageService_SearchMessages_Call {
 	_c.Call.Return(messages, err)
 	return _c
 }
 
-func (_c *MockMessageService_GetMessagesByReceiver_Call) RunAndReturn(run func(receiverID string, limit int, offset int) ([]*domain.Message, error)) *MockMessageService_GetMessagesByReceiver_Call {
+func (_c *MockMessageService_SearchMessages_Call) RunAndReturn(run func(userID string, search ports.MessageSearch) ([]*domain.Message, error)) *MockMessageService_SearchMessages_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -186,3 +1170,107 @@ func (_c *MockMessageService_SetMessageStatus_Call) RunAndReturn(run func(messag
 	_c.Call.Return(run)
 	return _c
 }
+
+// SetMessageTTL provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) SetMessageTTL(userID string, conversationID string, ttl time.Duration) (*domain.Conversation, error) {
+	ret := _mock.Called(userID, conversationID, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMessageTTL")
+	}
+
+	var r0 *domain.Conversation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, time.Duration) (*domain.Conversation, error)); ok {
+		return returnFunc(userID, conversationID, ttl)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, time.Duration) *domain.Conversation); ok {
+		r0 = returnFunc(userID, conversationID, ttl)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Conversation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, time.Duration) error); ok {
+		r1 = returnFunc(userID, conversationID, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMessageService_SetMessageTTL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetMessageTTL'
+type MockMessageService_SetMessageTTL_Call struct {
+	*mock.Call
+}
+
+// SetMessageTTL is a helper method to define mock.On call
+//   - userID
+//   - conversationID
+//   - ttl
+func (_e *MockMessageService_Expecter) SetMessageTTL(userID interface{}, conversationID interface{}, ttl interface{}) *MockMessageService_SetMessageTTL_Call {
+	return &MockMessageService_SetMessageTTL_Call{Call: _e.mock.On("SetMessageTTL", userID, conversationID, ttl)}
+}
+
+func (_c *MockMessageService_SetMessageTTL_Call) Run(run func(userID string, conversationID string, ttl time.Duration)) *MockMessageService_SetMessageTTL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_SetMessageTTL_Call) Return(conversation *domain.Conversation, err error) *MockMessageService_SetMessageTTL_Call {
+	_c.Call.Return(conversation, err)
+	return _c
+}
+
+func (_c *MockMessageService_SetMessageTTL_Call) RunAndReturn(run func(userID string, conversationID string, ttl time.Duration) (*domain.Conversation, error)) *MockMessageService_SetMessageTTL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnpinMessage provides a mock function for the type MockMessageService
+func (_mock *MockMessageService) UnpinMessage(userID string, messageID string) error {
+	ret := _mock.Called(userID, messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnpinMessage")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(userID, messageID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMessageService_UnpinMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnpinMessage'
+type MockMessageService_UnpinMessage_Call struct {
+	*mock.Call
+}
+
+// UnpinMessage is a helper method to define mock.On call
+//   - userID
+//   - messageID
+func (_e *MockMessageService_Expecter) UnpinMessage(userID interface{}, messageID interface{}) *MockMessageService_UnpinMessage_Call {
+	return &MockMessageService_UnpinMessage_Call{Call: _e.mock.On("UnpinMessage", userID, messageID)}
+}
+
+func (_c *MockMessageService_UnpinMessage_Call) Run(run func(userID string, messageID string)) *MockMessageService_UnpinMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMessageService_UnpinMessage_Call) Return(err error) *MockMessageService_UnpinMessage_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMessageService_UnpinMessage_Call) RunAndReturn(run func(userID string, messageID string) error) *MockMessageService_UnpinMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
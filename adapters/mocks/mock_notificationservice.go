@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	"github.com/chrikar/chatheon/domain"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -36,16 +37,16 @@ func (_m *MockNotificationService) EXPECT() *MockNotificationService_Expecter {
 }
 
 // Notify provides a mock function for the type MockNotificationService
-func (_mock *MockNotificationService) Notify(userID string, message string) error {
-	ret := _mock.Called(userID, message)
+func (_mock *MockNotificationService) Notify(notification *domain.Notification) error {
+	ret := _mock.Called(notification)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Notify")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = returnFunc(userID, message)
+	if returnFunc, ok := ret.Get(0).(func(*domain.Notification) error); ok {
+		r0 = returnFunc(notification)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -58,15 +59,14 @@ type MockNotificationService_Notify_Call struct {
 }
 
 // Notify is a helper method to define mock.On call
-//   - userID
-//   - message
-func (_e *MockNotificationService_Expecter) Notify(userID interface{}, message interface{}) *MockNotificationService_Notify_Call {
-	return &MockNotificationService_Notify_Call{Call: _e.mock.On("Notify", userID, message)}
+//   - notification
+func (_e *MockNotificationService_Expecter) Notify(notification interface{}) *MockNotificationService_Notify_Call {
+	return &MockNotificationService_Notify_Call{Call: _e.mock.On("Notify", notification)}
 }
 
-func (_c *MockNotificationService_Notify_Call) Run(run func(userID string, message string)) *MockNotificationService_Notify_Call {
+func (_c *MockNotificationService_Notify_Call) Run(run func(notification *domain.Notification)) *MockNotificationService_Notify_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(string))
+		run(args[0].(*domain.Notification))
 	})
 	return _c
 }
@@ -76,7 +76,7 @@ func (_c *MockNotificationService_Notify_Call) Return(err error) *MockNotificati
 	return _c
 }
 
-func (_c *MockNotificationService_Notify_Call) RunAndReturn(run func(userID string, message string) error) *MockNotificationService_Notify_Call {
+func (_c *MockNotificationService_Notify_Call) RunAndReturn(run func(notification *domain.Notification) error) *MockNotificationService_Notify_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"github.com/chrikar/chatheon/domain"
+	"github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -81,6 +82,62 @@ func (_c *MockUserRepository_Create_Call) RunAndReturn(run func(user *domain.Use
 	return _c
 }
 
+// FindByID provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) FindByID(id uuid.UUID) (*domain.User, error) {
+	ret := _mock.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID) (*domain.User, error)); ok {
+		return returnFunc(id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID) *domain.User); ok {
+		r0 = returnFunc(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(uuid.UUID) error); ok {
+		r1 = returnFunc(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_FindByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByID'
+type MockUserRepository_FindByID_Call struct {
+	*mock.Call
+}
+
+// FindByID is a helper method to define mock.On call
+//   - id
+func (_e *MockUserRepository_Expecter) FindByID(id interface{}) *MockUserRepository_FindByID_Call {
+	return &MockUserRepository_FindByID_Call{Call: _e.mock.On("FindByID", id)}
+}
+
+func (_c *MockUserRepository_FindByID_Call) Run(run func(id uuid.UUID)) *MockUserRepository_FindByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_FindByID_Call) Return(user *domain.User, err error) *MockUserRepository_FindByID_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockUserRepository_FindByID_Call) RunAndReturn(run func(id uuid.UUID) (*domain.User, error)) *MockUserRepository_FindByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindByUsername provides a mock function for the type MockUserRepository
 func (_mock *MockUserRepository) FindByUsername(username string) (*domain.User, error) {
 	ret := _mock.Called(username)
@@ -136,3 +193,199 @@ func (_c *MockUserRepository_FindByUsername_Call) RunAndReturn(run func(username
 	_c.Call.Return(run)
 	return _c
 }
+
+// Search provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) Search(query string, exclude []string, limit int, offset int) ([]*domain.User, error) {
+	ret := _mock.Called(query, exclude, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []*domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, []string, int, int) ([]*domain.User, error)); ok {
+		return returnFunc(query, exclude, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, []string, int, int) []*domain.User); ok {
+		r0 = returnFunc(query, exclude, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, []string, int, int) error); ok {
+		r1 = returnFunc(query, exclude, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_Search_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Search'
+type MockUserRepository_Search_Call struct {
+	*mock.Call
+}
+
+// Search is a helper method to define mock.On call
+//   - query
+//   - exclude
+//   - limit
+//   - offset
+func (_e *MockUserRepository_Expecter) Search(query interface{}, exclude interface{}, limit interface{}, offset interface{}) *MockUserRepository_Search_Call {
+	return &MockUserRepository_Search_Call{Call: _e.mock.On("Search", query, exclude, limit, offset)}
+}
+
+func (_c *MockUserRepository_Search_Call) Run(run func(query string, exclude []string, limit int, offset int)) *MockUserRepository_Search_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].([]string), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_Search_Call) Return(users []*domain.User, err error) *MockUserRepository_Search_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *MockUserRepository_Search_Call) RunAndReturn(run func(query string, exclude []string, limit int, offset int) ([]*domain.User, error)) *MockUserRepository_Search_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) Update(user *domain.User) error {
+	ret := _mock.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*domain.User) error); ok {
+		r0 = returnFunc(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockUserRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - user
+func (_e *MockUserRepository_Expecter) Update(user interface{}) *MockUserRepository_Update_Call {
+	return &MockUserRepository_Update_Call{Call: _e.mock.On("Update", user)}
+}
+
+func (_c *MockUserRepository_Update_Call) Run(run func(user *domain.User)) *MockUserRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*domain.User))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_Update_Call) Return(err error) *MockUserRepository_Update_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_Update_Call) RunAndReturn(run func(user *domain.User) error) *MockUserRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseRecoveryCode provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) UseRecoveryCode(id uuid.UUID, hash string) error {
+	ret := _mock.Called(id, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID, string) error); ok {
+		r0 = returnFunc(id, hash)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_UseRecoveryCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseRecoveryCode'
+type MockUserRepository_UseRecoveryCode_Call struct {
+	*mock.Call
+}
+
+// UseRecoveryCode is a helper method to define mock.On call
+//   - id
+//   - hash
+func (_e *MockUserRepository_Expecter) UseRecoveryCode(id interface{}, hash interface{}) *MockUserRepository_UseRecoveryCode_Call {
+	return &MockUserRepository_UseRecoveryCode_Call{Call: _e.mock.On("UseRecoveryCode", id, hash)}
+}
+
+func (_c *MockUserRepository_UseRecoveryCode_Call) Run(run func(id uuid.UUID, hash string)) *MockUserRepository_UseRecoveryCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UseRecoveryCode_Call) Return(err error) *MockUserRepository_UseRecoveryCode_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_UseRecoveryCode_Call) RunAndReturn(run func(id uuid.UUID, hash string) error) *MockUserRepository_UseRecoveryCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseTOTPCounter provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) UseTOTPCounter(id uuid.UUID, counter int64) error {
+	ret := _mock.Called(id, counter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPCounter")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(uuid.UUID, int64) error); ok {
+		r0 = returnFunc(id, counter)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserRepository_UseTOTPCounter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseTOTPCounter'
+type MockUserRepository_UseTOTPCounter_Call struct {
+	*mock.Call
+}
+
+// UseTOTPCounter is a helper method to define mock.On call
+//   - id
+//   - counter
+func (_e *MockUserRepository_Expecter) UseTOTPCounter(id interface{}, counter interface{}) *MockUserRepository_UseTOTPCounter_Call {
+	return &MockUserRepository_UseTOTPCounter_Call{Call: _e.mock.On("UseTOTPCounter", id, counter)}
+}
+
+func (_c *MockUserRepository_UseTOTPCounter_Call) Run(run func(id uuid.UUID, counter int64)) *MockUserRepository_UseTOTPCounter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uuid.UUID), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_UseTOTPCounter_Call) Return(err error) *MockUserRepository_UseTOTPCounter_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserRepository_UseTOTPCounter_Call) RunAndReturn(run func(id uuid.UUID, counter int64) error) *MockUserRepository_UseTOTPCounter_Call {
+	_c.Call.Return(run)
+	return _c
+}
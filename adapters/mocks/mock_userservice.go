@@ -5,6 +5,8 @@
 package mocks
 
 import (
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -35,26 +37,291 @@ func (_m *MockUserService) EXPECT() *MockUserService_Expecter {
 	return &MockUserService_Expecter{mock: &_m.Mock}
 }
 
-// Login provides a mock function for the type MockUserService
-func (_mock *MockUserService) Login(username string, password string) (string, error) {
-	ret := _mock.Called(username, password)
+// ChangePassword provides a mock function for the type MockUserService
+func (_mock *MockUserService) ChangePassword(username string, oldPassword string, newPassword string, code string) error {
+	ret := _mock.Called(username, oldPassword, newPassword, code)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, string, string) error); ok {
+		r0 = returnFunc(username, oldPassword, newPassword, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type MockUserService_ChangePassword_Call struct {
+	*mock.Call
+}
+
+// ChangePassword is a helper method to define mock.On call
+//   - username
+//   - oldPassword
+//   - newPassword
+//   - code
+func (_e *MockUserService_Expecter) ChangePassword(username interface{}, oldPassword interface{}, newPassword interface{}, code interface{}) *MockUserService_ChangePassword_Call {
+	return &MockUserService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", username, oldPassword, newPassword, code)}
+}
+
+func (_c *MockUserService_ChangePassword_Call) Run(run func(username string, oldPassword string, newPassword string, code string)) *MockUserService_ChangePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_ChangePassword_Call) Return(err error) *MockUserService_ChangePassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_ChangePassword_Call) RunAndReturn(run func(username string, oldPassword string, newPassword string, code string) error) *MockUserService_ChangePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompleteTOTPLogin provides a mock function for the type MockUserService
+func (_mock *MockUserService) CompleteTOTPLogin(challenge string, code string, client ports.ClientInfo) (string, error) {
+	ret := _mock.Called(challenge, code, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteTOTPLogin")
 	}
 
 	var r0 string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string, string) (string, error)); ok {
-		return returnFunc(username, password)
+	if returnFunc, ok := ret.Get(0).(func(string, string, ports.ClientInfo) (string, error)); ok {
+		return returnFunc(challenge, code, client)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string, string) string); ok {
-		r0 = returnFunc(username, password)
+	if returnFunc, ok := ret.Get(0).(func(string, string, ports.ClientInfo) string); ok {
+		r0 = returnFunc(challenge, code, client)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, ports.ClientInfo) error); ok {
+		r1 = returnFunc(challenge, code, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_CompleteTOTPLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteTOTPLogin'
+type MockUserService_CompleteTOTPLogin_Call struct {
+	*mock.Call
+}
+
+// CompleteTOTPLogin is a helper method to define mock.On call
+//   - challenge
+//   - code
+//   - client
+func (_e *MockUserService_Expecter) CompleteTOTPLogin(challenge interface{}, code interface{}, client interface{}) *MockUserService_CompleteTOTPLogin_Call {
+	return &MockUserService_CompleteTOTPLogin_Call{Call: _e.mock.On("CompleteTOTPLogin", challenge, code, client)}
+}
+
+func (_c *MockUserService_CompleteTOTPLogin_Call) Run(run func(challenge string, code string, client ports.ClientInfo)) *MockUserService_CompleteTOTPLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(ports.ClientInfo))
+	})
+	return _c
+}
+
+func (_c *MockUserService_CompleteTOTPLogin_Call) Return(s string, err error) *MockUserService_CompleteTOTPLogin_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockUserService_CompleteTOTPLogin_Call) RunAndReturn(run func(challenge string, code string, client ports.ClientInfo) (string, error)) *MockUserService_CompleteTOTPLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConfirmTOTP provides a mock function for the type MockUserService
+func (_mock *MockUserService) ConfirmTOTP(userID string, code string) ([]string, error) {
+	ret := _mock.Called(userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmTOTP")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) ([]string, error)); ok {
+		return returnFunc(userID, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string) []string); ok {
+		r0 = returnFunc(userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
 	if returnFunc, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = returnFunc(username, password)
+		r1 = returnFunc(userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_ConfirmTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmTOTP'
+type MockUserService_ConfirmTOTP_Call struct {
+	*mock.Call
+}
+
+// ConfirmTOTP is a helper method to define mock.On call
+//   - userID
+//   - code
+func (_e *MockUserService_Expecter) ConfirmTOTP(userID interface{}, code interface{}) *MockUserService_ConfirmTOTP_Call {
+	return &MockUserService_ConfirmTOTP_Call{Call: _e.mock.On("ConfirmTOTP", userID, code)}
+}
+
+func (_c *MockUserService_ConfirmTOTP_Call) Run(run func(userID string, code string)) *MockUserService_ConfirmTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_ConfirmTOTP_Call) Return(ss []string, err error) *MockUserService_ConfirmTOTP_Call {
+	_c.Call.Return(ss, err)
+	return _c
+}
+
+func (_c *MockUserService_ConfirmTOTP_Call) RunAndReturn(run func(userID string, code string) ([]string, error)) *MockUserService_ConfirmTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DisableTOTP provides a mock function for the type MockUserService
+func (_mock *MockUserService) DisableTOTP(userID string, code string) error {
+	ret := _mock.Called(userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTOTP")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_DisableTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableTOTP'
+type MockUserService_DisableTOTP_Call struct {
+	*mock.Call
+}
+
+// DisableTOTP is a helper method to define mock.On call
+//   - userID
+//   - code
+func (_e *MockUserService_Expecter) DisableTOTP(userID interface{}, code interface{}) *MockUserService_DisableTOTP_Call {
+	return &MockUserService_DisableTOTP_Call{Call: _e.mock.On("DisableTOTP", userID, code)}
+}
+
+func (_c *MockUserService_DisableTOTP_Call) Run(run func(userID string, code string)) *MockUserService_DisableTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_DisableTOTP_Call) Return(err error) *MockUserService_DisableTOTP_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_DisableTOTP_Call) RunAndReturn(run func(userID string, code string) error) *MockUserService_DisableTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnrollTOTP provides a mock function for the type MockUserService
+func (_mock *MockUserService) EnrollTOTP(userID string) (*ports.TOTPEnrollment, error) {
+	ret := _mock.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollTOTP")
+	}
+
+	var r0 *ports.TOTPEnrollment
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (*ports.TOTPEnrollment, error)); ok {
+		return returnFunc(userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) *ports.TOTPEnrollment); ok {
+		r0 = returnFunc(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ports.TOTPEnrollment)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_EnrollTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnrollTOTP'
+type MockUserService_EnrollTOTP_Call struct {
+	*mock.Call
+}
+
+// EnrollTOTP is a helper method to define mock.On call
+//   - userID
+func (_e *MockUserService_Expecter) EnrollTOTP(userID interface{}) *MockUserService_EnrollTOTP_Call {
+	return &MockUserService_EnrollTOTP_Call{Call: _e.mock.On("EnrollTOTP", userID)}
+}
+
+func (_c *MockUserService_EnrollTOTP_Call) Run(run func(userID string)) *MockUserService_EnrollTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_EnrollTOTP_Call) Return(tOTPEnrollment *ports.TOTPEnrollment, err error) *MockUserService_EnrollTOTP_Call {
+	_c.Call.Return(tOTPEnrollment, err)
+	return _c
+}
+
+func (_c *MockUserService_EnrollTOTP_Call) RunAndReturn(run func(userID string) (*ports.TOTPEnrollment, error)) *MockUserService_EnrollTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Login provides a mock function for the type MockUserService
+func (_mock *MockUserService) Login(username string, password string, client ports.ClientInfo) (*ports.LoginResult, error) {
+	ret := _mock.Called(username, password, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *ports.LoginResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, ports.ClientInfo) (*ports.LoginResult, error)); ok {
+		return returnFunc(username, password, client)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, ports.ClientInfo) *ports.LoginResult); ok {
+		r0 = returnFunc(username, password, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ports.LoginResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, ports.ClientInfo) error); ok {
+		r1 = returnFunc(username, password, client)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -69,23 +336,24 @@ type MockUserService_Login_Call struct {
 // Login is a helper method to define mock.On call
 //   - username
 //   - password
-func (_e *MockUserService_Expecter) Login(username interface{}, password interface{}) *MockUserService_Login_Call {
-	return &MockUserService_Login_Call{Call: _e.mock.On("Login", username, password)}
+//   - client
+func (_e *MockUserService_Expecter) Login(username interface{}, password interface{}, client interface{}) *MockUserService_Login_Call {
+	return &MockUserService_Login_Call{Call: _e.mock.On("Login", username, password, client)}
 }
 
-func (_c *MockUserService_Login_Call) Run(run func(username string, password string)) *MockUserService_Login_Call {
+func (_c *MockUserService_Login_Call) Run(run func(username string, password string, client ports.ClientInfo)) *MockUserService_Login_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(string))
+		run(args[0].(string), args[1].(string), args[2].(ports.ClientInfo))
 	})
 	return _c
 }
 
-func (_c *MockUserService_Login_Call) Return(s string, err error) *MockUserService_Login_Call {
-	_c.Call.Return(s, err)
+func (_c *MockUserService_Login_Call) Return(loginResult *ports.LoginResult, err error) *MockUserService_Login_Call {
+	_c.Call.Return(loginResult, err)
 	return _c
 }
 
-func (_c *MockUserService_Login_Call) RunAndReturn(run func(username string, password string) (string, error)) *MockUserService_Login_Call {
+func (_c *MockUserService_Login_Call) RunAndReturn(run func(username string, password string, client ports.ClientInfo) (*ports.LoginResult, error)) *MockUserService_Login_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -135,3 +403,154 @@ func (_c *MockUserService_Register_Call) RunAndReturn(run func(username string,
 	_c.Call.Return(run)
 	return _c
 }
+
+// SearchUsers provides a mock function for the type MockUserService
+func (_mock *MockUserService) SearchUsers(viewerID string, query string, limit int, offset int) ([]*domain.User, error) {
+	ret := _mock.Called(viewerID, query, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchUsers")
+	}
+
+	var r0 []*domain.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, string, int, int) ([]*domain.User, error)); ok {
+		return returnFunc(viewerID, query, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, string, int, int) []*domain.User); ok {
+		r0 = returnFunc(viewerID, query, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, string, int, int) error); ok {
+		r1 = returnFunc(viewerID, query, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserService_SearchUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchUsers'
+type MockUserService_SearchUsers_Call struct {
+	*mock.Call
+}
+
+// SearchUsers is a helper method to define mock.On call
+//   - viewerID
+//   - query
+//   - limit
+//   - offset
+func (_e *MockUserService_Expecter) SearchUsers(viewerID interface{}, query interface{}, limit interface{}, offset interface{}) *MockUserService_SearchUsers_Call {
+	return &MockUserService_SearchUsers_Call{Call: _e.mock.On("SearchUsers", viewerID, query, limit, offset)}
+}
+
+func (_c *MockUserService_SearchUsers_Call) Run(run func(viewerID string, query string, limit int, offset int)) *MockUserService_SearchUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockUserService_SearchUsers_Call) Return(users []*domain.User, err error) *MockUserService_SearchUsers_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *MockUserService_SearchUsers_Call) RunAndReturn(run func(viewerID string, query string, limit int, offset int) ([]*domain.User, error)) *MockUserService_SearchUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetEmail provides a mock function for the type MockUserService
+func (_mock *MockUserService) SetEmail(userID string, email string) error {
+	ret := _mock.Called(userID, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = returnFunc(userID, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_SetEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetEmail'
+type MockUserService_SetEmail_Call struct {
+	*mock.Call
+}
+
+// SetEmail is a helper method to define mock.On call
+//   - userID
+//   - email
+func (_e *MockUserService_Expecter) SetEmail(userID interface{}, email interface{}) *MockUserService_SetEmail_Call {
+	return &MockUserService_SetEmail_Call{Call: _e.mock.On("SetEmail", userID, email)}
+}
+
+func (_c *MockUserService_SetEmail_Call) Run(run func(userID string, email string)) *MockUserService_SetEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserService_SetEmail_Call) Return(err error) *MockUserService_SetEmail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_SetEmail_Call) RunAndReturn(run func(userID string, email string) error) *MockUserService_SetEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetRole provides a mock function for the type MockUserService
+func (_mock *MockUserService) SetRole(userID string, role domain.Role) error {
+	ret := _mock.Called(userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRole")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, domain.Role) error); ok {
+		r0 = returnFunc(userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockUserService_SetRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetRole'
+type MockUserService_SetRole_Call struct {
+	*mock.Call
+}
+
+// SetRole is a helper method to define mock.On call
+//   - userID
+//   - role
+func (_e *MockUserService_Expecter) SetRole(userID interface{}, role interface{}) *MockUserService_SetRole_Call {
+	return &MockUserService_SetRole_Call{Call: _e.mock.On("SetRole", userID, role)}
+}
+
+func (_c *MockUserService_SetRole_Call) Run(run func(userID string, role domain.Role)) *MockUserService_SetRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(domain.Role))
+	})
+	return _c
+}
+
+func (_c *MockUserService_SetRole_Call) Return(err error) *MockUserService_SetRole_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockUserService_SetRole_Call) RunAndReturn(run func(userID string, role domain.Role) error) *MockUserService_SetRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
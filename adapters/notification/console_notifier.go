@@ -1,6 +1,10 @@
 package notification
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/chrikar/chatheon/domain"
+)
 
 type ConsoleNotifier struct{}
 
@@ -8,7 +12,7 @@ func NewConsoleNotifier() *ConsoleNotifier {
 	return &ConsoleNotifier{}
 }
 
-func (c *ConsoleNotifier) Notify(userID, message string) error {
-	fmt.Printf("Notification to user %s: %s\n", userID, message)
+func (c *ConsoleNotifier) Notify(n *domain.Notification) error {
+	fmt.Printf("Notification to user %s [%s]: %s\n", n.UserID, n.Type, n.Body)
 	return nil
 }
@@ -0,0 +1,384 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"log"
+	"sync"
+	texttemplate "text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt.tmpl"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html.tmpl"))
+)
+
+var (
+	ErrEmailNotifierClosed = errors.New("email notifier is closed")
+	ErrEmailRateLimited    = errors.New("too many emails to this address")
+)
+
+const (
+	// snippetLength caps how much of a message an email quotes.
+	snippetLength = 200
+	// DefaultEmailRate and DefaultEmailRateWindow cap how many immediate
+	// emails one address gets. An address is stored without proof that
+	// its owner asked for mail, so this bounds what anyone can be sent.
+	DefaultEmailRate       = 10
+	DefaultEmailRateWindow = time.Hour
+)
+
+// notificationEmail is the data for the notification templates.
+type notificationEmail struct {
+	Username string
+	Summary  string
+	Quote    string
+}
+
+// digestEmail is the data for the digest templates.
+type digestEmail struct {
+	Username string
+	Count    int
+	Items    []digestEntry
+	More     int
+}
+
+type digestEntry struct {
+	Sender string
+	Body   string
+	At     string
+}
+
+// digest is what has piled up for one user since the last digest. Items
+// beyond the limit are only counted.
+type digest struct {
+	items    []*domain.Notification
+	overflow int
+}
+
+// EmailNotifier emails notifications to users who have an email address.
+// Mentions and contact requests are sent straight away by a background
+// worker. New messages are collected into a digest per user that
+// SendDigests sends, so a busy conversation costs one email per interval
+// rather than one per message. Other events aren't emailed.
+type EmailNotifier struct {
+	sender   *SMTPSender
+	users    ports.UserRepository
+	messages ports.MessageRepository
+	limit    int
+	rate     int
+	window   time.Duration
+	now      func() time.Time
+
+	outbox chan *domain.Notification
+	wg     sync.WaitGroup
+	// sent holds when each address was last emailed straight away. Only
+	// the worker touches it.
+	sent map[string][]time.Time
+
+	mu      sync.Mutex
+	closed  bool
+	pending map[string]*digest
+}
+
+var _ ports.NotificationService = (*EmailNotifier)(nil)
+
+// EmailOption configures an EmailNotifier.
+type EmailOption func(*EmailNotifier)
+
+// WithEmailMessages lets SendDigests leave out messages that were deleted,
+// or direct messages that were read, since they arrived.
+func WithEmailMessages(messages ports.MessageRepository) EmailOption {
+	return func(e *EmailNotifier) {
+		e.messages = messages
+	}
+}
+
+// WithDigestLimit sets how many messages a digest lists; the rest are
+// only counted.
+func WithDigestLimit(n int) EmailOption {
+	return func(e *EmailNotifier) {
+		e.limit = n
+	}
+}
+
+// WithEmailRate caps the immediate emails one address gets to n per
+// window. Emails over the cap are dropped; digests aren't counted.
+func WithEmailRate(n int, window time.Duration) EmailOption {
+	return func(e *EmailNotifier) {
+		e.rate = n
+		e.window = window
+	}
+}
+
+// NewEmailNotifier starts the worker for immediate emails; Close stops it.
+func NewEmailNotifier(sender *SMTPSender, users ports.UserRepository, opts ...EmailOption) *EmailNotifier {
+	e := &EmailNotifier{
+		sender:  sender,
+		users:   users,
+		limit:   50,
+		rate:    DefaultEmailRate,
+		window:  DefaultEmailRateWindow,
+		now:     time.Now,
+		outbox:  make(chan *domain.Notification, 100),
+		sent:    make(map[string][]time.Time),
+		pending: make(map[string]*digest),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.wg.Add(1)
+	go e.run()
+	return e
+}
+
+func (e *EmailNotifier) Notify(n *domain.Notification) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return ErrEmailNotifierClosed
+	}
+
+	switch {
+	case n.Priority == domain.PriorityLow:
+	case n.Type == domain.NotificationNewMessage:
+		e.queue(n)
+	case n.Type == domain.NotificationMessageDeleted:
+		e.unqueue(n.UserID, n.MessageID)
+	case n.Type == domain.NotificationMention, n.Type == domain.NotificationContactRequest:
+		c := *n
+		select {
+		case e.outbox <- &c:
+		default:
+			return errors.New("email queue full")
+		}
+	}
+	return nil
+}
+
+// Close stops accepting notifications and waits for the immediate emails
+// already queued. Pending digests are left unsent.
+func (e *EmailNotifier) Close() {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return
+	}
+	e.closed = true
+	close(e.outbox)
+	e.mu.Unlock()
+	e.wg.Wait()
+}
+
+func (e *EmailNotifier) run() {
+	defer e.wg.Done()
+	for n := range e.outbox {
+		if err := e.sendNotification(n); err != nil {
+			log.Printf("email %s to %s: %v", n.Type, n.UserID, err)
+		}
+	}
+}
+
+func (e *EmailNotifier) queue(n *domain.Notification) {
+	d := e.pending[n.UserID]
+	if d == nil {
+		d = &digest{}
+		e.pending[n.UserID] = d
+	}
+	if len(d.items) < e.limit {
+		c := *n
+		d.items = append(d.items, &c)
+	} else {
+		d.overflow++
+	}
+}
+
+func (e *EmailNotifier) unqueue(userID string, messageID uuid.UUID) {
+	d := e.pending[userID]
+	if d == nil {
+		return
+	}
+	for i, item := range d.items {
+		if item.MessageID == messageID {
+			d.items = append(d.items[:i], d.items[i+1:]...)
+			return
+		}
+	}
+}
+
+// SendDigests emails every user with unread messages waiting a digest of
+// them. Digests that fail to send are kept for the next call.
+func (e *EmailNotifier) SendDigests() error {
+	e.mu.Lock()
+	pending := e.pending
+	e.pending = make(map[string]*digest)
+	e.mu.Unlock()
+
+	var errs []error
+	for userID, d := range pending {
+		if err := e.sendDigest(userID, d); err != nil {
+			errs = append(errs, fmt.Errorf("digest for %s: %w", userID, err))
+			e.requeue(userID, d)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// requeue puts a failed digest back in front of what arrived meanwhile.
+func (e *EmailNotifier) requeue(userID string, d *digest) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if later := e.pending[userID]; later != nil {
+		d.overflow += later.overflow
+		for _, item := range later.items {
+			if len(d.items) < e.limit {
+				d.items = append(d.items, item)
+			} else {
+				d.overflow++
+			}
+		}
+	}
+	e.pending[userID] = d
+}
+
+func (e *EmailNotifier) sendNotification(n *domain.Notification) error {
+	user, err := e.recipient(n.UserID)
+	if err != nil || user == nil {
+		return err
+	}
+	if !e.allow(user.Email) {
+		return ErrEmailRateLimited
+	}
+
+	data := notificationEmail{Username: user.Username, Summary: n.Body}
+	subject := n.Body
+	if n.Type == domain.NotificationMention {
+		actor := e.username(n.ActorID)
+		subject = actor + " mentioned you"
+		data.Summary = actor + " mentioned you:"
+		data.Quote = snippet(n.Body)
+	}
+	return e.send(user.Email, subject, "notification", data)
+}
+
+// allow records an immediate email to address unless it already had its
+// share within the window.
+func (e *EmailNotifier) allow(address string) bool {
+	cutoff := e.now().Add(-e.window)
+	recent := e.sent[address][:0]
+	for _, at := range e.sent[address] {
+		if at.After(cutoff) {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= e.rate {
+		e.sent[address] = recent
+		return false
+	}
+	e.sent[address] = append(recent, e.now())
+	return true
+}
+
+func (e *EmailNotifier) sendDigest(userID string, d *digest) error {
+	items := d.items
+	if e.messages != nil {
+		items = items[:0:0]
+		for _, item := range d.items {
+			msg, err := e.messages.FindByID(item.MessageID)
+			if err != nil || msg.DeletedAt != nil {
+				continue
+			}
+			// A message's status is shared by all its recipients, so
+			// only a direct message's tells whether this user read it.
+			if msg.ConversationID == uuid.Nil && msg.Status == domain.StatusRead {
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	user, err := e.recipient(userID)
+	if err != nil || user == nil {
+		return err
+	}
+
+	data := digestEmail{Username: user.Username, Count: len(items) + d.overflow, More: d.overflow}
+	senders := make(map[string]string)
+	for _, item := range items {
+		sender, ok := senders[item.ActorID]
+		if !ok {
+			sender = e.username(item.ActorID)
+			senders[item.ActorID] = sender
+		}
+		data.Items = append(data.Items, digestEntry{
+			Sender: sender,
+			Body:   snippet(item.Body),
+			At:     item.CreatedAt.UTC().Format("Jan 2 15:04 MST"),
+		})
+	}
+	subject := fmt.Sprintf("You have %d unread messages", data.Count)
+	if data.Count == 1 {
+		subject = "You have 1 unread message"
+	}
+	return e.send(user.Email, subject, "digest", data)
+}
+
+// recipient returns the user to email, or nil if they have no address.
+func (e *EmailNotifier) recipient(userID string) (*domain.User, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, nil
+	}
+	user, err := e.users.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Email == "" || user.Disabled {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// username names a sender, falling back to their ID.
+func (e *EmailNotifier) username(userID string) string {
+	if id, err := uuid.Parse(userID); err == nil {
+		if user, err := e.users.FindByID(id); err == nil {
+			return user.Username
+		}
+	}
+	return userID
+}
+
+func (e *EmailNotifier) send(to, subject, name string, data any) error {
+	var text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt.tmpl", data); err != nil {
+		return err
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html.tmpl", data); err != nil {
+		return err
+	}
+	return e.sender.Send(&Email{To: to, Subject: subject, Text: text.String(), HTML: html.String()})
+}
+
+// snippet shortens s to snippetLength runes.
+func snippet(s string) string {
+	if utf8.RuneCountInString(s) <= snippetLength {
+		return s
+	}
+	return string([]rune(s)[:snippetLength]) + "…"
+}
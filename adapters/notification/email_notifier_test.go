@@ -0,0 +1,206 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/domain"
+)
+
+type emailFixture struct {
+	server   *smtpServer
+	users    *memory.UserRepository
+	messages *memory.MessageRepository
+	notifier *EmailNotifier
+	alice    *domain.User
+	bob      *domain.User
+	carol    *domain.User
+}
+
+func newEmailFixture(t *testing.T, opts ...EmailOption) *emailFixture {
+	server := newSMTPServer(t, nil, "", "")
+	sender, err := NewSMTPSender(SMTPConfig{Addr: server.Addr(), From: "no-reply@chatheon.test", AllowPlaintext: true})
+	assert.NoError(t, err)
+
+	f := &emailFixture{
+		server:   server,
+		users:    memory.NewUserRepository(),
+		messages: memory.NewMessageRepository(),
+		alice:    &domain.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com"},
+		bob:      &domain.User{ID: uuid.New(), Username: "bob", Email: "bob@example.com"},
+		carol:    &domain.User{ID: uuid.New(), Username: "carol"},
+	}
+	for _, u := range []*domain.User{f.alice, f.bob, f.carol} {
+		assert.NoError(t, f.users.Create(u))
+	}
+	f.notifier = NewEmailNotifier(sender, f.users, append([]EmailOption{WithEmailMessages(f.messages)}, opts...)...)
+	t.Cleanup(f.notifier.Close)
+	return f
+}
+
+// message stores a direct message from alice and returns its new-message
+// notification for userID.
+func (f *emailFixture) message(t *testing.T, userID, content string) *domain.Notification {
+	return f.send(t, &domain.Message{ID: uuid.New(), SenderID: f.alice.ID.String(), ReceiverID: userID, Content: content}, userID)
+}
+
+// send stores msg and returns its new-message notification for userID.
+func (f *emailFixture) send(t *testing.T, msg *domain.Message, userID string) *domain.Notification {
+	assert.NoError(t, f.messages.Create(msg))
+	return &domain.Notification{
+		Type:      domain.NotificationNewMessage,
+		Priority:  domain.PriorityNormal,
+		UserID:    userID,
+		ActorID:   f.alice.ID.String(),
+		MessageID: msg.ID,
+		Body:      msg.Content,
+		CreatedAt: time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func TestEmailNotifier_SendsMentionsImmediately(t *testing.T) {
+	f := newEmailFixture(t)
+
+	assert.NoError(t, f.notifier.Notify(&domain.Notification{
+		Type:     domain.NotificationMention,
+		Priority: domain.PriorityHigh,
+		UserID:   f.bob.ID.String(),
+		ActorID:  f.alice.ID.String(),
+		Body:     "@bob <b>ship</b> it",
+	}))
+	// No address, no email.
+	assert.NoError(t, f.notifier.Notify(&domain.Notification{
+		Type:     domain.NotificationContactRequest,
+		Priority: domain.PriorityNormal,
+		UserID:   f.carol.ID.String(),
+		Body:     "alice wants to add you as a contact",
+	}))
+	// Typing and edits aren't emailed.
+	assert.NoError(t, f.notifier.Notify(&domain.Notification{
+		Type: domain.NotificationTyping, Priority: domain.PriorityLow, UserID: f.bob.ID.String(),
+	}))
+	assert.NoError(t, f.notifier.Notify(&domain.Notification{
+		Type: domain.NotificationMessageEdited, Priority: domain.PriorityNormal, UserID: f.bob.ID.String(),
+	}))
+	f.notifier.Close()
+
+	received := f.server.Mail()
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, []string{"TO:<bob@example.com>"}, received[0].To)
+		subject, text, html := readMail(t, received[0].Data)
+		assert.Equal(t, "alice mentioned you", subject)
+		assert.Contains(t, text, "Hi bob,")
+		assert.Contains(t, text, "> @bob <b>ship</b> it")
+		assert.Contains(t, html, "@bob &lt;b&gt;ship&lt;/b&gt; it")
+	}
+	assert.ErrorIs(t, f.notifier.Notify(&domain.Notification{Type: domain.NotificationMention}), ErrEmailNotifierClosed)
+}
+
+func TestEmailNotifier_RateLimitsImmediateEmails(t *testing.T) {
+	f := newEmailFixture(t, WithEmailRate(2, time.Hour))
+	start := time.Now()
+	f.notifier.now = func() time.Time { return start }
+
+	for range 3 {
+		assert.NoError(t, f.notifier.Notify(&domain.Notification{
+			Type: domain.NotificationMention, Priority: domain.PriorityHigh,
+			UserID: f.bob.ID.String(), ActorID: f.alice.ID.String(), Body: "@bob",
+		}))
+	}
+	f.notifier.Close()
+	assert.Len(t, f.server.Mail(), 2)
+
+	// An hour on, the address has room again.
+	assert.False(t, f.notifier.allow("bob@example.com"))
+	f.notifier.now = func() time.Time { return start.Add(time.Hour + time.Second) }
+	assert.True(t, f.notifier.allow("bob@example.com"))
+	assert.True(t, f.notifier.allow("alice@example.com"))
+}
+
+func TestEmailNotifier_Digests(t *testing.T) {
+	f := newEmailFixture(t)
+	bob := f.bob.ID.String()
+
+	first := f.message(t, bob, "lunch?")
+	read := f.message(t, bob, "never mind")
+	deleted := f.message(t, bob, "oops")
+	for _, n := range []*domain.Notification{first, read, deleted, f.message(t, f.carol.ID.String(), "hi carol")} {
+		assert.NoError(t, f.notifier.Notify(n))
+	}
+	assert.NoError(t, f.messages.SetMessageStatus(read.MessageID, domain.StatusRead))
+	assert.NoError(t, f.notifier.Notify(&domain.Notification{
+		Type: domain.NotificationMessageDeleted, Priority: domain.PriorityNormal, UserID: bob, MessageID: deleted.MessageID,
+	}))
+	assert.Empty(t, f.server.Mail(), "messages wait for the digest")
+
+	assert.NoError(t, f.notifier.SendDigests())
+	received := f.server.Mail()
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, []string{"TO:<bob@example.com>"}, received[0].To)
+		subject, text, html := readMail(t, received[0].Data)
+		assert.Equal(t, "You have 1 unread message", subject)
+		assert.Contains(t, text, "alice, May 1 09:30 UTC:\n  lunch?")
+		assert.NotContains(t, text, "never mind")
+		assert.Contains(t, html, "<strong>alice</strong>")
+	}
+
+	// Sent digests are cleared.
+	assert.NoError(t, f.notifier.SendDigests())
+	assert.Len(t, f.server.Mail(), 1)
+}
+
+func TestEmailNotifier_DigestIgnoresSharedReadStatus(t *testing.T) {
+	f := newEmailFixture(t)
+	bob := f.bob.ID.String()
+
+	// In a conversation one participant reading a message marks it read
+	// for everyone, so the status says nothing about bob.
+	msg := &domain.Message{ID: uuid.New(), SenderID: f.alice.ID.String(), ConversationID: uuid.New(), Content: "standup?"}
+	assert.NoError(t, f.notifier.Notify(f.send(t, msg, bob)))
+	assert.NoError(t, f.messages.SetMessageStatus(msg.ID, domain.StatusRead))
+
+	assert.NoError(t, f.notifier.SendDigests())
+	received := f.server.Mail()
+	if assert.Len(t, received, 1) {
+		_, text, _ := readMail(t, received[0].Data)
+		assert.Contains(t, text, "standup?")
+	}
+}
+
+func TestEmailNotifier_DigestLimit(t *testing.T) {
+	f := newEmailFixture(t, WithDigestLimit(2))
+	bob := f.bob.ID.String()
+
+	for _, content := range []string{"one", "two", "three", "four"} {
+		assert.NoError(t, f.notifier.Notify(f.message(t, bob, content)))
+	}
+	assert.NoError(t, f.notifier.SendDigests())
+
+	received := f.server.Mail()
+	if assert.Len(t, received, 1) {
+		subject, text, _ := readMail(t, received[0].Data)
+		assert.Equal(t, "You have 4 unread messages", subject)
+		assert.Contains(t, text, "two")
+		assert.NotContains(t, text, "three")
+		assert.Contains(t, text, "...and 2 more.")
+	}
+}
+
+func TestEmailNotifier_KeepsDigestWhenSendFails(t *testing.T) {
+	f := newEmailFixture(t)
+	bob := f.bob.ID.String()
+	assert.NoError(t, f.notifier.Notify(f.message(t, bob, "lunch?")))
+
+	f.server.ln.Close()
+	assert.Error(t, f.notifier.SendDigests())
+
+	f.notifier.mu.Lock()
+	defer f.notifier.mu.Unlock()
+	if assert.Contains(t, f.notifier.pending, bob) {
+		assert.Len(t, f.notifier.pending[bob].items, 1)
+	}
+}
@@ -0,0 +1,22 @@
+package notification
+
+import (
+	"errors"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+// MultiNotifier sends every notification through each of its notifiers,
+// even if some of them fail.
+type MultiNotifier []ports.NotificationService
+
+func (m MultiNotifier) Notify(n *domain.Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,181 @@
+package notification
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrSTARTTLSRequired = errors.New("smtp server does not offer STARTTLS")
+
+// SMTPConfig describes the mail server to relay through.
+type SMTPConfig struct {
+	// Addr is the server's host:port, usually port 587.
+	Addr string
+	// Username and Password are sent with AUTH PLAIN when Username is set,
+	// and only over TLS unless the server is on localhost.
+	Username string
+	Password string
+	// From is the sender, optionally with a display name.
+	From string
+	// AllowPlaintext lets mail go out over connections that can't be
+	// upgraded with STARTTLS. Only meant for local relays.
+	AllowPlaintext bool
+	// TLSConfig overrides the STARTTLS settings. ServerName defaults to
+	// the host in Addr.
+	TLSConfig *tls.Config
+	// Timeout bounds a whole delivery. Zero means 30 seconds.
+	Timeout time.Duration
+}
+
+// Email is a message with plain-text and HTML bodies.
+type Email struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// SMTPSender delivers emails over SMTP, one connection per email.
+type SMTPSender struct {
+	cfg  SMTPConfig
+	host string
+	from *mail.Address
+}
+
+func NewSMTPSender(cfg SMTPConfig) (*SMTPSender, error) {
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("smtp address: %w", err)
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("smtp sender: %w", err)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &SMTPSender{cfg: cfg, host: host, from: from}, nil
+}
+
+func (s *SMTPSender) Send(e *Email) error {
+	msg, err := s.compose(e, time.Now())
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", s.cfg.Addr, s.cfg.Timeout)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(s.cfg.Timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(s.tlsConfig()); err != nil {
+			return err
+		}
+	} else if !s.cfg.AllowPlaintext {
+		return ErrSTARTTLSRequired
+	}
+	if s.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(e.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (s *SMTPSender) tlsConfig() *tls.Config {
+	cfg := &tls.Config{}
+	if s.cfg.TLSConfig != nil {
+		cfg = s.cfg.TLSConfig.Clone()
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = s.host
+	}
+	return cfg
+}
+
+// compose renders e as a multipart/alternative message, plain text first
+// so that clients prefer the HTML part.
+func (s *SMTPSender) compose(e *Email, now time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", e.Text},
+		{"text/html; charset=utf-8", e.HTML},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	mailDomain := s.from.Address[strings.LastIndex(s.from.Address, "@")+1:]
+	var msg bytes.Buffer
+	for _, h := range [][2]string{
+		{"From", s.from.String()},
+		{"To", e.To},
+		{"Subject", mime.QEncoding.Encode("utf-8", e.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", "<" + uuid.NewString() + "@" + mailDomain + ">"},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `multipart/alternative; boundary="` + mw.Boundary() + `"`},
+	} {
+		fmt.Fprintf(&msg, "%s: %s\r\n", h[0], h[1])
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
@@ -0,0 +1,284 @@
+package notification
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// receivedMail is one message accepted by smtpServer.
+type receivedMail struct {
+	From   string
+	To     []string
+	Data   []byte
+	TLS    bool
+	Authed bool
+}
+
+// smtpServer is a minimal in-process SMTP server. It offers STARTTLS when
+// it has a TLS config and requires AUTH PLAIN when it has a username.
+type smtpServer struct {
+	ln       net.Listener
+	tls      *tls.Config
+	username string
+	password string
+
+	mu   sync.Mutex
+	mail []receivedMail
+}
+
+func newSMTPServer(t *testing.T, tlsConfig *tls.Config, username, password string) *smtpServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{ln: ln, tls: tlsConfig, username: username, password: password}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) Addr() string {
+	return s.ln.Addr().String()
+}
+
+func (s *smtpServer) Mail() []receivedMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedMail(nil), s.mail...)
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer func() { conn.Close() }()
+	tp := textproto.NewConn(conn)
+	var (
+		current        receivedMail
+		secure, authed bool
+	)
+	_ = tp.PrintfLine("220 localhost ESMTP test")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			ext := []string{"localhost"}
+			if s.tls != nil && !secure {
+				ext = append(ext, "STARTTLS")
+			}
+			if s.username != "" {
+				ext = append(ext, "AUTH PLAIN")
+			}
+			for i, e := range ext {
+				sep := "-"
+				if i == len(ext)-1 {
+					sep = " "
+				}
+				_ = tp.PrintfLine("250%s%s", sep, e)
+			}
+		case "STARTTLS":
+			_ = tp.PrintfLine("220 ready to start TLS")
+			tlsConn := tls.Server(conn, s.tls)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, tp, secure = tlsConn, textproto.NewConn(tlsConn), true
+		case "AUTH":
+			creds, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(arg, "PLAIN "))
+			if string(creds) == "\x00"+s.username+"\x00"+s.password {
+				authed = true
+				_ = tp.PrintfLine("235 authenticated")
+			} else {
+				_ = tp.PrintfLine("535 bad credentials")
+			}
+		case "MAIL":
+			if s.username != "" && !authed {
+				_ = tp.PrintfLine("530 authentication required")
+				continue
+			}
+			current = receivedMail{From: arg, TLS: secure, Authed: authed}
+			_ = tp.PrintfLine("250 ok")
+		case "RCPT":
+			current.To = append(current.To, arg)
+			_ = tp.PrintfLine("250 ok")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			current.Data = data
+			s.mu.Lock()
+			s.mail = append(s.mail, current)
+			s.mu.Unlock()
+			_ = tp.PrintfLine("250 queued")
+		case "RSET", "NOOP":
+			_ = tp.PrintfLine("250 ok")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+// testTLS returns a server config with a self-signed certificate for
+// 127.0.0.1 and a client config that trusts it.
+func testTLS(t *testing.T) (server, client *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}},
+		&tls.Config{RootCAs: pool}
+}
+
+// readMail decodes a received message into its subject and bodies.
+func readMail(t *testing.T, data []byte) (subject, text, html string) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err = new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.NoError(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(part)
+		assert.NoError(t, err)
+		switch {
+		case strings.HasPrefix(part.Header.Get("Content-Type"), "text/plain"):
+			text = string(body)
+		case strings.HasPrefix(part.Header.Get("Content-Type"), "text/html"):
+			html = string(body)
+		}
+	}
+	return subject, text, html
+}
+
+func TestSMTPSender_STARTTLSAndAuth(t *testing.T) {
+	serverTLS, clientTLS := testTLS(t)
+	server := newSMTPServer(t, serverTLS, "chatheon", "hunter2")
+
+	sender, err := NewSMTPSender(SMTPConfig{
+		Addr:      server.Addr(),
+		Username:  "chatheon",
+		Password:  "hunter2",
+		From:      "Chatheon <no-reply@chatheon.test>",
+		TLSConfig: clientTLS,
+	})
+	assert.NoError(t, err)
+
+	err = sender.Send(&Email{
+		To:      "bob@example.com",
+		Subject: "Grüße",
+		Text:    "hello\n" + strings.Repeat("long line ", 20),
+		HTML:    "<p>hello</p>",
+	})
+	assert.NoError(t, err)
+
+	received := server.Mail()
+	if assert.Len(t, received, 1) {
+		assert.True(t, received[0].TLS)
+		assert.True(t, received[0].Authed)
+		assert.Equal(t, "FROM:<no-reply@chatheon.test>", received[0].From)
+		assert.Equal(t, []string{"TO:<bob@example.com>"}, received[0].To)
+
+		subject, text, html := readMail(t, received[0].Data)
+		assert.Equal(t, "Grüße", subject)
+		assert.Equal(t, "hello\n"+strings.Repeat("long line ", 20), text)
+		assert.Equal(t, "<p>hello</p>", html)
+	}
+}
+
+func TestSMTPSender_Plaintext(t *testing.T) {
+	server := newSMTPServer(t, nil, "", "")
+	email := &Email{To: "bob@example.com", Subject: "hi", Text: "hi", HTML: "hi"}
+
+	sender, err := NewSMTPSender(SMTPConfig{Addr: server.Addr(), From: "no-reply@chatheon.test"})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, sender.Send(email), ErrSTARTTLSRequired)
+	assert.Empty(t, server.Mail())
+
+	sender, err = NewSMTPSender(SMTPConfig{Addr: server.Addr(), From: "no-reply@chatheon.test", AllowPlaintext: true})
+	assert.NoError(t, err)
+	assert.NoError(t, sender.Send(email))
+	if assert.Len(t, server.Mail(), 1) {
+		assert.False(t, server.Mail()[0].TLS)
+	}
+}
+
+func TestSMTPSender_BadCredentials(t *testing.T) {
+	serverTLS, clientTLS := testTLS(t)
+	server := newSMTPServer(t, serverTLS, "chatheon", "hunter2")
+
+	sender, err := NewSMTPSender(SMTPConfig{
+		Addr:      server.Addr(),
+		Username:  "chatheon",
+		Password:  "wrong",
+		From:      "no-reply@chatheon.test",
+		TLSConfig: clientTLS,
+	})
+	assert.NoError(t, err)
+	assert.Error(t, sender.Send(&Email{To: "bob@example.com"}))
+	assert.Empty(t, server.Mail())
+
+	_, err = NewSMTPSender(SMTPConfig{Addr: "no-port", From: "no-reply@chatheon.test"})
+	assert.Error(t, err)
+}
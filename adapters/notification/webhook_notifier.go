@@ -0,0 +1,377 @@
+package notification
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+// Headers set on every webhook request. The signature is
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body)).
+const (
+	WebhookEventHeader     = "X-Chatheon-Event"
+	WebhookDeliveryHeader  = "X-Chatheon-Delivery"
+	WebhookTimestampHeader = "X-Chatheon-Timestamp"
+	WebhookSignatureHeader = "X-Chatheon-Signature"
+)
+
+var (
+	ErrWebhookNotifierClosed   = errors.New("webhook notifier is closed")
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	ErrStaleWebhook            = errors.New("webhook timestamp outside tolerance")
+)
+
+// WebhookEndpoint is a URL events are POSTed to, signed with Secret.
+type WebhookEndpoint struct {
+	URL    string
+	Secret string
+}
+
+// webhookEvent is the JSON body of a webhook request. ID stays the same
+// across retries so receivers can drop duplicates.
+type webhookEvent struct {
+	ID           uuid.UUID               `json:"id"`
+	Type         domain.NotificationType `json:"type"`
+	Notification domain.Notification     `json:"notification"`
+}
+
+// endpoint is a WebhookEndpoint with its queue and circuit breaker. The
+// breaker fields are only touched by the endpoint's worker.
+type endpoint struct {
+	WebhookEndpoint
+	queue     chan webhookEvent
+	failures  int // consecutive failed deliveries
+	openUntil time.Time
+}
+
+// WebhookNotifier POSTs notifications as signed JSON to a set of
+// endpoints. Each endpoint has its own queue and worker, so a slow or dead
+// endpoint delays neither the caller nor the other endpoints.
+//
+// Failed requests are retried with exponential backoff; after enough
+// consecutive failed deliveries the endpoint's circuit opens and events
+// are skipped until the cooldown ends, when one attempt is let through to
+// probe it. Every outcome is kept in a bounded delivery log.
+type WebhookNotifier struct {
+	endpoints        []*endpoint
+	client           *http.Client
+	maxAttempts      int
+	baseBackoff      time.Duration
+	maxBackoff       time.Duration
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	queueSize        int
+	now              func() time.Time
+
+	mu     sync.RWMutex // guards closed against sends on closed queues
+	closed bool
+	wg     sync.WaitGroup
+
+	logMu   sync.Mutex
+	log     []ports.WebhookDelivery // ring buffer
+	logNext int
+	logSize int
+}
+
+var (
+	_ ports.NotificationService = (*WebhookNotifier)(nil)
+	_ ports.WebhookDeliveryLog  = (*WebhookNotifier)(nil)
+)
+
+// WebhookOption configures a WebhookNotifier.
+type WebhookOption func(*WebhookNotifier)
+
+// WithWebhookClient sets the HTTP client; its Timeout bounds each attempt.
+func WithWebhookClient(client *http.Client) WebhookOption {
+	return func(w *WebhookNotifier) {
+		w.client = client
+	}
+}
+
+// WithWebhookRetries sets how many attempts a delivery gets and the backoff
+// between them, which doubles after each attempt up to maxBackoff.
+func WithWebhookRetries(maxAttempts int, baseBackoff, maxBackoff time.Duration) WebhookOption {
+	return func(w *WebhookNotifier) {
+		w.maxAttempts = maxAttempts
+		w.baseBackoff = baseBackoff
+		w.maxBackoff = maxBackoff
+	}
+}
+
+// WithWebhookBreaker opens an endpoint's circuit for cooldown after
+// threshold consecutive failed deliveries.
+func WithWebhookBreaker(threshold int, cooldown time.Duration) WebhookOption {
+	return func(w *WebhookNotifier) {
+		w.breakerThreshold = threshold
+		w.breakerCooldown = cooldown
+	}
+}
+
+// WithWebhookQueueSize sets how many events may wait per endpoint before
+// new ones are dropped.
+func WithWebhookQueueSize(n int) WebhookOption {
+	return func(w *WebhookNotifier) {
+		w.queueSize = n
+	}
+}
+
+// WithWebhookLogSize sets how many deliveries the log retains.
+func WithWebhookLogSize(n int) WebhookOption {
+	return func(w *WebhookNotifier) {
+		w.logSize = n
+	}
+}
+
+// WithWebhookClock replaces time.Now, for tests.
+func WithWebhookClock(now func() time.Time) WebhookOption {
+	return func(w *WebhookNotifier) {
+		w.now = now
+	}
+}
+
+// NewWebhookNotifier starts a worker per endpoint; Close stops them.
+func NewWebhookNotifier(endpoints []WebhookEndpoint, opts ...WebhookOption) *WebhookNotifier {
+	w := &WebhookNotifier{
+		client:           &http.Client{Timeout: 10 * time.Second},
+		maxAttempts:      5,
+		baseBackoff:      time.Second,
+		maxBackoff:       time.Minute,
+		breakerThreshold: 5,
+		breakerCooldown:  5 * time.Minute,
+		queueSize:        1000,
+		now:              time.Now,
+		logSize:          1000,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.log = make([]ports.WebhookDelivery, 0, w.logSize)
+
+	for _, e := range endpoints {
+		ep := &endpoint{WebhookEndpoint: e, queue: make(chan webhookEvent, w.queueSize)}
+		w.endpoints = append(w.endpoints, ep)
+		w.wg.Add(1)
+		go w.run(ep)
+	}
+	return w
+}
+
+// Notify queues the notification for every endpoint and returns without
+// waiting for delivery.
+func (w *WebhookNotifier) Notify(n *domain.Notification) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return ErrWebhookNotifierClosed
+	}
+
+	event := webhookEvent{ID: uuid.New(), Type: n.Type, Notification: *n}
+	for _, ep := range w.endpoints {
+		select {
+		case ep.queue <- event:
+		default:
+			w.record(ep, event, ports.DeliveryDropped, 0, 0, errors.New("queue full"))
+		}
+	}
+	return nil
+}
+
+// Close stops accepting notifications and waits until the queued ones have
+// been delivered or given up on.
+func (w *WebhookNotifier) Close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	for _, ep := range w.endpoints {
+		close(ep.queue)
+	}
+	w.mu.Unlock()
+	w.wg.Wait()
+}
+
+func (w *WebhookNotifier) run(ep *endpoint) {
+	defer w.wg.Done()
+	for event := range ep.queue {
+		w.deliver(ep, event)
+	}
+}
+
+func (w *WebhookNotifier) deliver(ep *endpoint, event webhookEvent) {
+	if w.now().Before(ep.openUntil) {
+		w.record(ep, event, ports.DeliverySkipped, 0, 0, errors.New("circuit open"))
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		w.record(ep, event, ports.DeliveryFailed, 0, 0, err)
+		return
+	}
+
+	attempts := w.maxAttempts
+	// Low-priority events are worthless once late, and a half-open circuit
+	// only gets a single probe.
+	if event.Notification.Priority == domain.PriorityLow || ep.failures >= w.breakerThreshold {
+		attempts = 1
+	}
+
+	var status int
+	attempt := 1
+	for ; ; attempt++ {
+		status, err = w.post(ep, event, body)
+		if err == nil {
+			ep.failures = 0
+			w.record(ep, event, ports.DeliverySucceeded, attempt, status, nil)
+			return
+		}
+		if !retryable(status) || attempt >= attempts {
+			break
+		}
+		time.Sleep(w.backoff(attempt))
+	}
+
+	// A 4xx means the endpoint is up but refuses the event; only count
+	// failures that suggest it is down.
+	if retryable(status) {
+		ep.failures++
+		if ep.failures >= w.breakerThreshold {
+			ep.openUntil = w.now().Add(w.breakerCooldown)
+			log.Printf("webhook %s: %d consecutive failures, pausing deliveries until %s",
+				ep.URL, ep.failures, ep.openUntil.Format(time.RFC3339))
+		}
+	}
+	w.record(ep, event, ports.DeliveryFailed, attempt, status, err)
+}
+
+// post makes one attempt, returning the response status (0 if there was
+// none) and an error unless it was 2xx.
+func (w *WebhookNotifier) post(ep *endpoint, event webhookEvent, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, ep.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	// The timestamp is signed afresh on every attempt so receivers can
+	// reject replays by age.
+	timestamp := strconv.FormatInt(w.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(event.Type))
+	req.Header.Set(WebhookDeliveryHeader, event.ID.String())
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(ep.Secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryable reports whether a failed attempt is worth repeating: no
+// response at all, a server error or rate limiting.
+func retryable(status int) bool {
+	return status == 0 || status >= 500 || status == http.StatusTooManyRequests
+}
+
+// backoff is the wait after the given attempt: baseBackoff doubled for each
+// earlier attempt, capped at maxBackoff.
+func (w *WebhookNotifier) backoff(attempt int) time.Duration {
+	d := w.baseBackoff
+	for i := 1; i < attempt && d < w.maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, w.maxBackoff)
+}
+
+func (w *WebhookNotifier) record(ep *endpoint, event webhookEvent, status ports.WebhookDeliveryStatus, attempts, code int, err error) {
+	d := ports.WebhookDelivery{
+		EventID:    event.ID,
+		URL:        ep.URL,
+		Type:       event.Type,
+		UserID:     event.Notification.UserID,
+		Status:     status,
+		Attempts:   attempts,
+		StatusCode: code,
+		At:         w.now(),
+	}
+	if err != nil {
+		d.Error = err.Error()
+	}
+
+	w.logMu.Lock()
+	defer w.logMu.Unlock()
+	if w.logSize <= 0 {
+		return
+	}
+	if len(w.log) < w.logSize {
+		w.log = append(w.log, d)
+	} else {
+		w.log[w.logNext] = d
+	}
+	w.logNext = (w.logNext + 1) % w.logSize
+}
+
+func (w *WebhookNotifier) Deliveries(limit, offset int) []ports.WebhookDelivery {
+	w.logMu.Lock()
+	defer w.logMu.Unlock()
+
+	out := []ports.WebhookDelivery{}
+	n := len(w.log)
+	for i := offset; i < n && len(out) < limit; i++ {
+		// Walk backwards from the most recent entry.
+		out = append(out, w.log[(w.logNext-1-i+2*n)%n])
+	}
+	return out
+}
+
+// SignWebhook returns the signature header value for a request body sent
+// at timestamp (Unix seconds, as sent in WebhookTimestampHeader).
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks a received webhook's signature and that its
+// timestamp is within tolerance of now. Receivers written in Go can use it
+// as-is.
+func VerifyWebhook(secret, timestamp, signature string, body []byte, now time.Time, tolerance time.Duration) error {
+	if !hmac.Equal([]byte(signature), []byte(SignWebhook(secret, timestamp, body))) {
+		return ErrInvalidWebhookSignature
+	}
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidWebhookSignature
+	}
+	age := now.Sub(time.Unix(sec, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrStaleWebhook
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package notification
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func fastRetries() WebhookOption {
+	return WithWebhookRetries(3, time.Millisecond, 4*time.Millisecond)
+}
+
+func testNotification() *domain.Notification {
+	return &domain.Notification{
+		Type:     domain.NotificationMention,
+		Priority: domain.PriorityNormal,
+		UserID:   "user2",
+		ActorID:  "user1",
+		Body:     "user1 mentioned you",
+	}
+}
+
+func TestWebhookNotifier_DeliversSignedEvent(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		body    []byte
+		headers http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ = io.ReadAll(r.Body)
+		headers = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier([]WebhookEndpoint{{URL: server.URL, Secret: "s3cret"}}, fastRetries())
+	assert.NoError(t, notifier.Notify(testNotification()))
+	notifier.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	assert.Equal(t, "message.mention", headers.Get(WebhookEventHeader))
+	assert.NoError(t, VerifyWebhook("s3cret", headers.Get(WebhookTimestampHeader),
+		headers.Get(WebhookSignatureHeader), body, time.Now(), time.Minute))
+	assert.ErrorIs(t, VerifyWebhook("wrong", headers.Get(WebhookTimestampHeader),
+		headers.Get(WebhookSignatureHeader), body, time.Now(), time.Minute), ErrInvalidWebhookSignature)
+
+	var event webhookEvent
+	assert.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, headers.Get(WebhookDeliveryHeader), event.ID.String())
+	assert.Equal(t, "user2", event.Notification.UserID)
+
+	deliveries := notifier.Deliveries(10, 0)
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, ports.DeliverySucceeded, deliveries[0].Status)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		assert.Equal(t, http.StatusOK, deliveries[0].StatusCode)
+	}
+}
+
+func TestWebhookNotifier_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	ids := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids <- r.Header.Get(WebhookDeliveryHeader)
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier([]WebhookEndpoint{{URL: server.URL}}, fastRetries())
+	assert.NoError(t, notifier.Notify(testNotification()))
+	notifier.Close()
+
+	assert.Equal(t, int32(3), calls.Load())
+	first := <-ids
+	assert.Equal(t, first, <-ids, "retries keep the delivery ID")
+	deliveries := notifier.Deliveries(10, 0)
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, ports.DeliverySucceeded, deliveries[0].Status)
+		assert.Equal(t, 3, deliveries[0].Attempts)
+	}
+}
+
+func TestWebhookNotifier_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier([]WebhookEndpoint{{URL: server.URL}}, fastRetries())
+	assert.NoError(t, notifier.Notify(testNotification()))
+	notifier.Close()
+
+	assert.Equal(t, int32(1), calls.Load())
+	deliveries := notifier.Deliveries(10, 0)
+	if assert.Len(t, deliveries, 1) {
+		assert.Equal(t, ports.DeliveryFailed, deliveries[0].Status)
+		assert.Equal(t, http.StatusBadRequest, deliveries[0].StatusCode)
+		assert.Equal(t, "unexpected status 400", deliveries[0].Error)
+	}
+}
+
+func TestWebhookNotifier_DoesNotRetryLowPriority(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier([]WebhookEndpoint{{URL: server.URL}}, fastRetries())
+	n := testNotification()
+	n.Type = domain.NotificationTyping
+	n.Priority = domain.PriorityLow
+	assert.NoError(t, notifier.Notify(n))
+	notifier.Close()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWebhookNotifier_CircuitBreaker(t *testing.T) {
+	var (
+		calls atomic.Int32
+		up    atomic.Bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !up.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+
+	notifier := NewWebhookNotifier([]WebhookEndpoint{{URL: server.URL}},
+		WithWebhookRetries(2, time.Millisecond, time.Millisecond),
+		WithWebhookBreaker(2, time.Minute),
+		WithWebhookClock(clock))
+	defer notifier.Close()
+	// Waiting for each event to be logged keeps deliveries in step with
+	// the clock.
+	send := func(want int) {
+		assert.NoError(t, notifier.Notify(testNotification()))
+		assert.Eventually(t, func() bool {
+			return len(notifier.Deliveries(100, 0)) == want
+		}, time.Second, time.Millisecond)
+	}
+
+	send(1)
+	send(2) // second consecutive failure opens the circuit
+	assert.Equal(t, int32(4), calls.Load())
+
+	send(3)
+	assert.Equal(t, int32(4), calls.Load())
+	assert.Equal(t, ports.DeliverySkipped, notifier.Deliveries(1, 0)[0].Status)
+
+	// After the cooldown a single probe is let through; it fails and the
+	// circuit opens again.
+	advance(time.Minute)
+	send(4)
+	assert.Equal(t, int32(5), calls.Load())
+	send(5)
+	assert.Equal(t, ports.DeliverySkipped, notifier.Deliveries(1, 0)[0].Status)
+
+	// A successful probe closes it.
+	up.Store(true)
+	advance(time.Minute)
+	send(6)
+	send(7)
+	assert.Equal(t, int32(7), calls.Load())
+	assert.Equal(t, ports.DeliverySucceeded, notifier.Deliveries(1, 0)[0].Status)
+}
+
+func TestWebhookNotifier_DeadEndpointDoesNotDelayOthers(t *testing.T) {
+	block := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer slow.Close()
+	delivered := make(chan struct{}, 1)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- struct{}{}
+	}))
+	defer fast.Close()
+
+	notifier := NewWebhookNotifier([]WebhookEndpoint{{URL: slow.URL}, {URL: fast.URL}}, fastRetries())
+	assert.NoError(t, notifier.Notify(testNotification()))
+
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("fast endpoint was held up by the slow one")
+	}
+	close(block)
+	notifier.Close()
+}
+
+func TestWebhookNotifier_DeliveryLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier([]WebhookEndpoint{{URL: server.URL}}, WithWebhookLogSize(2))
+	for _, user := range []string{"a", "b", "c"} {
+		n := testNotification()
+		n.UserID = user
+		assert.NoError(t, notifier.Notify(n))
+	}
+	notifier.Close()
+
+	deliveries := notifier.Deliveries(10, 0)
+	if assert.Len(t, deliveries, 2) {
+		assert.Equal(t, "c", deliveries[0].UserID)
+		assert.Equal(t, "b", deliveries[1].UserID)
+	}
+	assert.Equal(t, "b", notifier.Deliveries(1, 1)[0].UserID)
+	assert.Empty(t, notifier.Deliveries(10, 2))
+
+	assert.ErrorIs(t, notifier.Notify(testNotification()), ErrWebhookNotifierClosed)
+}
+
+func TestVerifyWebhook_RejectsStaleTimestamp(t *testing.T) {
+	body := []byte(`{}`)
+	sig := SignWebhook("s", "1700000000", body)
+	at := time.Unix(1700000000, 0)
+
+	assert.NoError(t, VerifyWebhook("s", "1700000000", sig, body, at.Add(time.Minute), 5*time.Minute))
+	assert.ErrorIs(t, VerifyWebhook("s", "1700000000", sig, body, at.Add(10*time.Minute), 5*time.Minute), ErrStaleWebhook)
+	assert.ErrorIs(t, VerifyWebhook("s", "1700000000", sig, []byte(`{"x":1}`), at, 5*time.Minute), ErrInvalidWebhookSignature)
+}
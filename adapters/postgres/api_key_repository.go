@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+const apiKeyColumns = "id, user_id, name, prefix, hash, scopes, created_at, last_used_at, revoked_at"
+
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) ports.APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) Create(k *domain.APIKey) error {
+	_, err := r.db.Exec("INSERT INTO api_keys ("+apiKeyColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		k.ID, k.UserID, k.Name, k.Prefix, k.Hash, pq.Array(scopeStrings(k.Scopes)), k.CreatedAt, k.LastUsedAt, k.RevokedAt)
+	return err
+}
+
+func (r *APIKeyRepository) FindByID(id uuid.UUID) (*domain.APIKey, error) {
+	return r.findOne("SELECT "+apiKeyColumns+" FROM api_keys WHERE id = $1", id)
+}
+
+func (r *APIKeyRepository) FindByPrefix(prefix string) (*domain.APIKey, error) {
+	return r.findOne("SELECT "+apiKeyColumns+" FROM api_keys WHERE prefix = $1", prefix)
+}
+
+func (r *APIKeyRepository) FindByUser(userID string) ([]*domain.APIKey, error) {
+	rows, err := r.db.Query("SELECT "+apiKeyColumns+" FROM api_keys WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, k)
+	}
+	return result, rows.Err()
+}
+
+func (r *APIKeyRepository) Update(k *domain.APIKey) error {
+	res, err := r.db.Exec(`UPDATE api_keys SET name = $2, scopes = $3, last_used_at = $4, revoked_at = $5 WHERE id = $1`,
+		k.ID, k.Name, pq.Array(scopeStrings(k.Scopes)), k.LastUsedAt, k.RevokedAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("API key not found")
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) findOne(query string, arg any) (*domain.APIKey, error) {
+	k, err := scanAPIKey(r.db.QueryRow(query, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("API key not found")
+	}
+	return k, err
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	var k domain.APIKey
+	var scopes []string
+	err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Hash, pq.Array(&scopes), &k.CreatedAt, &k.LastUsedAt, &k.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range scopes {
+		k.Scopes = append(k.Scopes, domain.Scope(s))
+	}
+	return &k, nil
+}
+
+func scopeStrings(scopes []domain.Scope) []string {
+	out := make([]string, len(scopes))
+	for i, s := range scopes {
+		out[i] = string(s)
+	}
+	return out
+}
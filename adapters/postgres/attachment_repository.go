@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+const attachmentColumns = "id, uploader_id, message_id, name, size, content_type, checksum, width, height, has_thumbnail, created_at"
+
+type AttachmentRepository struct {
+	db *sql.DB
+}
+
+func NewAttachmentRepository(db *sql.DB) ports.AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) Create(a *domain.Attachment) error {
+	_, err := r.db.Exec(`INSERT INTO attachments (`+attachmentColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		a.ID, a.UploaderID, a.MessageID, a.Name, a.Size, a.ContentType, a.Checksum,
+		a.Width, a.Height, a.HasThumbnail, a.CreatedAt)
+	return err
+}
+
+func (r *AttachmentRepository) FindByID(id uuid.UUID) (*domain.Attachment, error) {
+	a, err := scanAttachment(r.db.QueryRow("SELECT "+attachmentColumns+" FROM attachments WHERE id = $1", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("attachment not found")
+	}
+	return a, err
+}
+
+func (r *AttachmentRepository) FindByMessages(messageIDs []uuid.UUID) ([]*domain.Attachment, error) {
+	rows, err := r.db.Query(`SELECT `+attachmentColumns+` FROM attachments
+		WHERE message_id = ANY($1::uuid[]) ORDER BY created_at`, pq.Array(uuidStrings(messageIDs)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
+
+// AttachToMessage claims only attachments no other message has, and
+// rolls back unless it got all of them.
+func (r *AttachmentRepository) AttachToMessage(ids []uuid.UUID, messageID uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("UPDATE attachments SET message_id = $1 WHERE id = ANY($2::uuid[]) AND message_id IS NULL",
+		messageID, pq.Array(uuidStrings(ids)))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n < int64(len(ids)) {
+		return ports.ErrAttachmentSent
+	}
+	return tx.Commit()
+}
+
+func (r *AttachmentRepository) DeleteByMessages(messageIDs []uuid.UUID) error {
+	_, err := r.db.Exec("DELETE FROM attachments WHERE message_id = ANY($1::uuid[])",
+		pq.Array(uuidStrings(messageIDs)))
+	return err
+}
+
+// DeleteUnsent re-checks message_id in the outer statement, so an upload
+// claimed while the batch is picked is kept.
+func (r *AttachmentRepository) DeleteUnsent(before time.Time, keep []uuid.UUID, limit int) ([]*domain.Attachment, error) {
+	rows, err := r.db.Query(`DELETE FROM attachments WHERE id IN (
+			SELECT id FROM attachments
+			WHERE message_id IS NULL AND created_at < $1 AND NOT id = ANY($3::uuid[])
+			ORDER BY created_at LIMIT $2
+		) AND message_id IS NULL
+		RETURNING `+attachmentColumns, before, limit, pq.Array(uuidStrings(keep)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
+
+func scanAttachment(row rowScanner) (*domain.Attachment, error) {
+	var a domain.Attachment
+	err := row.Scan(&a.ID, &a.UploaderID, &a.MessageID, &a.Name, &a.Size, &a.ContentType, &a.Checksum,
+		&a.Width, &a.Height, &a.HasThumbnail, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
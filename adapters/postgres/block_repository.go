@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+type BlockRepository struct {
+	db *sql.DB
+}
+
+func NewBlockRepository(db *sql.DB) ports.BlockRepository {
+	return &BlockRepository{db: db}
+}
+
+func (r *BlockRepository) Add(block *domain.Block) error {
+	_, err := r.db.Exec(`INSERT INTO user_blocks (blocker_id, blocked_id, created_at)
+		VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		block.BlockerID, block.BlockedID, block.CreatedAt)
+	return err
+}
+
+func (r *BlockRepository) Remove(blockerID, blockedID string) error {
+	res, err := r.db.Exec("DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2",
+		blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("block not found")
+	}
+	return nil
+}
+
+func (r *BlockRepository) Exists(blockerID, blockedID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2)`,
+		blockerID, blockedID).Scan(&exists)
+	return exists, err
+}
+
+func (r *BlockRepository) FindByBlocker(blockerID string) ([]*domain.Block, error) {
+	rows, err := r.db.Query(`SELECT blocker_id, blocked_id, created_at FROM user_blocks
+		WHERE blocker_id = $1 ORDER BY created_at DESC, blocked_id`, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.Block
+	for rows.Next() {
+		var block domain.Block
+		if err := rows.Scan(&block.BlockerID, &block.BlockedID, &block.CreatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, &block)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+const contactRequestColumns = "id, from_id, to_id, status, created_at, responded_at"
+
+type ContactRepository struct {
+	db *sql.DB
+}
+
+func NewContactRepository(db *sql.DB) ports.ContactRepository {
+	return &ContactRepository{db: db}
+}
+
+func (r *ContactRepository) CreateRequest(req *domain.ContactRequest) error {
+	_, err := r.db.Exec("INSERT INTO contact_requests ("+contactRequestColumns+") VALUES ($1, $2, $3, $4, $5, $6)",
+		req.ID, req.FromID, req.ToID, req.Status, req.CreatedAt, req.RespondedAt)
+	return err
+}
+
+func (r *ContactRepository) FindRequest(id uuid.UUID) (*domain.ContactRequest, error) {
+	return scanContactRequest(r.db.QueryRow("SELECT "+contactRequestColumns+" FROM contact_requests WHERE id = $1", id))
+}
+
+func (r *ContactRepository) FindPendingRequest(userA, userB string) (*domain.ContactRequest, error) {
+	return scanContactRequest(r.db.QueryRow("SELECT "+contactRequestColumns+` FROM contact_requests
+		WHERE status = 'pending' AND ((from_id = $1 AND to_id = $2) OR (from_id = $2 AND to_id = $1))`,
+		userA, userB))
+}
+
+func (r *ContactRepository) FindPendingRequests(userID string) ([]*domain.ContactRequest, error) {
+	rows, err := r.db.Query("SELECT "+contactRequestColumns+` FROM contact_requests
+		WHERE status = 'pending' AND (from_id = $1 OR to_id = $1) ORDER BY created_at DESC, id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.ContactRequest
+	for rows.Next() {
+		req, err := scanContactRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, req)
+	}
+	return result, rows.Err()
+}
+
+func (r *ContactRepository) UpdateRequest(req *domain.ContactRequest) error {
+	res, err := r.db.Exec("UPDATE contact_requests SET status = $2, responded_at = $3 WHERE id = $1",
+		req.ID, req.Status, req.RespondedAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("contact request not found")
+	}
+	return nil
+}
+
+func (r *ContactRepository) AddContact(userA, userB string, since time.Time) error {
+	_, err := r.db.Exec(`INSERT INTO contacts (user_id, contact_id, since)
+		VALUES ($1, $2, $3), ($2, $1, $3) ON CONFLICT DO NOTHING`, userA, userB, since)
+	return err
+}
+
+func (r *ContactRepository) RemoveContact(userA, userB string) error {
+	res, err := r.db.Exec(`DELETE FROM contacts
+		WHERE (user_id = $1 AND contact_id = $2) OR (user_id = $2 AND contact_id = $1)`, userA, userB)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("contact not found")
+	}
+	return nil
+}
+
+func (r *ContactRepository) IsContact(userID, contactID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM contacts WHERE user_id = $1 AND contact_id = $2)`,
+		userID, contactID).Scan(&exists)
+	return exists, err
+}
+
+func (r *ContactRepository) FindContacts(userID string) ([]*domain.Contact, error) {
+	rows, err := r.db.Query(`SELECT user_id, contact_id, since FROM contacts
+		WHERE user_id = $1 ORDER BY since DESC, contact_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.Contact
+	for rows.Next() {
+		var c domain.Contact
+		if err := rows.Scan(&c.UserID, &c.ContactID, &c.Since); err != nil {
+			return nil, err
+		}
+		result = append(result, &c)
+	}
+	return result, rows.Err()
+}
+
+func scanContactRequest(row rowScanner) (*domain.ContactRequest, error) {
+	var req domain.ContactRequest
+	err := row.Scan(&req.ID, &req.FromID, &req.ToID, &req.Status, &req.CreatedAt, &req.RespondedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("contact request not found")
+		}
+		return nil, err
+	}
+	return &req, nil
+}
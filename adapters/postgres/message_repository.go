@@ -0,0 +1,363 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+const messageColumns = "id, sender_id, receiver_id, conversation_id, content, status, created_at, edited_at, deleted_at, reply_to_id, thread_root_id, mentions, expires_at, system, forwarded_from, quote, format, entities"
+
+// unexpired keeps disappearing messages out of reads from the moment they
+// expire until the reaper deletes them.
+const unexpired = "(m.expires_at IS NULL OR m.expires_at > now())"
+
+type MessageRepository struct {
+	db *sql.DB
+}
+
+func NewMessageRepository(db *sql.DB) ports.MessageRepository {
+	return &MessageRepository{db: db}
+}
+
+func (r *MessageRepository) Create(m *domain.Message) error {
+	m.CreatedAt = time.Now()
+	m.Status = domain.StatusSent
+	mentions, err := marshalList(m.Mentions)
+	if err != nil {
+		return err
+	}
+	entities, err := marshalList(m.Entities)
+	if err != nil {
+		return err
+	}
+	var forwardedFrom, quote any
+	if m.ForwardedFrom != nil {
+		if forwardedFrom, err = json.Marshal(m.ForwardedFrom); err != nil {
+			return err
+		}
+	}
+	if m.Quote != nil {
+		if quote, err = json.Marshal(m.Quote); err != nil {
+			return err
+		}
+	}
+	_, err = r.db.Exec("INSERT INTO messages ("+messageColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`,
+		m.ID, m.SenderID, m.ReceiverID, nullUUID(m.ConversationID), m.Content, m.Status,
+		m.CreatedAt, m.EditedAt, m.DeletedAt, m.ReplyToID, m.ThreadRootID, mentions, m.ExpiresAt, m.System,
+		forwardedFrom, quote, m.Format, entities)
+	return err
+}
+
+func (r *MessageRepository) GetMessagesBySender(senderID string) ([]*domain.Message, error) {
+	return r.query("SELECT "+messageColumns+` FROM messages m
+		WHERE sender_id = $1 AND `+unexpired+` AND NOT EXISTS (
+			SELECT 1 FROM message_hidden h WHERE h.message_id = m.id AND h.user_id = $1)
+		ORDER BY created_at, id`, senderID)
+}
+
+func (r *MessageRepository) GetMessagesByReceiver(receiverID string, limit, offset int) ([]*domain.Message, error) {
+	return r.query("SELECT "+messageColumns+` FROM messages m
+		WHERE receiver_id = $1 AND `+unexpired+` AND NOT EXISTS (
+			SELECT 1 FROM message_hidden h WHERE h.message_id = m.id AND h.user_id = $1)
+		ORDER BY created_at, id LIMIT $2 OFFSET $3`, receiverID, limit, offset)
+}
+
+func (r *MessageRepository) GetMessagesByConversation(conversationID uuid.UUID, userID string, limit, offset int) ([]*domain.Message, error) {
+	return r.query("SELECT "+messageColumns+` FROM messages m
+		WHERE conversation_id = $1 AND thread_root_id IS NULL AND `+unexpired+` AND NOT EXISTS (
+			SELECT 1 FROM message_hidden h WHERE h.message_id = m.id AND h.user_id = $2)
+		ORDER BY created_at, id LIMIT $3 OFFSET $4`, conversationID, userID, limit, offset)
+}
+
+func (r *MessageRepository) GetThreadReplies(rootID uuid.UUID, userID string, limit, offset int) ([]*domain.Message, error) {
+	return r.query("SELECT "+messageColumns+` FROM messages m
+		WHERE thread_root_id = $1 AND `+unexpired+` AND NOT EXISTS (
+			SELECT 1 FROM message_hidden h WHERE h.message_id = m.id AND h.user_id = $2)
+		ORDER BY created_at, id LIMIT $3 OFFSET $4`, rootID, userID, limit, offset)
+}
+
+func (r *MessageRepository) ThreadSummaries(rootIDs []uuid.UUID, userID string) (map[uuid.UUID]*domain.ThreadSummary, error) {
+	rows, err := r.db.Query(`SELECT m.thread_root_id,
+			count(*),
+			(array_agg(m.id ORDER BY m.created_at DESC, m.id DESC))[1],
+			max(m.created_at),
+			array_agg(DISTINCT m.sender_id),
+			count(*) FILTER (WHERE m.sender_id <> $2 AND (tr.read_at IS NULL OR m.created_at > tr.read_at))
+		FROM messages m
+		LEFT JOIN thread_reads tr ON tr.root_id = m.thread_root_id AND tr.user_id = $2
+		WHERE m.thread_root_id = ANY($1::uuid[]) AND `+unexpired+`
+		GROUP BY m.thread_root_id`, pq.Array(uuidStrings(rootIDs)), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID]*domain.ThreadSummary)
+	for rows.Next() {
+		var root uuid.UUID
+		var sum domain.ThreadSummary
+		err := rows.Scan(&root, &sum.ReplyCount, &sum.LastReplyID, &sum.LastReplyAt,
+			pq.Array(&sum.ParticipantIDs), &sum.UnreadCount)
+		if err != nil {
+			return nil, err
+		}
+		result[root] = &sum
+	}
+	return result, rows.Err()
+}
+
+func (r *MessageRepository) MarkThreadRead(rootID uuid.UUID, userID string, readAt time.Time) error {
+	_, err := r.db.Exec(`INSERT INTO thread_reads (root_id, user_id, read_at) VALUES ($1, $2, $3)
+		ON CONFLICT (root_id, user_id) DO UPDATE SET read_at = GREATEST(thread_reads.read_at, EXCLUDED.read_at)`,
+		rootID, userID, readAt)
+	return err
+}
+
+func (r *MessageRepository) GetMentions(userID string, limit, offset int) ([]*domain.Message, error) {
+	return r.query("SELECT "+messageColumns+` FROM messages m
+		WHERE mentions @> jsonb_build_array(jsonb_build_object('user_id', $1::text)) AND `+unexpired+` AND NOT EXISTS (
+			SELECT 1 FROM message_hidden h WHERE h.message_id = m.id AND h.user_id = $1)
+		ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`, userID, limit, offset)
+}
+
+func (r *MessageRepository) SetMessageStatus(id uuid.UUID, status domain.MessageStatus) error {
+	return r.exec("UPDATE messages SET status = $2 WHERE id = $1", id, status)
+}
+
+func (r *MessageRepository) FindByID(id uuid.UUID) (*domain.Message, error) {
+	m, err := scanMessage(r.db.QueryRow("SELECT "+messageColumns+" FROM messages m WHERE id = $1 AND "+unexpired, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ports.ErrMessageNotFound
+	}
+	return m, err
+}
+
+func (r *MessageRepository) Update(m *domain.Message) error {
+	mentions, err := marshalList(m.Mentions)
+	if err != nil {
+		return err
+	}
+	entities, err := marshalList(m.Entities)
+	if err != nil {
+		return err
+	}
+	return r.exec(`UPDATE messages SET content = $2, status = $3, edited_at = $4, deleted_at = $5,
+		mentions = $6, entities = $7 WHERE id = $1`, m.ID, m.Content, m.Status, m.EditedAt, m.DeletedAt, mentions, entities)
+}
+
+func (r *MessageRepository) AddRevision(rev *domain.MessageRevision) error {
+	_, err := r.db.Exec("INSERT INTO message_revisions (message_id, content, created_at) VALUES ($1, $2, $3)",
+		rev.MessageID, rev.Content, rev.CreatedAt)
+	return err
+}
+
+func (r *MessageRepository) FindRevisions(messageID uuid.UUID) ([]*domain.MessageRevision, error) {
+	rows, err := r.db.Query(`SELECT message_id, content, created_at FROM message_revisions
+		WHERE message_id = $1 ORDER BY created_at`, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.MessageRevision
+	for rows.Next() {
+		var rev domain.MessageRevision
+		if err := rows.Scan(&rev.MessageID, &rev.Content, &rev.CreatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, &rev)
+	}
+	return result, rows.Err()
+}
+
+func (r *MessageRepository) HideForUser(messageID uuid.UUID, userID string) error {
+	res, err := r.db.Exec(`INSERT INTO message_hidden (message_id, user_id)
+		SELECT id, $2 FROM messages WHERE id = $1
+		ON CONFLICT DO NOTHING`, messageID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		// Either the message is missing or it was already hidden.
+		if _, err := r.FindByID(messageID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MessageRepository) DeleteForEveryone(messageID uuid.UUID, deletedAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE messages SET content = '', mentions = '[]', entities = '[]', forwarded_from = NULL, quote = NULL,
+		deleted_at = $2 WHERE id = $1`, messageID, deletedAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ports.ErrMessageNotFound
+	}
+	if _, err := tx.Exec("DELETE FROM message_revisions WHERE message_id = $1", messageID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *MessageRepository) Delete(id uuid.UUID) error {
+	return r.exec("DELETE FROM messages WHERE id = $1", id)
+}
+
+func (r *MessageRepository) DeleteByConversation(conversationID uuid.UUID) error {
+	_, err := r.db.Exec("DELETE FROM messages WHERE conversation_id = $1", conversationID)
+	return err
+}
+
+func (r *MessageRepository) FindThreadIDs(rootID uuid.UUID) ([]uuid.UUID, error) {
+	return r.queryIDs(`SELECT id FROM messages WHERE id = $1 OR thread_root_id = $1
+		ORDER BY thread_root_id NULLS FIRST, created_at`, rootID)
+}
+
+// FindExpired returns up to limit messages that expired by now together
+// with their thread replies.
+func (r *MessageRepository) FindExpired(now time.Time, limit int) ([]uuid.UUID, error) {
+	return r.queryIDs(`WITH expired AS (
+			SELECT id FROM messages WHERE expires_at <= $1 ORDER BY expires_at LIMIT $2
+		)
+		SELECT id FROM messages
+		WHERE id IN (SELECT id FROM expired) OR thread_root_id IN (SELECT id FROM expired)`, now, limit)
+}
+
+// FindPastRetention returns a page of messages matching filter together
+// with their thread replies.
+func (r *MessageRepository) FindPastRetention(filter ports.RetentionFilter, limit, offset int) ([]*domain.Message, error) {
+	scope, arg := "(m.conversation_id IS NULL OR NOT m.conversation_id = ANY($2::uuid[]))", any(pq.Array(uuidStrings(filter.Exclude)))
+	if filter.ConversationID != uuid.Nil {
+		scope, arg = "m.conversation_id = $2", filter.ConversationID
+	}
+	return r.query(`WITH page AS (
+			SELECT m.id FROM messages m WHERE m.created_at < $1 AND `+scope+`
+			ORDER BY m.created_at, m.id LIMIT $3 OFFSET $4
+		)
+		SELECT `+messageColumns+` FROM messages m
+		WHERE m.id IN (SELECT id FROM page) OR m.thread_root_id IN (SELECT id FROM page)
+		ORDER BY m.created_at, m.id`, filter.Before, arg, limit, offset)
+}
+
+// DeleteMany removes the given messages. Rows that hang off them go with
+// them through ON DELETE CASCADE.
+func (r *MessageRepository) DeleteMany(messageIDs []uuid.UUID) error {
+	_, err := r.db.Exec("DELETE FROM messages WHERE id = ANY($1::uuid[])", pq.Array(uuidStrings(messageIDs)))
+	return err
+}
+
+func (r *MessageRepository) query(q string, args ...any) ([]*domain.Message, error) {
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.Message
+	for rows.Next() {
+		m, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
+
+func (r *MessageRepository) queryIDs(q string, args ...any) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// exec runs a statement that must touch exactly one message.
+func (r *MessageRepository) exec(q string, args ...any) error {
+	res, err := r.db.Exec(q, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ports.ErrMessageNotFound
+	}
+	return nil
+}
+
+func scanMessage(row rowScanner) (*domain.Message, error) {
+	var m domain.Message
+	var conversationID uuid.NullUUID
+	var mentions, forwardedFrom, quote, entities []byte
+	err := row.Scan(&m.ID, &m.SenderID, &m.ReceiverID, &conversationID, &m.Content, &m.Status,
+		&m.CreatedAt, &m.EditedAt, &m.DeletedAt, &m.ReplyToID, &m.ThreadRootID, &mentions,
+		&m.ExpiresAt, &m.System, &forwardedFrom, &quote, &m.Format, &entities)
+	if err != nil {
+		return nil, err
+	}
+	m.ConversationID = conversationID.UUID
+	if err := json.Unmarshal(mentions, &m.Mentions); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(entities, &m.Entities); err != nil {
+		return nil, err
+	}
+	if forwardedFrom != nil {
+		if err := json.Unmarshal(forwardedFrom, &m.ForwardedFrom); err != nil {
+			return nil, err
+		}
+	}
+	if quote != nil {
+		if err := json.Unmarshal(quote, &m.Quote); err != nil {
+			return nil, err
+		}
+	}
+	return &m, nil
+}
+
+// marshalList encodes items for a JSONB array column, never as null.
+func marshalList[T any](items []T) ([]byte, error) {
+	if items == nil {
+		items = []T{}
+	}
+	return json.Marshal(items)
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
+
+// nullUUID stores uuid.Nil as SQL NULL.
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+// MessageSearchIndex implements ports.MessageSearchIndex with a tsvector
+// column and a GIN index.
+type MessageSearchIndex struct {
+	db *sql.DB
+}
+
+func NewMessageSearchIndex(db *sql.DB) ports.MessageSearchIndex {
+	return &MessageSearchIndex{db: db}
+}
+
+func (x *MessageSearchIndex) Index(m *domain.Message) error {
+	_, err := x.db.Exec(`INSERT INTO message_search (message_id, conversation_id, sender_id, receiver_id, created_at, document)
+		VALUES ($1, $2, $3, $4, $5, to_tsvector('simple', $6))
+		ON CONFLICT (message_id) DO UPDATE SET document = EXCLUDED.document`,
+		m.ID, nullUUID(m.ConversationID), m.SenderID, m.ReceiverID, m.CreatedAt,
+		strings.Join(domain.Tokenize(m.Content), " "))
+	return err
+}
+
+func (x *MessageSearchIndex) Remove(messageID uuid.UUID) error {
+	_, err := x.db.Exec("DELETE FROM message_search WHERE message_id = $1", messageID)
+	return err
+}
+
+// Hide is a no-op: Search reads message_hidden, which MessageRepository
+// already maintains.
+func (x *MessageSearchIndex) Hide(uuid.UUID, string) error {
+	return nil
+}
+
+func (x *MessageSearchIndex) Search(q ports.SearchQuery) ([]uuid.UUID, error) {
+	tsquery := toTSQuery(q.Terms)
+	if tsquery == "" {
+		return nil, nil
+	}
+
+	args := []any{tsquery, q.ParticipantID, pq.Array(uuidStrings(q.ConversationIDs))}
+	where := []string{
+		"s.document @@ to_tsquery('simple', $1)",
+		"(s.conversation_id = ANY($3::uuid[]) OR (s.conversation_id IS NULL AND (s.sender_id = $2 OR s.receiver_id = $2)))",
+		"NOT EXISTS (SELECT 1 FROM message_hidden h WHERE h.message_id = s.message_id AND h.user_id = $2)",
+	}
+	filter := func(cond string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if q.ConversationID != uuid.Nil {
+		filter("s.conversation_id = $%d", q.ConversationID)
+	}
+	if q.SenderID != "" {
+		filter("s.sender_id = $%d", q.SenderID)
+	}
+	if !q.Since.IsZero() {
+		filter("s.created_at >= $%d", q.Since)
+	}
+	if !q.Until.IsZero() {
+		filter("s.created_at < $%d", q.Until)
+	}
+	args = append(args, q.Limit, q.Offset)
+
+	rows, err := x.db.Query(fmt.Sprintf(`SELECT s.message_id FROM message_search s
+		WHERE %s ORDER BY s.created_at DESC LIMIT $%d OFFSET $%d`,
+		strings.Join(where, " AND "), len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// toTSQuery renders terms as a tsquery: words are ANDed, phrases use the
+// <-> operator and prefixes :*. Tokenized words hold only letters and
+// digits, so they need no escaping.
+func toTSQuery(terms []domain.SearchTerm) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		words := append([]string(nil), term.Words...)
+		if term.Prefix {
+			words[len(words)-1] += ":*"
+		}
+		parts = append(parts, "("+strings.Join(words, " <-> ")+")")
+	}
+	return strings.Join(parts, " & ")
+}
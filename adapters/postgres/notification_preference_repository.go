@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+type NotificationPreferenceRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationPreferenceRepository(db *sql.DB) ports.NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+func (r *NotificationPreferenceRepository) FindSettings(userID string) (*domain.NotificationSettings, error) {
+	settings := domain.NotificationSettings{UserID: userID}
+	var dndStart, dndEnd sql.NullString
+	err := r.db.QueryRow(`SELECT level, time_zone, dnd_start, dnd_end FROM notification_settings
+		WHERE user_id = $1`, userID).Scan(&settings.Level, &settings.TimeZone, &dndStart, &dndEnd)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if dndStart.Valid && dndEnd.Valid {
+		settings.DoNotDisturb = &domain.DoNotDisturb{Start: dndStart.String, End: dndEnd.String}
+	}
+	return &settings, nil
+}
+
+func (r *NotificationPreferenceRepository) SaveSettings(settings *domain.NotificationSettings) error {
+	var dndStart, dndEnd sql.NullString
+	if dnd := settings.DoNotDisturb; dnd != nil {
+		dndStart = sql.NullString{String: dnd.Start, Valid: true}
+		dndEnd = sql.NullString{String: dnd.End, Valid: true}
+	}
+	_, err := r.db.Exec(`INSERT INTO notification_settings (user_id, level, time_zone, dnd_start, dnd_end)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET level = $2, time_zone = $3, dnd_start = $4, dnd_end = $5`,
+		settings.UserID, settings.Level, settings.TimeZone, dndStart, dndEnd)
+	return err
+}
+
+func (r *NotificationPreferenceRepository) Mute(mute *domain.ConversationMute) error {
+	_, err := r.db.Exec(`INSERT INTO conversation_mutes (user_id, conversation_id, until)
+		VALUES ($1, $2, $3) ON CONFLICT (user_id, conversation_id) DO UPDATE SET until = $3`,
+		mute.UserID, mute.ConversationID, mute.Until)
+	return err
+}
+
+func (r *NotificationPreferenceRepository) Unmute(userID string, conversationID uuid.UUID) error {
+	res, err := r.db.Exec("DELETE FROM conversation_mutes WHERE user_id = $1 AND conversation_id = $2",
+		userID, conversationID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("mute not found")
+	}
+	return nil
+}
+
+func (r *NotificationPreferenceRepository) FindMute(userID string, conversationID uuid.UUID) (*domain.ConversationMute, error) {
+	mute := domain.ConversationMute{UserID: userID, ConversationID: conversationID}
+	err := r.db.QueryRow("SELECT until FROM conversation_mutes WHERE user_id = $1 AND conversation_id = $2",
+		userID, conversationID).Scan(&mute.Until)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &mute, nil
+}
+
+func (r *NotificationPreferenceRepository) FindMutes(userID string) ([]*domain.ConversationMute, error) {
+	rows, err := r.db.Query(`SELECT conversation_id, until FROM conversation_mutes
+		WHERE user_id = $1 ORDER BY conversation_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.ConversationMute
+	for rows.Next() {
+		mute := domain.ConversationMute{UserID: userID}
+		if err := rows.Scan(&mute.ConversationID, &mute.Until); err != nil {
+			return nil, err
+		}
+		result = append(result, &mute)
+	}
+	return result, rows.Err()
+}
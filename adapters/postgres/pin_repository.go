@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+type PinRepository struct {
+	db *sql.DB
+}
+
+func NewPinRepository(db *sql.DB) ports.PinRepository {
+	return &PinRepository{db: db}
+}
+
+// Add holds a per-conversation advisory lock while it counts and
+// inserts, so two pins can't both take the last slot.
+func (r *PinRepository) Add(pin *domain.Pin, limit int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext($1::text))", pin.ConversationID); err != nil {
+		return err
+	}
+	var count int
+	var pinned bool
+	err = tx.QueryRow(`SELECT count(*), COALESCE(bool_or(message_id = $2), false) FROM message_pins
+		WHERE conversation_id = $1`, pin.ConversationID, pin.MessageID).Scan(&count, &pinned)
+	if err != nil {
+		return err
+	}
+	if pinned {
+		return nil
+	}
+	if count >= limit {
+		return ports.ErrPinLimit
+	}
+	_, err = tx.Exec(`INSERT INTO message_pins (conversation_id, message_id, pinned_by, pinned_at)
+		VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`,
+		pin.ConversationID, pin.MessageID, pin.PinnedBy, pin.PinnedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PinRepository) Remove(conversationID, messageID uuid.UUID) error {
+	res, err := r.db.Exec("DELETE FROM message_pins WHERE conversation_id = $1 AND message_id = $2",
+		conversationID, messageID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("pin not found")
+	}
+	return nil
+}
+
+func (r *PinRepository) FindByConversation(conversationID uuid.UUID) ([]*domain.Pin, error) {
+	rows, err := r.db.Query(`SELECT conversation_id, message_id, pinned_by, pinned_at FROM message_pins
+		WHERE conversation_id = $1 ORDER BY pinned_at DESC, message_id`, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.Pin
+	for rows.Next() {
+		var pin domain.Pin
+		if err := rows.Scan(&pin.ConversationID, &pin.MessageID, &pin.PinnedBy, &pin.PinnedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, &pin)
+	}
+	return result, rows.Err()
+}
+
+func (r *PinRepository) DeleteByMessages(messageIDs []uuid.UUID) error {
+	_, err := r.db.Exec("DELETE FROM message_pins WHERE message_id = ANY($1::uuid[])",
+		pq.Array(uuidStrings(messageIDs)))
+	return err
+}
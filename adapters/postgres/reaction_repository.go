@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+type ReactionRepository struct {
+	db *sql.DB
+}
+
+func NewReactionRepository(db *sql.DB) ports.ReactionRepository {
+	return &ReactionRepository{db: db}
+}
+
+func (r *ReactionRepository) Add(reaction *domain.Reaction) error {
+	_, err := r.db.Exec(`INSERT INTO message_reactions (message_id, user_id, emoji, created_at)
+		VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`,
+		reaction.MessageID, reaction.UserID, reaction.Emoji, reaction.CreatedAt)
+	return err
+}
+
+func (r *ReactionRepository) Remove(messageID uuid.UUID, userID, emoji string) error {
+	res, err := r.db.Exec("DELETE FROM message_reactions WHERE message_id = $1 AND user_id = $2 AND emoji = $3",
+		messageID, userID, emoji)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("reaction not found")
+	}
+	return nil
+}
+
+func (r *ReactionRepository) FindByMessages(messageIDs []uuid.UUID) ([]*domain.Reaction, error) {
+	rows, err := r.db.Query(`SELECT message_id, user_id, emoji, created_at FROM message_reactions
+		WHERE message_id = ANY($1::uuid[]) ORDER BY created_at`, pq.Array(uuidStrings(messageIDs)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.Reaction
+	for rows.Next() {
+		var reaction domain.Reaction
+		if err := rows.Scan(&reaction.MessageID, &reaction.UserID, &reaction.Emoji, &reaction.CreatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, &reaction)
+	}
+	return result, rows.Err()
+}
+
+func (r *ReactionRepository) DeleteByMessages(messageIDs []uuid.UUID) error {
+	_, err := r.db.Exec("DELETE FROM message_reactions WHERE message_id = ANY($1::uuid[])",
+		pq.Array(uuidStrings(messageIDs)))
+	return err
+}
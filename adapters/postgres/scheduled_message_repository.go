@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+const scheduledColumns = "id, sender_id, receiver_id, conversation_id, reply_to_id, content, attachment_ids, send_at, status, failure, created_at, claimed_until, quote_id, format"
+
+var errScheduledNotFound = errors.New("scheduled message not found")
+
+type ScheduledMessageRepository struct {
+	db *sql.DB
+}
+
+func NewScheduledMessageRepository(db *sql.DB) ports.ScheduledMessageRepository {
+	return &ScheduledMessageRepository{db: db}
+}
+
+func (r *ScheduledMessageRepository) Create(m *domain.ScheduledMessage) error {
+	_, err := r.db.Exec(`INSERT INTO scheduled_messages (`+scheduledColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		m.ID, m.SenderID, m.ReceiverID, nullUUID(m.ConversationID), m.ReplyToID, m.Content,
+		pq.Array(uuidStrings(m.AttachmentIDs)), m.SendAt, m.Status, m.Failure, m.CreatedAt, m.ClaimedUntil,
+		m.QuoteID, m.Format)
+	return err
+}
+
+func (r *ScheduledMessageRepository) FindByID(id uuid.UUID) (*domain.ScheduledMessage, error) {
+	m, err := scanScheduled(r.db.QueryRow("SELECT "+scheduledColumns+" FROM scheduled_messages WHERE id = $1", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errScheduledNotFound
+	}
+	return m, err
+}
+
+func (r *ScheduledMessageRepository) ListPending(senderID string, limit, offset int) ([]*domain.ScheduledMessage, error) {
+	return r.query(`SELECT `+scheduledColumns+` FROM scheduled_messages
+		WHERE sender_id = $1 AND status = 'pending' ORDER BY send_at LIMIT $2 OFFSET $3`,
+		senderID, limit, offset)
+}
+
+func (r *ScheduledMessageRepository) Reschedule(id uuid.UUID, sendAt, now time.Time) error {
+	return r.updateUnclaimed("UPDATE scheduled_messages SET send_at = $3", id, now, sendAt)
+}
+
+func (r *ScheduledMessageRepository) Cancel(id uuid.UUID, now time.Time) error {
+	return r.updateUnclaimed("UPDATE scheduled_messages SET status = 'canceled'", id, now)
+}
+
+func (r *ScheduledMessageRepository) updateUnclaimed(update string, id uuid.UUID, now time.Time, args ...any) error {
+	res, err := r.db.Exec(update+` WHERE id = $1 AND status = 'pending'
+		AND (claimed_until IS NULL OR claimed_until <= $2)`, append([]any{id, now}, args...)...)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ports.ErrNotPending
+	}
+	return nil
+}
+
+// ClaimDue uses SKIP LOCKED so concurrent servers split the due messages
+// between them instead of waiting on each other.
+func (r *ScheduledMessageRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]*domain.ScheduledMessage, error) {
+	return r.query(`UPDATE scheduled_messages SET claimed_until = $2
+		WHERE id IN (
+			SELECT id FROM scheduled_messages
+			WHERE status = 'pending' AND send_at <= $1
+				AND (claimed_until IS NULL OR claimed_until <= $1)
+			ORDER BY send_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+scheduledColumns,
+		now, now.Add(lease), limit)
+}
+
+func (r *ScheduledMessageRepository) PendingAttachmentIDs() ([]uuid.UUID, error) {
+	rows, err := r.db.Query(`SELECT DISTINCT unnest(attachment_ids) FROM scheduled_messages WHERE status = 'pending'`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *ScheduledMessageRepository) MarkSent(id uuid.UUID) error {
+	return r.resolve(id, domain.ScheduleSent, "")
+}
+
+func (r *ScheduledMessageRepository) MarkFailed(id uuid.UUID, reason string) error {
+	return r.resolve(id, domain.ScheduleFailed, reason)
+}
+
+func (r *ScheduledMessageRepository) resolve(id uuid.UUID, status domain.ScheduleStatus, reason string) error {
+	res, err := r.db.Exec("UPDATE scheduled_messages SET status = $2, failure = $3, claimed_until = NULL WHERE id = $1",
+		id, status, reason)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errScheduledNotFound
+	}
+	return nil
+}
+
+func (r *ScheduledMessageRepository) query(query string, args ...any) ([]*domain.ScheduledMessage, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.ScheduledMessage
+	for rows.Next() {
+		m, err := scanScheduled(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
+
+func scanScheduled(row rowScanner) (*domain.ScheduledMessage, error) {
+	var m domain.ScheduledMessage
+	var conversationID uuid.NullUUID
+	var attachmentIDs []string
+	err := row.Scan(&m.ID, &m.SenderID, &m.ReceiverID, &conversationID, &m.ReplyToID, &m.Content,
+		pq.Array(&attachmentIDs), &m.SendAt, &m.Status, &m.Failure, &m.CreatedAt, &m.ClaimedUntil,
+		&m.QuoteID, &m.Format)
+	if err != nil {
+		return nil, err
+	}
+	m.ConversationID = conversationID.UUID
+	for _, id := range attachmentIDs {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+		m.AttachmentIDs = append(m.AttachmentIDs, parsed)
+	}
+	return &m, nil
+}
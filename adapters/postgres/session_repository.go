@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+const sessionColumns = "id, user_id, device_name, user_agent, ip, created_at, last_seen_at, revoked_at"
+
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) ports.SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(s *domain.Session) error {
+	_, err := r.db.Exec("INSERT INTO sessions ("+sessionColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		s.ID, s.UserID, s.DeviceName, s.UserAgent, s.IP, s.CreatedAt, s.LastSeenAt, s.RevokedAt)
+	return err
+}
+
+func (r *SessionRepository) FindByID(id uuid.UUID) (*domain.Session, error) {
+	s, err := scanSession(r.db.QueryRow("SELECT "+sessionColumns+" FROM sessions WHERE id = $1", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("session not found")
+	}
+	return s, err
+}
+
+func (r *SessionRepository) FindByUser(userID string) ([]*domain.Session, error) {
+	rows, err := r.db.Query("SELECT "+sessionColumns+" FROM sessions WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.Session
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+func (r *SessionRepository) Update(s *domain.Session) error {
+	res, err := r.db.Exec(`UPDATE sessions SET device_name = $2, user_agent = $3, ip = $4,
+		last_seen_at = $5, revoked_at = $6 WHERE id = $1`,
+		s.ID, s.DeviceName, s.UserAgent, s.IP, s.LastSeenAt, s.RevokedAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("session not found")
+	}
+	return nil
+}
+
+func (r *SessionRepository) TouchSession(id uuid.UUID, at time.Time) error {
+	_, err := r.db.Exec("UPDATE sessions SET last_seen_at = $2 WHERE id = $1 AND revoked_at IS NULL", id, at)
+	return err
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSession(row rowScanner) (*domain.Session, error) {
+	var s domain.Session
+	err := row.Scan(&s.ID, &s.UserID, &s.DeviceName, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastSeenAt, &s.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
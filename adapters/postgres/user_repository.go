@@ -3,14 +3,18 @@ package postgres
 import (
 	"database/sql"
 	"errors"
+	"strings"
 
-	_ "github.com/lib/pq"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"github.com/chrikar/chatheon/application/ports"
 	"github.com/chrikar/chatheon/domain"
 	"github.com/chrikar/chatheon/internal/config"
 )
 
+const userColumns = "id, username, password_hash, role, disabled, password_reset_required, totp_secret, totp_enabled, totp_last_counter, recovery_code_hashes, is_bot, owner_id, hide_last_seen, contacts_only, email"
+
 type UserRepository struct {
 	db *sql.DB
 }
@@ -20,16 +24,96 @@ func NewUserRepository(db *sql.DB) ports.UserRepository {
 }
 
 func (r *UserRepository) Create(user *domain.User) error {
-	_, err := r.db.Exec("INSERT INTO users (id, username, password_hash) VALUES ($1, $2, $3)",
-		user.ID, user.Username, user.PasswordHash)
+	_, err := r.db.Exec("INSERT INTO users ("+userColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)",
+		user.ID, user.Username, user.PasswordHash, user.Role, user.Disabled, user.PasswordResetRequired,
+		user.TOTPSecret, user.TOTPEnabled, user.TOTPLastCounter, recoveryCodes(user),
+		user.IsBot, user.OwnerID, user.HideLastSeen, user.ContactsOnly, user.Email)
 	return err
 }
 
 func (r *UserRepository) FindByUsername(username string) (*domain.User, error) {
-	row := r.db.QueryRow("SELECT id, username, password_hash FROM users WHERE username = $1", username)
+	return scanUser(r.db.QueryRow("SELECT "+userColumns+" FROM users WHERE username = $1", username))
+}
+
+func (r *UserRepository) FindByID(id uuid.UUID) (*domain.User, error) {
+	return scanUser(r.db.QueryRow("SELECT "+userColumns+" FROM users WHERE id = $1", id))
+}
+
+func (r *UserRepository) Update(user *domain.User) error {
+	res, err := r.db.Exec(`UPDATE users SET username = $2, password_hash = $3, role = $4,
+		disabled = $5, password_reset_required = $6, totp_secret = $7,
+		totp_enabled = $8, totp_last_counter = $9, recovery_code_hashes = $10, hide_last_seen = $11,
+		contacts_only = $12, email = $13 WHERE id = $1`,
+		user.ID, user.Username, user.PasswordHash, user.Role, user.Disabled, user.PasswordResetRequired,
+		user.TOTPSecret, user.TOTPEnabled, user.TOTPLastCounter, recoveryCodes(user), user.HideLastSeen,
+		user.ContactsOnly, user.Email)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (r *UserRepository) UseTOTPCounter(id uuid.UUID, counter int64) error {
+	return r.use(`UPDATE users SET totp_last_counter = $2 WHERE id = $1 AND totp_last_counter < $2`, id, counter)
+}
+
+func (r *UserRepository) UseRecoveryCode(id uuid.UUID, hash string) error {
+	return r.use(`UPDATE users SET recovery_code_hashes = array_remove(recovery_code_hashes, $2)
+		WHERE id = $1 AND $2 = ANY(recovery_code_hashes)`, id, hash)
+}
+
+// use runs a conditional update that consumes a second-factor code.
+func (r *UserRepository) use(query string, args ...any) error {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ports.ErrCodeUsed
+	}
+	return nil
+}
+
+func (r *UserRepository) Search(query string, exclude []string, limit, offset int) ([]*domain.User, error) {
+	rows, err := r.db.Query("SELECT "+userColumns+` FROM users
+		WHERE username ILIKE '%' || $1 || '%' AND id::text <> ALL($2::text[])
+		ORDER BY username LIMIT $3 OFFSET $4`,
+		escapeLike(query), pq.Array(append([]string{}, exclude...)), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domain.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, user)
+	}
+	return result, rows.Err()
+}
+
+// recoveryCodes binds the user's recovery code hashes. A nil slice would
+// be sent as NULL, which the NOT NULL column rejects.
+func recoveryCodes(user *domain.User) any {
+	return pq.Array(append([]string{}, user.RecoveryCodeHashes...))
+}
+
+// escapeLike stops user input from acting as LIKE wildcards.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
 
+func scanUser(row rowScanner) (*domain.User, error) {
 	var user domain.User
-	err := row.Scan(&user.ID, &user.Username, &user.PasswordHash)
+	err := row.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.Role, &user.Disabled, &user.PasswordResetRequired,
+		&user.TOTPSecret, &user.TOTPEnabled, &user.TOTPLastCounter, pq.Array(&user.RecoveryCodeHashes),
+		&user.IsBot, &user.OwnerID, &user.HideLastSeen, &user.ContactsOnly, &user.Email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("user not found")
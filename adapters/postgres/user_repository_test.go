@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// recordingDriver is a database/sql driver that accepts every statement
+// and records the arguments it was given, so tests can check what would
+// reach Postgres.
+type recordingDriver struct {
+	mu   sync.Mutex
+	args [][]driver.Value
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return &recordingConn{d: d}, nil }
+
+func (d *recordingDriver) lastArgs() []driver.Value {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.args[len(d.args)-1]
+}
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{d: c.d}, nil
+}
+func (c *recordingConn) Close() error              { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type recordingStmt struct{ d *recordingDriver }
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.args = append(s.d.args, args)
+	return driver.RowsAffected(1), nil
+}
+func (s *recordingStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func newRecordingDB(t *testing.T) (*sql.DB, *recordingDriver) {
+	d := &recordingDriver{}
+	db := sql.OpenDB(connector{d})
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+type connector struct{ d *recordingDriver }
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c connector) Driver() driver.Driver                        { return c.d }
+
+func TestUserRepository_NilRecoveryCodesAreEmptyArrays(t *testing.T) {
+	db, d := newRecordingDB(t)
+	repo := NewUserRepository(db)
+	user := &domain.User{ID: uuid.New(), Username: "alice", Role: domain.RoleUser}
+
+	// recovery_code_hashes is the 10th column in both statements.
+	assert.NoError(t, repo.Create(user))
+	assert.Equal(t, "{}", d.lastArgs()[9])
+
+	assert.NoError(t, repo.Update(user))
+	assert.Equal(t, "{}", d.lastArgs()[9])
+
+	user.RecoveryCodeHashes = []string{"a", "b"}
+	assert.NoError(t, repo.Update(user))
+	assert.Equal(t, `{"a","b"}`, d.lastArgs()[9])
+}
@@ -0,0 +1,190 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+var ErrCannotDisableSelf = errors.New("admins cannot disable their own account")
+
+// AdminService is the application‑layer implementation
+// of ports.AdminService.
+type AdminService struct {
+	users         ports.UserRepository
+	sessions      ports.SessionRepository
+	messages      ports.MessageRepository
+	conversations ports.ConversationRepository
+	reactions     ports.ReactionRepository
+	attachments   ports.AttachmentRepository
+	blobs         ports.BlobStore
+	search        ports.MessageSearchIndex
+	pins          ports.PinRepository
+	now           func() time.Time
+}
+
+// AdminOption configures optional AdminService collaborators, which
+// deleted messages are cleaned out of.
+type AdminOption func(*AdminService)
+
+// WithAdminReactions deletes reactions along with their messages.
+func WithAdminReactions(repo ports.ReactionRepository) AdminOption {
+	return func(s *AdminService) { s.reactions = repo }
+}
+
+// WithAdminAttachments deletes attachments, and their files in blobs,
+// along with their messages.
+func WithAdminAttachments(repo ports.AttachmentRepository, blobs ports.BlobStore) AdminOption {
+	return func(s *AdminService) {
+		s.attachments = repo
+		s.blobs = blobs
+	}
+}
+
+// WithAdminSearchIndex removes deleted messages from the search index.
+func WithAdminSearchIndex(index ports.MessageSearchIndex) AdminOption {
+	return func(s *AdminService) { s.search = index }
+}
+
+// WithAdminPins unpins deleted messages.
+func WithAdminPins(repo ports.PinRepository) AdminOption {
+	return func(s *AdminService) { s.pins = repo }
+}
+
+// NewAdminService constructs an AdminService.
+func NewAdminService(
+	users ports.UserRepository,
+	sessions ports.SessionRepository,
+	messages ports.MessageRepository,
+	conversations ports.ConversationRepository,
+	opts ...AdminOption,
+) *AdminService {
+	s := &AdminService{
+		users:         users,
+		sessions:      sessions,
+		messages:      messages,
+		conversations: conversations,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ListUsers searches users by username.
+func (s *AdminService) ListUsers(query string, limit, offset int) ([]*domain.User, error) {
+	return s.users.Search(query, nil, limit, offset)
+}
+
+// DisableUser blocks logins for userID and revokes their sessions. API
+// keys are rejected by APIKeyService while the account is disabled.
+func (s *AdminService) DisableUser(actorID, userID string) error {
+	if actorID == userID {
+		return ErrCannotDisableSelf
+	}
+	user, err := s.findUser(userID)
+	if err != nil {
+		return err
+	}
+	user.Disabled = true
+	if err := s.users.Update(user); err != nil {
+		return err
+	}
+	return revokeSessions(s.sessions, userID, s.now())
+}
+
+// EnableUser lifts a previous DisableUser. Revoked sessions stay revoked.
+func (s *AdminService) EnableUser(userID string) error {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return err
+	}
+	user.Disabled = false
+	return s.users.Update(user)
+}
+
+// ForcePasswordReset logs the user out everywhere and makes Login refuse
+// them until they call ChangePassword.
+func (s *AdminService) ForcePasswordReset(userID string) error {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return err
+	}
+	user.PasswordResetRequired = true
+	if err := s.users.Update(user); err != nil {
+		return err
+	}
+	return revokeSessions(s.sessions, userID, s.now())
+}
+
+// DeleteMessage removes any message along with its thread replies and
+// everything derived from them.
+func (s *AdminService) DeleteMessage(messageID string) error {
+	id, err := uuid.Parse(messageID)
+	if err != nil {
+		return ErrMessageNotFound
+	}
+	ids, err := s.messages.FindThreadIDs(id)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return ErrMessageNotFound
+	}
+	_, err = s.purger().purge(ids, false)
+	return err
+}
+
+// DeleteConversation removes a conversation and its messages, with
+// everything derived from them.
+func (s *AdminService) DeleteConversation(conversationID string) error {
+	id, err := uuid.Parse(conversationID)
+	if err != nil {
+		return ErrConversationNotFound
+	}
+	if _, err := s.conversations.FindByID(id); err != nil {
+		return ErrConversationNotFound
+	}
+	var counts domain.PurgeCounts
+	filter := ports.RetentionFilter{Before: s.now(), ConversationID: id}
+	if err := s.purger().purgeMatching(filter, false, &counts); err != nil {
+		return err
+	}
+	// Catch messages sent while the purge ran.
+	if err := s.messages.DeleteByConversation(id); err != nil {
+		return err
+	}
+	return s.conversations.Delete(id)
+}
+
+func (s *AdminService) purger() *messagePurger {
+	return &messagePurger{
+		messages:    s.messages,
+		reactions:   s.reactions,
+		attachments: s.attachments,
+		blobs:       s.blobs,
+		search:      s.search,
+		pins:        s.pins,
+	}
+}
+
+func (s *AdminService) findUser(userID string) (*domain.User, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUserNotFound, err)
+	}
+	user, err := s.users.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// compile‑time check: ensure AdminService implements the interface
+var _ ports.AdminService = (*AdminService)(nil)
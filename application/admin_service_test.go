@@ -0,0 +1,170 @@
+package application
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+func TestAdminService_DisableAndEnableUser(t *testing.T) {
+	users := memory.NewUserRepository()
+	sessions := memory.NewSessionRepository()
+	keys := memory.NewAPIKeyRepository()
+	userSvc := NewUserService(users, auth.NewJWTManager("secret", time.Hour), sessions)
+	keySvc := NewAPIKeyService(users, keys)
+	svc := NewAdminService(users, sessions, memory.NewMessageRepository(), memory.NewConversationRepository())
+
+	assert.NoError(t, userSvc.Register("alice", "pw"))
+	alice, _ := users.FindByUsername("alice")
+	_, err := userSvc.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+	key, _, err := keySvc.CreateAPIKey(alice.ID.String(), alice.ID.String(), "ci", []domain.Scope{domain.ScopeMessagesRead})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, svc.DisableUser(alice.ID.String(), alice.ID.String()), ErrCannotDisableSelf)
+	assert.ErrorIs(t, svc.DisableUser("admin", uuid.NewString()), ErrUserNotFound)
+	assert.NoError(t, svc.DisableUser("admin", alice.ID.String()))
+
+	active, _ := sessions.FindByUser(alice.ID.String())
+	for _, s := range active {
+		assert.False(t, s.Active())
+	}
+	_, err = userSvc.Login("alice", "pw", ports.ClientInfo{})
+	assert.ErrorIs(t, err, ErrAccountDisabled)
+	_, err = keySvc.AuthenticateAPIKey(key)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	// A wrong password must not reveal that the account is disabled.
+	_, err = userSvc.Login("alice", "nope", ports.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	assert.NoError(t, svc.EnableUser(alice.ID.String()))
+	_, err = userSvc.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+	_, err = keySvc.AuthenticateAPIKey(key)
+	assert.NoError(t, err)
+}
+
+func TestAdminService_ForcePasswordReset(t *testing.T) {
+	users := memory.NewUserRepository()
+	sessions := memory.NewSessionRepository()
+	userSvc := NewUserService(users, auth.NewJWTManager("secret", time.Hour), sessions)
+	svc := NewAdminService(users, sessions, memory.NewMessageRepository(), memory.NewConversationRepository())
+
+	assert.NoError(t, userSvc.Register("bob", "old"))
+	bob, _ := users.FindByUsername("bob")
+	assert.NoError(t, svc.ForcePasswordReset(bob.ID.String()))
+
+	_, err := userSvc.Login("bob", "old", ports.ClientInfo{})
+	assert.ErrorIs(t, err, ErrPasswordResetRequired)
+
+	assert.ErrorIs(t, userSvc.ChangePassword("bob", "wrong", "new", ""), ErrInvalidCredentials)
+	assert.ErrorIs(t, userSvc.ChangePassword("bob", "old", "", ""), ErrPasswordRequired)
+	assert.NoError(t, userSvc.ChangePassword("bob", "old", "new", ""))
+
+	_, err = userSvc.Login("bob", "old", ports.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = userSvc.Login("bob", "new", ports.ClientInfo{})
+	assert.NoError(t, err)
+}
+
+func TestAdminService_DeleteContent(t *testing.T) {
+	messages := memory.NewMessageRepository()
+	conversations := memory.NewConversationRepository()
+	svc := NewAdminService(memory.NewUserRepository(), memory.NewSessionRepository(), messages, conversations)
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"a", "b"}}
+	assert.NoError(t, conversations.Create(conv))
+	inConv := &domain.Message{ID: uuid.New(), ConversationID: conv.ID, SenderID: "a", ReceiverID: "b"}
+	direct := &domain.Message{ID: uuid.New(), SenderID: "a", ReceiverID: "b"}
+	other := &domain.Message{ID: uuid.New(), SenderID: "b", ReceiverID: "a"}
+	for _, m := range []*domain.Message{inConv, direct, other} {
+		assert.NoError(t, messages.Create(m))
+	}
+
+	assert.ErrorIs(t, svc.DeleteMessage("not-a-uuid"), ErrMessageNotFound)
+	assert.ErrorIs(t, svc.DeleteMessage(uuid.NewString()), ErrMessageNotFound)
+	assert.NoError(t, svc.DeleteMessage(direct.ID.String()))
+
+	assert.ErrorIs(t, svc.DeleteConversation(uuid.NewString()), ErrConversationNotFound)
+	assert.NoError(t, svc.DeleteConversation(conv.ID.String()))
+
+	_, err := conversations.FindByID(conv.ID)
+	assert.Error(t, err)
+	remaining, _ := messages.GetMessagesBySender("a")
+	assert.Empty(t, remaining)
+	remaining, _ = messages.GetMessagesBySender("b")
+	assert.Len(t, remaining, 1)
+}
+
+func TestAdminService_DeleteCleansUp(t *testing.T) {
+	msgRepo := memory.NewMessageRepository()
+	convs := memory.NewConversationRepository()
+	reactions := memory.NewReactionRepository()
+	attRepo := memory.NewAttachmentRepository()
+	blobs := memory.NewBlobStore()
+	index := memory.NewMessageSearchIndex()
+	pins := memory.NewPinRepository()
+
+	messages := NewMessageService(msgRepo, WithConversations(convs), WithReactions(reactions),
+		WithAttachments(attRepo, blobs), WithSearchIndex(index), WithPins(pins))
+	uploads := NewAttachmentService(attRepo, msgRepo, convs, blobs)
+	svc := NewAdminService(memory.NewUserRepository(), memory.NewSessionRepository(), msgRepo, convs,
+		WithAdminReactions(reactions),
+		WithAdminAttachments(attRepo, blobs),
+		WithAdminSearchIndex(index),
+		WithAdminPins(pins))
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(conv))
+	send := func(content string) (*domain.Message, *domain.Attachment) {
+		file, err := uploads.Upload("alice", "notes.txt", strings.NewReader(content))
+		assert.NoError(t, err)
+		msg, err := messages.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(),
+			Content: content, AttachmentIDs: []string{file.ID.String()}})
+		assert.NoError(t, err)
+		return msg, file
+	}
+	gone := func(msg *domain.Message, file *domain.Attachment) {
+		_, err := blobs.Get(originalKey(file.ID))
+		assert.ErrorIs(t, err, ports.ErrBlobNotFound)
+		_, err = attRepo.FindByID(file.ID)
+		assert.Error(t, err)
+		left, err := reactions.FindByMessages([]uuid.UUID{msg.ID})
+		assert.NoError(t, err)
+		assert.Empty(t, left)
+		pinned, err := pins.FindByConversation(conv.ID)
+		assert.NoError(t, err)
+		for _, pin := range pinned {
+			assert.NotEqual(t, msg.ID, pin.MessageID)
+		}
+		hits, err := index.Search(ports.SearchQuery{Terms: domain.ParseSearchQuery(msg.Content), ParticipantID: "alice",
+			ConversationIDs: []uuid.UUID{conv.ID}, Limit: 10})
+		assert.NoError(t, err)
+		assert.Empty(t, hits)
+	}
+
+	agenda, agendaFile := send("agenda")
+	minutes, minutesFile := send("minutes")
+	for _, msg := range []*domain.Message{agenda, minutes} {
+		assert.NoError(t, messages.AddReaction("bob", msg.ID.String(), "👍"))
+		_, err := messages.PinMessage("bob", msg.ID.String())
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, svc.DeleteMessage(agenda.ID.String()))
+	gone(agenda, agendaFile)
+	_, err := blobs.Get(originalKey(minutesFile.ID))
+	assert.NoError(t, err, "other messages keep their files")
+
+	assert.NoError(t, svc.DeleteConversation(conv.ID.String()))
+	gone(minutes, minutesFile)
+}
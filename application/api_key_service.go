@@ -0,0 +1,199 @@
+package application
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+// apiKeyUsedResolution limits how often LastUsedAt is written.
+const apiKeyUsedResolution = time.Minute
+
+var (
+	ErrAPIKeyNameRequired  = errors.New("API key name cannot be empty")
+	ErrAPIKeyScopeRequired = errors.New("API key needs at least one scope")
+	ErrInvalidScope        = errors.New("unknown API key scope")
+	ErrAPIKeyNotFound      = errors.New("API key not found")
+	ErrInvalidAPIKey       = errors.New("invalid or revoked API key")
+	ErrNotKeyOwner         = errors.New("not allowed to manage keys for this user")
+)
+
+// APIKeyService is the application‑layer implementation
+// of ports.APIKeyService.
+type APIKeyService struct {
+	users ports.UserRepository
+	keys  ports.APIKeyRepository
+	now   func() time.Time
+}
+
+// NewAPIKeyService constructs an APIKeyService.
+func NewAPIKeyService(users ports.UserRepository, keys ports.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{users: users, keys: keys, now: time.Now}
+}
+
+// CreateBot registers a bot account. Bots have no password and can only
+// authenticate with API keys.
+func (s *APIKeyService) CreateBot(ownerID, username string) (*domain.User, error) {
+	if username == "" {
+		return nil, ErrUsernameRequired
+	}
+	if _, err := s.users.FindByUsername(username); err == nil {
+		return nil, ErrUsernameTaken
+	}
+
+	bot := &domain.User{
+		ID:       uuid.New(),
+		Username: username,
+		Role:     domain.RoleUser,
+		IsBot:    true,
+		OwnerID:  ownerID,
+	}
+	if err := s.users.Create(bot); err != nil {
+		return nil, err
+	}
+	return bot, nil
+}
+
+// CreateAPIKey issues a new key for userID.
+func (s *APIKeyService) CreateAPIKey(callerID, userID, name string, scopes []domain.Scope) (string, *domain.APIKey, error) {
+	if name == "" {
+		return "", nil, ErrAPIKeyNameRequired
+	}
+	if len(scopes) == 0 {
+		return "", nil, ErrAPIKeyScopeRequired
+	}
+	for _, sc := range scopes {
+		if !sc.Valid() {
+			return "", nil, fmt.Errorf("%w: %q", ErrInvalidScope, sc)
+		}
+	}
+	if err := s.authorize(callerID, userID); err != nil {
+		return "", nil, err
+	}
+
+	raw, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return "", nil, err
+	}
+	key := &domain.APIKey{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      hash,
+		Scopes:    scopes,
+		CreatedAt: s.now(),
+	}
+	if err := s.keys.Create(key); err != nil {
+		return "", nil, err
+	}
+	return raw, key, nil
+}
+
+// ListAPIKeys returns the active keys of userID.
+func (s *APIKeyService) ListAPIKeys(callerID, userID string) ([]*domain.APIKey, error) {
+	if err := s.authorize(callerID, userID); err != nil {
+		return nil, err
+	}
+	all, err := s.keys.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]*domain.APIKey, 0, len(all))
+	for _, k := range all {
+		if k.Active() {
+			active = append(active, k)
+		}
+	}
+	return active, nil
+}
+
+// RevokeAPIKey revokes keyID. Keys the caller can't manage are reported
+// as not found.
+func (s *APIKeyService) RevokeAPIKey(callerID, keyID string) error {
+	id, err := uuid.Parse(keyID)
+	if err != nil {
+		return ErrAPIKeyNotFound
+	}
+	key, err := s.keys.FindByID(id)
+	if err != nil {
+		return ErrAPIKeyNotFound
+	}
+	if err := s.authorize(callerID, key.UserID); err != nil {
+		return ErrAPIKeyNotFound
+	}
+	if !key.Active() {
+		return nil
+	}
+	now := s.now()
+	key.RevokedAt = &now
+	return s.keys.Update(key)
+}
+
+// AuthenticateAPIKey implements auth.APIKeyAuthenticator.
+func (s *APIKeyService) AuthenticateAPIKey(raw string) (*auth.Principal, error) {
+	prefix, ok := auth.ParseAPIKey(raw)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := s.keys.FindByPrefix(prefix)
+	if err != nil || !key.Active() {
+		return nil, ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(auth.HashAPIKey(raw))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	user, err := s.findUser(key.UserID)
+	if err != nil || user.Disabled {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := s.now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyUsedResolution {
+		key.LastUsedAt = &now
+		if err := s.keys.Update(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return &auth.Principal{
+		UserID:   user.ID.String(),
+		Username: user.Username,
+		Role:     user.Role,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+// authorize allows callers to manage their own keys and those of the
+// bots they own.
+func (s *APIKeyService) authorize(callerID, userID string) error {
+	if callerID == userID {
+		return nil
+	}
+	user, err := s.findUser(userID)
+	if err != nil || !user.IsBot || user.OwnerID != callerID {
+		return ErrNotKeyOwner
+	}
+	return nil
+}
+
+func (s *APIKeyService) findUser(userID string) (*domain.User, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	return s.users.FindByID(id)
+}
+
+// compile‑time checks
+var (
+	_ ports.APIKeyService      = (*APIKeyService)(nil)
+	_ auth.APIKeyAuthenticator = (*APIKeyService)(nil)
+)
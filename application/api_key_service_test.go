@@ -0,0 +1,105 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+func TestAPIKeyService_BotKeys(t *testing.T) {
+	users := memory.NewUserRepository()
+	userSvc := NewUserService(users, auth.NewJWTManager("secret", time.Hour), memory.NewSessionRepository())
+	svc := NewAPIKeyService(users, memory.NewAPIKeyRepository())
+
+	assert.NoError(t, userSvc.Register("alice", "pw"))
+	assert.NoError(t, userSvc.Register("mallory", "pw"))
+	alice, _ := users.FindByUsername("alice")
+	mallory, _ := users.FindByUsername("mallory")
+
+	_, err := svc.CreateBot(alice.ID.String(), "")
+	assert.ErrorIs(t, err, ErrUsernameRequired)
+	_, err = svc.CreateBot(alice.ID.String(), "mallory")
+	assert.ErrorIs(t, err, ErrUsernameTaken)
+
+	bot, err := svc.CreateBot(alice.ID.String(), "ci-bot")
+	assert.NoError(t, err)
+	assert.True(t, bot.IsBot)
+	assert.Equal(t, alice.ID.String(), bot.OwnerID)
+
+	// bots can't log in with a password
+	_, err = userSvc.Login("ci-bot", "", ports.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	scopes := []domain.Scope{domain.ScopeMessagesWrite, domain.ScopeConversationsRead}
+
+	_, _, err = svc.CreateAPIKey(alice.ID.String(), bot.ID.String(), "", scopes)
+	assert.ErrorIs(t, err, ErrAPIKeyNameRequired)
+	_, _, err = svc.CreateAPIKey(alice.ID.String(), bot.ID.String(), "ci", nil)
+	assert.ErrorIs(t, err, ErrAPIKeyScopeRequired)
+	_, _, err = svc.CreateAPIKey(alice.ID.String(), bot.ID.String(), "ci", []domain.Scope{"admin:everything"})
+	assert.ErrorIs(t, err, ErrInvalidScope)
+
+	// only the owner may issue keys for the bot
+	_, _, err = svc.CreateAPIKey(mallory.ID.String(), bot.ID.String(), "ci", scopes)
+	assert.ErrorIs(t, err, ErrNotKeyOwner)
+	// and nobody may issue keys for another human
+	_, _, err = svc.CreateAPIKey(mallory.ID.String(), alice.ID.String(), "ci", scopes)
+	assert.ErrorIs(t, err, ErrNotKeyOwner)
+
+	raw, key, err := svc.CreateAPIKey(alice.ID.String(), bot.ID.String(), "ci", scopes)
+	assert.NoError(t, err)
+	assert.NotEqual(t, raw, key.Hash, "only the hash may be stored")
+
+	principal, err := svc.AuthenticateAPIKey(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, bot.ID.String(), principal.UserID)
+	assert.Equal(t, "ci-bot", principal.Username)
+	assert.Equal(t, scopes, principal.Scopes)
+
+	keys, err := svc.ListAPIKeys(alice.ID.String(), bot.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.NotNil(t, keys[0].LastUsedAt)
+
+	_, err = svc.ListAPIKeys(mallory.ID.String(), bot.ID.String())
+	assert.ErrorIs(t, err, ErrNotKeyOwner)
+
+	// tampering with the secret fails even though the prefix matches
+	_, err = svc.AuthenticateAPIKey(raw + "x")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	_, err = svc.AuthenticateAPIKey("not-a-key")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	assert.ErrorIs(t, svc.RevokeAPIKey(mallory.ID.String(), key.ID.String()), ErrAPIKeyNotFound)
+	assert.ErrorIs(t, svc.RevokeAPIKey(alice.ID.String(), "nope"), ErrAPIKeyNotFound)
+	assert.NoError(t, svc.RevokeAPIKey(alice.ID.String(), key.ID.String()))
+
+	_, err = svc.AuthenticateAPIKey(raw)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	keys, err = svc.ListAPIKeys(alice.ID.String(), bot.ID.String())
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestAPIKeyService_PersonalKey(t *testing.T) {
+	users := memory.NewUserRepository()
+	userSvc := NewUserService(users, auth.NewJWTManager("secret", time.Hour), memory.NewSessionRepository())
+	svc := NewAPIKeyService(users, memory.NewAPIKeyRepository())
+
+	assert.NoError(t, userSvc.Register("alice", "pw"))
+	alice, _ := users.FindByUsername("alice")
+
+	raw, _, err := svc.CreateAPIKey(alice.ID.String(), alice.ID.String(), "script", []domain.Scope{domain.ScopeMessagesRead})
+	assert.NoError(t, err)
+
+	principal, err := svc.AuthenticateAPIKey(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, alice.ID.String(), principal.UserID)
+}
@@ -0,0 +1,305 @@
+package application
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+	"github.com/chrikar/chatheon/internal/thumbnail"
+)
+
+var (
+	ErrAttachmentNotFound        = errors.New("attachment not found")
+	ErrAttachmentEmpty           = errors.New("attachment is empty")
+	ErrAttachmentTooLarge        = errors.New("attachment is too large")
+	ErrUnsupportedAttachmentType = errors.New("attachment type is not allowed")
+	ErrAttachmentAlreadySent     = errors.New("attachment was already sent with another message")
+	ErrTooManyAttachments        = errors.New("too many attachments on one message")
+	ErrAttachmentsUnavailable    = errors.New("attachments are not enabled")
+	ErrNoThumbnail               = errors.New("attachment has no thumbnail")
+)
+
+const (
+	// DefaultMaxAttachmentSize is the largest upload accepted, in bytes.
+	DefaultMaxAttachmentSize = 10 << 20
+	// MaxAttachmentsPerMessage caps how many files one message carries.
+	MaxAttachmentsPerMessage = 10
+	// ThumbnailSize is the longer side of generated previews, in pixels.
+	ThumbnailSize = 256
+	// DefaultUnsentAttachmentTTL is how long an upload may wait to be
+	// sent before PurgeUnsent deletes it.
+	DefaultUnsentAttachmentTTL = 24 * time.Hour
+)
+
+// DefaultAttachmentTypes are the sniffed media types accepted for upload.
+var DefaultAttachmentTypes = []string{
+	"image/png", "image/jpeg", "image/gif", "image/webp",
+	"application/pdf", "application/zip", "text/plain",
+	"audio/mpeg", "video/mp4",
+}
+
+// thumbnailTypes are the image types the standard library can decode.
+var thumbnailTypes = []string{"image/png", "image/jpeg", "image/gif"}
+
+type AttachmentService struct {
+	attachments   ports.AttachmentRepository
+	messages      ports.MessageRepository
+	conversations ports.ConversationRepository
+	blobs         ports.BlobStore
+	maxSize       int64
+	allowedTypes  []string
+	unsentTTL     time.Duration
+	scheduled     ports.ScheduledMessageRepository
+	now           func() time.Time
+}
+
+var _ ports.AttachmentService = (*AttachmentService)(nil)
+
+// AttachmentServiceOption configures optional AttachmentService limits.
+type AttachmentServiceOption func(*AttachmentService)
+
+// WithMaxAttachmentSize overrides DefaultMaxAttachmentSize.
+func WithMaxAttachmentSize(n int64) AttachmentServiceOption {
+	return func(s *AttachmentService) { s.maxSize = n }
+}
+
+// WithAttachmentTypes overrides DefaultAttachmentTypes.
+func WithAttachmentTypes(types ...string) AttachmentServiceOption {
+	return func(s *AttachmentService) { s.allowedTypes = types }
+}
+
+// WithUnsentAttachmentTTL overrides DefaultUnsentAttachmentTTL.
+func WithUnsentAttachmentTTL(d time.Duration) AttachmentServiceOption {
+	return func(s *AttachmentService) { s.unsentTTL = d }
+}
+
+// WithScheduledAttachments keeps uploads that pending scheduled messages
+// will send, however old they get.
+func WithScheduledAttachments(scheduled ports.ScheduledMessageRepository) AttachmentServiceOption {
+	return func(s *AttachmentService) { s.scheduled = scheduled }
+}
+
+func NewAttachmentService(
+	attachments ports.AttachmentRepository,
+	messages ports.MessageRepository,
+	conversations ports.ConversationRepository,
+	blobs ports.BlobStore,
+	opts ...AttachmentServiceOption,
+) *AttachmentService {
+	s := &AttachmentService{
+		attachments:   attachments,
+		messages:      messages,
+		conversations: conversations,
+		blobs:         blobs,
+		maxSize:       DefaultMaxAttachmentSize,
+		allowedTypes:  DefaultAttachmentTypes,
+		unsentTTL:     DefaultUnsentAttachmentTTL,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Upload sniffs the file's type from its first bytes rather than trusting
+// the client, streams it to the blob store while hashing it, and renders
+// a thumbnail for decodable images.
+func (s *AttachmentService) Upload(uploaderID, name string, r io.Reader) (*domain.Attachment, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrAttachmentEmpty
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !contains(s.allowedTypes, mediaType) {
+		return nil, ErrUnsupportedAttachmentType
+	}
+
+	a := &domain.Attachment{
+		ID:          uuid.New(),
+		UploaderID:  uploaderID,
+		Name:        attachmentName(name),
+		ContentType: contentType,
+		CreatedAt:   s.now(),
+	}
+	key := originalKey(a.ID)
+
+	// Read one byte past the limit so oversized files are detectable.
+	hash := sha256.New()
+	size := &byteCounter{}
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(head), r), s.maxSize+1)
+	if err := s.blobs.Put(key, io.TeeReader(body, io.MultiWriter(hash, size))); err != nil {
+		return nil, err
+	}
+	if size.n > s.maxSize {
+		_ = s.blobs.Delete(key)
+		return nil, ErrAttachmentTooLarge
+	}
+	a.Size = size.n
+	a.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	if contains(thumbnailTypes, mediaType) {
+		s.makeThumbnail(a)
+	}
+
+	if err := s.attachments.Create(a); err != nil {
+		_ = s.blobs.Delete(key)
+		_ = s.blobs.Delete(thumbnailKey(a.ID))
+		return nil, err
+	}
+	return a, nil
+}
+
+// makeThumbnail records the image's dimensions and stores a preview. A
+// file that looks like an image but won't decode is kept without one.
+func (s *AttachmentService) makeThumbnail(a *domain.Attachment) {
+	rc, err := s.blobs.Get(originalKey(a.ID))
+	if err != nil {
+		log.Printf("thumbnail for attachment %s: %v", a.ID, err)
+		return
+	}
+	defer rc.Close()
+
+	var buf bytes.Buffer
+	data := io.TeeReader(rc, &buf)
+	if a.Width, a.Height, err = thumbnail.Config(data); err != nil {
+		log.Printf("thumbnail for attachment %s: %v", a.ID, err)
+		return
+	}
+	var thumb bytes.Buffer
+	if err := thumbnail.Generate(&thumb, io.MultiReader(&buf, rc), ThumbnailSize); err != nil {
+		log.Printf("thumbnail for attachment %s: %v", a.ID, err)
+		return
+	}
+	if err := s.blobs.Put(thumbnailKey(a.ID), &thumb); err != nil {
+		log.Printf("thumbnail for attachment %s: %v", a.ID, err)
+		return
+	}
+	a.HasThumbnail = true
+}
+
+func (s *AttachmentService) Open(userID, attachmentID string) (*domain.Attachment, io.ReadCloser, error) {
+	a, err := s.authorize(userID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := s.blobs.Get(originalKey(a.ID))
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, rc, nil
+}
+
+func (s *AttachmentService) OpenThumbnail(userID, attachmentID string) (*domain.Attachment, io.ReadCloser, error) {
+	a, err := s.authorize(userID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !a.HasThumbnail {
+		return nil, nil, ErrNoThumbnail
+	}
+	rc, err := s.blobs.Get(thumbnailKey(a.ID))
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, rc, nil
+}
+
+// authorize loads an attachment userID may read: their own unsent
+// uploads, or files on messages they can see. Everything else reports
+// ErrAttachmentNotFound so IDs can't be probed.
+func (s *AttachmentService) authorize(userID, attachmentID string) (*domain.Attachment, error) {
+	id, err := uuid.Parse(attachmentID)
+	if err != nil {
+		return nil, ErrAttachmentNotFound
+	}
+	a, err := s.attachments.FindByID(id)
+	if err != nil {
+		return nil, ErrAttachmentNotFound
+	}
+	if a.MessageID == nil {
+		if a.UploaderID != userID {
+			return nil, ErrAttachmentNotFound
+		}
+		return a, nil
+	}
+	msg, err := s.messages.FindByID(*a.MessageID)
+	if err != nil || msg.Deleted() {
+		return nil, ErrAttachmentNotFound
+	}
+	if msg.SenderID != userID && !contains(messageRecipients(s.conversations, msg), userID) {
+		return nil, ErrAttachmentNotFound
+	}
+	return a, nil
+}
+
+// PurgeUnsent deletes uploads that were never sent within the unsent
+// TTL, with their files, and reports how many went. Uploads waiting on a
+// scheduled message are kept.
+func (s *AttachmentService) PurgeUnsent() (int, error) {
+	cutoff := s.now().Add(-s.unsentTTL)
+	var keep []uuid.UUID
+	if s.scheduled != nil {
+		var err error
+		if keep, err = s.scheduled.PendingAttachmentIDs(); err != nil {
+			return 0, err
+		}
+	}
+	total := 0
+	for {
+		batch, err := s.attachments.DeleteUnsent(cutoff, keep, purgeBatchSize)
+		if err != nil {
+			return total, err
+		}
+		for _, a := range batch {
+			if err := s.blobs.Delete(originalKey(a.ID)); err != nil {
+				return total, err
+			}
+			if err := s.blobs.Delete(thumbnailKey(a.ID)); err != nil {
+				return total, err
+			}
+			total++
+		}
+		if len(batch) < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func originalKey(id uuid.UUID) string  { return "attachments/" + id.String() + "/original" }
+func thumbnailKey(id uuid.UUID) string { return "attachments/" + id.String() + "/thumbnail" }
+
+// attachmentName keeps only the final element of a client-supplied path.
+func attachmentName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "attachment"
+	}
+	return name
+}
+
+type byteCounter struct{ n int64 }
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
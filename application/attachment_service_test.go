@@ -0,0 +1,241 @@
+package application
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+type attachmentFixture struct {
+	attachments *AttachmentService
+	messages    *MessageService
+	conv        *domain.Conversation
+}
+
+func newAttachmentFixture(t *testing.T, opts ...AttachmentServiceOption) *attachmentFixture {
+	t.Helper()
+	msgRepo := memory.NewMessageRepository()
+	convRepo := memory.NewConversationRepository()
+	attRepo := memory.NewAttachmentRepository()
+	blobs := memory.NewBlobStore()
+	scheduled := memory.NewScheduledMessageRepository()
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convRepo.Create(conv))
+
+	opts = append([]AttachmentServiceOption{WithScheduledAttachments(scheduled)}, opts...)
+	messages := NewMessageService(msgRepo, WithConversations(convRepo), WithAttachments(attRepo, blobs),
+		WithScheduledMessages(scheduled))
+	return &attachmentFixture{
+		attachments: NewAttachmentService(attRepo, msgRepo, convRepo, blobs, opts...),
+		messages:    messages,
+		conv:        conv,
+	}
+}
+
+func pngBytes(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+func readAll(t *testing.T, rc io.ReadCloser) string {
+	t.Helper()
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestAttachmentService_Upload(t *testing.T) {
+	f := newAttachmentFixture(t, WithMaxAttachmentSize(1024))
+
+	a, err := f.attachments.Upload("alice", `C:\Users\alice\notes.txt`, strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, "notes.txt", a.Name)
+	assert.Equal(t, int64(5), a.Size)
+	assert.Equal(t, "text/plain; charset=utf-8", a.ContentType)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", a.Checksum)
+	assert.False(t, a.HasThumbnail)
+
+	_, err = f.attachments.Upload("alice", "big.txt", strings.NewReader(strings.Repeat("x", 1025)))
+	assert.ErrorIs(t, err, ErrAttachmentTooLarge)
+	_, err = f.attachments.Upload("alice", "empty.txt", strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrAttachmentEmpty)
+	// The name claims a picture, but the bytes are an executable.
+	_, err = f.attachments.Upload("alice", "cat.png", bytes.NewReader([]byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00")))
+	assert.ErrorIs(t, err, ErrUnsupportedAttachmentType)
+}
+
+func TestAttachmentService_ImageThumbnail(t *testing.T) {
+	f := newAttachmentFixture(t)
+
+	a, err := f.attachments.Upload("alice", "wide.png", bytes.NewReader(pngBytes(t, 1024, 512)))
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", a.ContentType)
+	assert.Equal(t, 1024, a.Width)
+	assert.Equal(t, 512, a.Height)
+	assert.True(t, a.HasThumbnail)
+
+	_, rc, err := f.attachments.OpenThumbnail("alice", a.ID.String())
+	assert.NoError(t, err)
+	thumb, err := png.Decode(strings.NewReader(readAll(t, rc)))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, ThumbnailSize, ThumbnailSize/2), thumb.Bounds())
+
+	// Sniffed as PNG from its signature, but not decodable.
+	broken := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), "garbage"...)
+	b, err := f.attachments.Upload("alice", "broken.png", bytes.NewReader(broken))
+	assert.NoError(t, err)
+	assert.False(t, b.HasThumbnail)
+	_, _, err = f.attachments.OpenThumbnail("alice", b.ID.String())
+	assert.ErrorIs(t, err, ErrNoThumbnail)
+}
+
+func TestAttachmentService_AccessFollowsMessage(t *testing.T) {
+	f := newAttachmentFixture(t)
+
+	a, err := f.attachments.Upload("alice", "plan.txt", strings.NewReader("the plan"))
+	assert.NoError(t, err)
+	id := a.ID.String()
+
+	// Before sending, only the uploader can read it.
+	_, rc, err := f.attachments.Open("alice", id)
+	assert.NoError(t, err)
+	assert.Equal(t, "the plan", readAll(t, rc))
+	_, _, err = f.attachments.Open("bob", id)
+	assert.ErrorIs(t, err, ErrAttachmentNotFound)
+
+	// Others can't send alice's uploads.
+	_, err = f.messages.CreateMessage("bob", ports.MessageDraft{ConversationID: f.conv.ID.String(), AttachmentIDs: []string{id}})
+	assert.ErrorIs(t, err, ErrAttachmentNotFound)
+
+	msg, err := f.messages.CreateMessage("alice", ports.MessageDraft{ConversationID: f.conv.ID.String(), AttachmentIDs: []string{id}})
+	assert.NoError(t, err)
+	if assert.Len(t, msg.Attachments, 1) {
+		assert.Equal(t, &msg.ID, msg.Attachments[0].MessageID)
+	}
+
+	_, err = f.messages.CreateMessage("alice", ports.MessageDraft{ReceiverID: "carol", AttachmentIDs: []string{id}})
+	assert.ErrorIs(t, err, ErrAttachmentAlreadySent)
+
+	_, rc, err = f.attachments.Open("bob", id)
+	assert.NoError(t, err)
+	assert.Equal(t, "the plan", readAll(t, rc))
+	_, _, err = f.attachments.Open("mallory", id)
+	assert.ErrorIs(t, err, ErrAttachmentNotFound)
+
+	timeline, err := f.messages.GetConversationMessages("bob", f.conv.ID.String(), 10, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, timeline, 1) {
+		assert.Len(t, timeline[0].Attachments, 1)
+	}
+
+	// Deleting the message for everyone withdraws the file too.
+	assert.NoError(t, f.messages.DeleteMessageForEveryone("alice", msg.ID.String()))
+	_, _, err = f.attachments.Open("bob", id)
+	assert.ErrorIs(t, err, ErrAttachmentNotFound)
+	timeline, err = f.messages.GetConversationMessages("bob", f.conv.ID.String(), 10, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, timeline[0].Attachments)
+}
+
+func TestAttachmentService_PurgeUnsent(t *testing.T) {
+	f := newAttachmentFixture(t)
+	sent, err := f.attachments.Upload("alice", "sent.txt", strings.NewReader("sent"))
+	assert.NoError(t, err)
+	stale, err := f.attachments.Upload("alice", "stale.txt", strings.NewReader("stale"))
+	assert.NoError(t, err)
+	_, err = f.messages.CreateMessage("alice", ports.MessageDraft{ConversationID: f.conv.ID.String(), AttachmentIDs: []string{sent.ID.String()}})
+	assert.NoError(t, err)
+	held, err := f.attachments.Upload("alice", "later.txt", strings.NewReader("later"))
+	assert.NoError(t, err)
+	sm, err := f.messages.ScheduleMessage("alice", ports.MessageDraft{ConversationID: f.conv.ID.String(), AttachmentIDs: []string{held.ID.String()}},
+		time.Now().Add(3*DefaultUnsentAttachmentTTL))
+	assert.NoError(t, err)
+
+	purged, err := f.attachments.PurgeUnsent()
+	assert.NoError(t, err)
+	assert.Zero(t, purged, "fresh uploads are kept")
+
+	f.attachments.now = func() time.Time { return time.Now().Add(DefaultUnsentAttachmentTTL + time.Minute) }
+	purged, err = f.attachments.PurgeUnsent()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	_, _, err = f.attachments.Open("alice", stale.ID.String())
+	assert.ErrorIs(t, err, ErrAttachmentNotFound)
+	_, err = f.attachments.blobs.Get(originalKey(stale.ID))
+	assert.ErrorIs(t, err, ports.ErrBlobNotFound)
+	_, rc, err := f.attachments.Open("bob", sent.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "sent", readAll(t, rc))
+	_, rc, err = f.attachments.Open("alice", held.ID.String())
+	assert.NoError(t, err, "a scheduled message keeps its uploads")
+	assert.Equal(t, "later", readAll(t, rc))
+
+	assert.NoError(t, f.messages.CancelScheduledMessage("alice", sm.ID.String()))
+	purged, err = f.attachments.PurgeUnsent()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged, "canceling releases them")
+}
+
+func TestMessageService_AttachmentSentOnce(t *testing.T) {
+	f := newAttachmentFixture(t)
+	a, err := f.attachments.Upload("alice", "plan.txt", strings.NewReader("the plan"))
+	assert.NoError(t, err)
+
+	// Concurrent sends all see the upload unsent; only one may have it.
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = f.messages.CreateMessage("alice", ports.MessageDraft{
+				ConversationID: f.conv.ID.String(), AttachmentIDs: []string{a.ID.String()}})
+		}()
+	}
+	wg.Wait()
+
+	sent := 0
+	for _, err := range errs {
+		if err == nil {
+			sent++
+		} else {
+			assert.ErrorIs(t, err, ErrAttachmentAlreadySent)
+		}
+	}
+	assert.Equal(t, 1, sent)
+	timeline, err := f.messages.GetConversationMessages("bob", f.conv.ID.String(), 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, timeline, 1, "losing sends leave no message behind")
+}
+
+func TestMessageService_AttachmentLimits(t *testing.T) {
+	f := newAttachmentFixture(t)
+
+	ids := make([]string, MaxAttachmentsPerMessage+1)
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	_, err := f.messages.CreateMessage("alice", ports.MessageDraft{ReceiverID: "bob", AttachmentIDs: ids})
+	assert.ErrorIs(t, err, ErrTooManyAttachments)
+
+	_, err = NewMessageService(memory.NewMessageRepository()).
+		CreateMessage("alice", ports.MessageDraft{ReceiverID: "bob", AttachmentIDs: ids[:1]})
+	assert.ErrorIs(t, err, ErrAttachmentsUnavailable)
+}
@@ -0,0 +1,142 @@
+package application
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+var (
+	ErrCannotBlockSelf = errors.New("users cannot block themselves")
+	ErrBlockNotFound   = errors.New("user is not blocked")
+	// ErrBlocked doesn't say who blocked whom, so the blocked user can't
+	// tell from it that they were blocked rather than that they blocked.
+	ErrBlocked = errors.New("you can't message this user")
+)
+
+// BlockService is the application-layer implementation of
+// ports.BlockService.
+type BlockService struct {
+	blocks   ports.BlockRepository
+	users    ports.UserRepository
+	contacts ports.ContactRepository
+	now      func() time.Time
+}
+
+// BlockOption configures optional BlockService collaborators.
+type BlockOption func(*BlockService)
+
+// WithBlockContacts makes a block end any contact between the two users
+// and close the contact request pending between them.
+func WithBlockContacts(contacts ports.ContactRepository) BlockOption {
+	return func(s *BlockService) { s.contacts = contacts }
+}
+
+func NewBlockService(blocks ports.BlockRepository, users ports.UserRepository, opts ...BlockOption) *BlockService {
+	s := &BlockService{blocks: blocks, users: users, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *BlockService) BlockUser(blockerID, blockedID string) (*domain.Block, error) {
+	if blockerID == blockedID {
+		return nil, ErrCannotBlockSelf
+	}
+	if _, err := s.findUser(blockedID); err != nil {
+		return nil, err
+	}
+	existing, err := s.blocks.FindByBlocker(blockerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range existing {
+		if b.BlockedID == blockedID {
+			return b, nil
+		}
+	}
+	block := &domain.Block{BlockerID: blockerID, BlockedID: blockedID, CreatedAt: s.now()}
+	if err := s.blocks.Add(block); err != nil {
+		return nil, err
+	}
+	if err := s.dropContact(blockerID, blockedID); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func (s *BlockService) UnblockUser(blockerID, blockedID string) error {
+	if err := s.blocks.Remove(blockerID, blockedID); err != nil {
+		return ErrBlockNotFound
+	}
+	return nil
+}
+
+func (s *BlockService) GetBlocks(userID string) ([]*domain.Block, error) {
+	return s.blocks.FindByBlocker(userID)
+}
+
+// dropContact ends the contact between two users who are now blocked and
+// closes the request pending between them. The block is stored first, so
+// a request accepted meanwhile fails its own block check instead.
+func (s *BlockService) dropContact(blockerID, blockedID string) error {
+	if s.contacts == nil {
+		return nil
+	}
+	if req, err := s.contacts.FindPendingRequest(blockerID, blockedID); err == nil {
+		now := s.now()
+		req.Status = domain.ContactRequestDeclined
+		if req.FromID == blockerID {
+			req.Status = domain.ContactRequestCanceled
+		}
+		req.RespondedAt = &now
+		if err := s.contacts.UpdateRequest(req); err != nil {
+			return err
+		}
+	}
+	contacts, err := s.contacts.IsContact(blockerID, blockedID)
+	if err != nil || !contacts {
+		return err
+	}
+	return s.contacts.RemoveContact(blockerID, blockedID)
+}
+
+func (s *BlockService) findUser(userID string) (*domain.User, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	user, err := s.users.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// blockedEitherWay reports whether a has blocked b or b has blocked a.
+func blockedEitherWay(blocks ports.BlockRepository, a, b string) (bool, error) {
+	if blocked, err := blocks.Exists(a, b); err != nil || blocked {
+		return blocked, err
+	}
+	return blocks.Exists(b, a)
+}
+
+// blockedIDs returns the IDs of the users userID has blocked.
+func blockedIDs(blocks ports.BlockRepository, userID string) ([]string, error) {
+	list, err := blocks.FindByBlocker(userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(list))
+	for i, b := range list {
+		ids[i] = b.BlockedID
+	}
+	return ids, nil
+}
+
+var _ ports.BlockService = (*BlockService)(nil)
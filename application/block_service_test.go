@@ -0,0 +1,134 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestBlockService(t *testing.T) {
+	users := memory.NewUserRepository()
+	svc := NewBlockService(memory.NewBlockRepository(), users)
+
+	alice := &domain.User{ID: uuid.New(), Username: "alice"}
+	bob := &domain.User{ID: uuid.New(), Username: "bob"}
+	for _, u := range []*domain.User{alice, bob} {
+		assert.NoError(t, users.Create(u))
+	}
+	a, b := alice.ID.String(), bob.ID.String()
+
+	_, err := svc.BlockUser(a, a)
+	assert.ErrorIs(t, err, ErrCannotBlockSelf)
+	_, err = svc.BlockUser(a, uuid.NewString())
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	block, err := svc.BlockUser(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, b, block.BlockedID)
+	again, err := svc.BlockUser(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, block.CreatedAt, again.CreatedAt, "blocking twice keeps the first block")
+
+	blocks, err := svc.GetBlocks(a)
+	assert.NoError(t, err)
+	assert.Len(t, blocks, 1)
+	blocks, err = svc.GetBlocks(b)
+	assert.NoError(t, err)
+	assert.Empty(t, blocks)
+
+	assert.NoError(t, svc.UnblockUser(a, b))
+	assert.ErrorIs(t, svc.UnblockUser(a, b), ErrBlockNotFound)
+}
+
+func TestBlockService_EndsContact(t *testing.T) {
+	users := memory.NewUserRepository()
+	contacts := memory.NewContactRepository()
+	svc := NewBlockService(memory.NewBlockRepository(), users, WithBlockContacts(contacts))
+
+	alice := &domain.User{ID: uuid.New(), Username: "alice"}
+	bob := &domain.User{ID: uuid.New(), Username: "bob"}
+	carol := &domain.User{ID: uuid.New(), Username: "carol"}
+	for _, u := range []*domain.User{alice, bob, carol} {
+		assert.NoError(t, users.Create(u))
+	}
+	a, b, c := alice.ID.String(), bob.ID.String(), carol.ID.String()
+
+	assert.NoError(t, contacts.AddContact(a, b, time.Now()))
+	req := &domain.ContactRequest{ID: uuid.New(), FromID: c, ToID: a, Status: domain.ContactRequestPending}
+	assert.NoError(t, contacts.CreateRequest(req))
+
+	_, err := svc.BlockUser(a, b)
+	assert.NoError(t, err)
+	isContact, err := contacts.IsContact(b, a)
+	assert.NoError(t, err)
+	assert.False(t, isContact, "blocking ends the contact both ways")
+
+	_, err = svc.BlockUser(a, c)
+	assert.NoError(t, err)
+	closed, err := contacts.FindRequest(req.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ContactRequestDeclined, closed.Status)
+	assert.NotNil(t, closed.RespondedAt)
+}
+
+func TestBlocks_Messaging(t *testing.T) {
+	blocks := memory.NewBlockRepository()
+	convs := memory.NewConversationRepository()
+	msgs := NewMessageService(memory.NewMessageRepository(), WithConversations(convs), WithBlocks(blocks))
+	convSvc := NewConversationService(convs, WithConversationBlocks(blocks))
+
+	direct, err := convSvc.CreateConversation([]string{"alice", "bob"})
+	assert.NoError(t, err)
+	group, err := convSvc.CreateConversation([]string{"alice", "bob", "carol"})
+	assert.NoError(t, err)
+	assert.NoError(t, blocks.Add(&domain.Block{BlockerID: "alice", BlockedID: "bob"}))
+
+	_, err = msgs.CreateMessage("bob", ports.MessageDraft{ReceiverID: "alice", Content: "hi"})
+	assert.ErrorIs(t, err, ErrBlocked)
+	_, err = msgs.CreateMessage("alice", ports.MessageDraft{ReceiverID: "bob", Content: "hi"})
+	assert.ErrorIs(t, err, ErrBlocked, "the blocker can't message the blocked user either")
+	_, err = msgs.CreateMessage("bob", ports.MessageDraft{ConversationID: direct.ID.String(), Content: "hi"})
+	assert.ErrorIs(t, err, ErrBlocked)
+	_, err = msgs.CreateMessage("bob", ports.MessageDraft{ConversationID: group.ID.String(), Content: "hi all"})
+	assert.NoError(t, err, "groups are unaffected")
+	_, err = msgs.CreateMessage("bob", ports.MessageDraft{ReceiverID: "carol", Content: "hi"})
+	assert.NoError(t, err)
+
+	_, err = convSvc.CreateConversation([]string{"bob", "alice"})
+	assert.ErrorIs(t, err, ErrBlocked)
+	_, err = convSvc.CreateConversation([]string{"alice", "bob", "carol"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, blocks.Remove("alice", "bob"))
+	_, err = msgs.CreateMessage("bob", ports.MessageDraft{ReceiverID: "alice", Content: "hi"})
+	assert.NoError(t, err)
+}
+
+func TestUserService_SearchUsersHidesBlocked(t *testing.T) {
+	users := memory.NewUserRepository()
+	blocks := memory.NewBlockRepository()
+	svc := NewUserService(users, nil, nil, WithUserBlocks(blocks))
+
+	alice := &domain.User{ID: uuid.New(), Username: "alice"}
+	alina := &domain.User{ID: uuid.New(), Username: "alina"}
+	for _, u := range []*domain.User{alice, alina} {
+		assert.NoError(t, users.Create(u))
+	}
+	assert.NoError(t, blocks.Add(&domain.Block{BlockerID: alice.ID.String(), BlockedID: alina.ID.String()}))
+
+	found, err := svc.SearchUsers(alice.ID.String(), "al", 10, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, "alice", found[0].Username)
+	}
+
+	found, err = svc.SearchUsers(alina.ID.String(), "al", 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, found, 2, "only the blocker's searches are filtered")
+}
@@ -0,0 +1,234 @@
+package application
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+var (
+	ErrCannotAddSelf            = errors.New("users cannot add themselves as a contact")
+	ErrAlreadyContacts          = errors.New("users are already contacts")
+	ErrContactRequestNotFound   = errors.New("contact request not found")
+	ErrContactRequestNotPending = errors.New("contact request was already answered or canceled")
+	ErrContactNotFound          = errors.New("contact not found")
+	ErrContactsOnly             = errors.New("this user only accepts messages from contacts")
+)
+
+// ContactService is the application-layer implementation of
+// ports.ContactService.
+type ContactService struct {
+	contacts ports.ContactRepository
+	users    ports.UserRepository
+	blocks   ports.BlockRepository
+	notifier ports.NotificationService
+	now      func() time.Time
+}
+
+// ContactOption configures optional ContactService collaborators.
+type ContactOption func(*ContactService)
+
+// WithContactBlocks refuses contact requests between users who blocked
+// each other.
+func WithContactBlocks(blocks ports.BlockRepository) ContactOption {
+	return func(s *ContactService) { s.blocks = blocks }
+}
+
+// WithContactNotifier tells users about incoming contact requests.
+func WithContactNotifier(notifier ports.NotificationService) ContactOption {
+	return func(s *ContactService) { s.notifier = notifier }
+}
+
+func NewContactService(contacts ports.ContactRepository, users ports.UserRepository, opts ...ContactOption) *ContactService {
+	s := &ContactService{contacts: contacts, users: users, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *ContactService) SendContactRequest(fromID, toID string) (*domain.ContactRequest, error) {
+	if fromID == toID {
+		return nil, ErrCannotAddSelf
+	}
+	if _, err := s.findUser(toID); err != nil {
+		return nil, err
+	}
+	if s.blocks != nil {
+		blocked, err := blockedEitherWay(s.blocks, fromID, toID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, ErrBlocked
+		}
+	}
+	already, err := s.contacts.IsContact(fromID, toID)
+	if err != nil {
+		return nil, err
+	}
+	if already {
+		return nil, ErrAlreadyContacts
+	}
+
+	if pending, err := s.contacts.FindPendingRequest(fromID, toID); err == nil {
+		if pending.FromID == fromID {
+			return pending, nil
+		}
+		// They asked first, so asking back is as good as accepting.
+		return s.accept(pending)
+	}
+
+	req := &domain.ContactRequest{
+		ID:        uuid.New(),
+		FromID:    fromID,
+		ToID:      toID,
+		Status:    domain.ContactRequestPending,
+		CreatedAt: s.now(),
+	}
+	if err := s.contacts.CreateRequest(req); err != nil {
+		return nil, err
+	}
+	s.notifyRequest(req)
+	return req, nil
+}
+
+func (s *ContactService) AcceptContactRequest(userID, requestID string) (*domain.ContactRequest, error) {
+	req, err := s.findRequest(requestID, func(r *domain.ContactRequest) bool { return r.ToID == userID })
+	if err != nil {
+		return nil, err
+	}
+	return s.accept(req)
+}
+
+func (s *ContactService) DeclineContactRequest(userID, requestID string) error {
+	req, err := s.findRequest(requestID, func(r *domain.ContactRequest) bool { return r.ToID == userID })
+	if err != nil {
+		return err
+	}
+	return s.close(req, domain.ContactRequestDeclined)
+}
+
+func (s *ContactService) CancelContactRequest(userID, requestID string) error {
+	req, err := s.findRequest(requestID, func(r *domain.ContactRequest) bool { return r.FromID == userID })
+	if err != nil {
+		return err
+	}
+	return s.close(req, domain.ContactRequestCanceled)
+}
+
+func (s *ContactService) GetContactRequests(userID string) ([]*domain.ContactRequest, error) {
+	return s.contacts.FindPendingRequests(userID)
+}
+
+func (s *ContactService) GetContacts(userID string) ([]*domain.Contact, error) {
+	return s.contacts.FindContacts(userID)
+}
+
+func (s *ContactService) RemoveContact(userID, contactID string) error {
+	if err := s.contacts.RemoveContact(userID, contactID); err != nil {
+		return ErrContactNotFound
+	}
+	return nil
+}
+
+func (s *ContactService) SetContactsOnly(userID string, enabled bool) error {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return err
+	}
+	if user.ContactsOnly == enabled {
+		return nil
+	}
+	user.ContactsOnly = enabled
+	return s.users.Update(user)
+}
+
+func (s *ContactService) accept(req *domain.ContactRequest) (*domain.ContactRequest, error) {
+	// A block may have come after the request was sent.
+	if s.blocks != nil {
+		blocked, err := blockedEitherWay(s.blocks, req.FromID, req.ToID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, ErrBlocked
+		}
+	}
+	now := s.now()
+	if err := s.contacts.AddContact(req.FromID, req.ToID, now); err != nil {
+		return nil, err
+	}
+	req.Status = domain.ContactRequestAccepted
+	req.RespondedAt = &now
+	if err := s.contacts.UpdateRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s *ContactService) close(req *domain.ContactRequest, status domain.ContactRequestStatus) error {
+	now := s.now()
+	req.Status = status
+	req.RespondedAt = &now
+	return s.contacts.UpdateRequest(req)
+}
+
+// findRequest loads a pending request that party says the caller may act
+// on. Requests of other users are reported as not found.
+func (s *ContactService) findRequest(requestID string, party func(*domain.ContactRequest) bool) (*domain.ContactRequest, error) {
+	id, err := uuid.Parse(requestID)
+	if err != nil {
+		return nil, ErrContactRequestNotFound
+	}
+	req, err := s.contacts.FindRequest(id)
+	if err != nil || !party(req) {
+		return nil, ErrContactRequestNotFound
+	}
+	if req.Status != domain.ContactRequestPending {
+		return nil, ErrContactRequestNotPending
+	}
+	return req, nil
+}
+
+func (s *ContactService) findUser(userID string) (*domain.User, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	user, err := s.users.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// notifyRequest tells the recipient about a new request. The request is
+// stored either way, so a failure is only logged.
+func (s *ContactService) notifyRequest(req *domain.ContactRequest) {
+	if s.notifier == nil {
+		return
+	}
+	body := "You have a new contact request"
+	if sender, err := s.findUser(req.FromID); err == nil {
+		body = sender.Username + " wants to add you as a contact"
+	}
+	err := s.notifier.Notify(&domain.Notification{
+		Type:      domain.NotificationContactRequest,
+		Priority:  domain.PriorityNormal,
+		UserID:    req.ToID,
+		ActorID:   req.FromID,
+		Body:      body,
+		CreatedAt: s.now(),
+	})
+	if err != nil {
+		log.Printf("notify %s of %s: %v", req.ToID, domain.NotificationContactRequest, err)
+	}
+}
+
+var _ ports.ContactService = (*ContactService)(nil)
@@ -0,0 +1,141 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestContactService_Requests(t *testing.T) {
+	users := memory.NewUserRepository()
+	blocks := memory.NewBlockRepository()
+	notifier := mocks.NewMockNotificationService(t)
+	svc := NewContactService(memory.NewContactRepository(), users,
+		WithContactBlocks(blocks), WithContactNotifier(notifier))
+
+	alice := &domain.User{ID: uuid.New(), Username: "alice"}
+	bob := &domain.User{ID: uuid.New(), Username: "bob"}
+	carol := &domain.User{ID: uuid.New(), Username: "carol"}
+	for _, u := range []*domain.User{alice, bob, carol} {
+		assert.NoError(t, users.Create(u))
+	}
+	a, b, c := alice.ID.String(), bob.ID.String(), carol.ID.String()
+
+	_, err := svc.SendContactRequest(a, a)
+	assert.ErrorIs(t, err, ErrCannotAddSelf)
+	_, err = svc.SendContactRequest(a, uuid.NewString())
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Type == domain.NotificationContactRequest && n.UserID == b && n.ActorID == a &&
+			n.Body == "alice wants to add you as a contact"
+	})).Return(nil).Once()
+	req, err := svc.SendContactRequest(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ContactRequestPending, req.Status)
+	again, err := svc.SendContactRequest(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, req.ID, again.ID, "asking twice keeps one request")
+
+	// Only the recipient may answer, and only the sender may cancel.
+	_, err = svc.AcceptContactRequest(a, req.ID.String())
+	assert.ErrorIs(t, err, ErrContactRequestNotFound)
+	assert.ErrorIs(t, svc.CancelContactRequest(b, req.ID.String()), ErrContactRequestNotFound)
+
+	requests, err := svc.GetContactRequests(b)
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+
+	accepted, err := svc.AcceptContactRequest(b, req.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ContactRequestAccepted, accepted.Status)
+	assert.NotNil(t, accepted.RespondedAt)
+	assert.ErrorIs(t, svc.DeclineContactRequest(b, req.ID.String()), ErrContactRequestNotPending)
+	_, err = svc.SendContactRequest(b, a)
+	assert.ErrorIs(t, err, ErrAlreadyContacts)
+
+	contacts, err := svc.GetContacts(b)
+	assert.NoError(t, err)
+	if assert.Len(t, contacts, 1) {
+		assert.Equal(t, a, contacts[0].ContactID)
+	}
+
+	// Asking back accepts the pending request.
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool { return n.UserID == a })).Return(nil).Once()
+	req, err = svc.SendContactRequest(c, a)
+	assert.NoError(t, err)
+	back, err := svc.SendContactRequest(a, c)
+	assert.NoError(t, err)
+	assert.Equal(t, req.ID, back.ID)
+	assert.Equal(t, domain.ContactRequestAccepted, back.Status)
+
+	assert.NoError(t, svc.RemoveContact(a, c))
+	assert.ErrorIs(t, svc.RemoveContact(c, a), ErrContactNotFound)
+
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool { return n.UserID == c })).Return(nil).Twice()
+	req, err = svc.SendContactRequest(b, c)
+	assert.NoError(t, err)
+	assert.NoError(t, svc.DeclineContactRequest(c, req.ID.String()))
+	req, err = svc.SendContactRequest(b, c)
+	assert.NoError(t, err, "a declined request may be sent again")
+	assert.NoError(t, svc.CancelContactRequest(b, req.ID.String()))
+	requests, err = svc.GetContactRequests(c)
+	assert.NoError(t, err)
+	assert.Empty(t, requests)
+
+	assert.NoError(t, blocks.Add(&domain.Block{BlockerID: c, BlockedID: b}))
+	_, err = svc.SendContactRequest(b, c)
+	assert.ErrorIs(t, err, ErrBlocked)
+
+	// A block placed after the request was sent still stops the accept.
+	assert.NoError(t, blocks.Remove(c, b))
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool { return n.UserID == c })).Return(nil).Once()
+	req, err = svc.SendContactRequest(b, c)
+	assert.NoError(t, err)
+	assert.NoError(t, blocks.Add(&domain.Block{BlockerID: b, BlockedID: c}))
+	_, err = svc.AcceptContactRequest(c, req.ID.String())
+	assert.ErrorIs(t, err, ErrBlocked)
+	isContact, err := svc.contacts.IsContact(b, c)
+	assert.NoError(t, err)
+	assert.False(t, isContact)
+}
+
+func TestMessageService_ContactsOnly(t *testing.T) {
+	users := memory.NewUserRepository()
+	contacts := memory.NewContactRepository()
+	convs := memory.NewConversationRepository()
+	msgs := NewMessageService(memory.NewMessageRepository(),
+		WithConversations(convs), WithUsers(users), WithContacts(contacts))
+	svc := NewContactService(contacts, users)
+
+	alice := &domain.User{ID: uuid.New(), Username: "alice"}
+	bob := &domain.User{ID: uuid.New(), Username: "bob"}
+	for _, u := range []*domain.User{alice, bob} {
+		assert.NoError(t, users.Create(u))
+	}
+	a, b := alice.ID.String(), bob.ID.String()
+	group := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{a, b, "carol"}}
+	assert.NoError(t, convs.Create(group))
+
+	_, err := msgs.CreateMessage(b, ports.MessageDraft{ReceiverID: a, Content: "hi"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, svc.SetContactsOnly(a, true))
+	_, err = msgs.CreateMessage(b, ports.MessageDraft{ReceiverID: a, Content: "hi"})
+	assert.ErrorIs(t, err, ErrContactsOnly)
+	_, err = msgs.CreateMessage(b, ports.MessageDraft{ConversationID: group.ID.String(), Content: "hi all"})
+	assert.NoError(t, err, "groups are unaffected")
+	_, err = msgs.CreateMessage(a, ports.MessageDraft{ReceiverID: b, Content: "hi"})
+	assert.NoError(t, err, "the setting only limits who may message the user")
+
+	assert.NoError(t, contacts.AddContact(a, b, msgs.now()))
+	_, err = msgs.CreateMessage(b, ports.MessageDraft{ReceiverID: a, Content: "hi"})
+	assert.NoError(t, err)
+}
@@ -14,15 +14,33 @@ import (
 // a conversation with fewer than two participants.
 var ErrTooFewParticipants = errors.New("a conversation requires at least two participants")
 
+// ErrConversationNotFound is returned for unknown conversations and for
+// conversations the caller doesn't belong to.
+var ErrConversationNotFound = errors.New("conversation not found")
+
 // ConversationService is the application‑layer implementation
 // of ports.ConversationService.
 type ConversationService struct {
-	repo ports.ConversationRepository
+	repo   ports.ConversationRepository
+	blocks ports.BlockRepository
+}
+
+// ConversationOption configures optional ConversationService settings.
+type ConversationOption func(*ConversationService)
+
+// WithConversationBlocks refuses direct chats between users who blocked
+// each other.
+func WithConversationBlocks(blocks ports.BlockRepository) ConversationOption {
+	return func(s *ConversationService) { s.blocks = blocks }
 }
 
 // NewConversationService constructs a ConversationService.
-func NewConversationService(repo ports.ConversationRepository) *ConversationService {
-	return &ConversationService{repo: repo}
+func NewConversationService(repo ports.ConversationRepository, opts ...ConversationOption) *ConversationService {
+	s := &ConversationService{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // CreateConversation creates and persists a new conversation
@@ -31,6 +49,15 @@ func (s *ConversationService) CreateConversation(participantIDs []string) (*doma
 	if len(participantIDs) < 2 {
 		return nil, ErrTooFewParticipants
 	}
+	if s.blocks != nil && len(participantIDs) == 2 {
+		blocked, err := blockedEitherWay(s.blocks, participantIDs[0], participantIDs[1])
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, ErrBlocked
+		}
+	}
 
 	conv := &domain.Conversation{
 		ID:             uuid.New(),
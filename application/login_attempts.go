@@ -0,0 +1,66 @@
+package application
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrTooManyAttempts = errors.New("too many failed attempts, try again later")
+
+const (
+	// DefaultMaxLoginAttempts is how many wrong passwords an account
+	// takes within DefaultLoginAttemptWindow before further tries are
+	// refused.
+	DefaultMaxLoginAttempts   = 5
+	DefaultLoginAttemptWindow = 15 * time.Minute
+)
+
+// attemptLimiter counts recent failures per key and refuses keys with too
+// many, so that password checks can't be used for guessing.
+type attemptLimiter struct {
+	max    int
+	window time.Duration
+
+	mu       sync.Mutex
+	failures map[string][]time.Time
+}
+
+func newAttemptLimiter(max int, window time.Duration) *attemptLimiter {
+	return &attemptLimiter{max: max, window: window, failures: make(map[string][]time.Time)}
+}
+
+// allow reports whether key may try again at now.
+func (l *attemptLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.recent(key, now)) < l.max
+}
+
+func (l *attemptLimiter) fail(key string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.failures[key] = append(l.recent(key, now), now)
+}
+
+func (l *attemptLimiter) reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.failures, key)
+}
+
+// recent drops key's failures older than the window. Callers hold mu.
+func (l *attemptLimiter) recent(key string, now time.Time) []time.Time {
+	kept := l.failures[key][:0]
+	for _, at := range l.failures[key] {
+		if now.Sub(at) < l.window {
+			kept = append(kept, at)
+		}
+	}
+	if len(kept) == 0 {
+		delete(l.failures, key)
+		return nil
+	}
+	l.failures[key] = kept
+	return kept
+}
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestMessageService_DeleteMessageForMe(t *testing.T) {
+	repo := memory.NewMessageRepository()
+	svc := NewMessageService(repo)
+
+	msg := &domain.Message{ID: uuid.New(), SenderID: "alice", ReceiverID: "bob", Content: "hi"}
+	assert.NoError(t, repo.Create(msg))
+
+	assert.ErrorIs(t, svc.DeleteMessageForMe("mallory", msg.ID.String()), ErrMessageNotFound)
+	assert.NoError(t, svc.DeleteMessageForMe("bob", msg.ID.String()))
+
+	inbox, err := svc.GetMessagesByReceiver("bob", 10, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, inbox)
+
+	// The sender still sees the message they sent.
+	sent, err := svc.GetMessages("alice")
+	assert.NoError(t, err)
+	assert.Len(t, sent, 1)
+	assert.Equal(t, "hi", sent[0].Content)
+}
+
+func TestMessageService_DeleteMessageForEveryone(t *testing.T) {
+	repo := memory.NewMessageRepository()
+	notifier := mocks.NewMockNotificationService(t)
+	svc := NewMessageService(repo, WithNotifier(notifier), WithDeleteWindow(time.Hour))
+
+	first := &domain.Message{ID: uuid.New(), SenderID: "alice", ReceiverID: "bob", Content: "secret"}
+	second := &domain.Message{ID: uuid.New(), SenderID: "alice", ReceiverID: "bob", Content: "later"}
+	assert.NoError(t, repo.Create(first))
+	assert.NoError(t, repo.Create(second))
+	assert.NoError(t, repo.AddRevision(&domain.MessageRevision{MessageID: first.ID, Content: "secrte"}))
+	svc.now = func() time.Time { return first.CreatedAt.Add(time.Minute) }
+
+	assert.ErrorIs(t, svc.DeleteMessageForEveryone("bob", first.ID.String()), ErrNotMessageSender)
+
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == "bob" && n.Type == domain.NotificationMessageDeleted && n.Body == ""
+	})).Return(nil).Once()
+	assert.NoError(t, svc.DeleteMessageForEveryone("alice", first.ID.String()))
+	// Deleting again is a no-op.
+	assert.NoError(t, svc.DeleteMessageForEveryone("alice", first.ID.String()))
+
+	inbox, err := svc.GetMessagesByReceiver("bob", 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, inbox, 2)
+	assert.Equal(t, first.ID, inbox[0].ID)
+	assert.True(t, inbox[0].Deleted())
+	assert.Empty(t, inbox[0].Content)
+	assert.Equal(t, "later", inbox[1].Content)
+
+	history, err := svc.GetMessageHistory("bob", first.ID.String())
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+
+	_, err = svc.EditMessage("alice", first.ID.String(), "again")
+	assert.ErrorIs(t, err, ErrMessageDeleted)
+
+	svc.now = func() time.Time { return second.CreatedAt.Add(2 * time.Hour) }
+	assert.ErrorIs(t, svc.DeleteMessageForEveryone("alice", second.ID.String()), ErrDeleteWindowExpired)
+}
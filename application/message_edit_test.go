@@ -0,0 +1,59 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestMessageService_EditMessage(t *testing.T) {
+	repo := memory.NewMessageRepository()
+	convs := memory.NewConversationRepository()
+	notifier := mocks.NewMockNotificationService(t)
+	svc := NewMessageService(repo, WithConversations(convs), WithNotifier(notifier), WithEditWindow(time.Minute))
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob", "carol"}}
+	assert.NoError(t, convs.Create(conv))
+	msg := &domain.Message{ID: uuid.New(), SenderID: "alice", ConversationID: conv.ID, Content: "helo"}
+	assert.NoError(t, repo.Create(msg))
+	created := msg.CreatedAt
+	svc.now = func() time.Time { return created.Add(30 * time.Second) }
+
+	_, err := svc.EditMessage("bob", msg.ID.String(), "hello")
+	assert.ErrorIs(t, err, ErrNotMessageSender)
+	_, err = svc.EditMessage("alice", msg.ID.String(), "")
+	assert.ErrorIs(t, err, ErrMessageContentRequired)
+	_, err = svc.EditMessage("alice", uuid.NewString(), "hello")
+	assert.ErrorIs(t, err, ErrMessageNotFound)
+
+	for _, user := range []string{"bob", "carol"} {
+		notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == user && n.Type == domain.NotificationMessageEdited &&
+				n.MessageID == msg.ID && n.Body == "hello"
+		})).Return(nil).Once()
+	}
+	edited, err := svc.EditMessage("alice", msg.ID.String(), "hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", edited.Content)
+	assert.NotNil(t, edited.EditedAt)
+
+	history, err := svc.GetMessageHistory("carol", msg.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, "helo", history[0].Content)
+	assert.Equal(t, created, history[0].CreatedAt)
+
+	_, err = svc.GetMessageHistory("mallory", msg.ID.String())
+	assert.ErrorIs(t, err, ErrMessageNotFound)
+
+	svc.now = func() time.Time { return created.Add(2 * time.Minute) }
+	_, err = svc.EditMessage("alice", msg.ID.String(), "hello!")
+	assert.ErrorIs(t, err, ErrEditWindowExpired)
+}
@@ -0,0 +1,38 @@
+package application
+
+import (
+	"errors"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+var ErrInvalidFormat = errors.New("format must be plain or markdown")
+
+// messageFormat validates a draft's format, which defaults to plain.
+func messageFormat(format domain.MessageFormat) (domain.MessageFormat, error) {
+	if format == "" {
+		return domain.FormatPlain, nil
+	}
+	if !format.Valid() {
+		return "", ErrInvalidFormat
+	}
+	return format, nil
+}
+
+// formatted reduces content written in format to the plain text and
+// entities stored with a message. The plain text is what notifications,
+// quotes and search see.
+func formatted(format domain.MessageFormat, content string) (string, []domain.Entity) {
+	if format == domain.FormatMarkdown {
+		return domain.ParseMarkdown(content)
+	}
+	return content, nil
+}
+
+// renderHTML fills in msg.HTML for messages written in markdown.
+func renderHTML(msg *domain.Message) {
+	msg.HTML = ""
+	if msg.Format == domain.FormatMarkdown && !msg.Deleted() {
+		msg.HTML = domain.RenderHTML(msg.Content, msg.Entities)
+	}
+}
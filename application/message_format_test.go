@@ -0,0 +1,73 @@
+package application
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestMessageService_MarkdownMessages(t *testing.T) {
+	repo := memory.NewMessageRepository()
+	convs := memory.NewConversationRepository()
+	notifier := mocks.NewMockNotificationService(t)
+	svc := NewMessageService(repo, WithConversations(convs), WithNotifier(notifier))
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(conv))
+
+	_, err := svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "hi", Format: "html"})
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+	_, err = svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "```\n```", Format: domain.FormatMarkdown})
+	assert.ErrorIs(t, err, ErrMessageContentRequired)
+	_, err = svc.CreateMessage("alice", ports.MessageDraft{
+		ConversationID: conv.ID.String(),
+		Content:        strings.Repeat("é", MaxMessageLength+1),
+		Format:         domain.FormatMarkdown,
+	})
+	assert.ErrorIs(t, err, ErrMessageTooLong)
+
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Type == domain.NotificationNewMessage
+	})).Return(nil).Twice()
+	msg, err := svc.CreateMessage("alice", ports.MessageDraft{
+		ConversationID: conv.ID.String(),
+		Content:        "**ship it** <img src=x onerror=alert(1)>",
+		Format:         domain.FormatMarkdown,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ship it <img src=x onerror=alert(1)>", msg.Content)
+	assert.Equal(t, []domain.Entity{{Type: domain.EntityBold, Offset: 0, Length: 7}}, msg.Entities)
+	assert.Equal(t, "<strong>ship it</strong> &lt;img src=x onerror=alert(1)&gt;", msg.HTML)
+
+	plain, err := svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "**as typed**"})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.FormatPlain, plain.Format)
+	assert.Equal(t, "**as typed**", plain.Content)
+	assert.Empty(t, plain.HTML)
+
+	// Edits are read in the message's format, and notifications carry the
+	// plain text.
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == "bob" && n.Body == "ship it now"
+	})).Return(nil).Once()
+	_, err = svc.EditMessage("alice", msg.ID.String(), strings.Repeat("a", MaxMessageLength+1))
+	assert.ErrorIs(t, err, ErrMessageTooLong)
+	edited, err := svc.EditMessage("alice", msg.ID.String(), "ship it *now*")
+	assert.NoError(t, err)
+	assert.Equal(t, "ship it <em>now</em>", edited.HTML)
+
+	msgs, err := svc.GetConversationMessages("bob", conv.ID.String(), 10, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 2) {
+		assert.Equal(t, "ship it <em>now</em>", msgs[0].HTML)
+		assert.Empty(t, msgs[1].HTML)
+	}
+}
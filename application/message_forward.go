@@ -0,0 +1,107 @@
+package application
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+var (
+	ErrNotForwardable = errors.New("only messages with text can be forwarded")
+	ErrInvalidQuote   = errors.New("only messages from the same chat can be quoted")
+)
+
+// ForwardMessage copies a message userID can see into another chat under
+// userID's name, crediting the original sender and time. The copy stands
+// alone: editing or deleting the original doesn't change it. Attachments
+// aren't forwarded.
+func (s *MessageService) ForwardMessage(userID, messageID string, target ports.ForwardTarget) (*domain.Message, error) {
+	original, err := s.findMessage(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canSee(original, userID) {
+		return nil, ErrMessageNotFound
+	}
+	if original.Deleted() {
+		return nil, ErrMessageDeleted
+	}
+	if original.System || original.Content == "" {
+		return nil, ErrNotForwardable
+	}
+
+	attribution := domain.Forward{MessageID: original.ID, SenderID: original.SenderID, CreatedAt: original.CreatedAt}
+	if original.ForwardedFrom != nil {
+		attribution = *original.ForwardedFrom
+	}
+	message := &domain.Message{
+		ID:            uuid.New(),
+		SenderID:      userID,
+		Content:       original.Content,
+		Format:        original.Format,
+		Entities:      original.Entities,
+		ForwardedFrom: &attribution,
+	}
+	draft := ports.MessageDraft{ReceiverID: target.ReceiverID, ConversationID: target.ConversationID}
+	if err := s.placeMessage(message, draft); err != nil {
+		return nil, err
+	}
+	if err := s.checkAllowed(message); err != nil {
+		return nil, err
+	}
+	if err := s.store(message, nil); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// quoteFor snapshots the message quoteID for quoting in message, which
+// has already been placed.
+func (s *MessageService) quoteFor(message *domain.Message, quoteID string) (*domain.Quote, error) {
+	quoted, err := s.findMessage(quoteID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canSee(quoted, message.SenderID) {
+		return nil, ErrMessageNotFound
+	}
+	if quoted.Deleted() {
+		return nil, ErrMessageDeleted
+	}
+	if !sameChat(quoted, message) {
+		return nil, ErrInvalidQuote
+	}
+	return &domain.Quote{
+		MessageID: quoted.ID,
+		SenderID:  quoted.SenderID,
+		Content:   quoted.Content,
+		CreatedAt: quoted.CreatedAt,
+	}, nil
+}
+
+// quoteAsSeen withholds a quote's snapshot once its original is gone, so
+// deleting or outliving a message also removes it from quotes.
+func (s *MessageService) quoteAsSeen(quote *domain.Quote) *domain.Quote {
+	if quote == nil {
+		return nil
+	}
+	c := *quote
+	if original, err := s.repo.FindByID(quote.MessageID); err != nil || original.Deleted() {
+		c.Content = ""
+		c.Unavailable = true
+	}
+	return &c
+}
+
+// sameChat reports whether a and b belong to the same conversation or
+// the same pair of direct-message users.
+func sameChat(a, b *domain.Message) bool {
+	if a.ConversationID != uuid.Nil || b.ConversationID != uuid.Nil {
+		return a.ConversationID == b.ConversationID
+	}
+	return (a.SenderID == b.SenderID && a.ReceiverID == b.ReceiverID) ||
+		(a.SenderID == b.ReceiverID && a.ReceiverID == b.SenderID)
+}
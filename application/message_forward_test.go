@@ -0,0 +1,133 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestMessageService_ForwardMessage(t *testing.T) {
+	repo := memory.NewMessageRepository()
+	convs := memory.NewConversationRepository()
+	svc := NewMessageService(repo, WithConversations(convs))
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(conv))
+	original, err := svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "big news"})
+	assert.NoError(t, err)
+
+	_, err = svc.ForwardMessage("carol", original.ID.String(), ports.ForwardTarget{ReceiverID: "dave"})
+	assert.ErrorIs(t, err, ErrMessageNotFound)
+
+	forwarded, err := svc.ForwardMessage("bob", original.ID.String(), ports.ForwardTarget{ReceiverID: "carol"})
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", forwarded.SenderID)
+	assert.Equal(t, "carol", forwarded.ReceiverID)
+	assert.Equal(t, "big news", forwarded.Content)
+	if assert.NotNil(t, forwarded.ForwardedFrom) {
+		assert.Equal(t, original.ID, forwarded.ForwardedFrom.MessageID)
+		assert.Equal(t, "alice", forwarded.ForwardedFrom.SenderID)
+	}
+
+	// Forwarding a forward keeps crediting the original author.
+	again, err := svc.ForwardMessage("carol", forwarded.ID.String(), ports.ForwardTarget{ReceiverID: "dave"})
+	assert.NoError(t, err)
+	assert.Equal(t, original.ID, again.ForwardedFrom.MessageID)
+	assert.Equal(t, "alice", again.ForwardedFrom.SenderID)
+
+	// The copy outlives the original.
+	assert.NoError(t, svc.DeleteMessageForEveryone("alice", original.ID.String()))
+	_, err = svc.ForwardMessage("bob", original.ID.String(), ports.ForwardTarget{ReceiverID: "carol"})
+	assert.ErrorIs(t, err, ErrMessageDeleted)
+	stored, err := repo.FindByID(forwarded.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "big news", stored.Content)
+}
+
+func TestMessageService_Quotes(t *testing.T) {
+	repo := memory.NewMessageRepository()
+	convs := memory.NewConversationRepository()
+	svc := NewMessageService(repo, WithConversations(convs))
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	other := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(conv))
+	assert.NoError(t, convs.Create(other))
+	quoted, err := svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "lunch at noon?"})
+	assert.NoError(t, err)
+
+	_, err = svc.CreateMessage("bob", ports.MessageDraft{
+		ConversationID: other.ID.String(), Content: "sure", QuoteID: quoted.ID.String(),
+	})
+	assert.ErrorIs(t, err, ErrInvalidQuote)
+
+	reply, err := svc.CreateMessage("bob", ports.MessageDraft{
+		ConversationID: conv.ID.String(), Content: "sure", QuoteID: quoted.ID.String(),
+	})
+	assert.NoError(t, err)
+	if assert.NotNil(t, reply.Quote) {
+		assert.Equal(t, "alice", reply.Quote.SenderID)
+		assert.Equal(t, "lunch at noon?", reply.Quote.Content)
+	}
+
+	// The quote is a snapshot: editing the original leaves it as it was.
+	_, err = svc.EditMessage("alice", quoted.ID.String(), "lunch at one?")
+	assert.NoError(t, err)
+	msgs, err := svc.GetConversationMessages("bob", conv.ID.String(), 10, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 2) {
+		assert.Equal(t, "lunch at noon?", msgs[1].Quote.Content)
+		assert.False(t, msgs[1].Quote.Unavailable)
+	}
+
+	// Once the original is deleted its text is withheld from quotes too.
+	assert.NoError(t, svc.DeleteMessageForEveryone("alice", quoted.ID.String()))
+	msgs, err = svc.GetConversationMessages("bob", conv.ID.String(), 10, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, msgs, 2) {
+		assert.True(t, msgs[1].Quote.Unavailable)
+		assert.Empty(t, msgs[1].Quote.Content)
+		assert.Equal(t, "alice", msgs[1].Quote.SenderID)
+	}
+	_, err = svc.CreateMessage("bob", ports.MessageDraft{
+		ConversationID: conv.ID.String(), Content: "what?", QuoteID: quoted.ID.String(),
+	})
+	assert.ErrorIs(t, err, ErrMessageDeleted)
+}
+
+func TestMessageService_ForwardAnnouncesCopy(t *testing.T) {
+	repo := memory.NewMessageRepository()
+	convs := memory.NewConversationRepository()
+	users := memory.NewUserRepository()
+	notifier := mocks.NewMockNotificationService(t)
+	svc := NewMessageService(repo, WithConversations(convs), WithUsers(users), WithNotifier(notifier))
+
+	alice := &domain.User{ID: uuid.New(), Username: "alice"}
+	bob := &domain.User{ID: uuid.New(), Username: "bob"}
+	carol := &domain.User{ID: uuid.New(), Username: "carol"}
+	for _, u := range []*domain.User{alice, bob, carol} {
+		assert.NoError(t, users.Create(u))
+	}
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Type == domain.NotificationNewMessage && n.UserID == bob.ID.String()
+	})).Return(nil).Once()
+	original, err := svc.CreateMessage(alice.ID.String(), ports.MessageDraft{ReceiverID: bob.ID.String(), Content: "ask @carol"})
+	assert.NoError(t, err)
+
+	// The copy reaches carol like any message that mentions her.
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Type == domain.NotificationMention && n.UserID == carol.ID.String()
+	})).Return(nil).Once()
+	forwarded, err := svc.ForwardMessage(bob.ID.String(), original.ID.String(), ports.ForwardTarget{ReceiverID: carol.ID.String()})
+	assert.NoError(t, err)
+	if assert.Len(t, forwarded.Mentions, 1) {
+		assert.Equal(t, carol.ID.String(), forwarded.Mentions[0].UserID)
+	}
+}
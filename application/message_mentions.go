@@ -0,0 +1,73 @@
+package application
+
+import (
+	"github.com/chrikar/chatheon/domain"
+)
+
+// GetMentions returns the messages that mention userID, newest first.
+func (s *MessageService) GetMentions(userID string, limit, offset int) ([]*domain.Message, error) {
+	msgs, err := s.repo.GetMentions(userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.decorate(userID, msgs)
+}
+
+// resolveMentions looks up the @username tokens in msg.Content. Only users
+// who can see msg are kept, so a mention never reveals a message to an
+// outsider.
+func (s *MessageService) resolveMentions(msg *domain.Message) []domain.Mention {
+	if s.users == nil {
+		return nil
+	}
+	recipients := s.recipients(msg)
+
+	var out []domain.Mention
+	for _, m := range domain.ParseMentions(msg.Content) {
+		user, err := s.users.FindByUsername(m.Username)
+		if err != nil {
+			continue
+		}
+		id := user.ID.String()
+		if id != msg.SenderID && !contains(recipients, id) {
+			continue
+		}
+		m.UserID = id
+		out = append(out, m)
+	}
+	return out
+}
+
+// notifyMentions sends a high-priority notification to each user msg
+// mentions, except the sender and anyone already in previous.
+func (s *MessageService) notifyMentions(msg *domain.Message, previous []domain.Mention) {
+	var userIDs []string
+	for _, m := range msg.Mentions {
+		if m.UserID == msg.SenderID || mentioned(previous, m.UserID) {
+			continue
+		}
+		userIDs = append(userIDs, m.UserID)
+	}
+	s.notifyUsers(userIDs, msg, domain.NotificationMention, domain.PriorityHigh, msg.SenderID)
+}
+
+// notifyNew tells msg's recipients it arrived, except those it mentions,
+// who were already told with a mention.
+func (s *MessageService) notifyNew(msg *domain.Message) {
+	var userIDs []string
+	for _, id := range s.recipients(msg) {
+		if !mentioned(msg.Mentions, id) {
+			userIDs = append(userIDs, id)
+		}
+	}
+	s.notifyUsers(userIDs, msg, domain.NotificationNewMessage, domain.PriorityNormal, msg.SenderID)
+}
+
+func mentioned(mentions []domain.Mention, userID string) bool {
+	for _, m := range mentions {
+		if m.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestMessageService_Mentions(t *testing.T) {
+	users := memory.NewUserRepository()
+	convs := memory.NewConversationRepository()
+	notifier := mocks.NewMockNotificationService(t)
+	svc := NewMessageService(memory.NewMessageRepository(),
+		WithUsers(users), WithConversations(convs), WithNotifier(notifier))
+
+	ids := make(map[string]string)
+	for _, name := range []string{"alice", "bob", "carol", "mallory"} {
+		user := &domain.User{ID: uuid.New(), Username: name}
+		assert.NoError(t, users.Create(user))
+		ids[name] = user.ID.String()
+	}
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{ids["alice"], ids["bob"], ids["carol"]}}
+	assert.NoError(t, convs.Create(conv))
+
+	// Only participants are mentioned; the sender isn't notified about themselves.
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == ids["bob"] && n.Type == domain.NotificationMention && n.Priority == domain.PriorityHigh
+	})).Return(nil).Once()
+	// Everyone else only hears that a message arrived.
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == ids["carol"] && n.Type == domain.NotificationNewMessage
+	})).Return(nil).Once()
+	msg, err := svc.CreateMessage(ids["alice"], ports.MessageDraft{
+		ConversationID: conv.ID.String(),
+		Content:        "@bob @mallory @alice @nobody standup?",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, msg.Mentions, 2)
+	assert.Equal(t, ids["bob"], msg.Mentions[0].UserID)
+	assert.Equal(t, ids["alice"], msg.Mentions[1].UserID)
+
+	// Editing notifies newly mentioned users only, plus the usual edit event.
+	svc.now = func() time.Time { return msg.CreatedAt.Add(time.Minute) }
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Type == domain.NotificationMessageEdited
+	})).Return(nil).Twice()
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == ids["carol"] && n.Type == domain.NotificationMention
+	})).Return(nil).Once()
+	_, err = svc.EditMessage(ids["alice"], msg.ID.String(), "@bob @carol standup?")
+	assert.NoError(t, err)
+
+	inbox, err := svc.GetMentions(ids["carol"], 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, inbox, 1)
+	assert.Equal(t, msg.ID, inbox[0].ID)
+
+	inbox, err = svc.GetMentions(ids["mallory"], 10, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, inbox)
+}
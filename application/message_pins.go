@@ -0,0 +1,182 @@
+package application
+
+import (
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+var (
+	ErrPinsUnavailable = errors.New("pins are not enabled")
+	ErrPinNotFound     = errors.New("message is not pinned")
+	ErrTooManyPins     = errors.New("conversation has too many pinned messages")
+	ErrPinForbidden    = errors.New("your role may not pin messages")
+	ErrNotPinnable     = errors.New("only messages in a conversation can be pinned")
+)
+
+// MaxPinsPerConversation caps how many messages a conversation may have
+// pinned at once.
+const MaxPinsPerConversation = 50
+
+// PinMessage pins a message in its conversation and tells the other
+// participants. Pinning a pinned message is a no-op.
+func (s *MessageService) PinMessage(userID, messageID string) (*domain.Pin, error) {
+	if s.pins == nil {
+		return nil, ErrPinsUnavailable
+	}
+	msg, conv, err := s.pinTarget(userID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Deleted() {
+		return nil, ErrMessageDeleted
+	}
+
+	pins, err := s.currentPins(conv.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pin := range pins {
+		if pin.MessageID == msg.ID {
+			return pin, nil
+		}
+	}
+
+	pin := &domain.Pin{
+		ConversationID: conv.ID,
+		MessageID:      msg.ID,
+		PinnedBy:       userID,
+		PinnedAt:       s.now(),
+	}
+	if err := s.pins.Add(pin, MaxPinsPerConversation); err != nil {
+		if errors.Is(err, ports.ErrPinLimit) {
+			return nil, ErrTooManyPins
+		}
+		return nil, err
+	}
+	s.notifyUsers(others(conv.ParticipantIDs, userID), msg, domain.NotificationMessagePinned, domain.PriorityNormal, userID)
+	pin.Message = msg
+	return pin, nil
+}
+
+// UnpinMessage unpins a message and tells the other participants.
+func (s *MessageService) UnpinMessage(userID, messageID string) error {
+	if s.pins == nil {
+		return ErrPinsUnavailable
+	}
+	msg, conv, err := s.pinTarget(userID, messageID)
+	if err != nil {
+		return err
+	}
+	if err := s.pins.Remove(conv.ID, msg.ID); err != nil {
+		return ErrPinNotFound
+	}
+	s.notifyUsers(others(conv.ParticipantIDs, userID), msg, domain.NotificationMessageUnpinned, domain.PriorityNormal, userID)
+	return nil
+}
+
+// GetPins lists a conversation's pinned messages, most recently pinned
+// first.
+func (s *MessageService) GetPins(userID, conversationID string) ([]*domain.Pin, error) {
+	if s.pins == nil {
+		return nil, ErrPinsUnavailable
+	}
+	conv, err := s.conversationFor(userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	pins, err := s.currentPins(conv.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]*domain.Message, len(pins))
+	for i, pin := range pins {
+		msgs[i] = pin.Message
+	}
+	msgs, err = s.decorate(userID, msgs)
+	if err != nil {
+		return nil, err
+	}
+	for i, pin := range pins {
+		pin.Message = msgs[i]
+	}
+	return pins, nil
+}
+
+// pinTarget loads a message userID may pin or unpin, with its
+// conversation.
+func (s *MessageService) pinTarget(userID, messageID string) (*domain.Message, *domain.Conversation, error) {
+	msg, err := s.findMessage(messageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if msg.ConversationID == uuid.Nil {
+		if !s.canSee(msg, userID) {
+			return nil, nil, ErrMessageNotFound
+		}
+		return nil, nil, ErrNotPinnable
+	}
+	conv, err := s.conversationFor(userID, msg.ConversationID.String())
+	if err != nil {
+		return nil, nil, ErrMessageNotFound
+	}
+	if !s.mayPin(userID) {
+		return nil, nil, ErrPinForbidden
+	}
+	return msg, conv, nil
+}
+
+// mayPin reports whether userID's role allows pinning.
+func (s *MessageService) mayPin(userID string) bool {
+	role := domain.RoleUser
+	if s.users != nil {
+		if id, err := uuid.Parse(userID); err == nil {
+			if user, err := s.users.FindByID(id); err == nil {
+				role = user.Role
+			}
+		}
+	}
+	return role.AtLeast(s.pinRole)
+}
+
+// currentPins returns a conversation's pins with their messages. Pins
+// whose message has since been deleted or has expired are dropped so
+// they don't count against the cap.
+func (s *MessageService) currentPins(conversationID uuid.UUID) ([]*domain.Pin, error) {
+	pins, err := s.pins.FindByConversation(conversationID)
+	if err != nil {
+		return nil, err
+	}
+	kept := pins[:0]
+	for _, pin := range pins {
+		msg, err := s.repo.FindByID(pin.MessageID)
+		if err != nil && !errors.Is(err, ports.ErrMessageNotFound) {
+			return nil, err
+		}
+		if err != nil || msg.Deleted() {
+			if err := s.pins.Remove(conversationID, pin.MessageID); err != nil {
+				log.Printf("drop pin of message %s: %v", pin.MessageID, err)
+			}
+			continue
+		}
+		pin.Message = msg
+		kept = append(kept, pin)
+	}
+	return kept, nil
+}
+
+// others returns userIDs without userID.
+func others(userIDs []string, userID string) []string {
+	var out []string
+	for _, id := range userIDs {
+		if id != userID {
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestMessageService_Pins(t *testing.T) {
+	repo := memory.NewMessageRepository()
+	convs := memory.NewConversationRepository()
+	notifier := mocks.NewMockNotificationService(t)
+	svc := NewMessageService(repo, WithConversations(convs), WithPins(memory.NewPinRepository()), WithNotifier(notifier))
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob", "carol"}}
+	assert.NoError(t, convs.Create(conv))
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Type == domain.NotificationNewMessage
+	})).Return(nil).Times(5)
+	first, err := svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "rules"})
+	assert.NoError(t, err)
+	second, err := svc.CreateMessage("bob", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "agenda"})
+	assert.NoError(t, err)
+	direct, err := svc.CreateMessage("alice", ports.MessageDraft{ReceiverID: "bob", Content: "psst"})
+	assert.NoError(t, err)
+
+	_, err = svc.PinMessage("mallory", first.ID.String())
+	assert.ErrorIs(t, err, ErrMessageNotFound)
+	_, err = svc.PinMessage("bob", direct.ID.String())
+	assert.ErrorIs(t, err, ErrNotPinnable)
+
+	for _, user := range []string{"alice", "carol"} {
+		notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == user && n.Type == domain.NotificationMessagePinned && n.ActorID == "bob" && n.MessageID == first.ID
+		})).Return(nil).Once()
+	}
+	pin, err := svc.PinMessage("bob", first.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", pin.PinnedBy)
+	assert.Equal(t, "rules", pin.Message.Content)
+
+	// Pinning again changes nothing and tells no one.
+	again, err := svc.PinMessage("carol", first.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", again.PinnedBy)
+
+	notifier.On("Notify", mock.Anything).Return(nil).Twice()
+	_, err = svc.PinMessage("alice", second.ID.String())
+	assert.NoError(t, err)
+
+	pins, err := svc.GetPins("carol", conv.ID.String())
+	assert.NoError(t, err)
+	if assert.Len(t, pins, 2) {
+		assert.Equal(t, second.ID, pins[0].MessageID)
+		assert.Equal(t, "rules", pins[1].Message.Content)
+	}
+	_, err = svc.GetPins("mallory", conv.ID.String())
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+
+	for _, user := range []string{"bob", "carol"} {
+		notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == user && n.Type == domain.NotificationMessageUnpinned && n.MessageID == second.ID
+		})).Return(nil).Once()
+	}
+	assert.NoError(t, svc.UnpinMessage("alice", second.ID.String()))
+	assert.ErrorIs(t, svc.UnpinMessage("alice", second.ID.String()), ErrPinNotFound)
+
+	// A pinned message deleted for everyone drops off the list.
+	notifier.On("Notify", mock.Anything).Return(nil)
+	assert.NoError(t, svc.DeleteMessageForEveryone("alice", first.ID.String()))
+	pins, err = svc.GetPins("bob", conv.ID.String())
+	assert.NoError(t, err)
+	assert.Empty(t, pins)
+}
+
+func TestMessageService_PinLimitAndRole(t *testing.T) {
+	repo := memory.NewMessageRepository()
+	convs := memory.NewConversationRepository()
+	users := memory.NewUserRepository()
+	mod := &domain.User{ID: uuid.New(), Username: "mod", Role: domain.RoleModerator}
+	member := &domain.User{ID: uuid.New(), Username: "member", Role: domain.RoleUser}
+	assert.NoError(t, users.Create(mod))
+	assert.NoError(t, users.Create(member))
+	svc := NewMessageService(repo, WithConversations(convs), WithUsers(users),
+		WithPins(memory.NewPinRepository()), WithPinRole(domain.RoleModerator))
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{mod.ID.String(), member.ID.String()}}
+	assert.NoError(t, convs.Create(conv))
+
+	msg, err := svc.CreateMessage(member.ID.String(), ports.MessageDraft{ConversationID: conv.ID.String(), Content: "pin me"})
+	assert.NoError(t, err)
+	_, err = svc.PinMessage(member.ID.String(), msg.ID.String())
+	assert.ErrorIs(t, err, ErrPinForbidden)
+
+	for i := 0; i < MaxPinsPerConversation; i++ {
+		m, err := svc.CreateMessage(mod.ID.String(), ports.MessageDraft{ConversationID: conv.ID.String(), Content: "notice"})
+		assert.NoError(t, err)
+		_, err = svc.PinMessage(mod.ID.String(), m.ID.String())
+		assert.NoError(t, err)
+	}
+	_, err = svc.PinMessage(mod.ID.String(), msg.ID.String())
+	assert.ErrorIs(t, err, ErrTooManyPins)
+}
+
+// brokenReads fails every FindByID, as a database outage would.
+type brokenReads struct {
+	*memory.MessageRepository
+}
+
+func (brokenReads) FindByID(uuid.UUID) (*domain.Message, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestMessageService_PinsKeptWhenMessagesCantBeRead(t *testing.T) {
+	repo := memory.NewMessageRepository()
+	convs := memory.NewConversationRepository()
+	pins := memory.NewPinRepository()
+	svc := NewMessageService(repo, WithConversations(convs), WithPins(pins))
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(conv))
+	msg, err := svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "rules"})
+	assert.NoError(t, err)
+	_, err = svc.PinMessage("alice", msg.ID.String())
+	assert.NoError(t, err)
+
+	broken := NewMessageService(brokenReads{repo}, WithConversations(convs), WithPins(pins))
+	_, err = broken.GetPins("bob", conv.ID.String())
+	assert.Error(t, err)
+
+	// An outage isn't mistaken for a deleted message.
+	stored, err := pins.FindByConversation(conv.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stored, 1)
+}
@@ -0,0 +1,120 @@
+package application
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+// purgeBatchSize caps how many messages one delete removes, so no single
+// statement holds locks for long.
+const purgeBatchSize = 500
+
+// messagePurger deletes messages for good together with everything
+// derived from them: search-index entries, reactions, pins, and
+// attachments with their files. Collaborators left nil are skipped.
+type messagePurger struct {
+	messages    ports.MessageRepository
+	reactions   ports.ReactionRepository
+	attachments ports.AttachmentRepository
+	blobs       ports.BlobStore
+	search      ports.MessageSearchIndex
+	pins        ports.PinRepository
+}
+
+// purgeMatching removes the messages matching filter batch by batch,
+// adding what it removed to counts. A dry run pages through instead.
+func (p *messagePurger) purgeMatching(filter ports.RetentionFilter, dryRun bool, counts *domain.PurgeCounts) error {
+	// A reply can show up both with its root and on a later page of its
+	// own; seen keeps a dry run from counting it twice.
+	seen := make(map[uuid.UUID]bool)
+	for offset := 0; ; {
+		page, err := p.messages.FindPastRetention(filter, purgeBatchSize, offset)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		var ids []uuid.UUID
+		for _, msg := range page {
+			if !seen[msg.ID] {
+				seen[msg.ID] = true
+				ids = append(ids, msg.ID)
+			}
+		}
+		if dryRun {
+			offset += purgeBatchSize
+		} else if len(ids) == 0 {
+			// The last batch wasn't deleted; stop rather than spin.
+			return nil
+		}
+		batch, err := p.purge(ids, dryRun)
+		if err != nil {
+			return err
+		}
+		counts.Add(batch)
+	}
+}
+
+// purge removes the given messages, which must include the thread
+// replies of any root among them, and reports what went. A dry run only
+// counts. Derived data goes first, so an interrupted purge leaves
+// nothing behind that a retry can't find.
+func (p *messagePurger) purge(ids []uuid.UUID, dryRun bool) (domain.PurgeCounts, error) {
+	counts := domain.PurgeCounts{Messages: len(ids)}
+	if len(ids) == 0 {
+		return counts, nil
+	}
+	var attachments []*domain.Attachment
+	if p.attachments != nil {
+		var err error
+		if attachments, err = p.attachments.FindByMessages(ids); err != nil {
+			return counts, err
+		}
+	}
+	for _, a := range attachments {
+		counts.Attachments++
+		counts.Bytes += a.Size
+	}
+	if dryRun {
+		return counts, nil
+	}
+
+	if p.search != nil {
+		for _, id := range ids {
+			if err := p.search.Remove(id); err != nil {
+				return counts, fmt.Errorf("search index: %w", err)
+			}
+		}
+	}
+	if p.reactions != nil {
+		if err := p.reactions.DeleteByMessages(ids); err != nil {
+			return counts, err
+		}
+	}
+	if p.pins != nil {
+		if err := p.pins.DeleteByMessages(ids); err != nil {
+			return counts, err
+		}
+	}
+	if p.blobs != nil {
+		for _, a := range attachments {
+			if err := p.blobs.Delete(originalKey(a.ID)); err != nil {
+				return counts, err
+			}
+			if err := p.blobs.Delete(thumbnailKey(a.ID)); err != nil {
+				return counts, err
+			}
+		}
+	}
+	if p.attachments != nil {
+		if err := p.attachments.DeleteByMessages(ids); err != nil {
+			return counts, err
+		}
+	}
+	return counts, p.messages.DeleteMany(ids)
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestMessageService_ConversationTimeline(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	svc := NewMessageService(memory.NewMessageRepository(), WithConversations(convs))
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(conv))
+
+	_, err := svc.CreateMessage("alice", ports.MessageDraft{Content: "hi"})
+	assert.ErrorIs(t, err, ErrRecipientRequired)
+	_, err = svc.CreateMessage("alice", ports.MessageDraft{ReceiverID: "bob", ConversationID: conv.ID.String(), Content: "hi"})
+	assert.ErrorIs(t, err, ErrRecipientRequired)
+	_, err = svc.CreateMessage("mallory", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "hi"})
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+
+	for _, content := range []string{"one", "two", "three"} {
+		msg, err := svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: content})
+		assert.NoError(t, err)
+		assert.Equal(t, conv.ID, msg.ConversationID)
+	}
+
+	page, err := svc.GetConversationMessages("bob", conv.ID.String(), 2, 1)
+	assert.NoError(t, err)
+	assert.Len(t, page, 2)
+	assert.Equal(t, "two", page[0].Content)
+
+	_, err = svc.GetConversationMessages("mallory", conv.ID.String(), 10, 0)
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+}
+
+func TestMessageService_Reactions(t *testing.T) {
+	repo := memory.NewMessageRepository()
+	svc := NewMessageService(repo, WithReactions(memory.NewReactionRepository()))
+
+	msg, err := svc.CreateMessage("alice", ports.MessageDraft{ReceiverID: "bob", Content: "lunch?"})
+	assert.NoError(t, err)
+	id := msg.ID.String()
+
+	assert.ErrorIs(t, svc.AddReaction("bob", id, "yes"), ErrInvalidReaction)
+	assert.ErrorIs(t, svc.AddReaction("mallory", id, "👍"), ErrMessageNotFound)
+	assert.NoError(t, svc.AddReaction("bob", id, "👍"))
+	assert.NoError(t, svc.AddReaction("bob", id, "👍"))
+	assert.NoError(t, svc.AddReaction("alice", id, "👍"))
+	assert.NoError(t, svc.AddReaction("alice", id, ":pizza:"))
+
+	inbox, err := svc.GetMessagesByReceiver("bob", 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, inbox, 1)
+	assert.Equal(t, []domain.ReactionSummary{
+		{Emoji: "👍", Count: 2, UserIDs: []string{"bob", "alice"}},
+		{Emoji: ":pizza:", Count: 1, UserIDs: []string{"alice"}},
+	}, inbox[0].Reactions)
+
+	// Summaries are attached to copies, not to the stored message.
+	stored, _ := repo.FindByID(msg.ID)
+	assert.Nil(t, stored.Reactions)
+
+	assert.NoError(t, svc.RemoveReaction("bob", id, "👍"))
+	assert.ErrorIs(t, svc.RemoveReaction("bob", id, "👍"), ErrReactionNotFound)
+
+	inbox, err = svc.GetMessagesByReceiver("bob", 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inbox[0].Reactions[0].Count)
+
+	assert.ErrorIs(t, NewMessageService(repo).AddReaction("bob", id, "👍"), ErrReactionsUnavailable)
+}
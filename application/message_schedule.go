@@ -0,0 +1,237 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+var (
+	ErrScheduledMessageNotFound   = errors.New("scheduled message not found")
+	ErrScheduledMessageNotPending = errors.New("scheduled message was already sent or canceled")
+	ErrSendAtInPast               = errors.New("send_at must be in the future")
+	ErrSchedulingUnavailable      = errors.New("scheduled messages are not enabled")
+)
+
+const (
+	// scheduleLease is how long a scheduler may take to send a claimed
+	// message before another run retries it.
+	scheduleLease = time.Minute
+	// scheduleBatchSize caps how many messages one run sends.
+	scheduleBatchSize = 100
+)
+
+// undeliverable are the errors that will recur on every attempt to send a
+// scheduled message, so retrying is pointless.
+var undeliverable = []error{
+	ErrMessageContentRequired, ErrMessageTooLong, ErrRecipientRequired, ErrConversationNotFound,
+	ErrMessageNotFound, ErrMessageDeleted, ErrInvalidReply, ErrInvalidQuote, ErrBlocked, ErrContactsOnly,
+	ErrAttachmentNotFound, ErrAttachmentAlreadySent, ErrTooManyAttachments, ErrAttachmentsUnavailable,
+}
+
+// ScheduleMessage stores draft to be sent at sendAt. The draft is checked
+// now so obvious mistakes surface immediately, and again when it is sent.
+func (s *MessageService) ScheduleMessage(senderID string, draft ports.MessageDraft, sendAt time.Time) (*domain.ScheduledMessage, error) {
+	if s.scheduled == nil {
+		return nil, ErrSchedulingUnavailable
+	}
+	format, err := messageFormat(draft.Format)
+	if err != nil {
+		return nil, err
+	}
+	if utf8.RuneCountInString(draft.Content) > MaxMessageLength {
+		return nil, ErrMessageTooLong
+	}
+	if content, _ := formatted(format, draft.Content); content == "" && len(draft.AttachmentIDs) == 0 {
+		return nil, ErrMessageContentRequired
+	}
+	now := s.now()
+	if !sendAt.After(now) {
+		return nil, ErrSendAtInPast
+	}
+
+	probe := &domain.Message{SenderID: senderID}
+	if draft.ReplyToID != "" {
+		err = s.placeReply(probe, draft)
+	} else {
+		err = s.placeMessage(probe, draft)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAllowed(probe); err != nil {
+		return nil, err
+	}
+	if draft.QuoteID != "" {
+		if _, err := s.quoteFor(probe, draft.QuoteID); err != nil {
+			return nil, err
+		}
+	}
+	attachments, err := s.claimAttachments(senderID, draft.AttachmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	sm := &domain.ScheduledMessage{
+		ID:         uuid.New(),
+		SenderID:   senderID,
+		ReceiverID: draft.ReceiverID,
+		Content:    draft.Content,
+		Format:     format,
+		SendAt:     sendAt,
+		Status:     domain.SchedulePending,
+		CreatedAt:  now,
+	}
+	// These IDs all parsed successfully above.
+	if draft.ConversationID != "" {
+		sm.ConversationID = uuid.MustParse(draft.ConversationID)
+	}
+	if draft.ReplyToID != "" {
+		replyTo := uuid.MustParse(draft.ReplyToID)
+		sm.ReplyToID = &replyTo
+	}
+	if draft.QuoteID != "" {
+		quote := uuid.MustParse(draft.QuoteID)
+		sm.QuoteID = &quote
+	}
+	for _, a := range attachments {
+		sm.AttachmentIDs = append(sm.AttachmentIDs, a.ID)
+	}
+	if err := s.scheduled.Create(sm); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+// GetScheduledMessages lists userID's pending messages, soonest first.
+func (s *MessageService) GetScheduledMessages(userID string, limit, offset int) ([]*domain.ScheduledMessage, error) {
+	if s.scheduled == nil {
+		return nil, ErrSchedulingUnavailable
+	}
+	return s.scheduled.ListPending(userID, limit, offset)
+}
+
+// RescheduleMessage moves a pending message to sendAt.
+func (s *MessageService) RescheduleMessage(userID, scheduledID string, sendAt time.Time) (*domain.ScheduledMessage, error) {
+	sm, err := s.findScheduled(userID, scheduledID)
+	if err != nil {
+		return nil, err
+	}
+	if !sendAt.After(s.now()) {
+		return nil, ErrSendAtInPast
+	}
+	if err := s.scheduled.Reschedule(sm.ID, sendAt, s.now()); err != nil {
+		return nil, scheduleError(err)
+	}
+	sm.SendAt = sendAt
+	return sm, nil
+}
+
+// CancelScheduledMessage stops a pending message from being sent. Its
+// attachments stay with the sender for use elsewhere.
+func (s *MessageService) CancelScheduledMessage(userID, scheduledID string) error {
+	sm, err := s.findScheduled(userID, scheduledID)
+	if err != nil {
+		return err
+	}
+	return scheduleError(s.scheduled.Cancel(sm.ID, s.now()))
+}
+
+// DeliverDueMessages sends the scheduled messages that are due and
+// reports how many went out. It is safe to run from several servers at
+// once: each message is claimed by one run, and is sent under its
+// scheduled ID so a retry after a crash finds it rather than sending it
+// twice.
+func (s *MessageService) DeliverDueMessages() (int, error) {
+	if s.scheduled == nil {
+		return 0, nil
+	}
+	due, err := s.scheduled.ClaimDue(s.now(), scheduleLease, scheduleBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, sm := range due {
+		ok, err := s.deliver(sm)
+		if err != nil {
+			// Left claimed; retried once the lease lapses.
+			log.Printf("scheduled message %s: %v", sm.ID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// deliver sends one claimed message, reporting false if it had to be
+// given up.
+func (s *MessageService) deliver(sm *domain.ScheduledMessage) (bool, error) {
+	if _, err := s.repo.FindByID(sm.ID); err != nil {
+		_, err := s.createMessage(sm.ID, sm.SenderID, scheduledDraft(sm))
+		if isUndeliverable(err) {
+			return false, s.scheduled.MarkFailed(sm.ID, err.Error())
+		}
+		if err != nil {
+			return false, fmt.Errorf("send: %w", err)
+		}
+	}
+	return true, s.scheduled.MarkSent(sm.ID)
+}
+
+// findScheduled loads one of userID's scheduled messages.
+func (s *MessageService) findScheduled(userID, scheduledID string) (*domain.ScheduledMessage, error) {
+	if s.scheduled == nil {
+		return nil, ErrSchedulingUnavailable
+	}
+	id, err := uuid.Parse(scheduledID)
+	if err != nil {
+		return nil, ErrScheduledMessageNotFound
+	}
+	sm, err := s.scheduled.FindByID(id)
+	if err != nil || sm.SenderID != userID {
+		return nil, ErrScheduledMessageNotFound
+	}
+	return sm, nil
+}
+
+func scheduledDraft(sm *domain.ScheduledMessage) ports.MessageDraft {
+	draft := ports.MessageDraft{ReceiverID: sm.ReceiverID, Content: sm.Content, Format: sm.Format}
+	if sm.ConversationID != uuid.Nil {
+		draft.ConversationID = sm.ConversationID.String()
+	}
+	if sm.ReplyToID != nil {
+		draft.ReplyToID = sm.ReplyToID.String()
+	}
+	if sm.QuoteID != nil {
+		draft.QuoteID = sm.QuoteID.String()
+	}
+	for _, id := range sm.AttachmentIDs {
+		draft.AttachmentIDs = append(draft.AttachmentIDs, id.String())
+	}
+	return draft
+}
+
+func scheduleError(err error) error {
+	if errors.Is(err, ports.ErrNotPending) {
+		return ErrScheduledMessageNotPending
+	}
+	return err
+}
+
+func isUndeliverable(err error) bool {
+	for _, target := range undeliverable {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
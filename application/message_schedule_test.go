@@ -0,0 +1,126 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestMessageService_ScheduledMessages(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	repo := memory.NewMessageRepository()
+	scheduled := memory.NewScheduledMessageRepository()
+	svc := NewMessageService(repo, WithConversations(convs), WithScheduledMessages(scheduled))
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(conv))
+	draft := ports.MessageDraft{ConversationID: conv.ID.String(), Content: "good morning"}
+
+	_, err := svc.ScheduleMessage("alice", draft, now)
+	assert.ErrorIs(t, err, ErrSendAtInPast)
+	_, err = svc.ScheduleMessage("mallory", draft, now.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+
+	sm, err := svc.ScheduleMessage("alice", draft, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.SchedulePending, sm.Status)
+	other, err := svc.ScheduleMessage("alice", ports.MessageDraft{ReceiverID: "bob", Content: "later"}, now.Add(2*time.Hour))
+	assert.NoError(t, err)
+
+	pending, err := svc.GetScheduledMessages("alice", 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+
+	_, err = svc.RescheduleMessage("bob", sm.ID.String(), now.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrScheduledMessageNotFound)
+	moved, err := svc.RescheduleMessage("alice", sm.ID.String(), now.Add(30*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(30*time.Minute), moved.SendAt)
+	assert.NoError(t, svc.CancelScheduledMessage("alice", other.ID.String()))
+	assert.ErrorIs(t, svc.CancelScheduledMessage("alice", other.ID.String()), ErrScheduledMessageNotPending)
+
+	// Nothing is due yet.
+	sent, err := svc.DeliverDueMessages()
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+
+	now = now.Add(31 * time.Minute)
+	sent, err = svc.DeliverDueMessages()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	msg, err := repo.FindByID(sm.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "good morning", msg.Content)
+	assert.Equal(t, conv.ID, msg.ConversationID)
+	got, _ := scheduled.FindByID(sm.ID)
+	assert.Equal(t, domain.ScheduleSent, got.Status)
+	assert.ErrorIs(t, svc.CancelScheduledMessage("alice", sm.ID.String()), ErrScheduledMessageNotPending)
+
+	sent, err = svc.DeliverDueMessages()
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+}
+
+func TestMessageService_DeliverIsExactlyOnce(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	repo := memory.NewMessageRepository()
+	scheduled := memory.NewScheduledMessageRepository()
+	svc := NewMessageService(repo, WithConversations(convs), WithScheduledMessages(scheduled))
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	sm, err := svc.ScheduleMessage("alice", ports.MessageDraft{ReceiverID: "bob", Content: "once"}, now.Add(time.Minute))
+	assert.NoError(t, err)
+
+	// Simulate a crash after the message was stored but before the
+	// scheduled message was marked sent: the claim lapses and a later
+	// run sees it again.
+	now = now.Add(2 * time.Minute)
+	claimed, err := scheduled.ClaimDue(now, scheduleLease, 10)
+	assert.NoError(t, err)
+	assert.Len(t, claimed, 1)
+	_, err = svc.createMessage(sm.ID, "alice", scheduledDraft(claimed[0]))
+	assert.NoError(t, err)
+
+	now = now.Add(scheduleLease)
+	sent, err := svc.DeliverDueMessages()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	inbox, err := repo.GetMessagesByReceiver("bob", 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, inbox, 1)
+}
+
+func TestMessageService_UndeliverableScheduledMessage(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	scheduled := memory.NewScheduledMessageRepository()
+	svc := NewMessageService(memory.NewMessageRepository(), WithConversations(convs), WithScheduledMessages(scheduled))
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(conv))
+	sm, err := svc.ScheduleMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "hi"}, now.Add(time.Minute))
+	assert.NoError(t, err)
+
+	// The conversation is gone by the time the message is due.
+	assert.NoError(t, convs.Delete(conv.ID))
+	now = now.Add(time.Hour)
+	sent, err := svc.DeliverDueMessages()
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+
+	got, _ := scheduled.FindByID(sm.ID)
+	assert.Equal(t, domain.ScheduleFailed, got.Status)
+	assert.Equal(t, ErrConversationNotFound.Error(), got.Failure)
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+var (
+	ErrSearchQueryRequired = errors.New("search query cannot be empty")
+	ErrSearchUnavailable   = errors.New("search is not enabled")
+)
+
+// SearchMessages finds messages userID can see whose content matches
+// search.Query, newest first.
+func (s *MessageService) SearchMessages(userID string, search ports.MessageSearch) ([]*domain.Message, error) {
+	if s.search == nil {
+		return nil, ErrSearchUnavailable
+	}
+	terms := domain.ParseSearchQuery(search.Query)
+	if len(terms) == 0 {
+		return nil, ErrSearchQueryRequired
+	}
+
+	query := ports.SearchQuery{
+		Terms:         terms,
+		ParticipantID: userID,
+		SenderID:      search.SenderID,
+		Since:         search.Since,
+		Until:         search.Until,
+		Limit:         search.Limit,
+		Offset:        search.Offset,
+	}
+	if search.ConversationID != "" {
+		conv, err := s.conversationFor(userID, search.ConversationID)
+		if err != nil {
+			return nil, err
+		}
+		query.ConversationID = conv.ID
+		query.ConversationIDs = []uuid.UUID{conv.ID}
+	} else if s.conversations != nil {
+		convs, err := s.conversations.FindByParticipant(userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, conv := range convs {
+			query.ConversationIDs = append(query.ConversationIDs, conv.ID)
+		}
+	}
+
+	ids, err := s.search.Search(query)
+	if err != nil {
+		return nil, err
+	}
+	// The index can trail the message store, for instance after an admin
+	// removal, so each hit is re-checked.
+	msgs := make([]*domain.Message, 0, len(ids))
+	for _, id := range ids {
+		msg, err := s.repo.FindByID(id)
+		if err != nil || msg.Deleted() || !s.canSee(msg, userID) {
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	return s.decorate(userID, msgs)
+}
+
+// reindex brings the search index up to date with msg. The index is
+// derived data, so a failure is logged rather than failing the change.
+func (s *MessageService) reindex(msg *domain.Message) {
+	if s.search == nil {
+		return
+	}
+	var err error
+	if msg.Deleted() {
+		err = s.search.Remove(msg.ID)
+	} else {
+		err = s.search.Index(msg)
+	}
+	if err != nil {
+		log.Printf("search index: message %s: %v", msg.ID, err)
+	}
+}
@@ -0,0 +1,74 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestMessageService_SearchMessages(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	repo := memory.NewMessageRepository()
+	svc := NewMessageService(repo, WithConversations(convs), WithSearchIndex(memory.NewMessageSearchIndex()))
+
+	team := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	private := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"mallory", "bob"}}
+	assert.NoError(t, convs.Create(team))
+	assert.NoError(t, convs.Create(private))
+
+	send := func(sender string, draft ports.MessageDraft) *domain.Message {
+		msg, err := svc.CreateMessage(sender, draft)
+		assert.NoError(t, err)
+		return msg
+	}
+	notes := send("alice", ports.MessageDraft{ConversationID: team.ID.String(), Content: "Release notes attached"})
+	deploy := send("bob", ports.MessageDraft{ConversationID: team.ID.String(), Content: "deploying the release"})
+	dm := send("bob", ports.MessageDraft{ReceiverID: "alice", Content: "release party later?"})
+	send("mallory", ports.MessageDraft{ConversationID: private.ID.String(), Content: "release secrets"})
+
+	ids := func(search ports.MessageSearch) []uuid.UUID {
+		if search.Limit == 0 {
+			search.Limit = 10
+		}
+		msgs, err := svc.SearchMessages("alice", search)
+		assert.NoError(t, err)
+		out := make([]uuid.UUID, len(msgs))
+		for i, m := range msgs {
+			out[i] = m.ID
+		}
+		return out
+	}
+
+	assert.ElementsMatch(t, []uuid.UUID{notes.ID, deploy.ID, dm.ID}, ids(ports.MessageSearch{Query: "release"}))
+	assert.Equal(t, []uuid.UUID{deploy.ID}, ids(ports.MessageSearch{Query: "deploy*"}))
+	assert.Equal(t, []uuid.UUID{notes.ID}, ids(ports.MessageSearch{Query: `"release notes"`}))
+	assert.ElementsMatch(t, []uuid.UUID{deploy.ID, dm.ID}, ids(ports.MessageSearch{Query: "release", SenderID: "bob"}))
+	assert.ElementsMatch(t, []uuid.UUID{notes.ID, deploy.ID}, ids(ports.MessageSearch{Query: "release", ConversationID: team.ID.String()}))
+	assert.Empty(t, ids(ports.MessageSearch{Query: "release", Since: time.Now().Add(time.Hour)}))
+
+	_, err := svc.SearchMessages("alice", ports.MessageSearch{Query: "secrets", ConversationID: private.ID.String()})
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+	_, err = svc.SearchMessages("alice", ports.MessageSearch{Query: " !! "})
+	assert.ErrorIs(t, err, ErrSearchQueryRequired)
+
+	// The index follows edits and both kinds of delete.
+	_, err = svc.EditMessage("bob", deploy.ID.String(), "shipping now")
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{deploy.ID}, ids(ports.MessageSearch{Query: "shipping"}))
+	assert.NoError(t, svc.DeleteMessageForMe("alice", dm.ID.String()))
+	assert.NoError(t, svc.DeleteMessageForEveryone("alice", notes.ID.String()))
+	assert.Empty(t, ids(ports.MessageSearch{Query: "release"}))
+
+	// Stale index entries are skipped.
+	assert.NoError(t, repo.Delete(deploy.ID))
+	assert.Empty(t, ids(ports.MessageSearch{Query: "shipping"}))
+
+	_, err = NewMessageService(repo).SearchMessages("alice", ports.MessageSearch{Query: "x"})
+	assert.ErrorIs(t, err, ErrSearchUnavailable)
+}
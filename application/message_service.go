@@ -3,6 +3,10 @@ package application
 import (
 	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
@@ -18,37 +22,322 @@ type MessageServiceInterface interface {
 
 var (
 	ErrMessageContentRequired = errors.New("message content cannot be empty")
+	ErrMessageTooLong         = fmt.Errorf("message content cannot be longer than %d characters", MaxMessageLength)
+	ErrMessageNotFound        = errors.New("message not found")
+	ErrNotMessageSender       = errors.New("only the sender can change this message")
+	ErrEditWindowExpired      = errors.New("message can no longer be edited")
+	ErrDeleteWindowExpired    = errors.New("message can no longer be deleted for everyone")
+	ErrMessageDeleted         = errors.New("message was deleted")
+	ErrRecipientRequired      = errors.New("message needs either a receiver or a conversation")
+	ErrInvalidReaction        = errors.New("reaction must be an emoji or a :shortcode:")
+	ErrReactionNotFound       = errors.New("reaction not found")
+	ErrReactionsUnavailable   = errors.New("reactions are not enabled")
+)
+
+const (
+	// DefaultEditWindow is how long after sending a message its sender
+	// may still edit it.
+	DefaultEditWindow = 15 * time.Minute
+	// DefaultDeleteWindow is how long after sending a message its sender
+	// may still delete it for everyone.
+	DefaultDeleteWindow = time.Hour
+	// MaxMessageLength is the most characters a message's content may
+	// have, as written.
+	MaxMessageLength = 10000
 )
 
 type MessageService struct {
-	repo ports.MessageRepository
+	repo          ports.MessageRepository
+	conversations ports.ConversationRepository
+	reactions     ports.ReactionRepository
+	attachments   ports.AttachmentRepository
+	blobs         ports.BlobStore
+	search        ports.MessageSearchIndex
+	scheduled     ports.ScheduledMessageRepository
+	pins          ports.PinRepository
+	typing        ports.TypingStore
+	users         ports.UserRepository
+	blocks        ports.BlockRepository
+	contacts      ports.ContactRepository
+	notifier      ports.NotificationService
+	editWindow    time.Duration
+	deleteWindow  time.Duration
+	pinRole       domain.Role
+	now           func() time.Time
+}
+
+// MessageServiceOption configures optional MessageService collaborators.
+type MessageServiceOption func(*MessageService)
+
+// WithConversations lets the service resolve conversation participants,
+// so events reach everyone in a group rather than just ReceiverID.
+func WithConversations(repo ports.ConversationRepository) MessageServiceOption {
+	return func(s *MessageService) { s.conversations = repo }
 }
 
-func NewMessageService(repo ports.MessageRepository) *MessageService {
-	return &MessageService{repo: repo}
+// WithReactions enables emoji reactions on messages.
+func WithReactions(repo ports.ReactionRepository) MessageServiceOption {
+	return func(s *MessageService) { s.reactions = repo }
 }
 
-func (s *MessageService) CreateMessage(senderID, receiverID, content string) error {
-	if content == "" {
-		return ErrMessageContentRequired
+// WithAttachments lets messages carry uploaded files, whose contents in
+// blobs go when their message expires.
+func WithAttachments(repo ports.AttachmentRepository, blobs ports.BlobStore) MessageServiceOption {
+	return func(s *MessageService) {
+		s.attachments = repo
+		s.blobs = blobs
+	}
+}
+
+// WithSearchIndex enables message search and keeps index in sync with
+// every change.
+func WithSearchIndex(index ports.MessageSearchIndex) MessageServiceOption {
+	return func(s *MessageService) { s.search = index }
+}
+
+// WithScheduledMessages enables sending messages at a later time.
+func WithScheduledMessages(repo ports.ScheduledMessageRepository) MessageServiceOption {
+	return func(s *MessageService) { s.scheduled = repo }
+}
+
+// WithPins enables pinning messages in conversations.
+func WithPins(repo ports.PinRepository) MessageServiceOption {
+	return func(s *MessageService) { s.pins = repo }
+}
+
+// WithPinRole sets the lowest role that may pin and unpin messages. It
+// defaults to domain.RoleUser, letting every participant pin.
+func WithPinRole(role domain.Role) MessageServiceOption {
+	return func(s *MessageService) { s.pinRole = role }
+}
+
+// WithTypingStore clears the sender's typing indicator when their message
+// arrives.
+func WithTypingStore(store ports.TypingStore) MessageServiceOption {
+	return func(s *MessageService) { s.typing = store }
+}
+
+// WithUsers enables resolving @username mentions.
+func WithUsers(repo ports.UserRepository) MessageServiceOption {
+	return func(s *MessageService) { s.users = repo }
+}
+
+// WithBlocks stops users who blocked each other from exchanging direct
+// messages.
+func WithBlocks(repo ports.BlockRepository) MessageServiceOption {
+	return func(s *MessageService) { s.blocks = repo }
+}
+
+// WithContacts enforces the users' "only contacts may message me"
+// setting. It needs WithUsers to read the setting.
+func WithContacts(repo ports.ContactRepository) MessageServiceOption {
+	return func(s *MessageService) { s.contacts = repo }
+}
+
+// WithNotifier sets where message events are pushed.
+func WithNotifier(notifier ports.NotificationService) MessageServiceOption {
+	return func(s *MessageService) { s.notifier = notifier }
+}
+
+// WithEditWindow overrides DefaultEditWindow.
+func WithEditWindow(d time.Duration) MessageServiceOption {
+	return func(s *MessageService) { s.editWindow = d }
+}
+
+// WithDeleteWindow overrides DefaultDeleteWindow.
+func WithDeleteWindow(d time.Duration) MessageServiceOption {
+	return func(s *MessageService) { s.deleteWindow = d }
+}
+
+func NewMessageService(repo ports.MessageRepository, opts ...MessageServiceOption) *MessageService {
+	s := &MessageService{
+		repo:         repo,
+		editWindow:   DefaultEditWindow,
+		deleteWindow: DefaultDeleteWindow,
+		pinRole:      domain.RoleUser,
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateMessage stores a direct message, a message in a conversation the
+// sender belongs to, or a reply in the parent's thread.
+func (s *MessageService) CreateMessage(senderID string, draft ports.MessageDraft) (*domain.Message, error) {
+	return s.createMessage(uuid.New(), senderID, draft)
+}
+
+// createMessage stores draft as message id.
+func (s *MessageService) createMessage(id uuid.UUID, senderID string, draft ports.MessageDraft) (*domain.Message, error) {
+	format, err := messageFormat(draft.Format)
+	if err != nil {
+		return nil, err
+	}
+	if utf8.RuneCountInString(draft.Content) > MaxMessageLength {
+		return nil, ErrMessageTooLong
+	}
+	content, entities := formatted(format, draft.Content)
+	if content == "" && len(draft.AttachmentIDs) == 0 {
+		return nil, ErrMessageContentRequired
+	}
+	attachments, err := s.claimAttachments(senderID, draft.AttachmentIDs)
+	if err != nil {
+		return nil, err
 	}
 
 	message := &domain.Message{
-		ID:         uuid.New(),
-		SenderID:   senderID,
-		ReceiverID: receiverID,
-		Content:    content,
+		ID:       id,
+		SenderID: senderID,
+		Content:  content,
+		Format:   format,
+		Entities: entities,
+	}
+	if draft.ReplyToID != "" {
+		err = s.placeReply(message, draft)
+	} else {
+		err = s.placeMessage(message, draft)
+	}
+	if err != nil {
+		return nil, err
 	}
+	if err := s.checkAllowed(message); err != nil {
+		return nil, err
+	}
+	if draft.QuoteID != "" {
+		if message.Quote, err = s.quoteFor(message, draft.QuoteID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.store(message, attachments); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// store saves a placed message with its attachments and announces it:
+// mentions are resolved, its timer started, and the search index,
+// typing indicators and recipients told.
+func (s *MessageService) store(message *domain.Message, attachments []*domain.Attachment) error {
+	message.Mentions = s.resolveMentions(message)
+	s.setExpiry(message)
 
-	return s.repo.Create(message)
+	if err := s.repo.Create(message); err != nil {
+		return err
+	}
+	if len(attachments) > 0 {
+		ids := make([]uuid.UUID, len(attachments))
+		for i, a := range attachments {
+			ids[i] = a.ID
+			a.MessageID = &message.ID
+			message.Attachments = append(message.Attachments, *a)
+		}
+		// Files can only be claimed once their message exists. If another
+		// message claimed one first, this one is taken back.
+		if err := s.attachments.AttachToMessage(ids, message.ID); err != nil {
+			if undoErr := s.repo.Delete(message.ID); undoErr != nil {
+				log.Printf("undo message %s: %v", message.ID, undoErr)
+			}
+			if errors.Is(err, ports.ErrAttachmentSent) {
+				return ErrAttachmentAlreadySent
+			}
+			return err
+		}
+	}
+	renderHTML(message)
+	s.reindex(message)
+	s.clearTyping(message)
+	s.notifyMentions(message, nil)
+	s.notifyNew(message)
+	return nil
+}
+
+// placeMessage addresses a top-level message.
+func (s *MessageService) placeMessage(message *domain.Message, draft ports.MessageDraft) error {
+	if (draft.ReceiverID == "") == (draft.ConversationID == "") {
+		return ErrRecipientRequired
+	}
+	if draft.ReceiverID != "" {
+		message.ReceiverID = draft.ReceiverID
+		return nil
+	}
+	conv, err := s.conversationFor(message.SenderID, draft.ConversationID)
+	if err != nil {
+		return err
+	}
+	message.ConversationID = conv.ID
+	return nil
+}
+
+// checkAllowed refuses a one-to-one message between users who blocked
+// each other, or to someone who only accepts messages from contacts.
+// Group conversations are left alone: neither setting hides anyone from
+// the rest of the group.
+func (s *MessageService) checkAllowed(message *domain.Message) error {
+	recipients := s.recipients(message)
+	if len(recipients) != 1 {
+		return nil
+	}
+	recipientID := recipients[0]
+	if s.blocks != nil {
+		blocked, err := blockedEitherWay(s.blocks, message.SenderID, recipientID)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return ErrBlocked
+		}
+	}
+	if s.contacts != nil && s.users != nil {
+		id, err := uuid.Parse(recipientID)
+		if err != nil {
+			return nil
+		}
+		recipient, err := s.users.FindByID(id)
+		if err != nil || !recipient.ContactsOnly {
+			return nil
+		}
+		ok, err := s.contacts.IsContact(recipientID, message.SenderID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrContactsOnly
+		}
+	}
+	return nil
 }
 
 func (s *MessageService) GetMessages(senderID string) ([]*domain.Message, error) {
-	return s.repo.GetMessagesBySender(senderID)
+	msgs, err := s.repo.GetMessagesBySender(senderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.decorate(senderID, msgs)
 }
 
 func (s *MessageService) GetMessagesByReceiver(receiverID string, limit, offset int) ([]*domain.Message, error) {
-	return s.repo.GetMessagesByReceiver(receiverID, limit, offset)
+	msgs, err := s.repo.GetMessagesByReceiver(receiverID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.decorate(receiverID, msgs)
+}
+
+// GetConversationMessages returns a page of a conversation's timeline,
+// oldest first. Only participants may read it.
+func (s *MessageService) GetConversationMessages(userID, conversationID string, limit, offset int) ([]*domain.Message, error) {
+	conv, err := s.conversationFor(userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := s.repo.GetMessagesByConversation(conv.ID, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.decorate(userID, msgs)
 }
 
 func (s *MessageService) SetMessageStatus(messageID string, status domain.MessageStatus) error {
@@ -59,4 +348,371 @@ func (s *MessageService) SetMessageStatus(messageID string, status domain.Messag
 	return s.repo.SetMessageStatus(id, status)
 }
 
+// EditMessage replaces the content of a message the editor sent within the
+// edit window. The previous content is kept as a revision and the other
+// participants are notified.
+func (s *MessageService) EditMessage(editorID, messageID, content string) (*domain.Message, error) {
+	if content == "" {
+		return nil, ErrMessageContentRequired
+	}
+	if utf8.RuneCountInString(content) > MaxMessageLength {
+		return nil, ErrMessageTooLong
+	}
+	msg, err := s.findMessage(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if msg.SenderID != editorID || msg.System {
+		return nil, ErrNotMessageSender
+	}
+	if msg.Deleted() {
+		return nil, ErrMessageDeleted
+	}
+	now := s.now()
+	if now.Sub(msg.CreatedAt) > s.editWindow {
+		return nil, ErrEditWindowExpired
+	}
+	text, entities := formatted(msg.Format, content)
+	if text == "" {
+		return nil, ErrMessageContentRequired
+	}
+	if msg.Content == text && slices.Equal(msg.Entities, entities) {
+		renderHTML(msg)
+		return msg, nil
+	}
+
+	// The superseded version was written when the message was created or
+	// last edited, whichever is later.
+	written := msg.CreatedAt
+	if msg.EditedAt != nil {
+		written = *msg.EditedAt
+	}
+	revision := &domain.MessageRevision{MessageID: msg.ID, Content: msg.Content, CreatedAt: written}
+	if err := s.repo.AddRevision(revision); err != nil {
+		return nil, err
+	}
+
+	edited := *msg
+	edited.Content = text
+	edited.Entities = entities
+	edited.EditedAt = &now
+	edited.Mentions = s.resolveMentions(&edited)
+	if err := s.repo.Update(&edited); err != nil {
+		return nil, err
+	}
+	renderHTML(&edited)
+	s.reindex(&edited)
+
+	s.notify(&edited, domain.NotificationMessageEdited, editorID)
+	s.notifyMentions(&edited, msg.Mentions)
+	return &edited, nil
+}
+
+// GetMessageHistory returns the superseded versions of a message, oldest
+// first. Only participants of the message may read it.
+func (s *MessageService) GetMessageHistory(callerID, messageID string) ([]*domain.MessageRevision, error) {
+	msg, err := s.findMessage(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canSee(msg, callerID) {
+		return nil, ErrMessageNotFound
+	}
+	return s.repo.FindRevisions(msg.ID)
+}
+
+// DeleteMessageForMe hides a message from userID's own listings. Anyone
+// who can see the message may do this at any time.
+func (s *MessageService) DeleteMessageForMe(userID, messageID string) error {
+	msg, err := s.findMessage(messageID)
+	if err != nil {
+		return err
+	}
+	if !s.canSee(msg, userID) {
+		return ErrMessageNotFound
+	}
+	if err := s.repo.HideForUser(msg.ID, userID); err != nil {
+		return err
+	}
+	if s.search != nil {
+		if err := s.search.Hide(msg.ID, userID); err != nil {
+			log.Printf("search index: hide message %s: %v", msg.ID, err)
+		}
+	}
+	return nil
+}
+
+// DeleteMessageForEveryone replaces a message with a tombstone for all
+// participants. Only the sender may do this, within the delete window.
+func (s *MessageService) DeleteMessageForEveryone(userID, messageID string) error {
+	msg, err := s.findMessage(messageID)
+	if err != nil {
+		return err
+	}
+	if msg.SenderID != userID || msg.System {
+		return ErrNotMessageSender
+	}
+	if msg.Deleted() {
+		return nil
+	}
+	now := s.now()
+	if now.Sub(msg.CreatedAt) > s.deleteWindow {
+		return ErrDeleteWindowExpired
+	}
+	if err := s.repo.DeleteForEveryone(msg.ID, now); err != nil {
+		return err
+	}
+
+	msg.Content = ""
+	msg.Mentions = nil
+	msg.Entities = nil
+	msg.HTML = ""
+	msg.Quote = nil
+	msg.ForwardedFrom = nil
+	msg.DeletedAt = &now
+	s.reindex(msg)
+	s.notify(msg, domain.NotificationMessageDeleted, userID)
+	return nil
+}
+
+// AddReaction reacts to a message the user can see. Reacting twice with
+// the same emoji is a no-op.
+func (s *MessageService) AddReaction(userID, messageID, emoji string) error {
+	if s.reactions == nil {
+		return ErrReactionsUnavailable
+	}
+	if !domain.ValidReaction(emoji) {
+		return ErrInvalidReaction
+	}
+	msg, err := s.findMessage(messageID)
+	if err != nil {
+		return err
+	}
+	if !s.canSee(msg, userID) {
+		return ErrMessageNotFound
+	}
+	if msg.Deleted() {
+		return ErrMessageDeleted
+	}
+	return s.reactions.Add(&domain.Reaction{
+		MessageID: msg.ID,
+		UserID:    userID,
+		Emoji:     emoji,
+		CreatedAt: s.now(),
+	})
+}
+
+// RemoveReaction withdraws one of the user's own reactions.
+func (s *MessageService) RemoveReaction(userID, messageID, emoji string) error {
+	if s.reactions == nil {
+		return ErrReactionsUnavailable
+	}
+	msg, err := s.findMessage(messageID)
+	if err != nil {
+		return err
+	}
+	if !s.canSee(msg, userID) {
+		return ErrMessageNotFound
+	}
+	if err := s.reactions.Remove(msg.ID, userID, emoji); err != nil {
+		return ErrReactionNotFound
+	}
+	return nil
+}
+
+// claimAttachments loads the unsent uploads of senderID named by ids.
+func (s *MessageService) claimAttachments(senderID string, ids []string) ([]*domain.Attachment, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if s.attachments == nil {
+		return nil, ErrAttachmentsUnavailable
+	}
+	if len(ids) > MaxAttachmentsPerMessage {
+		return nil, ErrTooManyAttachments
+	}
+	var out []*domain.Attachment
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, raw := range ids {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, ErrAttachmentNotFound
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		a, err := s.attachments.FindByID(id)
+		if err != nil || a.UploaderID != senderID {
+			return nil, ErrAttachmentNotFound
+		}
+		if a.MessageID != nil {
+			return nil, ErrAttachmentAlreadySent
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// decorate returns copies of msgs with their attachments and their
+// reaction and thread summaries, leaving the repository's values
+// untouched.
+func (s *MessageService) decorate(userID string, msgs []*domain.Message) ([]*domain.Message, error) {
+	if len(msgs) == 0 {
+		return msgs, nil
+	}
+	ids := make([]uuid.UUID, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg.ID
+	}
+
+	byMessage := make(map[uuid.UUID][]*domain.Reaction)
+	if s.reactions != nil {
+		reactions, err := s.reactions.FindByMessages(ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range reactions {
+			byMessage[r.MessageID] = append(byMessage[r.MessageID], r)
+		}
+	}
+	files := make(map[uuid.UUID][]domain.Attachment)
+	if s.attachments != nil {
+		attachments, err := s.attachments.FindByMessages(ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range attachments {
+			files[*a.MessageID] = append(files[*a.MessageID], *a)
+		}
+	}
+	threads, err := s.repo.ThreadSummaries(ids, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*domain.Message, len(msgs))
+	for i, msg := range msgs {
+		c := *msg
+		c.Attachments = files[msg.ID]
+		if msg.Deleted() {
+			c.Attachments = nil
+		}
+		c.Reactions = domain.SummarizeReactions(byMessage[msg.ID])
+		c.Thread = threads[msg.ID]
+		c.Quote = s.quoteAsSeen(msg.Quote)
+		renderHTML(&c)
+		out[i] = &c
+	}
+	return out, nil
+}
+
+// conversationFor loads a conversation userID participates in. Outsiders
+// get ErrConversationNotFound, as if it didn't exist.
+func (s *MessageService) conversationFor(userID, conversationID string) (*domain.Conversation, error) {
+	if s.conversations == nil {
+		return nil, ErrConversationNotFound
+	}
+	id, err := uuid.Parse(conversationID)
+	if err != nil {
+		return nil, ErrConversationNotFound
+	}
+	conv, err := s.conversations.FindByID(id)
+	if err != nil || !contains(conv.ParticipantIDs, userID) {
+		return nil, ErrConversationNotFound
+	}
+	return conv, nil
+}
+
+func (s *MessageService) findMessage(messageID string) (*domain.Message, error) {
+	id, err := uuid.Parse(messageID)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+	msg, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+	return msg, nil
+}
+
+// canSee reports whether userID took part in msg. Callers answer
+// ErrMessageNotFound otherwise, so outsiders can't probe for messages.
+func (s *MessageService) canSee(msg *domain.Message, userID string) bool {
+	return msg.SenderID == userID || contains(s.recipients(msg), userID)
+}
+
+// recipients returns everyone other than the sender who can see msg.
+func (s *MessageService) recipients(msg *domain.Message) []string {
+	return messageRecipients(s.conversations, msg)
+}
+
+// messageRecipients resolves msg's audience through conversations, which
+// may be nil when only direct messages are in use.
+func messageRecipients(conversations ports.ConversationRepository, msg *domain.Message) []string {
+	if msg.ConversationID != uuid.Nil && conversations != nil {
+		if conv, err := conversations.FindByID(msg.ConversationID); err == nil {
+			var out []string
+			for _, id := range conv.ParticipantIDs {
+				if id != msg.SenderID {
+					out = append(out, id)
+				}
+			}
+			return out
+		}
+	}
+	if msg.ReceiverID == "" {
+		return nil
+	}
+	return []string{msg.ReceiverID}
+}
+
+// notify pushes an event about msg to its recipients.
+func (s *MessageService) notify(msg *domain.Message, typ domain.NotificationType, actorID string) {
+	s.notifyUsers(s.recipients(msg), msg, typ, domain.PriorityNormal, actorID)
+}
+
+// clearTyping drops the sender's typing indicator once their message is
+// in. The indicator would lapse anyway, so a failure is only logged.
+func (s *MessageService) clearTyping(msg *domain.Message) {
+	if s.typing == nil || msg.ConversationID == uuid.Nil {
+		return
+	}
+	if _, err := s.typing.Clear(msg.ConversationID, msg.SenderID, s.now()); err != nil {
+		log.Printf("typing: clear %s in %s: %v", msg.SenderID, msg.ConversationID, err)
+	}
+}
+
+// notifyUsers pushes an event about msg to userIDs. Delivery is best
+// effort: the change has already been stored.
+func (s *MessageService) notifyUsers(userIDs []string, msg *domain.Message, typ domain.NotificationType, priority domain.NotificationPriority, actorID string) {
+	if s.notifier == nil {
+		return
+	}
+	for _, userID := range userIDs {
+		err := s.notifier.Notify(&domain.Notification{
+			Type:           typ,
+			Priority:       priority,
+			UserID:         userID,
+			ActorID:        actorID,
+			ConversationID: msg.ConversationID,
+			MessageID:      msg.ID,
+			Body:           msg.Content,
+			CreatedAt:      s.now(),
+		})
+		if err != nil {
+			log.Printf("notify %s of %s: %v", userID, typ, err)
+		}
+	}
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
 var _ ports.MessageService = (*MessageService)(nil)
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/chrikar/chatheon/application/ports"
 	"github.com/chrikar/chatheon/domain"
 )
 
@@ -31,10 +32,91 @@ func (m *mockMessageRepo) GetMessagesBySender(senderID string) ([]*domain.Messag
 	return args.Get(0).([]*domain.Message), args.Error(1)
 }
 
+func (m *mockMessageRepo) GetMessagesByConversation(conversationID uuid.UUID, userID string, limit, offset int) ([]*domain.Message, error) {
+	args := m.Called(conversationID, userID, limit, offset)
+	return args.Get(0).([]*domain.Message), args.Error(1)
+}
+
+func (m *mockMessageRepo) GetThreadReplies(rootID uuid.UUID, userID string, limit, offset int) ([]*domain.Message, error) {
+	args := m.Called(rootID, userID, limit, offset)
+	return args.Get(0).([]*domain.Message), args.Error(1)
+}
+
+func (m *mockMessageRepo) ThreadSummaries(rootIDs []uuid.UUID, userID string) (map[uuid.UUID]*domain.ThreadSummary, error) {
+	args := m.Called(rootIDs, userID)
+	return args.Get(0).(map[uuid.UUID]*domain.ThreadSummary), args.Error(1)
+}
+
+func (m *mockMessageRepo) MarkThreadRead(rootID uuid.UUID, userID string, readAt time.Time) error {
+	return m.Called(rootID, userID, readAt).Error(0)
+}
+
+func (m *mockMessageRepo) GetMentions(userID string, limit, offset int) ([]*domain.Message, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]*domain.Message), args.Error(1)
+}
+
 func (m *mockMessageRepo) SetMessageStatus(id uuid.UUID, status domain.MessageStatus) error {
 	return m.Called(id, status).Error(0)
 }
 
+func (m *mockMessageRepo) FindByID(id uuid.UUID) (*domain.Message, error) {
+	args := m.Called(id)
+	msg, _ := args.Get(0).(*domain.Message)
+	return msg, args.Error(1)
+}
+
+func (m *mockMessageRepo) Update(msg *domain.Message) error {
+	return m.Called(msg).Error(0)
+}
+
+func (m *mockMessageRepo) AddRevision(revision *domain.MessageRevision) error {
+	return m.Called(revision).Error(0)
+}
+
+func (m *mockMessageRepo) FindRevisions(id uuid.UUID) ([]*domain.MessageRevision, error) {
+	args := m.Called(id)
+	return args.Get(0).([]*domain.MessageRevision), args.Error(1)
+}
+
+func (m *mockMessageRepo) HideForUser(id uuid.UUID, userID string) error {
+	return m.Called(id, userID).Error(0)
+}
+
+func (m *mockMessageRepo) DeleteForEveryone(id uuid.UUID, deletedAt time.Time) error {
+	return m.Called(id, deletedAt).Error(0)
+}
+
+func (m *mockMessageRepo) Delete(id uuid.UUID) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *mockMessageRepo) DeleteByConversation(conversationID uuid.UUID) error {
+	return m.Called(conversationID).Error(0)
+}
+
+func (m *mockMessageRepo) FindThreadIDs(rootID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(rootID)
+	ids, _ := args.Get(0).([]uuid.UUID)
+	return ids, args.Error(1)
+}
+
+func (m *mockMessageRepo) FindExpired(now time.Time, limit int) ([]uuid.UUID, error) {
+	args := m.Called(now, limit)
+	ids, _ := args.Get(0).([]uuid.UUID)
+	return ids, args.Error(1)
+}
+
+func (m *mockMessageRepo) FindPastRetention(filter ports.RetentionFilter, limit, offset int) ([]*domain.Message, error) {
+	args := m.Called(filter, limit, offset)
+	msgs, _ := args.Get(0).([]*domain.Message)
+	return msgs, args.Error(1)
+}
+
+func (m *mockMessageRepo) DeleteMany(messageIDs []uuid.UUID) error {
+	return m.Called(messageIDs).Error(0)
+}
+
 func TestMessageService_CreateMessage(t *testing.T) {
 	dbFailError := errors.New("db fail")
 	tests := []struct {
@@ -87,7 +169,7 @@ func TestMessageService_CreateMessage(t *testing.T) {
 			svc := NewMessageService(repo)
 
 			tc.setupStubs(repo)
-			err := svc.CreateMessage(tc.sender, tc.receiver, tc.content)
+			_, err := svc.CreateMessage(tc.sender, ports.MessageDraft{ReceiverID: tc.receiver, Content: tc.content})
 
 			if tc.expectedError != nil {
 				assert.ErrorIs(t, err, tc.expectedError)
@@ -108,6 +190,7 @@ func TestMessageService_GetMessagesByReceiver(t *testing.T) {
 		{ID: uuid.New(), SenderID: "u1", ReceiverID: "u2", Content: "a", CreatedAt: now, Status: domain.StatusSent},
 	}
 	repo.On("GetMessagesByReceiver", "u2", 5, 1).Return(fake, nil)
+	repo.On("ThreadSummaries", []uuid.UUID{fake[0].ID}, "u2").Return(map[uuid.UUID]*domain.ThreadSummary{}, nil)
 
 	out, err := svc.GetMessagesByReceiver("u2", 5, 1)
 	assert.NoError(t, err)
@@ -0,0 +1,109 @@
+package application
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+var ErrInvalidReply = errors.New("a reply must stay where its parent message is")
+
+// GetThread returns the root of the thread messageID belongs to along with
+// a page of its replies, oldest first.
+func (s *MessageService) GetThread(userID, messageID string, limit, offset int) (*ports.Thread, error) {
+	root, err := s.threadRoot(userID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	replies, err := s.repo.GetThreadReplies(root.ID, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	roots, err := s.decorate(userID, []*domain.Message{root})
+	if err != nil {
+		return nil, err
+	}
+	replies, err = s.decorate(userID, replies)
+	if err != nil {
+		return nil, err
+	}
+	return &ports.Thread{Root: roots[0], Replies: replies}, nil
+}
+
+// MarkThreadRead clears the caller's unread count for the thread
+// messageID belongs to.
+func (s *MessageService) MarkThreadRead(userID, messageID string) error {
+	root, err := s.threadRoot(userID, messageID)
+	if err != nil {
+		return err
+	}
+	return s.repo.MarkThreadRead(root.ID, userID, s.now())
+}
+
+// placeReply puts message in the thread of draft.ReplyToID. Replies go
+// where the parent is, so the draft may omit the receiver or
+// conversation, but must not point elsewhere.
+func (s *MessageService) placeReply(message *domain.Message, draft ports.MessageDraft) error {
+	parent, err := s.findMessage(draft.ReplyToID)
+	if err != nil {
+		return err
+	}
+	if !s.canSee(parent, message.SenderID) {
+		return ErrMessageNotFound
+	}
+	if parent.Deleted() {
+		return ErrMessageDeleted
+	}
+
+	if parent.ConversationID != uuid.Nil {
+		if draft.ReceiverID != "" || (draft.ConversationID != "" && draft.ConversationID != parent.ConversationID.String()) {
+			return ErrInvalidReply
+		}
+		// The sender may have left the conversation since.
+		if _, err := s.conversationFor(message.SenderID, parent.ConversationID.String()); err != nil {
+			return err
+		}
+		message.ConversationID = parent.ConversationID
+	} else {
+		other := parent.ReceiverID
+		if parent.SenderID != message.SenderID {
+			other = parent.SenderID
+		}
+		if draft.ConversationID != "" || (draft.ReceiverID != "" && draft.ReceiverID != other) {
+			return ErrInvalidReply
+		}
+		message.ReceiverID = other
+	}
+
+	root := parent.ID
+	if parent.ThreadRootID != nil {
+		root = *parent.ThreadRootID
+	}
+	message.ReplyToID = &parent.ID
+	message.ThreadRootID = &root
+	return nil
+}
+
+// threadRoot loads the root of the thread messageID belongs to, checking
+// the caller can see it.
+func (s *MessageService) threadRoot(userID, messageID string) (*domain.Message, error) {
+	msg, err := s.findMessage(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canSee(msg, userID) {
+		return nil, ErrMessageNotFound
+	}
+	if msg.ThreadRootID == nil {
+		return msg, nil
+	}
+	root, err := s.repo.FindByID(*msg.ThreadRootID)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+	return root, nil
+}
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestMessageService_Threads(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	svc := NewMessageService(memory.NewMessageRepository(), WithConversations(convs))
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob", "carol"}}
+	assert.NoError(t, convs.Create(conv))
+	root, err := svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "lunch?"})
+	assert.NoError(t, err)
+
+	reply, err := svc.CreateMessage("bob", ports.MessageDraft{ReplyToID: root.ID.String(), Content: "yes"})
+	assert.NoError(t, err)
+	assert.Equal(t, conv.ID, reply.ConversationID)
+	assert.Equal(t, root.ID, *reply.ThreadRootID)
+
+	// Replying to a reply stays in the same thread.
+	nested, err := svc.CreateMessage("carol", ports.MessageDraft{ReplyToID: reply.ID.String(), Content: "me too"})
+	assert.NoError(t, err)
+	assert.Equal(t, reply.ID, *nested.ReplyToID)
+	assert.Equal(t, root.ID, *nested.ThreadRootID)
+
+	_, err = svc.CreateMessage("mallory", ports.MessageDraft{ReplyToID: root.ID.String(), Content: "hi"})
+	assert.ErrorIs(t, err, ErrMessageNotFound)
+	_, err = svc.CreateMessage("bob", ports.MessageDraft{ReplyToID: root.ID.String(), ReceiverID: "alice", Content: "hi"})
+	assert.ErrorIs(t, err, ErrInvalidReply)
+
+	// Replies stay out of the main timeline; the root carries a summary.
+	timeline, err := svc.GetConversationMessages("alice", conv.ID.String(), 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, timeline, 1)
+	sum := timeline[0].Thread
+	assert.Equal(t, 2, sum.ReplyCount)
+	assert.Equal(t, nested.ID, sum.LastReplyID)
+	assert.Equal(t, []string{"bob", "carol"}, sum.ParticipantIDs)
+	assert.Equal(t, 2, sum.UnreadCount)
+
+	thread, err := svc.GetThread("alice", nested.ID.String(), 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, root.ID, thread.Root.ID)
+	assert.Len(t, thread.Replies, 1)
+	assert.Equal(t, "me too", thread.Replies[0].Content)
+
+	assert.NoError(t, svc.MarkThreadRead("alice", root.ID.String()))
+	_, err = svc.CreateMessage("bob", ports.MessageDraft{ReplyToID: root.ID.String(), Content: "12:30?"})
+	assert.NoError(t, err)
+
+	thread, err = svc.GetThread("alice", root.ID.String(), 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, thread.Root.Thread.ReplyCount)
+	assert.Equal(t, 1, thread.Root.Thread.UnreadCount)
+
+	// Bob's own replies never count as unread for him.
+	thread, err = svc.GetThread("bob", root.ID.String(), 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, thread.Root.Thread.UnreadCount)
+}
+
+func TestMessageService_DirectReply(t *testing.T) {
+	svc := NewMessageService(memory.NewMessageRepository())
+
+	msg, err := svc.CreateMessage("alice", ports.MessageDraft{ReceiverID: "bob", Content: "ping"})
+	assert.NoError(t, err)
+
+	reply, err := svc.CreateMessage("bob", ports.MessageDraft{ReplyToID: msg.ID.String(), Content: "pong"})
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", reply.ReceiverID)
+
+	again, err := svc.CreateMessage("alice", ports.MessageDraft{ReplyToID: reply.ID.String(), Content: "ok"})
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", again.ReceiverID)
+}
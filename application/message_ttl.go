@@ -0,0 +1,114 @@
+package application
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+var ErrInvalidMessageTTL = errors.New("message timer must be off or between 1 minute and 4 weeks")
+
+const (
+	// MinMessageTTL and MaxMessageTTL bound a conversation's
+	// disappearing-message timer.
+	MinMessageTTL = time.Minute
+	MaxMessageTTL = 4 * 7 * 24 * time.Hour
+	// reapBatchSize caps how many expired messages, not counting their
+	// thread replies, one delete removes.
+	reapBatchSize = 500
+)
+
+// SetMessageTTL sets how long new messages in a conversation last before
+// they disappear; zero turns the timer off. Messages already sent keep
+// the timer they were sent under. A system message tells the
+// participants about the change.
+func (s *MessageService) SetMessageTTL(userID, conversationID string, ttl time.Duration) (*domain.Conversation, error) {
+	if ttl != 0 && (ttl < MinMessageTTL || ttl > MaxMessageTTL) {
+		return nil, ErrInvalidMessageTTL
+	}
+	conv, err := s.conversationFor(userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conv.MessageTTL == ttl {
+		return conv, nil
+	}
+
+	updated := *conv
+	updated.MessageTTL = ttl
+	if err := s.conversations.Update(&updated); err != nil {
+		return nil, err
+	}
+
+	notice := &domain.Message{
+		ID:             uuid.New(),
+		SenderID:       userID,
+		ConversationID: conv.ID,
+		Content:        ttlNotice(ttl),
+		System:         true,
+	}
+	if err := s.store(notice, nil); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// ReapExpiredMessages deletes messages whose timer has run out, along
+// with everything derived from them, and reports how many went. It stops
+// once a batch holds nothing new, so messages that somehow survive their
+// purge can't keep it looping.
+func (s *MessageService) ReapExpiredMessages() (int, error) {
+	purger := &messagePurger{
+		messages:    s.repo,
+		reactions:   s.reactions,
+		attachments: s.attachments,
+		blobs:       s.blobs,
+		search:      s.search,
+		pins:        s.pins,
+	}
+	seen := make(map[uuid.UUID]bool)
+	total := 0
+	for {
+		expired, err := s.repo.FindExpired(s.now(), reapBatchSize)
+		if err != nil {
+			return total, err
+		}
+		var ids []uuid.UUID
+		for _, id := range expired {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+		if _, err := purger.purge(ids, false); err != nil {
+			return total, err
+		}
+		total += len(ids)
+	}
+}
+
+// setExpiry starts message's timer if its conversation has one.
+func (s *MessageService) setExpiry(message *domain.Message) {
+	if message.ConversationID == uuid.Nil || s.conversations == nil {
+		return
+	}
+	conv, err := s.conversations.FindByID(message.ConversationID)
+	if err != nil || conv.MessageTTL == 0 {
+		return
+	}
+	expiresAt := s.now().Add(conv.MessageTTL)
+	message.ExpiresAt = &expiresAt
+}
+
+func ttlNotice(ttl time.Duration) string {
+	if ttl == 0 {
+		return "turned off disappearing messages"
+	}
+	return "set disappearing messages to " + domain.FormatTTL(ttl)
+}
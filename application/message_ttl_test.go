@@ -0,0 +1,184 @@
+package application
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestMessageService_SetMessageTTL(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	repo := memory.NewMessageRepository()
+	svc := NewMessageService(repo, WithConversations(convs))
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(conv))
+
+	_, err := svc.SetMessageTTL("alice", conv.ID.String(), 30*time.Second)
+	assert.ErrorIs(t, err, ErrInvalidMessageTTL)
+	_, err = svc.SetMessageTTL("alice", conv.ID.String(), 5*7*24*time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidMessageTTL)
+	_, err = svc.SetMessageTTL("mallory", conv.ID.String(), time.Hour)
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+
+	updated, err := svc.SetMessageTTL("alice", conv.ID.String(), 24*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, updated.MessageTTL)
+
+	msg, err := svc.CreateMessage("bob", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "psst"})
+	assert.NoError(t, err)
+	if assert.NotNil(t, msg.ExpiresAt) {
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), *msg.ExpiresAt, time.Minute)
+	}
+
+	// Turning the timer off announces it too; setting the same value
+	// again doesn't.
+	_, err = svc.SetMessageTTL("bob", conv.ID.String(), 0)
+	assert.NoError(t, err)
+	_, err = svc.SetMessageTTL("bob", conv.ID.String(), 0)
+	assert.NoError(t, err)
+
+	timeline, err := svc.GetConversationMessages("alice", conv.ID.String(), 10, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, timeline, 3) {
+		assert.True(t, timeline[0].System)
+		assert.Equal(t, "alice", timeline[0].SenderID)
+		assert.Equal(t, "set disappearing messages to 1 day", timeline[0].Content)
+		assert.Equal(t, "turned off disappearing messages", timeline[2].Content)
+		assert.Nil(t, timeline[2].ExpiresAt)
+	}
+
+	_, err = svc.EditMessage("alice", timeline[0].ID.String(), "hacked")
+	assert.ErrorIs(t, err, ErrNotMessageSender)
+}
+
+func TestMessageService_ReapExpiredMessages(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	repo := memory.NewMessageRepository()
+	index := memory.NewMessageSearchIndex()
+	svc := NewMessageService(repo, WithConversations(convs), WithSearchIndex(index))
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}, MessageTTL: time.Hour}
+	assert.NoError(t, convs.Create(conv))
+
+	// Sent two hours ago under a one hour timer.
+	svc.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+	_, err := svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "secret plans"})
+	assert.NoError(t, err)
+	svc.now = time.Now
+	fresh, err := svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "secret snacks"})
+	assert.NoError(t, err)
+
+	// Expired messages drop out of reads before the reaper runs.
+	timeline, err := svc.GetConversationMessages("bob", conv.ID.String(), 10, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, timeline, 1) {
+		assert.Equal(t, fresh.ID, timeline[0].ID)
+	}
+
+	reaped, err := svc.ReapExpiredMessages()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reaped)
+	ids, err := index.Search(ports.SearchQuery{
+		Terms:           domain.ParseSearchQuery("secret"),
+		ParticipantID:   "bob",
+		ConversationIDs: []uuid.UUID{conv.ID},
+		Limit:           10,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{fresh.ID}, ids)
+
+	reaped, err = svc.ReapExpiredMessages()
+	assert.NoError(t, err)
+	assert.Zero(t, reaped)
+}
+
+func TestMessageService_ReapPurgesDerivedData(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	repo := memory.NewMessageRepository()
+	reactions := memory.NewReactionRepository()
+	attRepo := memory.NewAttachmentRepository()
+	blobs := memory.NewBlobStore()
+	pins := memory.NewPinRepository()
+	svc := NewMessageService(repo, WithConversations(convs), WithReactions(reactions),
+		WithAttachments(attRepo, blobs), WithPins(pins))
+	uploads := NewAttachmentService(attRepo, repo, convs, blobs)
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}, MessageTTL: time.Hour}
+	assert.NoError(t, convs.Create(conv))
+
+	file, err := uploads.Upload("alice", "plans.txt", strings.NewReader("secret plans"))
+	assert.NoError(t, err)
+	msg, err := svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(),
+		Content: "see attached", AttachmentIDs: []string{file.ID.String()}})
+	assert.NoError(t, err)
+	assert.NoError(t, svc.AddReaction("bob", msg.ID.String(), "👍"))
+	_, err = svc.PinMessage("bob", msg.ID.String())
+	assert.NoError(t, err)
+
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	reaped, err := svc.ReapExpiredMessages()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reaped)
+
+	_, err = blobs.Get(originalKey(file.ID))
+	assert.ErrorIs(t, err, ports.ErrBlobNotFound)
+	_, err = attRepo.FindByID(file.ID)
+	assert.Error(t, err)
+	left, err := reactions.FindByMessages([]uuid.UUID{msg.ID})
+	assert.NoError(t, err)
+	assert.Empty(t, left)
+	pinned, err := pins.FindByConversation(conv.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, pinned)
+}
+
+func TestMessageService_SetMessageTTLAnnounces(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	index := memory.NewMessageSearchIndex()
+	notifier := mocks.NewMockNotificationService(t)
+	svc := NewMessageService(memory.NewMessageRepository(), WithConversations(convs),
+		WithSearchIndex(index), WithNotifier(notifier))
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(conv))
+
+	notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Type == domain.NotificationNewMessage && n.UserID == "bob" && n.ConversationID == conv.ID
+	})).Return(nil).Once()
+	_, err := svc.SetMessageTTL("alice", conv.ID.String(), time.Hour)
+	assert.NoError(t, err)
+
+	ids, err := index.Search(ports.SearchQuery{
+		Terms:           domain.ParseSearchQuery("disappearing"),
+		ParticipantID:   "bob",
+		ConversationIDs: []uuid.UUID{conv.ID},
+		Limit:           10,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, ids, 1)
+}
+
+// undeletableMessages keeps every message through a purge.
+type undeletableMessages struct{ *memory.MessageRepository }
+
+func (undeletableMessages) DeleteMany([]uuid.UUID) error { return nil }
+
+func TestMessageService_ReapStopsWhenNothingIsDeleted(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	svc := NewMessageService(undeletableMessages{memory.NewMessageRepository()}, WithConversations(convs))
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}, MessageTTL: time.Hour}
+	assert.NoError(t, convs.Create(conv))
+	_, err := svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "stuck"})
+	assert.NoError(t, err)
+
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	reaped, err := svc.ReapExpiredMessages()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reaped)
+}
@@ -0,0 +1,163 @@
+package application
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+var (
+	ErrInvalidNotificationLevel = errors.New("notification level must be all, mentions or none")
+	ErrInvalidTimeZone          = errors.New("unknown time zone")
+	ErrInvalidDoNotDisturb      = errors.New("do-not-disturb hours must be two different HH:MM times")
+	ErrMuteExpired              = errors.New("mute must end in the future")
+	ErrMuteNotFound             = errors.New("conversation is not muted")
+)
+
+// NotificationPreferenceService is the application-layer implementation
+// of ports.NotificationPreferenceService. It is also the
+// ports.NotificationService the other services notify through: each
+// notification is checked against its recipient's preferences and only
+// the ones they want are passed on to next.
+type NotificationPreferenceService struct {
+	prefs         ports.NotificationPreferenceRepository
+	conversations ports.ConversationRepository
+	next          ports.NotificationService
+	now           func() time.Time
+}
+
+func NewNotificationPreferenceService(prefs ports.NotificationPreferenceRepository, conversations ports.ConversationRepository, next ports.NotificationService) *NotificationPreferenceService {
+	return &NotificationPreferenceService{prefs: prefs, conversations: conversations, next: next, now: time.Now}
+}
+
+// Notify passes n on unless the recipient's level, do-not-disturb hours
+// or a mute of its conversation hold it back. Only alerting events are
+// held back; edits, deletions, pins and typing always go through. If the
+// preferences can't be read, n is delivered rather than lost.
+func (s *NotificationPreferenceService) Notify(n *domain.Notification) error {
+	allowed, err := s.allows(n)
+	if err != nil {
+		log.Printf("notification preferences of %s: %v", n.UserID, err)
+	} else if !allowed {
+		return nil
+	}
+	return s.next.Notify(n)
+}
+
+func (s *NotificationPreferenceService) allows(n *domain.Notification) (bool, error) {
+	if !n.Type.Alerting() {
+		return true, nil
+	}
+	now := s.now()
+	settings, err := s.prefs.FindSettings(n.UserID)
+	if err != nil {
+		return true, err
+	}
+	if !settings.Allows(n, now) {
+		return false, nil
+	}
+	if n.ConversationID == uuid.Nil {
+		return true, nil
+	}
+	mute, err := s.prefs.FindMute(n.UserID, n.ConversationID)
+	if err != nil {
+		return true, err
+	}
+	return mute == nil || !mute.Active(now), nil
+}
+
+func (s *NotificationPreferenceService) GetNotificationSettings(userID string) (*domain.NotificationSettings, error) {
+	settings, err := s.prefs.FindSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.Level == "" {
+		settings.Level = domain.NotifyAll
+	}
+	return settings, nil
+}
+
+func (s *NotificationPreferenceService) UpdateNotificationSettings(userID string, settings domain.NotificationSettings) (*domain.NotificationSettings, error) {
+	if settings.Level == "" {
+		settings.Level = domain.NotifyAll
+	}
+	if !settings.Level.Valid() {
+		return nil, ErrInvalidNotificationLevel
+	}
+	// time.LoadLocation takes "Local" to mean the server's zone, which is
+	// meaningless to clients.
+	if _, err := time.LoadLocation(settings.TimeZone); err != nil || settings.TimeZone == "Local" {
+		return nil, ErrInvalidTimeZone
+	}
+	if settings.DoNotDisturb != nil && !settings.DoNotDisturb.Valid() {
+		return nil, ErrInvalidDoNotDisturb
+	}
+	settings.UserID = userID
+	if err := s.prefs.SaveSettings(&settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (s *NotificationPreferenceService) MuteConversation(userID, conversationID string, until *time.Time) (*domain.ConversationMute, error) {
+	conv, err := s.conversationFor(userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if until != nil && !until.After(s.now()) {
+		return nil, ErrMuteExpired
+	}
+	mute := &domain.ConversationMute{UserID: userID, ConversationID: conv.ID, Until: until}
+	if err := s.prefs.Mute(mute); err != nil {
+		return nil, err
+	}
+	return mute, nil
+}
+
+func (s *NotificationPreferenceService) UnmuteConversation(userID, conversationID string) error {
+	id, err := uuid.Parse(conversationID)
+	if err != nil {
+		return ErrMuteNotFound
+	}
+	if err := s.prefs.Unmute(userID, id); err != nil {
+		return ErrMuteNotFound
+	}
+	return nil
+}
+
+func (s *NotificationPreferenceService) GetMutes(userID string) ([]*domain.ConversationMute, error) {
+	mutes, err := s.prefs.FindMutes(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	active := mutes[:0]
+	for _, m := range mutes {
+		if m.Active(now) {
+			active = append(active, m)
+		}
+	}
+	return active, nil
+}
+
+func (s *NotificationPreferenceService) conversationFor(userID, conversationID string) (*domain.Conversation, error) {
+	id, err := uuid.Parse(conversationID)
+	if err != nil {
+		return nil, ErrConversationNotFound
+	}
+	conv, err := s.conversations.FindByID(id)
+	if err != nil || !contains(conv.ParticipantIDs, userID) {
+		return nil, ErrConversationNotFound
+	}
+	return conv, nil
+}
+
+var (
+	_ ports.NotificationPreferenceService = (*NotificationPreferenceService)(nil)
+	_ ports.NotificationService           = (*NotificationPreferenceService)(nil)
+)
@@ -0,0 +1,140 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestNotificationPreferenceService_Settings(t *testing.T) {
+	svc := NewNotificationPreferenceService(memory.NewNotificationPreferenceRepository(),
+		memory.NewConversationRepository(), mocks.NewMockNotificationService(t))
+
+	settings, err := svc.GetNotificationSettings("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NotifyAll, settings.Level)
+
+	_, err = svc.UpdateNotificationSettings("alice", domain.NotificationSettings{Level: "loud"})
+	assert.ErrorIs(t, err, ErrInvalidNotificationLevel)
+	_, err = svc.UpdateNotificationSettings("alice", domain.NotificationSettings{TimeZone: "Mars/Olympus"})
+	assert.ErrorIs(t, err, ErrInvalidTimeZone)
+	_, err = svc.UpdateNotificationSettings("alice", domain.NotificationSettings{TimeZone: "Local"})
+	assert.ErrorIs(t, err, ErrInvalidTimeZone)
+	_, err = svc.UpdateNotificationSettings("alice", domain.NotificationSettings{DoNotDisturb: &domain.DoNotDisturb{Start: "22:00"}})
+	assert.ErrorIs(t, err, ErrInvalidDoNotDisturb)
+
+	updated, err := svc.UpdateNotificationSettings("alice", domain.NotificationSettings{
+		Level:        domain.NotifyMentions,
+		TimeZone:     "America/New_York",
+		DoNotDisturb: &domain.DoNotDisturb{Start: "22:00", End: "07:00"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", updated.UserID)
+	settings, err = svc.GetNotificationSettings("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, updated, settings)
+}
+
+func TestNotificationPreferenceService_Mutes(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	svc := NewNotificationPreferenceService(memory.NewNotificationPreferenceRepository(), convs, mocks.NewMockNotificationService(t))
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	other := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "carol"}}
+	assert.NoError(t, convs.Create(conv))
+	assert.NoError(t, convs.Create(other))
+
+	_, err := svc.MuteConversation("carol", conv.ID.String(), nil)
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+	past := now.Add(-time.Minute)
+	_, err = svc.MuteConversation("alice", conv.ID.String(), &past)
+	assert.ErrorIs(t, err, ErrMuteExpired)
+
+	hour := now.Add(time.Hour)
+	_, err = svc.MuteConversation("alice", conv.ID.String(), &hour)
+	assert.NoError(t, err)
+	_, err = svc.MuteConversation("alice", other.ID.String(), nil)
+	assert.NoError(t, err)
+
+	mutes, err := svc.GetMutes("alice")
+	assert.NoError(t, err)
+	assert.Len(t, mutes, 2)
+
+	now = now.Add(2 * time.Hour)
+	mutes, err = svc.GetMutes("alice")
+	assert.NoError(t, err)
+	if assert.Len(t, mutes, 1, "expired mutes are left out") {
+		assert.Equal(t, other.ID, mutes[0].ConversationID)
+	}
+
+	assert.NoError(t, svc.UnmuteConversation("alice", other.ID.String()))
+	assert.ErrorIs(t, svc.UnmuteConversation("alice", other.ID.String()), ErrMuteNotFound)
+	assert.ErrorIs(t, svc.UnmuteConversation("alice", "nope"), ErrMuteNotFound)
+}
+
+func TestNotificationPreferenceService_Notify(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	next := mocks.NewMockNotificationService(t)
+	svc := NewNotificationPreferenceService(memory.NewNotificationPreferenceRepository(), convs, next)
+	// 23:30 in Athens.
+	now := time.Date(2024, 1, 10, 21, 30, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob", "carol"}}
+	assert.NoError(t, convs.Create(conv))
+	message := func(userID string) *domain.Notification {
+		return &domain.Notification{Type: domain.NotificationNewMessage, UserID: userID, ConversationID: conv.ID, MessageID: uuid.New()}
+	}
+	edit := func(userID string) *domain.Notification {
+		return &domain.Notification{Type: domain.NotificationMessageEdited, UserID: userID, ConversationID: conv.ID, MessageID: uuid.New()}
+	}
+	delivered := func(userID string) {
+		next.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool { return n.UserID == userID })).Return(nil).Once()
+	}
+
+	// No preferences: everything goes through.
+	delivered("alice")
+	assert.NoError(t, svc.Notify(message("alice")))
+
+	// Muted until later, then the mute runs out.
+	later := now.Add(time.Hour)
+	_, err := svc.MuteConversation("alice", conv.ID.String(), &later)
+	assert.NoError(t, err)
+	assert.NoError(t, svc.Notify(message("alice")))
+	// Edits keep the muted conversation in sync.
+	delivered("alice")
+	assert.NoError(t, svc.Notify(edit("alice")))
+	now = later
+	delivered("alice")
+	assert.NoError(t, svc.Notify(message("alice")))
+
+	// Mentions only.
+	_, err = svc.UpdateNotificationSettings("bob", domain.NotificationSettings{Level: domain.NotifyMentions})
+	assert.NoError(t, err)
+	assert.NoError(t, svc.Notify(message("bob")))
+	delivered("bob")
+	assert.NoError(t, svc.Notify(&domain.Notification{Type: domain.NotificationMention, UserID: "bob", ConversationID: conv.ID}))
+
+	// Do not disturb at night in the user's zone.
+	now = time.Date(2024, 1, 10, 21, 30, 0, 0, time.UTC)
+	_, err = svc.UpdateNotificationSettings("carol", domain.NotificationSettings{
+		TimeZone:     "Europe/Athens",
+		DoNotDisturb: &domain.DoNotDisturb{Start: "23:00", End: "07:00"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, svc.Notify(&domain.Notification{Type: domain.NotificationContactRequest, UserID: "carol"}))
+	delivered("carol")
+	assert.NoError(t, svc.Notify(&domain.Notification{Type: domain.NotificationTyping, UserID: "carol", ConversationID: conv.ID}))
+	now = now.Add(10 * time.Hour)
+	delivered("carol")
+	assert.NoError(t, svc.Notify(&domain.Notification{Type: domain.NotificationContactRequest, UserID: "carol"}))
+}
@@ -0,0 +1,25 @@
+package ports
+
+import "github.com/chrikar/chatheon/domain"
+
+// AdminService holds operator actions. Callers are expected to have
+// checked that the actor is an admin.
+type AdminService interface {
+	// List users whose username contains query.
+	ListUsers(query string, limit, offset int) ([]*domain.User, error)
+
+	// Disable an account and cut off its sessions and API keys.
+	DisableUser(actorID, userID string) error
+
+	// Re-enable a disabled account.
+	EnableUser(userID string) error
+
+	// Require the user to change their password before logging in again.
+	ForcePasswordReset(userID string) error
+
+	// Delete any message.
+	DeleteMessage(messageID string) error
+
+	// Delete any conversation together with its messages.
+	DeleteConversation(conversationID string) error
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// APIKeyRepository defines persistence for API keys.
+type APIKeyRepository interface {
+	// Create persists a new key.
+	Create(key *domain.APIKey) error
+	// FindByID returns a single key, revoked or not.
+	FindByID(id uuid.UUID) (*domain.APIKey, error)
+	// FindByPrefix looks a key up by its public prefix.
+	FindByPrefix(prefix string) (*domain.APIKey, error)
+	// FindByUser returns all keys belonging to userID.
+	FindByUser(userID string) ([]*domain.APIKey, error)
+	// Update overwrites an existing key.
+	Update(key *domain.APIKey) error
+}
@@ -0,0 +1,20 @@
+package ports
+
+import "github.com/chrikar/chatheon/domain"
+
+// APIKeyService manages bot accounts and the API keys used by
+// integrations.
+type APIKeyService interface {
+	// CreateBot registers a bot account owned by ownerID.
+	CreateBot(ownerID, username string) (*domain.User, error)
+
+	// CreateAPIKey issues a key for userID, which must be the caller or a
+	// bot the caller owns. The plaintext key is returned only here.
+	CreateAPIKey(callerID, userID, name string, scopes []domain.Scope) (string, *domain.APIKey, error)
+
+	// ListAPIKeys returns the active keys of userID.
+	ListAPIKeys(callerID, userID string) ([]*domain.APIKey, error)
+
+	// RevokeAPIKey revokes a key the caller is allowed to manage.
+	RevokeAPIKey(callerID, keyID string) error
+}
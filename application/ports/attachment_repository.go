@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// ErrAttachmentSent is returned when an attachment was sent with another
+// message first.
+var ErrAttachmentSent = errors.New("attachment was already sent")
+
+type AttachmentRepository interface {
+	Create(attachment *domain.Attachment) error
+	FindByID(id uuid.UUID) (*domain.Attachment, error)
+	// FindByMessages returns the attachments sent with the given
+	// messages, in upload order.
+	FindByMessages(messageIDs []uuid.UUID) ([]*domain.Attachment, error)
+	// AttachToMessage links unsent attachments to messageID, all of them
+	// or none: if any is missing or already sent it returns
+	// ErrAttachmentSent.
+	AttachToMessage(ids []uuid.UUID, messageID uuid.UUID) error
+	// DeleteByMessages removes the records of files sent with the given
+	// messages. Their blobs are left for the caller.
+	DeleteByMessages(messageIDs []uuid.UUID) error
+	// DeleteUnsent removes up to limit records of files uploaded before
+	// the cutoff and never sent, other than those in keep, and returns
+	// them. Their blobs are left for the caller.
+	DeleteUnsent(before time.Time, keep []uuid.UUID, limit int) ([]*domain.Attachment, error)
+}
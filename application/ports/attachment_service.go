@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"io"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+type AttachmentService interface {
+	// Upload stores a file for uploaderID to send with a later message.
+	Upload(uploaderID, name string, r io.Reader) (*domain.Attachment, error)
+	// Open returns an attachment's metadata and contents. The caller
+	// must close the reader.
+	Open(userID, attachmentID string) (*domain.Attachment, io.ReadCloser, error)
+	// OpenThumbnail returns an image attachment's PNG preview.
+	OpenThumbnail(userID, attachmentID string) (*domain.Attachment, io.ReadCloser, error)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrBlobNotFound is returned by BlobStore.Get for unknown keys.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore keeps opaque file contents under string keys. Keys may contain
+// slashes but never "..".
+type BlobStore interface {
+	// Put stores everything read from r under key, replacing any
+	// existing blob.
+	Put(key string, r io.Reader) error
+	Get(key string) (io.ReadCloser, error)
+	// Delete removes a blob; deleting a missing key is a no-op.
+	Delete(key string) error
+}
@@ -0,0 +1,13 @@
+package ports
+
+import "github.com/chrikar/chatheon/domain"
+
+type BlockRepository interface {
+	// Add records a block; blocking someone already blocked is a no-op.
+	Add(block *domain.Block) error
+	Remove(blockerID, blockedID string) error
+	// Exists reports whether blockerID has blocked blockedID.
+	Exists(blockerID, blockedID string) (bool, error)
+	// FindByBlocker returns the blocks blockerID made, newest first.
+	FindByBlocker(blockerID string) ([]*domain.Block, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import "github.com/chrikar/chatheon/domain"
+
+// BlockService lets users stop others from contacting them.
+type BlockService interface {
+	// BlockUser blocks blockedID on behalf of blockerID. Blocking someone
+	// already blocked returns the existing block.
+	BlockUser(blockerID, blockedID string) (*domain.Block, error)
+	UnblockUser(blockerID, blockedID string) error
+	// GetBlocks lists the users userID has blocked, newest first.
+	GetBlocks(userID string) ([]*domain.Block, error)
+}
@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+type ContactRepository interface {
+	CreateRequest(request *domain.ContactRequest) error
+	FindRequest(id uuid.UUID) (*domain.ContactRequest, error)
+	// FindPendingRequest returns the pending request between two users,
+	// whichever of them sent it.
+	FindPendingRequest(userA, userB string) (*domain.ContactRequest, error)
+	// FindPendingRequests returns the pending requests userID sent or
+	// received, newest first.
+	FindPendingRequests(userID string) ([]*domain.ContactRequest, error)
+	UpdateRequest(request *domain.ContactRequest) error
+
+	// AddContact makes two users each other's contacts. Adding existing
+	// contacts is a no-op.
+	AddContact(userA, userB string, since time.Time) error
+	// RemoveContact removes the contact both ways.
+	RemoveContact(userA, userB string) error
+	IsContact(userID, contactID string) (bool, error)
+	// FindContacts returns userID's contacts, newest first.
+	FindContacts(userID string) ([]*domain.Contact, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import "github.com/chrikar/chatheon/domain"
+
+// ContactService manages contact lists and the requests that fill them.
+type ContactService interface {
+	// SendContactRequest asks toID to become fromID's contact. If toID
+	// already asked fromID, that request is accepted instead.
+	SendContactRequest(fromID, toID string) (*domain.ContactRequest, error)
+	AcceptContactRequest(userID, requestID string) (*domain.ContactRequest, error)
+	DeclineContactRequest(userID, requestID string) error
+	CancelContactRequest(userID, requestID string) error
+	// GetContactRequests lists the pending requests userID sent or
+	// received, newest first.
+	GetContactRequests(userID string) ([]*domain.ContactRequest, error)
+
+	GetContacts(userID string) ([]*domain.Contact, error)
+	RemoveContact(userID, contactID string) error
+	// SetContactsOnly sets whether only userID's contacts may send them
+	// direct messages.
+	SetContactsOnly(userID string, enabled bool) error
+}
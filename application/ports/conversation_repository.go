@@ -1,6 +1,10 @@
 package ports
 
-import "github.com/chrikar/chatheon/domain"
+import (
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
 
 // ConversationRepository defines persistence for conversations.
 type ConversationRepository interface {
@@ -8,4 +12,13 @@ type ConversationRepository interface {
 	Create(conversation *domain.Conversation) error
 	// FindByParticipant returns all conversations containing userID.
 	FindByParticipant(userID string) ([]*domain.Conversation, error)
+	// FindByID returns a single conversation.
+	FindByID(id uuid.UUID) (*domain.Conversation, error)
+	// FindWithRetention returns the conversations that override the
+	// server-wide retention policy.
+	FindWithRetention() ([]*domain.Conversation, error)
+	// Update saves changes to an existing conversation.
+	Update(conversation *domain.Conversation) error
+	// Delete removes a conversation.
+	Delete(id uuid.UUID) error
 }
@@ -1,14 +1,74 @@
 package ports
 
 import (
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
 
 	"github.com/chrikar/chatheon/domain"
 )
 
+// ErrMessageNotFound is returned for a message that doesn't exist or, on
+// reads, has expired.
+var ErrMessageNotFound = errors.New("message not found")
+
+// RetentionFilter selects the messages a retention policy applies to.
+type RetentionFilter struct {
+	// Before is the cutoff; messages sent earlier are past retention.
+	Before time.Time
+	// ConversationID restricts the filter to one conversation. When it
+	// is zero the filter covers direct messages and every conversation
+	// not listed in Exclude.
+	ConversationID uuid.UUID
+	Exclude        []uuid.UUID
+}
+
+// MessageRepository stores messages. Reads leave out expired messages, and
+// listings also leave out messages the listing user has hidden with
+// HideForUser.
 type MessageRepository interface {
 	Create(message *domain.Message) error
 	GetMessagesBySender(senderID string) ([]*domain.Message, error)
 	GetMessagesByReceiver(receiverID string, limit, offset int) ([]*domain.Message, error)
+	// GetMessagesByConversation lists a conversation's top-level messages
+	// oldest first, as seen by userID. Thread replies are left out.
+	GetMessagesByConversation(conversationID uuid.UUID, userID string, limit, offset int) ([]*domain.Message, error)
+	// GetThreadReplies lists the replies under rootID oldest first, as
+	// seen by userID.
+	GetThreadReplies(rootID uuid.UUID, userID string, limit, offset int) ([]*domain.Message, error)
+	// ThreadSummaries summarizes the threads under the given roots for
+	// userID. Roots without replies are absent from the result.
+	ThreadSummaries(rootIDs []uuid.UUID, userID string) (map[uuid.UUID]*domain.ThreadSummary, error)
+	MarkThreadRead(rootID uuid.UUID, userID string, readAt time.Time) error
+	// GetMentions lists messages mentioning userID, newest first.
+	GetMentions(userID string, limit, offset int) ([]*domain.Message, error)
 	SetMessageStatus(messageID uuid.UUID, status domain.MessageStatus) error
+	FindByID(messageID uuid.UUID) (*domain.Message, error)
+	Update(message *domain.Message) error
+	AddRevision(revision *domain.MessageRevision) error
+	FindRevisions(messageID uuid.UUID) ([]*domain.MessageRevision, error)
+	// HideForUser removes a message from userID's listings only.
+	HideForUser(messageID uuid.UUID, userID string) error
+	// DeleteForEveryone turns a message into a tombstone and drops its
+	// revisions.
+	DeleteForEveryone(messageID uuid.UUID, deletedAt time.Time) error
+	Delete(messageID uuid.UUID) error
+	DeleteByConversation(conversationID uuid.UUID) error
+	// FindThreadIDs returns rootID followed by the IDs of its thread
+	// replies, hidden and expired ones included. It returns nothing if
+	// rootID doesn't exist.
+	FindThreadIDs(rootID uuid.UUID) ([]uuid.UUID, error)
+	// FindExpired returns the IDs of up to limit messages that expired
+	// by now together with their thread replies, which are purged with
+	// their root.
+	FindExpired(now time.Time, limit int) ([]uuid.UUID, error)
+	// FindPastRetention pages through the messages matching filter,
+	// oldest first, including hidden and expired ones. Each page also
+	// holds the thread replies of the messages on it, which are purged
+	// with their root.
+	FindPastRetention(filter RetentionFilter, limit, offset int) ([]*domain.Message, error)
+	// DeleteMany removes the given messages with everything stored about
+	// them. Callers list thread replies along with their root.
+	DeleteMany(messageIDs []uuid.UUID) error
 }
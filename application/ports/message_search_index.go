@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// SearchQuery selects indexed messages. Results are limited to messages in
+// ConversationIDs plus direct messages ParticipantID sent or received,
+// leaving out ones ParticipantID hid. Zero-valued filters are ignored.
+type SearchQuery struct {
+	Terms           []domain.SearchTerm
+	ParticipantID   string
+	ConversationIDs []uuid.UUID
+	// ConversationID and SenderID narrow the results further.
+	ConversationID uuid.UUID
+	SenderID       string
+	// Since and Until bound CreatedAt; Until is exclusive.
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+	Offset int
+}
+
+// MessageSearchIndex is a full-text index over message content. It is
+// kept in sync by MessageService.
+type MessageSearchIndex interface {
+	// Index adds a message or replaces its indexed content.
+	Index(message *domain.Message) error
+	Remove(messageID uuid.UUID) error
+	// Hide leaves a message out of userID's results.
+	Hide(messageID uuid.UUID, userID string) error
+	// Search returns matching message IDs, newest first.
+	Search(query SearchQuery) ([]uuid.UUID, error)
+}
@@ -1,9 +1,73 @@
 package ports
 
-import "github.com/chrikar/chatheon/domain"
+import (
+	"time"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// MessageDraft is a message as submitted by its sender. Set either
+// ReceiverID for a direct message or ConversationID. A reply sets
+// ReplyToID and may leave both empty to stay where the parent is.
+// AttachmentIDs name files the sender uploaded beforehand, and QuoteID a
+// message from the same chat to quote inline. Format defaults to plain.
+type MessageDraft struct {
+	ReceiverID     string
+	ConversationID string
+	ReplyToID      string
+	QuoteID        string
+	Content        string
+	Format         domain.MessageFormat
+	AttachmentIDs  []string
+}
+
+// ForwardTarget says where a forwarded message goes: set either
+// ReceiverID or ConversationID.
+type ForwardTarget struct {
+	ReceiverID     string
+	ConversationID string
+}
+
+// MessageSearch is a search request. Query uses the syntax of
+// domain.ParseSearchQuery; the other fields are optional filters.
+type MessageSearch struct {
+	Query          string
+	ConversationID string
+	SenderID       string
+	Since          time.Time
+	Until          time.Time
+	Limit          int
+	Offset         int
+}
+
+// Thread is a page of replies under a thread root.
+type Thread struct {
+	Root    *domain.Message   `json:"root"`
+	Replies []*domain.Message `json:"replies"`
+}
 
 type MessageService interface {
-	CreateMessage(senderID, receiverID, content string) error
+	CreateMessage(senderID string, draft MessageDraft) (*domain.Message, error)
 	GetMessagesByReceiver(receiverID string, limit, offset int) ([]*domain.Message, error)
+	GetConversationMessages(userID, conversationID string, limit, offset int) ([]*domain.Message, error)
 	SetMessageStatus(messageID string, status domain.MessageStatus) error
+	ForwardMessage(userID, messageID string, target ForwardTarget) (*domain.Message, error)
+	EditMessage(editorID, messageID, content string) (*domain.Message, error)
+	GetMessageHistory(callerID, messageID string) ([]*domain.MessageRevision, error)
+	DeleteMessageForMe(userID, messageID string) error
+	DeleteMessageForEveryone(userID, messageID string) error
+	GetThread(userID, messageID string, limit, offset int) (*Thread, error)
+	MarkThreadRead(userID, messageID string) error
+	GetMentions(userID string, limit, offset int) ([]*domain.Message, error)
+	SearchMessages(userID string, search MessageSearch) ([]*domain.Message, error)
+	ScheduleMessage(senderID string, draft MessageDraft, sendAt time.Time) (*domain.ScheduledMessage, error)
+	GetScheduledMessages(userID string, limit, offset int) ([]*domain.ScheduledMessage, error)
+	RescheduleMessage(userID, scheduledID string, sendAt time.Time) (*domain.ScheduledMessage, error)
+	CancelScheduledMessage(userID, scheduledID string) error
+	SetMessageTTL(userID, conversationID string, ttl time.Duration) (*domain.Conversation, error)
+	PinMessage(userID, messageID string) (*domain.Pin, error)
+	UnpinMessage(userID, messageID string) error
+	GetPins(userID, conversationID string) ([]*domain.Pin, error)
+	AddReaction(userID, messageID, emoji string) error
+	RemoveReaction(userID, messageID, emoji string) error
 }
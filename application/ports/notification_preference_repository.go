@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+type NotificationPreferenceRepository interface {
+	// FindSettings returns userID's settings, or the zero value with
+	// UserID set if they never saved any.
+	FindSettings(userID string) (*domain.NotificationSettings, error)
+	SaveSettings(settings *domain.NotificationSettings) error
+
+	// Mute creates or replaces a user's mute of a conversation.
+	Mute(mute *domain.ConversationMute) error
+	Unmute(userID string, conversationID uuid.UUID) error
+	// FindMute returns userID's mute of a conversation, or nil if there is
+	// none. Expired mutes are returned as stored.
+	FindMute(userID string, conversationID uuid.UUID) (*domain.ConversationMute, error)
+	// FindMutes returns userID's mutes ordered by conversation ID.
+	FindMutes(userID string) ([]*domain.ConversationMute, error)
+}
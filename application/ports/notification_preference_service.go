@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// NotificationPreferenceService manages what users want to be notified
+// about.
+type NotificationPreferenceService interface {
+	GetNotificationSettings(userID string) (*domain.NotificationSettings, error)
+	// UpdateNotificationSettings replaces userID's settings.
+	UpdateNotificationSettings(userID string, settings domain.NotificationSettings) (*domain.NotificationSettings, error)
+
+	// MuteConversation silences a conversation for userID until the given
+	// time, or until unmuted if until is nil.
+	MuteConversation(userID, conversationID string, until *time.Time) (*domain.ConversationMute, error)
+	UnmuteConversation(userID, conversationID string) error
+	// GetMutes lists userID's conversation mutes still in force.
+	GetMutes(userID string) ([]*domain.ConversationMute, error)
+}
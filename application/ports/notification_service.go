@@ -1,5 +1,7 @@
 package ports
 
+import "github.com/chrikar/chatheon/domain"
+
 type NotificationService interface {
-	Notify(userID, message string) error
+	Notify(notification *domain.Notification) error
 }
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// ErrPinLimit is returned when a conversation already has as many pins
+// as it may.
+var ErrPinLimit = errors.New("conversation has reached its pin limit")
+
+type PinRepository interface {
+	// Add pins a message unless its conversation already has limit pins,
+	// in which case it returns ErrPinLimit. Pinning a message that is
+	// already pinned is a no-op.
+	Add(pin *domain.Pin, limit int) error
+	Remove(conversationID, messageID uuid.UUID) error
+	// FindByConversation returns a conversation's pins, newest first.
+	FindByConversation(conversationID uuid.UUID) ([]*domain.Pin, error)
+	// DeleteByMessages unpins the given messages wherever they are pinned.
+	DeleteByMessages(messageIDs []uuid.UUID) error
+}
@@ -0,0 +1,16 @@
+package ports
+
+import "github.com/chrikar/chatheon/domain"
+
+type PresenceService interface {
+	// TrackActivity records that userID made an authenticated request.
+	TrackActivity(userID string)
+	// Heartbeat keeps userID's presence alive between requests and says
+	// whether they are away.
+	Heartbeat(userID string, away bool) error
+	GetPresence(viewerID, userID string) (*domain.Presence, error)
+	// GetConversationPresence returns the presence of every participant
+	// of a conversation viewerID is in.
+	GetConversationPresence(viewerID, conversationID string) ([]domain.Presence, error)
+	SetLastSeenHidden(userID string, hidden bool) error
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// PresenceStore holds what the server last heard from each user.
+type PresenceStore interface {
+	// Seen records activity by userID at, leaving their away flag as is.
+	Seen(userID string, at time.Time) error
+	// Heartbeat records activity by userID at and whether they are away.
+	Heartbeat(userID string, away bool, at time.Time) error
+	// Find returns the activity of those of userIDs ever seen.
+	Find(userIDs []string) (map[string]domain.Activity, error)
+}
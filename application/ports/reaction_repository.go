@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+type ReactionRepository interface {
+	// Add stores a reaction; adding one that already exists is a no-op.
+	Add(reaction *domain.Reaction) error
+	Remove(messageID uuid.UUID, userID, emoji string) error
+	// FindByMessages returns the reactions on the given messages, oldest
+	// first.
+	FindByMessages(messageIDs []uuid.UUID) ([]*domain.Reaction, error)
+	// DeleteByMessages removes every reaction on the given messages.
+	DeleteByMessages(messageIDs []uuid.UUID) error
+}
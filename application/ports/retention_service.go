@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// RetentionPolicies lists the retention policies in force.
+type RetentionPolicies struct {
+	// DefaultDays is the server-wide policy; zero keeps messages forever.
+	DefaultDays int `json:"default_days"`
+	// Conversations override the server-wide policy.
+	Conversations []*domain.Conversation `json:"conversations"`
+	// LastPurge is the most recent purge other than a dry run.
+	LastPurge *domain.PurgeReport `json:"last_purge,omitempty"`
+}
+
+// RetentionService removes messages once they are older than the
+// retention policy that covers them. Callers are expected to have checked
+// that the actor is an admin.
+type RetentionService interface {
+	// GetRetentionPolicies describes the policies and the last purge.
+	GetRetentionPolicies() (*RetentionPolicies, error)
+	// SetConversationRetention overrides the server-wide policy for one
+	// conversation; zero reverts to the server-wide policy.
+	SetConversationRetention(conversationID string, retention time.Duration) (*domain.Conversation, error)
+	// Purge deletes everything past retention, or only reports what it
+	// would delete when dryRun is set.
+	Purge(dryRun bool) (*domain.PurgeReport, error)
+}
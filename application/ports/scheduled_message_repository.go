@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// ErrNotPending is returned when a scheduled message was already sent,
+// canceled, or is being sent right now.
+var ErrNotPending = errors.New("scheduled message is no longer pending")
+
+type ScheduledMessageRepository interface {
+	Create(message *domain.ScheduledMessage) error
+	FindByID(id uuid.UUID) (*domain.ScheduledMessage, error)
+	// ListPending lists senderID's pending messages, soonest first.
+	ListPending(senderID string, limit, offset int) ([]*domain.ScheduledMessage, error)
+	// Reschedule and Cancel only touch pending messages that no scheduler
+	// has claimed; otherwise they return ErrNotPending.
+	Reschedule(id uuid.UUID, sendAt, now time.Time) error
+	Cancel(id uuid.UUID, now time.Time) error
+	// ClaimDue leases up to limit pending messages due by now, so that
+	// concurrent schedulers never pick the same one. A claim that isn't
+	// resolved before the lease ends is picked up again.
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]*domain.ScheduledMessage, error)
+	// PendingAttachmentIDs returns the uploads that pending messages will
+	// send, which must outlive the unsent-upload TTL.
+	PendingAttachmentIDs() ([]uuid.UUID, error)
+	MarkSent(id uuid.UUID) error
+	MarkFailed(id uuid.UUID, reason string) error
+}
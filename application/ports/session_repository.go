@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// SessionRepository defines persistence for login sessions.
+type SessionRepository interface {
+	// Create persists a new session.
+	Create(session *domain.Session) error
+	// FindByID returns a single session, revoked or not.
+	FindByID(id uuid.UUID) (*domain.Session, error)
+	// FindByUser returns all sessions of userID, oldest first.
+	FindByUser(userID string) ([]*domain.Session, error)
+	// Update overwrites an existing session.
+	Update(session *domain.Session) error
+	// TouchSession records activity on a session at at. It only writes
+	// LastSeenAt, and leaves revoked sessions alone, so it can't undo a
+	// revocation that raced with it.
+	TouchSession(id uuid.UUID, at time.Time) error
+}
@@ -0,0 +1,12 @@
+package ports
+
+import "github.com/chrikar/chatheon/domain"
+
+// SessionService lets users see and revoke their login sessions.
+type SessionService interface {
+	// List the active sessions of a user.
+	ListSessions(userID string) ([]*domain.Session, error)
+
+	// Revoke one of the user's own sessions.
+	RevokeSession(userID, sessionID string) error
+}
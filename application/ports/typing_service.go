@@ -0,0 +1,12 @@
+package ports
+
+import "github.com/chrikar/chatheon/domain"
+
+type TypingService interface {
+	// StartTyping marks userID as typing in a conversation for a few
+	// seconds; clients repeat it while the user keeps typing.
+	StartTyping(userID, conversationID string) (*domain.Typer, error)
+	StopTyping(userID, conversationID string) error
+	// GetTypers lists the other participants typing in a conversation.
+	GetTypers(userID, conversationID string) ([]domain.Typer, error)
+}
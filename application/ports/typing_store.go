@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// TypingStore holds typing indicators. Entries lapse on their own at
+// their expiry; now is passed in to judge which have.
+type TypingStore interface {
+	// Touch marks userID as typing until expiresAt, reporting whether
+	// they weren't typing already.
+	Touch(conversationID uuid.UUID, userID string, now, expiresAt time.Time) (bool, error)
+	// Clear removes userID's indicator, reporting whether they were
+	// typing.
+	Clear(conversationID uuid.UUID, userID string, now time.Time) (bool, error)
+	// Typers lists who is typing at now, ordered by user ID.
+	Typers(conversationID uuid.UUID, now time.Time) ([]domain.Typer, error)
+}
@@ -1,8 +1,30 @@
 package ports
 
-import "github.com/chrikar/chatheon/domain"
+import (
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// ErrCodeUsed is returned when a second-factor code was used already.
+var ErrCodeUsed = errors.New("two-factor code was already used")
 
 type UserRepository interface {
 	Create(user *domain.User) error
 	FindByUsername(username string) (*domain.User, error)
+	FindByID(id uuid.UUID) (*domain.User, error)
+	Update(user *domain.User) error
+	// UseTOTPCounter records counter as the last TOTP time step the user
+	// logged in with. If that step or a later one was used already it
+	// returns ErrCodeUsed, so a code can't be redeemed twice at once.
+	UseTOTPCounter(id uuid.UUID, counter int64) error
+	// UseRecoveryCode removes hash from the user's recovery codes, or
+	// returns ErrCodeUsed if it isn't among them.
+	UseRecoveryCode(id uuid.UUID, hash string) error
+	// Search returns users whose username contains query (case-insensitive),
+	// ordered by username, leaving out the IDs in exclude. An empty query
+	// matches everyone.
+	Search(query string, exclude []string, limit, offset int) ([]*domain.User, error)
 }
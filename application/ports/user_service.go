@@ -1,6 +1,53 @@
 package ports
 
+import "github.com/chrikar/chatheon/domain"
+
+// LoginResult is what a successful password check yields. Users without
+// two-factor authentication get a Token straight away; everyone else gets
+// a Challenge to redeem with CompleteTOTPLogin.
+type LoginResult struct {
+	Token     string
+	Challenge string
+}
+
+// ClientInfo describes where a login comes from. It is recorded on the
+// session the login creates.
+type ClientInfo struct {
+	DeviceName string
+	UserAgent  string
+	IP         string
+}
+
+// TOTPEnrollment holds what a client needs to set up an authenticator app.
+type TOTPEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
 type UserService interface {
 	Register(username, password string) error
-	Login(username, password string) (string, error)
+	Login(username, password string, client ClientInfo) (*LoginResult, error)
+	CompleteTOTPLogin(challenge, code string, client ClientInfo) (string, error)
+	// ChangePassword needs a current two-factor code as well when the
+	// account has two-factor authentication.
+	ChangePassword(username, oldPassword, newPassword, code string) error
+
+	// EnrollTOTP starts two-factor enrollment for userID. It has no effect
+	// on login until ConfirmTOTP succeeds.
+	EnrollTOTP(userID string) (*TOTPEnrollment, error)
+	// ConfirmTOTP enables two-factor authentication and returns the
+	// recovery codes. They are only ever shown this once.
+	ConfirmTOTP(userID, code string) ([]string, error)
+	DisableTOTP(userID, code string) error
+
+	// SetRole changes the global role of userID.
+	SetRole(userID string, role domain.Role) error
+
+	// SetEmail sets or, when email is empty, removes the address userID
+	// gets email notifications at.
+	SetEmail(userID, email string) error
+
+	// SearchUsers searches the user directory by username as viewerID
+	// sees it, ordered by username.
+	SearchUsers(viewerID, query string, limit, offset int) ([]*domain.User, error)
 }
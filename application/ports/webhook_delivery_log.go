@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// WebhookDeliveryStatus is how an attempt to deliver an event to a
+// webhook endpoint ended.
+type WebhookDeliveryStatus string
+
+const (
+	DeliverySucceeded WebhookDeliveryStatus = "delivered"
+	// DeliveryFailed means every attempt failed.
+	DeliveryFailed WebhookDeliveryStatus = "failed"
+	// DeliverySkipped means the endpoint's circuit was open, so no attempt
+	// was made.
+	DeliverySkipped WebhookDeliveryStatus = "skipped"
+	// DeliveryDropped means the endpoint's queue was full.
+	DeliveryDropped WebhookDeliveryStatus = "dropped"
+)
+
+// WebhookDelivery records the outcome of sending one event to one
+// endpoint.
+type WebhookDelivery struct {
+	EventID    uuid.UUID               `json:"event_id"`
+	URL        string                  `json:"url"`
+	Type       domain.NotificationType `json:"type"`
+	UserID     string                  `json:"user_id"`
+	Status     WebhookDeliveryStatus   `json:"status"`
+	Attempts   int                     `json:"attempts"`
+	StatusCode int                     `json:"status_code,omitempty"`
+	Error      string                  `json:"error,omitempty"`
+	At         time.Time               `json:"at"`
+}
+
+// WebhookDeliveryLog keeps the outcome of recent webhook deliveries.
+type WebhookDeliveryLog interface {
+	// Deliveries pages through the retained deliveries, newest first.
+	Deliveries(limit, offset int) []WebhookDelivery
+}
@@ -0,0 +1,176 @@
+package application
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+const (
+	// DefaultPresenceIdle is how long after their last activity a user
+	// shows as away.
+	DefaultPresenceIdle = 2 * time.Minute
+	// DefaultPresenceTimeout is how long after their last activity a user
+	// shows as offline.
+	DefaultPresenceTimeout = 5 * time.Minute
+)
+
+// PresenceService is the application-layer implementation of
+// ports.PresenceService.
+type PresenceService struct {
+	store         ports.PresenceStore
+	users         ports.UserRepository
+	conversations ports.ConversationRepository
+	blocks        ports.BlockRepository
+	idle          time.Duration
+	timeout       time.Duration
+	now           func() time.Time
+}
+
+// PresenceOption configures optional PresenceService settings.
+type PresenceOption func(*PresenceService)
+
+// WithPresenceWindows overrides DefaultPresenceIdle and
+// DefaultPresenceTimeout.
+func WithPresenceWindows(idle, timeout time.Duration) PresenceOption {
+	return func(s *PresenceService) {
+		s.idle = idle
+		s.timeout = timeout
+	}
+}
+
+// WithPresenceBlocks shows users who blocked each other as offline to one
+// another.
+func WithPresenceBlocks(blocks ports.BlockRepository) PresenceOption {
+	return func(s *PresenceService) { s.blocks = blocks }
+}
+
+func NewPresenceService(store ports.PresenceStore, users ports.UserRepository, conversations ports.ConversationRepository, opts ...PresenceOption) *PresenceService {
+	s := &PresenceService{
+		store:         store,
+		users:         users,
+		conversations: conversations,
+		idle:          DefaultPresenceIdle,
+		timeout:       DefaultPresenceTimeout,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// TrackActivity runs on every authenticated request, so it never fails
+// the request; errors are only logged.
+func (s *PresenceService) TrackActivity(userID string) {
+	if err := s.store.Seen(userID, s.now()); err != nil {
+		log.Printf("presence: %s: %v", userID, err)
+	}
+}
+
+func (s *PresenceService) Heartbeat(userID string, away bool) error {
+	return s.store.Heartbeat(userID, away, s.now())
+}
+
+func (s *PresenceService) GetPresence(viewerID, userID string) (*domain.Presence, error) {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	presence, err := s.presenceOf(viewerID, []*domain.User{user})
+	if err != nil {
+		return nil, err
+	}
+	return &presence[0], nil
+}
+
+func (s *PresenceService) GetConversationPresence(viewerID, conversationID string) ([]domain.Presence, error) {
+	id, err := uuid.Parse(conversationID)
+	if err != nil {
+		return nil, ErrConversationNotFound
+	}
+	conv, err := s.conversations.FindByID(id)
+	if err != nil || !contains(conv.ParticipantIDs, viewerID) {
+		return nil, ErrConversationNotFound
+	}
+	users := make([]*domain.User, 0, len(conv.ParticipantIDs))
+	for _, participantID := range conv.ParticipantIDs {
+		// Participants whose accounts are gone are skipped rather than
+		// failing the whole list.
+		if user, err := s.findUser(participantID); err == nil {
+			users = append(users, user)
+		}
+	}
+	return s.presenceOf(viewerID, users)
+}
+
+func (s *PresenceService) SetLastSeenHidden(userID string, hidden bool) error {
+	user, err := s.findUser(userID)
+	if err != nil {
+		return err
+	}
+	if user.HideLastSeen == hidden {
+		return nil
+	}
+	user.HideLastSeen = hidden
+	return s.users.Update(user)
+}
+
+// presenceOf builds the presence of users as viewerID may see it.
+func (s *PresenceService) presenceOf(viewerID string, users []*domain.User) ([]domain.Presence, error) {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.ID.String()
+	}
+	activity, err := s.store.Find(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	out := make([]domain.Presence, len(users))
+	for i, user := range users {
+		hidden, err := s.blocked(viewerID, ids[i])
+		if err != nil {
+			return nil, err
+		}
+		if hidden {
+			out[i] = domain.Presence{UserID: ids[i], Status: domain.PresenceOffline}
+			continue
+		}
+		a := activity[ids[i]]
+		out[i] = domain.Presence{UserID: ids[i], Status: a.Status(now, s.idle, s.timeout)}
+		if !a.LastSeenAt.IsZero() && (!user.HideLastSeen || ids[i] == viewerID) {
+			seen := a.LastSeenAt
+			out[i].LastSeenAt = &seen
+		}
+	}
+	return out, nil
+}
+
+// blocked reports whether viewerID and userID blocked each other, in
+// which case neither sees the other's presence.
+func (s *PresenceService) blocked(viewerID, userID string) (bool, error) {
+	if s.blocks == nil || viewerID == userID {
+		return false, nil
+	}
+	return blockedEitherWay(s.blocks, viewerID, userID)
+}
+
+func (s *PresenceService) findUser(userID string) (*domain.User, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	user, err := s.users.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+var _ ports.PresenceService = (*PresenceService)(nil)
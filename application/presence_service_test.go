@@ -0,0 +1,98 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestPresenceService(t *testing.T) {
+	users := memory.NewUserRepository()
+	convs := memory.NewConversationRepository()
+	svc := NewPresenceService(memory.NewPresenceStore(), users, convs, WithPresenceWindows(time.Minute, 5*time.Minute))
+	start := time.Now()
+	now := start
+	svc.now = func() time.Time { return now }
+
+	alice := &domain.User{ID: uuid.New(), Username: "alice"}
+	bob := &domain.User{ID: uuid.New(), Username: "bob"}
+	carol := &domain.User{ID: uuid.New(), Username: "carol"}
+	for _, u := range []*domain.User{alice, bob, carol} {
+		assert.NoError(t, users.Create(u))
+	}
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{alice.ID.String(), bob.ID.String(), carol.ID.String()}}
+	assert.NoError(t, convs.Create(conv))
+
+	svc.TrackActivity(alice.ID.String())
+	assert.NoError(t, svc.Heartbeat(bob.ID.String(), true))
+
+	presence, err := svc.GetPresence(bob.ID.String(), alice.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PresenceOnline, presence.Status)
+	assert.Equal(t, start, *presence.LastSeenAt)
+
+	_, err = svc.GetPresence(bob.ID.String(), uuid.NewString())
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	// Hiding last seen keeps the status visible, and the owner still sees
+	// their own.
+	assert.NoError(t, svc.SetLastSeenHidden(alice.ID.String(), true))
+	now = now.Add(2 * time.Minute)
+	presence, err = svc.GetPresence(bob.ID.String(), alice.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PresenceAway, presence.Status)
+	assert.Nil(t, presence.LastSeenAt)
+	presence, err = svc.GetPresence(alice.ID.String(), alice.ID.String())
+	assert.NoError(t, err)
+	assert.NotNil(t, presence.LastSeenAt)
+
+	all, err := svc.GetConversationPresence(carol.ID.String(), conv.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Presence{
+		{UserID: alice.ID.String(), Status: domain.PresenceAway},
+		{UserID: bob.ID.String(), Status: domain.PresenceAway, LastSeenAt: &start},
+		{UserID: carol.ID.String(), Status: domain.PresenceOffline},
+	}, all)
+
+	_, err = svc.GetConversationPresence(uuid.NewString(), conv.ID.String())
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+}
+
+func TestPresenceService_HiddenAcrossBlocks(t *testing.T) {
+	users := memory.NewUserRepository()
+	convs := memory.NewConversationRepository()
+	blocks := memory.NewBlockRepository()
+	svc := NewPresenceService(memory.NewPresenceStore(), users, convs, WithPresenceBlocks(blocks))
+
+	alice := &domain.User{ID: uuid.New(), Username: "alice"}
+	bob := &domain.User{ID: uuid.New(), Username: "bob"}
+	carol := &domain.User{ID: uuid.New(), Username: "carol"}
+	for _, u := range []*domain.User{alice, bob, carol} {
+		assert.NoError(t, users.Create(u))
+		svc.TrackActivity(u.ID.String())
+	}
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{alice.ID.String(), bob.ID.String(), carol.ID.String()}}
+	assert.NoError(t, convs.Create(conv))
+	assert.NoError(t, blocks.Add(&domain.Block{BlockerID: alice.ID.String(), BlockedID: bob.ID.String()}))
+
+	// Neither side of the block sees the other.
+	for _, pair := range [][2]*domain.User{{alice, bob}, {bob, alice}} {
+		presence, err := svc.GetPresence(pair[0].ID.String(), pair[1].ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PresenceOffline, presence.Status)
+		assert.Nil(t, presence.LastSeenAt)
+	}
+
+	group, err := svc.GetConversationPresence(bob.ID.String(), conv.ID.String())
+	assert.NoError(t, err)
+	if assert.Len(t, group, 3) {
+		assert.Equal(t, domain.PresenceOffline, group[0].Status, "alice blocked bob")
+		assert.Equal(t, domain.PresenceOnline, group[1].Status, "bob sees himself")
+		assert.Equal(t, domain.PresenceOnline, group[2].Status)
+	}
+}
@@ -0,0 +1,184 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+var ErrInvalidRetention = errors.New("retention must be at least one day")
+
+// RetentionService is the application-layer implementation of
+// ports.RetentionService.
+type RetentionService struct {
+	messages         ports.MessageRepository
+	conversations    ports.ConversationRepository
+	reactions        ports.ReactionRepository
+	attachments      ports.AttachmentRepository
+	blobs            ports.BlobStore
+	search           ports.MessageSearchIndex
+	pins             ports.PinRepository
+	defaultRetention time.Duration
+	now              func() time.Time
+
+	// purging keeps the scheduled job and an admin from purging at once.
+	purging sync.Mutex
+	mu      sync.Mutex
+	last    *domain.PurgeReport
+}
+
+// RetentionOption configures optional RetentionService collaborators.
+type RetentionOption func(*RetentionService)
+
+// WithDefaultRetention sets the server-wide policy. Without it, only
+// conversations with their own policy are purged.
+func WithDefaultRetention(d time.Duration) RetentionOption {
+	return func(s *RetentionService) { s.defaultRetention = d }
+}
+
+// WithRetentionReactions purges reactions along with their messages.
+func WithRetentionReactions(repo ports.ReactionRepository) RetentionOption {
+	return func(s *RetentionService) { s.reactions = repo }
+}
+
+// WithRetentionAttachments purges attachments, and their files in blobs,
+// along with their messages.
+func WithRetentionAttachments(repo ports.AttachmentRepository, blobs ports.BlobStore) RetentionOption {
+	return func(s *RetentionService) {
+		s.attachments = repo
+		s.blobs = blobs
+	}
+}
+
+// WithRetentionSearchIndex removes purged messages from the search index.
+func WithRetentionSearchIndex(index ports.MessageSearchIndex) RetentionOption {
+	return func(s *RetentionService) { s.search = index }
+}
+
+// WithRetentionPins unpins purged messages.
+func WithRetentionPins(repo ports.PinRepository) RetentionOption {
+	return func(s *RetentionService) { s.pins = repo }
+}
+
+// NewRetentionService constructs a RetentionService.
+func NewRetentionService(messages ports.MessageRepository, conversations ports.ConversationRepository, opts ...RetentionOption) *RetentionService {
+	s := &RetentionService{
+		messages:      messages,
+		conversations: conversations,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetRetentionPolicies lists the server-wide policy, the conversations
+// that override it and the last purge.
+func (s *RetentionService) GetRetentionPolicies() (*ports.RetentionPolicies, error) {
+	convs, err := s.conversations.FindWithRetention()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &ports.RetentionPolicies{
+		DefaultDays:   int(s.defaultRetention / domain.Day),
+		Conversations: convs,
+		LastPurge:     s.last,
+	}, nil
+}
+
+// SetConversationRetention sets how long a conversation's messages are
+// kept, overriding the server-wide policy in either direction.
+func (s *RetentionService) SetConversationRetention(conversationID string, retention time.Duration) (*domain.Conversation, error) {
+	if retention != 0 && retention < domain.Day {
+		return nil, ErrInvalidRetention
+	}
+	id, err := uuid.Parse(conversationID)
+	if err != nil {
+		return nil, ErrConversationNotFound
+	}
+	conv, err := s.conversations.FindByID(id)
+	if err != nil {
+		return nil, ErrConversationNotFound
+	}
+	updated := *conv
+	updated.Retention = retention
+	if err := s.conversations.Update(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Purge applies every retention policy in turn: each conversation's own
+// first, then the server-wide one for everything else. Work is done in
+// batches, and derived data goes before the messages themselves, so an
+// interrupted run is finished by the next.
+func (s *RetentionService) Purge(dryRun bool) (*domain.PurgeReport, error) {
+	s.purging.Lock()
+	defer s.purging.Unlock()
+
+	report := &domain.PurgeReport{DryRun: dryRun, StartedAt: s.now()}
+	overrides, err := s.conversations.FindWithRetention()
+	if err != nil {
+		return nil, err
+	}
+	var exclude []uuid.UUID
+	for _, conv := range overrides {
+		exclude = append(exclude, conv.ID)
+		policy := domain.PolicyPurge{
+			ConversationID: conv.ID,
+			RetentionDays:  int(conv.Retention / domain.Day),
+			Cutoff:         report.StartedAt.Add(-conv.Retention),
+		}
+		filter := ports.RetentionFilter{Before: policy.Cutoff, ConversationID: conv.ID}
+		if err := s.purge(filter, dryRun, &policy.PurgeCounts); err != nil {
+			return nil, fmt.Errorf("purge conversation %s: %w", conv.ID, err)
+		}
+		report.Policies = append(report.Policies, policy)
+		report.Add(policy.PurgeCounts)
+	}
+	if s.defaultRetention > 0 {
+		policy := domain.PolicyPurge{
+			RetentionDays: int(s.defaultRetention / domain.Day),
+			Cutoff:        report.StartedAt.Add(-s.defaultRetention),
+		}
+		filter := ports.RetentionFilter{Before: policy.Cutoff, Exclude: exclude}
+		if err := s.purge(filter, dryRun, &policy.PurgeCounts); err != nil {
+			return nil, fmt.Errorf("purge: %w", err)
+		}
+		report.Policies = append(report.Policies, policy)
+		report.Add(policy.PurgeCounts)
+	}
+	report.FinishedAt = s.now()
+
+	if !dryRun {
+		s.mu.Lock()
+		s.last = report
+		s.mu.Unlock()
+	}
+	return report, nil
+}
+
+// purge removes the messages matching filter with their derived data,
+// adding what it removed to counts.
+func (s *RetentionService) purge(filter ports.RetentionFilter, dryRun bool, counts *domain.PurgeCounts) error {
+	purger := &messagePurger{
+		messages:    s.messages,
+		reactions:   s.reactions,
+		attachments: s.attachments,
+		blobs:       s.blobs,
+		search:      s.search,
+		pins:        s.pins,
+	}
+	return purger.purgeMatching(filter, dryRun, counts)
+}
+
+var _ ports.RetentionService = (*RetentionService)(nil)
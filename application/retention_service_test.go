@@ -0,0 +1,135 @@
+package application
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestRetentionService_SetConversationRetention(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	svc := NewRetentionService(memory.NewMessageRepository(), convs, WithDefaultRetention(30*domain.Day))
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(conv))
+
+	_, err := svc.SetConversationRetention(conv.ID.String(), time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidRetention)
+	_, err = svc.SetConversationRetention(uuid.NewString(), 7*domain.Day)
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+
+	updated, err := svc.SetConversationRetention(conv.ID.String(), 7*domain.Day)
+	assert.NoError(t, err)
+	assert.Equal(t, 7*domain.Day, updated.Retention)
+
+	policies, err := svc.GetRetentionPolicies()
+	assert.NoError(t, err)
+	assert.Equal(t, 30, policies.DefaultDays)
+	if assert.Len(t, policies.Conversations, 1) {
+		assert.Equal(t, conv.ID, policies.Conversations[0].ID)
+	}
+	assert.Nil(t, policies.LastPurge)
+
+	_, err = svc.SetConversationRetention(conv.ID.String(), 0)
+	assert.NoError(t, err)
+	policies, err = svc.GetRetentionPolicies()
+	assert.NoError(t, err)
+	assert.Empty(t, policies.Conversations)
+}
+
+func TestRetentionService_Purge(t *testing.T) {
+	msgRepo := memory.NewMessageRepository()
+	convs := memory.NewConversationRepository()
+	reactions := memory.NewReactionRepository()
+	attRepo := memory.NewAttachmentRepository()
+	blobs := memory.NewBlobStore()
+	index := memory.NewMessageSearchIndex()
+
+	messages := NewMessageService(msgRepo, WithConversations(convs), WithReactions(reactions),
+		WithAttachments(attRepo, blobs), WithSearchIndex(index))
+	uploads := NewAttachmentService(attRepo, msgRepo, convs, blobs)
+	retention := NewRetentionService(msgRepo, convs,
+		WithDefaultRetention(30*domain.Day),
+		WithRetentionReactions(reactions),
+		WithRetentionAttachments(attRepo, blobs),
+		WithRetentionSearchIndex(index))
+
+	short := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}, Retention: 7 * domain.Day}
+	long := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(short))
+	assert.NoError(t, convs.Create(long))
+
+	brief, err := messages.CreateMessage("alice", ports.MessageDraft{ConversationID: short.ID.String(), Content: "brief"})
+	assert.NoError(t, err)
+	assert.NoError(t, messages.AddReaction("bob", brief.ID.String(), "👍"))
+	file, err := uploads.Upload("alice", "notes.txt", strings.NewReader("minutes"))
+	assert.NoError(t, err)
+	root, err := messages.CreateMessage("alice", ports.MessageDraft{ConversationID: long.ID.String(), Content: "agenda", AttachmentIDs: []string{file.ID.String()}})
+	assert.NoError(t, err)
+	_, err = messages.CreateMessage("bob", ports.MessageDraft{ReplyToID: root.ID.String(), Content: "agreed"})
+	assert.NoError(t, err)
+	_, err = messages.CreateMessage("bob", ports.MessageDraft{ReceiverID: "alice", Content: "direct"})
+	assert.NoError(t, err)
+
+	// Ten days on, only the conversation with a one week policy is due.
+	start := time.Now()
+	retention.now = func() time.Time { return start.Add(10 * domain.Day) }
+
+	report, err := retention.Purge(true)
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Messages)
+	_, err = msgRepo.FindByID(brief.ID)
+	assert.NoError(t, err, "a dry run deletes nothing")
+
+	report, err = retention.Purge(false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Messages)
+	if assert.Len(t, report.Policies, 2) {
+		assert.Equal(t, short.ID, report.Policies[0].ConversationID)
+		assert.Equal(t, 7, report.Policies[0].RetentionDays)
+		assert.Equal(t, 1, report.Policies[0].Messages)
+		assert.Equal(t, uuid.Nil, report.Policies[1].ConversationID)
+		assert.Zero(t, report.Policies[1].Messages)
+	}
+	_, err = msgRepo.FindByID(brief.ID)
+	assert.Error(t, err)
+	left, err := reactions.FindByMessages([]uuid.UUID{brief.ID})
+	assert.NoError(t, err)
+	assert.Empty(t, left)
+
+	// A month on, everything else is past the server-wide policy,
+	// including the reply and the attachment's file.
+	retention.now = func() time.Time { return start.Add(31 * domain.Day) }
+
+	report, err = retention.Purge(true)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PurgeCounts{Messages: 3, Attachments: 1, Bytes: int64(len("minutes"))}, report.PurgeCounts)
+
+	report, err = retention.Purge(false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Messages)
+	assert.Equal(t, 1, report.Attachments)
+	_, err = blobs.Get(originalKey(file.ID))
+	assert.ErrorIs(t, err, ports.ErrBlobNotFound)
+	_, err = attRepo.FindByID(file.ID)
+	assert.Error(t, err)
+	hits, err := index.Search(ports.SearchQuery{Terms: domain.ParseSearchQuery("agenda"), ParticipantID: "alice",
+		ConversationIDs: []uuid.UUID{long.ID}, Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+
+	policies, err := retention.GetRetentionPolicies()
+	assert.NoError(t, err)
+	assert.Equal(t, report, policies.LastPurge)
+
+	report, err = retention.Purge(false)
+	assert.NoError(t, err)
+	assert.Zero(t, report.Messages)
+}
@@ -0,0 +1,120 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+// lastSeenResolution limits how often ValidateSession writes LastSeenAt,
+// since it runs on every authenticated request.
+const lastSeenResolution = time.Minute
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("session has been revoked")
+)
+
+// SessionService is the application‑layer implementation
+// of ports.SessionService.
+type SessionService struct {
+	repo ports.SessionRepository
+	now  func() time.Time
+}
+
+// NewSessionService constructs a SessionService.
+func NewSessionService(repo ports.SessionRepository) *SessionService {
+	return &SessionService{repo: repo, now: time.Now}
+}
+
+// ListSessions returns the sessions of userID that haven't been revoked.
+func (s *SessionService) ListSessions(userID string) ([]*domain.Session, error) {
+	all, err := s.repo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]*domain.Session, 0, len(all))
+	for _, sess := range all {
+		if sess.Active() {
+			active = append(active, sess)
+		}
+	}
+	return active, nil
+}
+
+// RevokeSession revokes one of userID's sessions. Sessions belonging to
+// someone else are reported as not found.
+func (s *SessionService) RevokeSession(userID, sessionID string) error {
+	sess, err := s.find(sessionID)
+	if err != nil {
+		return err
+	}
+	if sess.UserID != userID {
+		return ErrSessionNotFound
+	}
+	if !sess.Active() {
+		return nil
+	}
+	now := s.now()
+	sess.RevokedAt = &now
+	return s.repo.Update(sess)
+}
+
+// ValidateSession is used by the auth middleware: it rejects unknown and
+// revoked sessions and records activity on the rest.
+func (s *SessionService) ValidateSession(sessionID string) error {
+	sess, err := s.find(sessionID)
+	if err != nil {
+		return err
+	}
+	if !sess.Active() {
+		return ErrSessionRevoked
+	}
+	now := s.now()
+	if now.Sub(sess.LastSeenAt) < lastSeenResolution {
+		return nil
+	}
+	return s.repo.TouchSession(sess.ID, now)
+}
+
+func (s *SessionService) find(sessionID string) (*domain.Session, error) {
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSessionNotFound, err)
+	}
+	sess, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	return sess, nil
+}
+
+// revokeSessions ends every active session of userID. A nil repository
+// has nothing to revoke.
+func revokeSessions(sessions ports.SessionRepository, userID string, now time.Time) error {
+	if sessions == nil {
+		return nil
+	}
+	list, err := sessions.FindByUser(userID)
+	if err != nil {
+		return err
+	}
+	for _, sess := range list {
+		if !sess.Active() {
+			continue
+		}
+		sess.RevokedAt = &now
+		if err := sessions.Update(sess); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compile‑time check: ensure SessionService implements the interface
+var _ ports.SessionService = (*SessionService)(nil)
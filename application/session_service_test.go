@@ -0,0 +1,102 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+func TestSessionService_LoginCreatesSession(t *testing.T) {
+	sessions := memory.NewSessionRepository()
+	jwtManager := auth.NewJWTManager("secret", time.Hour)
+	users := NewUserService(memory.NewUserRepository(), jwtManager, sessions)
+	svc := NewSessionService(sessions)
+
+	assert.NoError(t, users.Register("alice", "pw"))
+	result, err := users.Login("alice", "pw", ports.ClientInfo{DeviceName: "laptop", UserAgent: "curl/8", IP: "10.0.0.1"})
+	assert.NoError(t, err)
+
+	claims, err := jwtManager.Verify(result.Token)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, claims.SessionID)
+
+	list, err := svc.ListSessions(claims.UserID)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, claims.SessionID, list[0].ID.String())
+	assert.Equal(t, "laptop", list[0].DeviceName)
+	assert.Equal(t, "curl/8", list[0].UserAgent)
+	assert.Equal(t, "10.0.0.1", list[0].IP)
+
+	assert.NoError(t, svc.ValidateSession(claims.SessionID))
+}
+
+func TestSessionService_RevokeSession(t *testing.T) {
+	sessions := memory.NewSessionRepository()
+	users := NewUserService(memory.NewUserRepository(), auth.NewJWTManager("secret", time.Hour), sessions)
+	svc := NewSessionService(sessions)
+
+	assert.NoError(t, users.Register("alice", "pw"))
+	_, err := users.Login("alice", "pw", ports.ClientInfo{DeviceName: "laptop"})
+	assert.NoError(t, err)
+	_, err = users.Login("alice", "pw", ports.ClientInfo{DeviceName: "phone"})
+	assert.NoError(t, err)
+
+	user, _ := users.repo.FindByUsername("alice")
+	list, err := svc.ListSessions(user.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	phone := list[1].ID.String()
+
+	// someone else can't revoke it
+	assert.ErrorIs(t, svc.RevokeSession("mallory", phone), ErrSessionNotFound)
+	assert.ErrorIs(t, svc.RevokeSession(user.ID.String(), "not-a-uuid"), ErrSessionNotFound)
+	assert.ErrorIs(t, svc.RevokeSession(user.ID.String(), uuid.NewString()), ErrSessionNotFound)
+
+	assert.NoError(t, svc.RevokeSession(user.ID.String(), phone))
+	// revoking twice is harmless
+	assert.NoError(t, svc.RevokeSession(user.ID.String(), phone))
+
+	assert.ErrorIs(t, svc.ValidateSession(phone), ErrSessionRevoked)
+
+	list, err = svc.ListSessions(user.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "laptop", list[0].DeviceName)
+}
+
+func TestSessionService_ValidateSessionTouchesLastSeen(t *testing.T) {
+	sessions := memory.NewSessionRepository()
+	users := NewUserService(memory.NewUserRepository(), auth.NewJWTManager("secret", time.Hour), sessions)
+	svc := NewSessionService(sessions)
+
+	start := time.Unix(1_700_000_000, 0)
+	users.now = func() time.Time { return start }
+	assert.NoError(t, users.Register("alice", "pw"))
+	_, err := users.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+
+	user, _ := users.repo.FindByUsername("alice")
+	list, _ := svc.ListSessions(user.ID.String())
+	id := list[0].ID.String()
+
+	// within the resolution nothing is written
+	svc.now = func() time.Time { return start.Add(10 * time.Second) }
+	assert.NoError(t, svc.ValidateSession(id))
+	list, _ = svc.ListSessions(user.ID.String())
+	assert.True(t, list[0].LastSeenAt.Equal(start))
+
+	later := start.Add(5 * time.Minute)
+	svc.now = func() time.Time { return later }
+	assert.NoError(t, svc.ValidateSession(id))
+	list, _ = svc.ListSessions(user.ID.String())
+	assert.True(t, list[0].LastSeenAt.Equal(later))
+
+	assert.ErrorIs(t, svc.ValidateSession(uuid.NewString()), ErrSessionNotFound)
+}
@@ -0,0 +1,141 @@
+package application
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+// DefaultTypingTTL is how long a typing indicator lasts without a
+// refresh.
+const DefaultTypingTTL = 6 * time.Second
+
+// TypingService is the application-layer implementation of
+// ports.TypingService.
+type TypingService struct {
+	conversations ports.ConversationRepository
+	store         ports.TypingStore
+	notifier      ports.NotificationService
+	ttl           time.Duration
+	now           func() time.Time
+}
+
+// TypingOption configures optional TypingService settings.
+type TypingOption func(*TypingService)
+
+// WithTypingNotifier pushes typing started and stopped events to the
+// other participants. Refreshes aren't pushed.
+func WithTypingNotifier(notifier ports.NotificationService) TypingOption {
+	return func(s *TypingService) { s.notifier = notifier }
+}
+
+// WithTypingTTL overrides DefaultTypingTTL.
+func WithTypingTTL(d time.Duration) TypingOption {
+	return func(s *TypingService) { s.ttl = d }
+}
+
+func NewTypingService(conversations ports.ConversationRepository, store ports.TypingStore, opts ...TypingOption) *TypingService {
+	s := &TypingService{
+		conversations: conversations,
+		store:         store,
+		ttl:           DefaultTypingTTL,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *TypingService) StartTyping(userID, conversationID string) (*domain.Typer, error) {
+	conv, err := s.conversationFor(userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	typer := &domain.Typer{UserID: userID, ExpiresAt: now.Add(s.ttl)}
+	started, err := s.store.Touch(conv.ID, userID, now, typer.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if started {
+		s.notify(conv, userID, domain.NotificationTyping)
+	}
+	return typer, nil
+}
+
+func (s *TypingService) StopTyping(userID, conversationID string) error {
+	conv, err := s.conversationFor(userID, conversationID)
+	if err != nil {
+		return err
+	}
+	stopped, err := s.store.Clear(conv.ID, userID, s.now())
+	if err != nil {
+		return err
+	}
+	if stopped {
+		s.notify(conv, userID, domain.NotificationTypingStopped)
+	}
+	return nil
+}
+
+func (s *TypingService) GetTypers(userID, conversationID string) ([]domain.Typer, error) {
+	conv, err := s.conversationFor(userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	typers, err := s.store.Typers(conv.ID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	others := make([]domain.Typer, 0, len(typers))
+	for _, t := range typers {
+		if t.UserID != userID {
+			others = append(others, t)
+		}
+	}
+	return others, nil
+}
+
+// conversationFor loads a conversation userID participates in.
+func (s *TypingService) conversationFor(userID, conversationID string) (*domain.Conversation, error) {
+	id, err := uuid.Parse(conversationID)
+	if err != nil {
+		return nil, ErrConversationNotFound
+	}
+	conv, err := s.conversations.FindByID(id)
+	if err != nil || !contains(conv.ParticipantIDs, userID) {
+		return nil, ErrConversationNotFound
+	}
+	return conv, nil
+}
+
+// notify tells the other participants that userID started or stopped
+// typing. These events are ephemeral, so failures are only logged.
+func (s *TypingService) notify(conv *domain.Conversation, userID string, typ domain.NotificationType) {
+	if s.notifier == nil {
+		return
+	}
+	for _, participant := range conv.ParticipantIDs {
+		if participant == userID {
+			continue
+		}
+		err := s.notifier.Notify(&domain.Notification{
+			Type:           typ,
+			Priority:       domain.PriorityLow,
+			UserID:         participant,
+			ActorID:        userID,
+			ConversationID: conv.ID,
+			CreatedAt:      s.now(),
+		})
+		if err != nil {
+			log.Printf("notify %s of %s: %v", participant, typ, err)
+		}
+	}
+}
+
+var _ ports.TypingService = (*TypingService)(nil)
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/adapters/mocks"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+)
+
+func TestTypingService(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	store := memory.NewTypingStore()
+	notifier := mocks.NewMockNotificationService(t)
+	svc := NewTypingService(convs, store, WithTypingNotifier(notifier), WithTypingTTL(5*time.Second))
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob", "carol"}}
+	assert.NoError(t, convs.Create(conv))
+
+	_, err := svc.StartTyping("mallory", conv.ID.String())
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+
+	for _, user := range []string{"bob", "carol"} {
+		notifier.On("Notify", mock.MatchedBy(func(n *domain.Notification) bool {
+			return n.UserID == user && n.Type == domain.NotificationTyping && n.ActorID == "alice" &&
+				n.Priority == domain.PriorityLow
+		})).Return(nil).Once()
+	}
+	typer, err := svc.StartTyping("alice", conv.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(5*time.Second), typer.ExpiresAt)
+
+	// Refreshing only extends the indicator.
+	now = now.Add(3 * time.Second)
+	_, err = svc.StartTyping("alice", conv.ID.String())
+	assert.NoError(t, err)
+
+	typers, err := svc.GetTypers("bob", conv.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Typer{{UserID: "alice", ExpiresAt: now.Add(5 * time.Second)}}, typers)
+	typers, err = svc.GetTypers("alice", conv.ID.String())
+	assert.NoError(t, err)
+	assert.Empty(t, typers, "callers don't see themselves")
+
+	now = now.Add(6 * time.Second)
+	typers, err = svc.GetTypers("bob", conv.ID.String())
+	assert.NoError(t, err)
+	assert.Empty(t, typers, "the indicator lapsed")
+	assert.NoError(t, svc.StopTyping("alice", conv.ID.String()), "stopping after a lapse tells no one")
+}
+
+func TestMessageService_ClearsTyping(t *testing.T) {
+	convs := memory.NewConversationRepository()
+	store := memory.NewTypingStore()
+	svc := NewMessageService(memory.NewMessageRepository(), WithConversations(convs), WithTypingStore(store))
+
+	conv := &domain.Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}}
+	assert.NoError(t, convs.Create(conv))
+	_, err := store.Touch(conv.ID, "alice", time.Now(), time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+
+	_, err = svc.CreateMessage("alice", ports.MessageDraft{ConversationID: conv.ID.String(), Content: "done"})
+	assert.NoError(t, err)
+	typers, err := store.Typers(conv.ID, time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, typers)
+}
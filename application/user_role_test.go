@@ -0,0 +1,56 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+func TestUserService_RolesInTokens(t *testing.T) {
+	repo := memory.NewUserRepository()
+	sessions := memory.NewSessionRepository()
+	jwtManager := auth.NewJWTManager("secret", time.Hour)
+	svc := NewUserService(repo, jwtManager, sessions, WithAdminUsernames("root"))
+
+	assert.NoError(t, svc.Register("root", "pw"))
+	assert.NoError(t, svc.Register("alice", "pw"))
+
+	root, _ := repo.FindByUsername("root")
+	alice, _ := repo.FindByUsername("alice")
+	assert.Equal(t, domain.RoleAdmin, root.Role)
+	assert.Equal(t, domain.RoleUser, alice.Role)
+
+	result, err := svc.Login("root", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+	claims, err := jwtManager.Verify(result.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoleAdmin, claims.Role)
+
+	_, err = svc.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, svc.SetRole(alice.ID.String(), "superuser"), ErrInvalidRole)
+	assert.ErrorIs(t, svc.SetRole(uuid.NewString(), domain.RoleModerator), ErrUserNotFound)
+	assert.ErrorIs(t, svc.SetRole("not-a-uuid", domain.RoleModerator), ErrUserNotFound)
+	assert.NoError(t, svc.SetRole(alice.ID.String(), domain.RoleModerator))
+
+	// The token issued before the change no longer works.
+	issued, err := sessions.FindByUser(alice.ID.String())
+	assert.NoError(t, err)
+	if assert.Len(t, issued, 1) {
+		assert.False(t, issued[0].Active())
+	}
+
+	result, err = svc.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+	claims, err = jwtManager.Verify(result.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoleModerator, claims.Role)
+}
@@ -2,6 +2,9 @@ package application
 
 import (
 	"errors"
+	"fmt"
+	"net/mail"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -15,24 +18,74 @@ var (
 	ErrUsernameRequired   = errors.New("username cannot be empty")
 	ErrPasswordRequired   = errors.New("password cannot be empty")
 	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidRole        = errors.New("unknown role")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidEmail       = errors.New("invalid email address")
+
+	ErrAccountDisabled       = errors.New("account is disabled")
+	ErrPasswordResetRequired = errors.New("password must be changed before logging in")
 )
 
 type TokenGenerator interface {
-	Generate(username, userID string) (string, error)
+	Generate(username, userID, sessionID string, role domain.Role) (string, error)
+	GenerateChallenge(userID string) (string, error)
+	VerifyChallenge(challenge string) (string, error)
 }
 
 type UserServiceInterface interface {
 	Register(username, password string) error
-	Login(username, password string) (string, error)
+	Login(username, password string, client ports.ClientInfo) (*ports.LoginResult, error)
 }
 
 type UserService struct {
 	repo     ports.UserRepository
 	tokenGen TokenGenerator
+	sessions ports.SessionRepository
+	blocks   ports.BlockRepository
+	admins   map[string]bool
+	attempts *attemptLimiter
+	now      func() time.Time
+}
+
+// UserServiceOption configures optional UserService behaviour.
+type UserServiceOption func(*UserService)
+
+// WithAdminUsernames makes Register give the admin role to these
+// usernames, which is how a fresh deployment gets its first admin.
+func WithAdminUsernames(usernames ...string) UserServiceOption {
+	return func(s *UserService) {
+		for _, u := range usernames {
+			s.admins[u] = true
+		}
+	}
 }
 
-func NewUserService(r ports.UserRepository, t TokenGenerator) *UserService {
-	return &UserService{repo: r, tokenGen: t}
+// WithUserBlocks hides the users someone blocked from their directory
+// searches.
+func WithUserBlocks(blocks ports.BlockRepository) UserServiceOption {
+	return func(s *UserService) { s.blocks = blocks }
+}
+
+// WithLoginAttempts sets how many wrong passwords an account takes within
+// window before Login and ChangePassword refuse to check more. Wrong
+// two-factor codes are counted separately against the same limit.
+func WithLoginAttempts(max int, window time.Duration) UserServiceOption {
+	return func(s *UserService) { s.attempts = newAttemptLimiter(max, window) }
+}
+
+func NewUserService(r ports.UserRepository, t TokenGenerator, sessions ports.SessionRepository, opts ...UserServiceOption) *UserService {
+	s := &UserService{
+		repo:     r,
+		tokenGen: t,
+		sessions: sessions,
+		admins:   map[string]bool{},
+		attempts: newAttemptLimiter(DefaultMaxLoginAttempts, DefaultLoginAttemptWindow),
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *UserService) Register(username, password string) error {
@@ -53,24 +106,181 @@ func (s *UserService) Register(username, password string) error {
 		return err
 	}
 
+	role := domain.RoleUser
+	if s.admins[username] {
+		role = domain.RoleAdmin
+	}
+
 	user := &domain.User{
 		ID:           uuid.New(),
 		Username:     username,
 		PasswordHash: string(hashedPassword),
+		Role:         role,
 	}
 
 	return s.repo.Create(user)
 }
 
-func (s *UserService) Login(username, password string) (string, error) {
-	user, err := s.repo.FindByUsername(username)
+// Login checks the password. Users with two-factor authentication get a
+// challenge back instead of a token.
+func (s *UserService) Login(username, password string, client ports.ClientInfo) (*ports.LoginResult, error) {
+	user, err := s.checkPassword(username, password)
 	if err != nil {
-		return "", ErrInvalidCredentials
+		return nil, err
+	}
+
+	// Only reveal account state to someone who knows the password.
+	if user.Disabled {
+		return nil, ErrAccountDisabled
+	}
+	if user.PasswordResetRequired {
+		return nil, ErrPasswordResetRequired
 	}
 
+	if user.TOTPEnabled {
+		challenge, err := s.tokenGen.GenerateChallenge(user.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		return &ports.LoginResult{Challenge: challenge}, nil
+	}
+
+	token, err := s.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &ports.LoginResult{Token: token}, nil
+}
+
+// startSession records a new session for user and issues a token bound
+// to it.
+func (s *UserService) startSession(user *domain.User, client ports.ClientInfo) (string, error) {
+	now := s.now()
+	session := &domain.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID.String(),
+		DeviceName: client.DeviceName,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := s.sessions.Create(session); err != nil {
+		return "", err
+	}
+	return s.tokenGen.Generate(user.Username, user.ID.String(), session.ID.String(), user.Role)
+}
+
+// checkPassword returns the user username names if password is theirs.
+// Failures count towards the account's attempt limit.
+func (s *UserService) checkPassword(username, password string) (*domain.User, error) {
+	now := s.now()
+	if !s.attempts.allow(username, now) {
+		return nil, ErrTooManyAttempts
+	}
+	user, err := s.repo.FindByUsername(username)
+	if err != nil || user.IsBot {
+		s.attempts.fail(username, now)
+		return nil, ErrInvalidCredentials
+	}
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return "", ErrInvalidCredentials
+		s.attempts.fail(username, now)
+		return nil, ErrInvalidCredentials
 	}
+	s.attempts.reset(username)
+	return user, nil
+}
 
-	return s.tokenGen.Generate(user.Username, user.ID.String())
+// ChangePassword replaces the password of username after checking the
+// current one and, if two-factor authentication is on, a code. It also
+// satisfies a forced password reset. Every session of the user is revoked,
+// so whoever knew the old password is logged out.
+func (s *UserService) ChangePassword(username, oldPassword, newPassword, code string) error {
+	if newPassword == "" {
+		return ErrPasswordRequired
+	}
+	user, err := s.checkPassword(username, oldPassword)
+	if err != nil {
+		return err
+	}
+	if user.Disabled {
+		return ErrAccountDisabled
+	}
+	if user.TOTPEnabled {
+		if err := s.checkSecondFactor(user, code); err != nil {
+			return err
+		}
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = string(hashedPassword)
+	user.PasswordResetRequired = false
+	if err := s.repo.Update(user); err != nil {
+		return err
+	}
+	return revokeSessions(s.sessions, user.ID.String(), s.now())
+}
+
+// SetRole changes a user's role. Tokens carry the role they were issued
+// with, so a change logs the user out everywhere and the new role
+// applies from their next login.
+func (s *UserService) SetRole(userID string, role domain.Role) error {
+	if !role.Valid() {
+		return ErrInvalidRole
+	}
+	user, err := s.findByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if user.Role == role {
+		return nil
+	}
+	user.Role = role
+	if err := s.repo.Update(user); err != nil {
+		return err
+	}
+	return revokeSessions(s.sessions, userID, s.now())
+}
+
+// SetEmail sets the address userID gets email notifications at. An
+// empty email removes it.
+func (s *UserService) SetEmail(userID, email string) error {
+	if email != "" {
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Address != email {
+			return ErrInvalidEmail
+		}
+	}
+	user, err := s.findByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	user.Email = email
+	return s.repo.Update(user)
 }
+
+// SearchUsers searches the user directory by username on behalf of
+// viewerID.
+func (s *UserService) SearchUsers(viewerID, query string, limit, offset int) ([]*domain.User, error) {
+	var exclude []string
+	if s.blocks != nil {
+		var err error
+		if exclude, err = blockedIDs(s.blocks, viewerID); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Search(query, exclude, limit, offset)
+}
+
+func (s *UserService) findByID(userID string) (*domain.User, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	return s.repo.FindByID(id)
+}
+
+var _ ports.UserService = (*UserService)(nil)
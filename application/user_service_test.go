@@ -10,6 +10,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
 	"github.com/chrikar/chatheon/domain"
 	"github.com/chrikar/chatheon/internal/auth"
 )
@@ -28,6 +30,27 @@ func (m *mockUserRepo) FindByUsername(username string) (*domain.User, error) {
 func (m *mockUserRepo) Create(user *domain.User) error {
 	return m.Called(user).Error(0)
 }
+func (m *mockUserRepo) FindByID(id uuid.UUID) (*domain.User, error) {
+	args := m.Called(id)
+	u := args.Get(0)
+	if u == nil {
+		return nil, args.Error(1)
+	}
+	return u.(*domain.User), args.Error(1)
+}
+func (m *mockUserRepo) Update(user *domain.User) error {
+	return m.Called(user).Error(0)
+}
+func (m *mockUserRepo) UseTOTPCounter(id uuid.UUID, counter int64) error {
+	return m.Called(id, counter).Error(0)
+}
+func (m *mockUserRepo) UseRecoveryCode(id uuid.UUID, hash string) error {
+	return m.Called(id, hash).Error(0)
+}
+func (m *mockUserRepo) Search(query string, exclude []string, limit, offset int) ([]*domain.User, error) {
+	args := m.Called(query, exclude, limit, offset)
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
 
 func TestUserService_Register(t *testing.T) {
 	type scenario struct {
@@ -91,7 +114,7 @@ func TestUserService_Register(t *testing.T) {
 		t.Run(sc.name, func(t *testing.T) {
 			repo := new(mockUserRepo)
 			mgr := auth.NewJWTManager("secret", time.Hour)
-			svc := NewUserService(repo, mgr)
+			svc := NewUserService(repo, mgr, memory.NewSessionRepository())
 
 			// arrange
 			sc.setupStubs(repo)
@@ -109,3 +132,47 @@ func TestUserService_Register(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_LimitsPasswordAttempts(t *testing.T) {
+	repo := memory.NewUserRepository()
+	svc := NewUserService(repo, auth.NewJWTManager("secret", time.Hour), memory.NewSessionRepository(),
+		WithLoginAttempts(3, time.Minute))
+	now := time.Unix(1_700_000_000, 0)
+	svc.now = func() time.Time { return now }
+	assert.NoError(t, svc.Register("alice", "pw"))
+
+	// Wrong guesses through either door count against the same limit.
+	_, err := svc.Login("alice", "a", ports.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.ErrorIs(t, svc.ChangePassword("alice", "b", "new", ""), ErrInvalidCredentials)
+	_, err = svc.Login("alice", "c", ports.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = svc.Login("alice", "pw", ports.ClientInfo{})
+	assert.ErrorIs(t, err, ErrTooManyAttempts)
+	assert.ErrorIs(t, svc.ChangePassword("alice", "pw", "new", ""), ErrTooManyAttempts)
+
+	now = now.Add(time.Minute)
+	_, err = svc.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+}
+
+func TestUserService_SetEmail(t *testing.T) {
+	repo := memory.NewUserRepository()
+	svc := NewUserService(repo, nil, nil)
+	alice := &domain.User{ID: uuid.New(), Username: "alice"}
+	assert.NoError(t, repo.Create(alice))
+
+	for _, email := range []string{"alice", "Alice <alice@example.com>", "alice@example.com\r\nBcc: x@example.com"} {
+		assert.ErrorIs(t, svc.SetEmail(alice.ID.String(), email), ErrInvalidEmail, email)
+	}
+	assert.ErrorIs(t, svc.SetEmail(uuid.NewString(), "a@example.com"), ErrUserNotFound)
+
+	assert.NoError(t, svc.SetEmail(alice.ID.String(), "alice@example.com"))
+	stored, _ := repo.FindByID(alice.ID)
+	assert.Equal(t, "alice@example.com", stored.Email)
+
+	assert.NoError(t, svc.SetEmail(alice.ID.String(), ""))
+	stored, _ = repo.FindByID(alice.ID)
+	assert.Empty(t, stored.Email)
+}
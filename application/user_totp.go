@@ -0,0 +1,187 @@
+package application
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/domain"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+const (
+	totpIssuer        = "Chatheon"
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTOTPNotEnrolled    = errors.New("two-factor authentication is not enrolled")
+	ErrTOTPNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTOTPCode    = errors.New("invalid two-factor code")
+	ErrInvalidChallenge   = errors.New("invalid or expired login challenge")
+)
+
+// EnrollTOTP generates a fresh secret for userID. Calling it again before
+// confirming simply replaces the pending secret.
+func (s *UserService) EnrollTOTP(userID string) (*ports.TOTPEnrollment, error) {
+	user, err := s.findByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPSecret = secret
+	user.TOTPLastCounter = 0
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return &ports.TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(totpIssuer, user.Username, secret),
+	}, nil
+}
+
+// ConfirmTOTP turns two-factor authentication on once the user proves
+// their authenticator produces valid codes.
+func (s *UserService) ConfirmTOTP(userID, code string) ([]string, error) {
+	user, err := s.findByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	counter, ok := auth.ValidateTOTP(user.TOTPSecret, code, s.now())
+	if !ok {
+		return nil, ErrInvalidTOTPCode
+	}
+
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, c := range codes {
+		hashes[i] = auth.HashRecoveryCode(c)
+	}
+
+	user.TOTPEnabled = true
+	user.TOTPLastCounter = counter
+	user.RecoveryCodeHashes = hashes
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableTOTP turns two-factor authentication off. It requires a current
+// code (or a recovery code) so a stolen access token alone isn't enough.
+func (s *UserService) DisableTOTP(userID, code string) error {
+	user, err := s.findByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return ErrTOTPNotEnabled
+	}
+	if err := s.checkSecondFactor(user, code); err != nil {
+		return err
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastCounter = 0
+	user.RecoveryCodeHashes = nil
+	return s.repo.Update(user)
+}
+
+// CompleteTOTPLogin redeems a challenge from Login for an access token.
+// Once the account has had too many wrong codes, every challenge it holds
+// is refused until the attempt window passes.
+func (s *UserService) CompleteTOTPLogin(challenge, code string, client ports.ClientInfo) (string, error) {
+	userID, err := s.tokenGen.VerifyChallenge(challenge)
+	if err != nil {
+		return "", ErrInvalidChallenge
+	}
+	user, err := s.findByID(userID)
+	if err != nil || !user.TOTPEnabled {
+		return "", ErrInvalidChallenge
+	}
+	if user.Disabled {
+		return "", ErrAccountDisabled
+	}
+	if user.PasswordResetRequired {
+		return "", ErrPasswordResetRequired
+	}
+	if err := s.checkSecondFactor(user, code); err != nil {
+		return "", err
+	}
+
+	return s.startSession(user, client)
+}
+
+// checkSecondFactor accepts either a TOTP code that hasn't been used yet
+// or an unused recovery code, and persists whichever was consumed. Wrong
+// codes count towards the account's attempt limit; a correct password
+// doesn't clear them, so knowing it doesn't help guess the code.
+func (s *UserService) checkSecondFactor(user *domain.User, code string) error {
+	key := secondFactorKey(user)
+	now := s.now()
+	if !s.attempts.allow(key, now) {
+		return ErrTooManyAttempts
+	}
+	if err := s.useSecondFactor(user, code, now); err != nil {
+		if errors.Is(err, ErrInvalidTOTPCode) {
+			s.attempts.fail(key, now)
+		}
+		return err
+	}
+	s.attempts.reset(key)
+	return nil
+}
+
+// useSecondFactor consumes code in the repository, which refuses a code
+// that a concurrent request has just used, and mirrors that on user so a
+// later Update doesn't bring the code back.
+func (s *UserService) useSecondFactor(user *domain.User, code string, now time.Time) error {
+	if counter, ok := auth.ValidateTOTP(user.TOTPSecret, code, now); ok {
+		if err := s.repo.UseTOTPCounter(user.ID, counter); err != nil {
+			return codeError(err)
+		}
+		user.TOTPLastCounter = counter
+		return nil
+	}
+
+	hash := auth.HashRecoveryCode(code)
+	if err := s.repo.UseRecoveryCode(user.ID, hash); err != nil {
+		return codeError(err)
+	}
+	user.RecoveryCodeHashes = slices.DeleteFunc(user.RecoveryCodeHashes, func(h string) bool { return h == hash })
+	return nil
+}
+
+func codeError(err error) error {
+	if errors.Is(err, ports.ErrCodeUsed) {
+		return ErrInvalidTOTPCode
+	}
+	return err
+}
+
+// secondFactorKey keeps an account's wrong codes apart from its wrong
+// passwords in the attempt limiter.
+func secondFactorKey(user *domain.User) string {
+	return "2fa:" + user.ID.String()
+}
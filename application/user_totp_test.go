@@ -0,0 +1,194 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/application/ports"
+	"github.com/chrikar/chatheon/internal/auth"
+)
+
+func newTOTPTestService(t *testing.T) (*UserService, *memory.UserRepository, string) {
+	t.Helper()
+	repo := memory.NewUserRepository()
+	svc := NewUserService(repo, auth.NewJWTManager("secret", time.Hour), memory.NewSessionRepository())
+	assert.NoError(t, svc.Register("alice", "pw"))
+	user, err := repo.FindByUsername("alice")
+	assert.NoError(t, err)
+	return svc, repo, user.ID.String()
+}
+
+func TestUserService_TOTPEnrollmentAndLogin(t *testing.T) {
+	svc, repo, userID := newTOTPTestService(t)
+	now := time.Unix(1_700_000_000, 0)
+	svc.now = func() time.Time { return now }
+
+	enrollment, err := svc.EnrollTOTP(userID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, enrollment.Secret)
+	assert.Contains(t, enrollment.ProvisioningURI, "otpauth://totp/Chatheon:alice")
+
+	// not enforced until confirmed
+	result, err := svc.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Token)
+
+	// wrong code is rejected
+	_, err = svc.ConfirmTOTP(userID, "000000")
+	assert.ErrorIs(t, err, ErrInvalidTOTPCode)
+
+	code, err := auth.TOTPCode(enrollment.Secret, now)
+	assert.NoError(t, err)
+	recovery, err := svc.ConfirmTOTP(userID, code)
+	assert.NoError(t, err)
+	assert.Len(t, recovery, recoveryCodeCount)
+
+	user, _ := repo.FindByUsername("alice")
+	assert.True(t, user.TOTPEnabled)
+	assert.NotContains(t, user.RecoveryCodeHashes, recovery[0], "recovery codes must be stored hashed")
+
+	_, err = svc.EnrollTOTP(userID)
+	assert.ErrorIs(t, err, ErrTOTPAlreadyEnabled)
+
+	// password alone now yields a challenge
+	result, err = svc.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Token)
+	assert.NotEmpty(t, result.Challenge)
+
+	// the code used for confirmation can't be replayed
+	_, err = svc.CompleteTOTPLogin(result.Challenge, code, ports.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidTOTPCode)
+
+	now = now.Add(30 * time.Second)
+	next, err := auth.TOTPCode(enrollment.Secret, now)
+	assert.NoError(t, err)
+	token, err := svc.CompleteTOTPLogin(result.Challenge, next, ports.ClientInfo{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+
+	_, err = svc.CompleteTOTPLogin("garbage", next, ports.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidChallenge)
+}
+
+func TestUserService_TOTPRecoveryCodeIsSingleUse(t *testing.T) {
+	svc, _, userID := newTOTPTestService(t)
+	now := time.Unix(1_700_000_000, 0)
+	svc.now = func() time.Time { return now }
+
+	enrollment, err := svc.EnrollTOTP(userID)
+	assert.NoError(t, err)
+	code, _ := auth.TOTPCode(enrollment.Secret, now)
+	recovery, err := svc.ConfirmTOTP(userID, code)
+	assert.NoError(t, err)
+
+	result, err := svc.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+
+	token, err := svc.CompleteTOTPLogin(result.Challenge, recovery[3], ports.ClientInfo{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+
+	_, err = svc.CompleteTOTPLogin(result.Challenge, recovery[3], ports.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidTOTPCode)
+}
+
+func TestUserService_TOTPGuessesAreLimited(t *testing.T) {
+	svc, _, userID := newTOTPTestService(t)
+	now := time.Unix(1_700_000_000, 0)
+	svc.now = func() time.Time { return now }
+
+	enrollment, err := svc.EnrollTOTP(userID)
+	assert.NoError(t, err)
+	code, _ := auth.TOTPCode(enrollment.Secret, now)
+	_, err = svc.ConfirmTOTP(userID, code)
+	assert.NoError(t, err)
+
+	for range DefaultMaxLoginAttempts {
+		// Logging in again with the right password doesn't reset the count.
+		result, err := svc.Login("alice", "pw", ports.ClientInfo{})
+		assert.NoError(t, err)
+		_, err = svc.CompleteTOTPLogin(result.Challenge, "000000", ports.ClientInfo{})
+		assert.ErrorIs(t, err, ErrInvalidTOTPCode)
+	}
+
+	now = now.Add(30 * time.Second)
+	code, _ = auth.TOTPCode(enrollment.Secret, now)
+	result, err := svc.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+	_, err = svc.CompleteTOTPLogin(result.Challenge, code, ports.ClientInfo{})
+	assert.ErrorIs(t, err, ErrTooManyAttempts, "even the right code is refused")
+
+	now = now.Add(DefaultLoginAttemptWindow)
+	code, _ = auth.TOTPCode(enrollment.Secret, now)
+	result, err = svc.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+	token, err := svc.CompleteTOTPLogin(result.Challenge, code, ports.ClientInfo{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+}
+
+func TestUserService_DisableTOTP(t *testing.T) {
+	svc, repo, userID := newTOTPTestService(t)
+	now := time.Unix(1_700_000_000, 0)
+	svc.now = func() time.Time { return now }
+
+	assert.ErrorIs(t, svc.DisableTOTP(userID, "123456"), ErrTOTPNotEnabled)
+
+	_, err := svc.ConfirmTOTP(userID, "123456")
+	assert.ErrorIs(t, err, ErrTOTPNotEnrolled)
+
+	enrollment, err := svc.EnrollTOTP(userID)
+	assert.NoError(t, err)
+	code, _ := auth.TOTPCode(enrollment.Secret, now)
+	_, err = svc.ConfirmTOTP(userID, code)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, svc.DisableTOTP(userID, "000000"), ErrInvalidTOTPCode)
+
+	now = now.Add(30 * time.Second)
+	code, _ = auth.TOTPCode(enrollment.Secret, now)
+	assert.NoError(t, svc.DisableTOTP(userID, code))
+
+	user, _ := repo.FindByUsername("alice")
+	assert.False(t, user.TOTPEnabled)
+	assert.Empty(t, user.TOTPSecret)
+	assert.Empty(t, user.RecoveryCodeHashes)
+
+	result, err := svc.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Token)
+}
+
+func TestUserService_ChangePasswordNeedsSecondFactor(t *testing.T) {
+	svc, _, userID := newTOTPTestService(t)
+	now := time.Unix(1_700_000_000, 0)
+	svc.now = func() time.Time { return now }
+
+	// A session from before the change.
+	result, err := svc.Login("alice", "pw", ports.ClientInfo{})
+	assert.NoError(t, err)
+	claims, err := auth.NewJWTManager("secret", time.Hour).Verify(result.Token)
+	assert.NoError(t, err)
+
+	enrollment, err := svc.EnrollTOTP(userID)
+	assert.NoError(t, err)
+	code, _ := auth.TOTPCode(enrollment.Secret, now)
+	_, err = svc.ConfirmTOTP(userID, code)
+	assert.NoError(t, err)
+
+	// The password alone isn't enough.
+	assert.ErrorIs(t, svc.ChangePassword("alice", "pw", "new", ""), ErrInvalidTOTPCode)
+
+	now = now.Add(30 * time.Second)
+	code, _ = auth.TOTPCode(enrollment.Secret, now)
+	assert.NoError(t, svc.ChangePassword("alice", "pw", "new", code))
+
+	session, err := svc.sessions.FindByID(uuid.MustParse(claims.SessionID))
+	assert.NoError(t, err)
+	assert.False(t, session.Active(), "sessions end when the password changes")
+}
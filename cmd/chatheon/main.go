@@ -1,58 +1,329 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
+	// Do-not-disturb hours are read in the users' time zones, which the
+	// runtime image doesn't ship.
+	_ "time/tzdata"
 
 	"github.com/gorilla/mux"
 
+	"github.com/chrikar/chatheon/adapters/blob"
 	handler "github.com/chrikar/chatheon/adapters/http"
 	"github.com/chrikar/chatheon/adapters/memory"
+	"github.com/chrikar/chatheon/adapters/notification"
 	"github.com/chrikar/chatheon/application"
+	"github.com/chrikar/chatheon/domain"
 	"github.com/chrikar/chatheon/internal/auth"
+	"github.com/chrikar/chatheon/internal/config"
+	"github.com/chrikar/chatheon/internal/worker"
 )
 
 func main() {
 	fmt.Printf("Chatheon server started at %s\n", time.Now().Format(time.RFC1123))
 
-	messageRepo := memory.NewMessageRepository()
-	messageService := application.NewMessageService(messageRepo)
-	messageHandler := handler.NewMessageHandler(messageService)
-
+	cfg := config.Load()
 	jwtManager := auth.NewJWTManager("your-secret-key", time.Hour)
 
 	// Repositories
+	messageRepo := memory.NewMessageRepository()
+	reactionRepo := memory.NewReactionRepository()
+	attachmentRepo := memory.NewAttachmentRepository()
+	searchIndex := memory.NewMessageSearchIndex()
+	scheduledRepo := memory.NewScheduledMessageRepository()
+	pinRepo := memory.NewPinRepository()
+	typingStore := memory.NewTypingStore()
+	presenceStore := memory.NewPresenceStore()
+	blockRepo := memory.NewBlockRepository()
+	contactRepo := memory.NewContactRepository()
+	notificationPrefRepo := memory.NewNotificationPreferenceRepository()
 	userRepo := memory.NewUserRepository()
 	convRepo := memory.NewConversationRepository()
+	sessionRepo := memory.NewSessionRepository()
+	apiKeyRepo := memory.NewAPIKeyRepository()
+	blobStore, err := blob.NewLocalStore(cfg.BlobDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pinRole := domain.Role(cfg.PinRole)
+	if !pinRole.Valid() {
+		log.Fatalf("PIN_ROLE: unknown role %q", cfg.PinRole)
+	}
+
+	if len(cfg.WebhookURLs) > 0 && cfg.WebhookSecret == "" {
+		log.Fatal("WEBHOOK_SECRET must be set when WEBHOOK_URLS is")
+	}
+	var webhookEndpoints []notification.WebhookEndpoint
+	for _, url := range cfg.WebhookURLs {
+		webhookEndpoints = append(webhookEndpoints, notification.WebhookEndpoint{URL: url, Secret: cfg.WebhookSecret})
+	}
+	webhookNotifier := notification.NewWebhookNotifier(webhookEndpoints,
+		notification.WithWebhookClient(&http.Client{Timeout: cfg.WebhookTimeout}))
+	notifiers := notification.MultiNotifier{notification.NewConsoleNotifier(), webhookNotifier}
+
+	var emailNotifier *notification.EmailNotifier
+	if cfg.SMTPAddr != "" {
+		sender, err := notification.NewSMTPSender(notification.SMTPConfig{
+			Addr:           cfg.SMTPAddr,
+			Username:       cfg.SMTPUsername,
+			Password:       cfg.SMTPPassword,
+			From:           cfg.SMTPFrom,
+			AllowPlaintext: cfg.SMTPAllowPlaintext,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		emailNotifier = notification.NewEmailNotifier(sender, userRepo,
+			notification.WithEmailMessages(messageRepo))
+		notifiers = append(notifiers, emailNotifier)
+	}
 
 	// Services
-	userService := application.NewUserService(userRepo, jwtManager)
-	convService := application.NewConversationService(convRepo)
+	// Notifications only go out if their recipient's preferences allow.
+	notifier := application.NewNotificationPreferenceService(notificationPrefRepo, convRepo, notifiers)
+	messageService := application.NewMessageService(messageRepo,
+		application.WithConversations(convRepo),
+		application.WithReactions(reactionRepo),
+		application.WithAttachments(attachmentRepo, blobStore),
+		application.WithSearchIndex(searchIndex),
+		application.WithScheduledMessages(scheduledRepo),
+		application.WithPins(pinRepo),
+		application.WithPinRole(pinRole),
+		application.WithTypingStore(typingStore),
+		application.WithUsers(userRepo),
+		application.WithBlocks(blockRepo),
+		application.WithContacts(contactRepo),
+		application.WithNotifier(notifier),
+		application.WithEditWindow(cfg.MessageEditWindow),
+		application.WithDeleteWindow(cfg.MessageDeleteWindow))
+	userService := application.NewUserService(userRepo, jwtManager, sessionRepo,
+		application.WithAdminUsernames(cfg.AdminUsernames...),
+		application.WithUserBlocks(blockRepo))
+	convService := application.NewConversationService(convRepo,
+		application.WithConversationBlocks(blockRepo))
+	sessionService := application.NewSessionService(sessionRepo)
+	apiKeyService := application.NewAPIKeyService(userRepo, apiKeyRepo)
+	adminService := application.NewAdminService(userRepo, sessionRepo, messageRepo, convRepo,
+		application.WithAdminReactions(reactionRepo),
+		application.WithAdminAttachments(attachmentRepo, blobStore),
+		application.WithAdminSearchIndex(searchIndex),
+		application.WithAdminPins(pinRepo))
+	retentionService := application.NewRetentionService(messageRepo, convRepo,
+		application.WithDefaultRetention(cfg.MessageRetention),
+		application.WithRetentionReactions(reactionRepo),
+		application.WithRetentionAttachments(attachmentRepo, blobStore),
+		application.WithRetentionSearchIndex(searchIndex),
+		application.WithRetentionPins(pinRepo))
+	typingService := application.NewTypingService(convRepo, typingStore,
+		application.WithTypingNotifier(notifier),
+		application.WithTypingTTL(cfg.TypingTTL))
+	presenceService := application.NewPresenceService(presenceStore, userRepo, convRepo,
+		application.WithPresenceWindows(cfg.PresenceIdle, cfg.PresenceTimeout),
+		application.WithPresenceBlocks(blockRepo))
+	blockService := application.NewBlockService(blockRepo, userRepo,
+		application.WithBlockContacts(contactRepo))
+	contactService := application.NewContactService(contactRepo, userRepo,
+		application.WithContactBlocks(blockRepo),
+		application.WithContactNotifier(notifier))
+	attachmentService := application.NewAttachmentService(attachmentRepo, messageRepo, convRepo, blobStore,
+		application.WithMaxAttachmentSize(cfg.MaxAttachmentSize),
+		application.WithUnsentAttachmentTTL(cfg.UnsentAttachmentTTL),
+		application.WithScheduledAttachments(scheduledRepo))
 
 	// Handlers
+	messageHandler := handler.NewMessageHandler(messageService)
 	userHandler := handler.NewUserHandler(userService)
 	convHandler := handler.NewConversationHandler(convService)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	adminHandler := handler.NewAdminHandler(userService, adminService)
+	retentionHandler := handler.NewRetentionHandler(retentionService)
+	webhookHandler := handler.NewWebhookHandler(webhookNotifier)
+	typingHandler := handler.NewTypingHandler(typingService)
+	presenceHandler := handler.NewPresenceHandler(presenceService)
+	blockHandler := handler.NewBlockHandler(blockService)
+	contactHandler := handler.NewContactHandler(contactService)
+	notificationPrefHandler := handler.NewNotificationPreferenceHandler(notifier)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService, cfg.MaxAttachmentSize)
 
 	router := mux.NewRouter()
 
 	// Public routes
 	router.HandleFunc("/register", userHandler.RegisterUser).Methods(http.MethodPost)
 	router.HandleFunc("/login", userHandler.LoginUser).Methods(http.MethodPost)
+	router.HandleFunc("/login/totp", userHandler.CompleteTOTPLogin).Methods(http.MethodPost)
+	router.HandleFunc("/password", userHandler.ChangePassword).Methods(http.MethodPost)
 
 	// Protected routes
 	secured := router.PathPrefix("/").Subrouter()
-	secured.Use(auth.JWTMiddleware(jwtManager))
+	// API keys may only call the routes registered through scoped below.
+	apiKeyRoutes := map[*mux.Route]bool{}
+	secured.Use(auth.JWTMiddleware(jwtManager,
+		auth.WithSessionValidator(sessionService),
+		auth.WithAPIKeys(apiKeyService),
+		auth.WithAPIKeyRoutes(func(r *http.Request) bool { return apiKeyRoutes[mux.CurrentRoute(r)] }),
+		auth.WithActivityTracker(presenceService),
+	))
+
+	// Account management is only reachable with a login token, never an API key.
+	account := func(h http.HandlerFunc) http.Handler { return auth.RequireSession(h) }
+	scoped := func(path string, scope domain.Scope, h http.HandlerFunc) *mux.Route {
+		route := secured.Handle(path, auth.RequireScope(scope)(h))
+		apiKeyRoutes[route] = true
+		return route
+	}
+
+	// Two-factor authentication
+	secured.Handle("/users/me/totp", account(userHandler.EnrollTOTP)).Methods(http.MethodPost)
+	secured.Handle("/users/me/totp/confirm", account(userHandler.ConfirmTOTP)).Methods(http.MethodPost)
+	secured.Handle("/users/me/totp", account(userHandler.DisableTOTP)).Methods(http.MethodDelete)
+	secured.Handle("/users/me/email", account(userHandler.SetEmail)).Methods(http.MethodPut)
+
+	// Sessions
+	secured.Handle("/users/me/sessions", account(sessionHandler.ListSessions)).Methods(http.MethodGet)
+	secured.Handle("/users/me/sessions/{id}", account(sessionHandler.RevokeSession)).Methods(http.MethodDelete)
+
+	// Presence
+	secured.Handle("/users/me/presence", account(presenceHandler.Heartbeat)).Methods(http.MethodPost)
+	secured.Handle("/users/me/privacy", account(presenceHandler.SetPrivacy)).Methods(http.MethodPut)
+	scoped("/users/{id}/presence", domain.ScopeConversationsRead, presenceHandler.GetPresence).Methods(http.MethodGet)
+	scoped("/conversations/{id}/presence", domain.ScopeConversationsRead, presenceHandler.GetConversationPresence).Methods(http.MethodGet)
+
+	// User directory and blocks
+	secured.HandleFunc("/users", userHandler.SearchUsers).Methods(http.MethodGet)
+	secured.Handle("/users/me/blocks", account(blockHandler.BlockUser)).Methods(http.MethodPost)
+	secured.Handle("/users/me/blocks", account(blockHandler.GetBlocks)).Methods(http.MethodGet)
+	secured.Handle("/users/me/blocks/{id}", account(blockHandler.UnblockUser)).Methods(http.MethodDelete)
+
+	// Contacts
+	secured.Handle("/users/me/contacts", account(contactHandler.GetContacts)).Methods(http.MethodGet)
+	secured.Handle("/users/me/contacts/{id}", account(contactHandler.RemoveContact)).Methods(http.MethodDelete)
+	secured.Handle("/users/me/contact-settings", account(contactHandler.SetSettings)).Methods(http.MethodPut)
+	secured.Handle("/users/me/contact-requests", account(contactHandler.SendRequest)).Methods(http.MethodPost)
+	secured.Handle("/users/me/contact-requests", account(contactHandler.GetRequests)).Methods(http.MethodGet)
+	secured.Handle("/contact-requests/{id}/accept", account(contactHandler.AcceptRequest)).Methods(http.MethodPost)
+	secured.Handle("/contact-requests/{id}/decline", account(contactHandler.DeclineRequest)).Methods(http.MethodPost)
+	secured.Handle("/contact-requests/{id}", account(contactHandler.CancelRequest)).Methods(http.MethodDelete)
+
+	// Notification preferences
+	secured.Handle("/users/me/notification-settings", account(notificationPrefHandler.GetSettings)).Methods(http.MethodGet)
+	secured.Handle("/users/me/notification-settings", account(notificationPrefHandler.UpdateSettings)).Methods(http.MethodPut)
+	secured.Handle("/users/me/mutes", account(notificationPrefHandler.GetMutes)).Methods(http.MethodGet)
+	scoped("/conversations/{id}/mute", domain.ScopeConversationsWrite, notificationPrefHandler.MuteConversation).Methods(http.MethodPut)
+	scoped("/conversations/{id}/mute", domain.ScopeConversationsWrite, notificationPrefHandler.UnmuteConversation).Methods(http.MethodDelete)
+
+	// Bots and API keys
+	secured.Handle("/bots", account(apiKeyHandler.CreateBot)).Methods(http.MethodPost)
+	secured.Handle("/users/{id}/api-keys", account(apiKeyHandler.CreateAPIKey)).Methods(http.MethodPost)
+	secured.Handle("/users/{id}/api-keys", account(apiKeyHandler.ListAPIKeys)).Methods(http.MethodGet)
+	secured.Handle("/api-keys/{id}", account(apiKeyHandler.RevokeAPIKey)).Methods(http.MethodDelete)
+
+	// Admin endpoints
+	admin := secured.PathPrefix("/admin").Subrouter()
+	admin.Use(auth.RequireSession, auth.RequireRole(domain.RoleAdmin))
+	admin.HandleFunc("/users", adminHandler.ListUsers).Methods(http.MethodGet)
+	admin.HandleFunc("/users/{id}/role", adminHandler.SetUserRole).Methods(http.MethodPut)
+	admin.HandleFunc("/users/{id}/disable", adminHandler.DisableUser).Methods(http.MethodPost)
+	admin.HandleFunc("/users/{id}/enable", adminHandler.EnableUser).Methods(http.MethodPost)
+	admin.HandleFunc("/users/{id}/password-reset", adminHandler.ForcePasswordReset).Methods(http.MethodPost)
+	admin.HandleFunc("/messages/{id}", adminHandler.DeleteMessage).Methods(http.MethodDelete)
+	admin.HandleFunc("/conversations/{id}", adminHandler.DeleteConversation).Methods(http.MethodDelete)
+	admin.HandleFunc("/conversations/{id}/retention", retentionHandler.SetConversationRetention).Methods(http.MethodPut)
+	admin.HandleFunc("/retention", retentionHandler.GetPolicies).Methods(http.MethodGet)
+	admin.HandleFunc("/retention/purge", retentionHandler.Purge).Methods(http.MethodPost)
+	admin.HandleFunc("/webhooks/deliveries", webhookHandler.GetDeliveries).Methods(http.MethodGet)
 
 	// Conversation endpoints
-	secured.HandleFunc("/conversations", convHandler.CreateConversation).Methods(http.MethodPost)
-	secured.HandleFunc("/conversations", convHandler.GetConversations).Methods(http.MethodGet)
+	scoped("/conversations", domain.ScopeConversationsWrite, convHandler.CreateConversation).Methods(http.MethodPost)
+	scoped("/conversations", domain.ScopeConversationsRead, convHandler.GetConversations).Methods(http.MethodGet)
+	scoped("/conversations/{id}/ttl", domain.ScopeConversationsWrite, messageHandler.SetMessageTTL).Methods(http.MethodPut)
+	scoped("/conversations/{id}/pins", domain.ScopeMessagesRead, messageHandler.GetPins).Methods(http.MethodGet)
+	scoped("/conversations/{id}/typing", domain.ScopeMessagesWrite, typingHandler.StartTyping).Methods(http.MethodPost)
+	scoped("/conversations/{id}/typing", domain.ScopeMessagesWrite, typingHandler.StopTyping).Methods(http.MethodDelete)
+	scoped("/conversations/{id}/typing", domain.ScopeMessagesRead, typingHandler.GetTypers).Methods(http.MethodGet)
+	scoped("/conversations/{id}/messages", domain.ScopeMessagesRead, messageHandler.GetConversationMessages).Methods(http.MethodGet)
+
+	scoped("/messages", domain.ScopeMessagesWrite, messageHandler.CreateMessage).Methods(http.MethodPost)
+	scoped("/messages", domain.ScopeMessagesRead, messageHandler.GetMessages).Methods(http.MethodGet)
+	scoped("/messages/{id}/status", domain.ScopeMessagesWrite, messageHandler.UpdateStatus).Methods(http.MethodPut)
+	scoped("/messages/{id}", domain.ScopeMessagesWrite, messageHandler.EditMessage).Methods(http.MethodPatch)
+	scoped("/messages/{id}", domain.ScopeMessagesWrite, messageHandler.DeleteMessage).Methods(http.MethodDelete)
+	scoped("/messages/{id}/reactions", domain.ScopeMessagesWrite, messageHandler.AddReaction).Methods(http.MethodPost)
+	scoped("/messages/{id}/reactions/{emoji}", domain.ScopeMessagesWrite, messageHandler.RemoveReaction).Methods(http.MethodDelete)
+	scoped("/messages/{id}/pin", domain.ScopeMessagesWrite, messageHandler.PinMessage).Methods(http.MethodPost)
+	scoped("/messages/{id}/pin", domain.ScopeMessagesWrite, messageHandler.UnpinMessage).Methods(http.MethodDelete)
+	scoped("/messages/{id}/forward", domain.ScopeMessagesWrite, messageHandler.ForwardMessage).Methods(http.MethodPost)
+	scoped("/users/me/mentions", domain.ScopeMessagesRead, messageHandler.GetMentions).Methods(http.MethodGet)
+	scoped("/messages/{id}/thread", domain.ScopeMessagesRead, messageHandler.GetThread).Methods(http.MethodGet)
+	scoped("/messages/{id}/thread/read", domain.ScopeMessagesWrite, messageHandler.MarkThreadRead).Methods(http.MethodPost)
+	scoped("/scheduled-messages", domain.ScopeMessagesRead, messageHandler.GetScheduledMessages).Methods(http.MethodGet)
+	scoped("/scheduled-messages/{id}", domain.ScopeMessagesWrite, messageHandler.RescheduleMessage).Methods(http.MethodPatch)
+	scoped("/scheduled-messages/{id}", domain.ScopeMessagesWrite, messageHandler.CancelScheduledMessage).Methods(http.MethodDelete)
+	scoped("/search", domain.ScopeMessagesRead, messageHandler.SearchMessages).Methods(http.MethodGet)
+	scoped("/messages/{id}/history", domain.ScopeMessagesRead, messageHandler.GetMessageHistory).Methods(http.MethodGet)
+
+	// Attachments
+	scoped("/attachments", domain.ScopeMessagesWrite, attachmentHandler.Upload).Methods(http.MethodPost)
+	scoped("/attachments/{id}", domain.ScopeMessagesRead, attachmentHandler.Download).Methods(http.MethodGet)
+	scoped("/attachments/{id}/thumbnail", domain.ScopeMessagesRead, attachmentHandler.Thumbnail).Methods(http.MethodGet)
+
+	// Background jobs
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go worker.Every(ctx, "scheduled messages", cfg.SchedulerInterval, func() error {
+		_, err := messageService.DeliverDueMessages()
+		return err
+	})
+	go worker.Every(ctx, "message reaper", cfg.ReaperInterval, func() error {
+		_, err := messageService.ReapExpiredMessages()
+		return err
+	})
+	go worker.Every(ctx, "unsent attachments", cfg.ReaperInterval, func() error {
+		_, err := attachmentService.PurgeUnsent()
+		return err
+	})
+	go worker.Every(ctx, "retention purge", cfg.RetentionInterval, func() error {
+		report, err := retentionService.Purge(cfg.RetentionDryRun)
+		if err != nil {
+			return err
+		}
+		if report.Messages > 0 {
+			log.Printf("retention purge (dry run: %t): %d messages, %d attachments, %d bytes",
+				report.DryRun, report.Messages, report.Attachments, report.Bytes)
+		}
+		return nil
+	})
+	if emailNotifier != nil {
+		go worker.Every(ctx, "email digests", cfg.EmailDigestInterval, emailNotifier.SendDigests)
+	}
 
-	secured.HandleFunc("/messages", messageHandler.CreateMessage).Methods(http.MethodPost)
-	secured.HandleFunc("/messages", messageHandler.GetMessages).Methods(http.MethodGet)
-	secured.HandleFunc("/messages/{id}/status", messageHandler.UpdateStatus).Methods(http.MethodPut)
+	srv := &http.Server{Addr: ":8080", Handler: router}
+	go func() {
+		log.Println("Chat server running on :8080")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
 
-	log.Println("Chat server running on :8080")
-	log.Fatal(http.ListenAndServe(":8080", router))
+	<-ctx.Done()
+	log.Println("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("shutdown: %v", err)
+	}
+	// Deliver what is already queued before exiting.
+	webhookNotifier.Close()
+	if emailNotifier != nil {
+		emailNotifier.Close()
+	}
 }
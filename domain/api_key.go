@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scope limits what an API key may do.
+type Scope string
+
+const (
+	ScopeMessagesRead       Scope = "messages:read"
+	ScopeMessagesWrite      Scope = "messages:write"
+	ScopeConversationsRead  Scope = "conversations:read"
+	ScopeConversationsWrite Scope = "conversations:write"
+)
+
+var knownScopes = map[Scope]bool{
+	ScopeMessagesRead:       true,
+	ScopeMessagesWrite:      true,
+	ScopeConversationsRead:  true,
+	ScopeConversationsWrite: true,
+}
+
+// Valid reports whether s is a scope the API understands.
+func (s Scope) Valid() bool {
+	return knownScopes[s]
+}
+
+// APIKey is a long-lived credential for a user or bot. Only a hash of the
+// secret is stored; Prefix identifies the key without revealing it.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Hash       string     `json:"-"`
+	Scopes     []Scope    `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the key has not been revoked.
+func (k *APIKey) Active() bool {
+	return k.RevokedAt == nil
+}
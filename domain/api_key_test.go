@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopeValid(t *testing.T) {
+	for _, s := range []Scope{ScopeMessagesRead, ScopeMessagesWrite, ScopeConversationsRead, ScopeConversationsWrite} {
+		assert.True(t, s.Valid(), s)
+	}
+	assert.False(t, Scope("messages:delete").Valid())
+	assert.False(t, Scope("").Valid())
+}
+
+func TestAPIKeyActive(t *testing.T) {
+	key := &APIKey{Scopes: []Scope{ScopeMessagesRead}}
+	assert.True(t, key.Active())
+
+	now := time.Now()
+	key.RevokedAt = &now
+	assert.False(t, key.Active())
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment describes an uploaded file. It belongs to its uploader until
+// it is sent with a message; from then on the message's participants may
+// download it.
+type Attachment struct {
+	ID          uuid.UUID  `json:"id"`
+	UploaderID  string     `json:"uploader_id"`
+	MessageID   *uuid.UUID `json:"message_id,omitempty"`
+	Name        string     `json:"name"`
+	Size        int64      `json:"size"`
+	ContentType string     `json:"content_type"`
+	// Checksum is the hex-encoded SHA-256 of the file's bytes.
+	Checksum string `json:"checksum"`
+	// Width and Height are set for images; HasThumbnail tells whether a
+	// preview could be generated.
+	Width        int       `json:"width,omitempty"`
+	Height       int       `json:"height,omitempty"`
+	HasThumbnail bool      `json:"has_thumbnail"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Image reports whether the attachment is a picture.
+func (a *Attachment) Image() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// Block stops BlockedID from messaging BlockerID directly or starting a
+// direct chat with them. A user blocks another at most once.
+type Block struct {
+	BlockerID string    `json:"blocker_id"`
+	BlockedID string    `json:"blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContactRequestStatus tracks a contact request from sending to its answer.
+type ContactRequestStatus string
+
+const (
+	ContactRequestPending  ContactRequestStatus = "pending"
+	ContactRequestAccepted ContactRequestStatus = "accepted"
+	ContactRequestDeclined ContactRequestStatus = "declined"
+	ContactRequestCanceled ContactRequestStatus = "canceled"
+)
+
+// ContactRequest asks ToID to become FromID's contact. At most one request
+// between two users is pending at a time.
+type ContactRequest struct {
+	ID          uuid.UUID            `json:"id"`
+	FromID      string               `json:"from_id"`
+	ToID        string               `json:"to_id"`
+	Status      ContactRequestStatus `json:"status"`
+	CreatedAt   time.Time            `json:"created_at"`
+	RespondedAt *time.Time           `json:"responded_at,omitempty"`
+}
+
+// Contact is one entry in UserID's contact list. Contacts are mutual: the
+// other user's list holds the matching entry.
+type Contact struct {
+	UserID    string    `json:"-"`
+	ContactID string    `json:"contact_id"`
+	Since     time.Time `json:"since"`
+}
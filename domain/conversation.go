@@ -1,13 +1,84 @@
 package domain
 
 import (
+	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Day is the unit retention policies are set in.
+const Day = 24 * time.Hour
+
 type Conversation struct {
 	ID             uuid.UUID `json:"id"`
 	ParticipantIDs []string  `json:"participant_ids"`
 	CreatedAt      time.Time `json:"created_at"`
+	// MessageTTL makes new messages disappear this long after they are
+	// sent. Zero keeps them until deleted.
+	MessageTTL time.Duration `json:"-"`
+	// Retention overrides the server-wide retention policy: messages
+	// older than this are purged. Zero follows the server-wide policy.
+	Retention time.Duration `json:"-"`
+}
+
+type conversationJSON struct {
+	ID                uuid.UUID `json:"id"`
+	ParticipantIDs    []string  `json:"participant_ids"`
+	CreatedAt         time.Time `json:"created_at"`
+	MessageTTLSeconds int64     `json:"message_ttl_seconds,omitempty"`
+	RetentionDays     int       `json:"retention_days,omitempty"`
+}
+
+// MarshalJSON renders MessageTTL in whole seconds and Retention in whole
+// days.
+func (c Conversation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(conversationJSON{
+		ID:                c.ID,
+		ParticipantIDs:    c.ParticipantIDs,
+		CreatedAt:         c.CreatedAt,
+		MessageTTLSeconds: int64(c.MessageTTL / time.Second),
+		RetentionDays:     int(c.Retention / Day),
+	})
+}
+
+func (c *Conversation) UnmarshalJSON(data []byte) error {
+	var v conversationJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*c = Conversation{
+		ID:             v.ID,
+		ParticipantIDs: v.ParticipantIDs,
+		CreatedAt:      v.CreatedAt,
+		MessageTTL:     time.Duration(v.MessageTTLSeconds) * time.Second,
+		Retention:      time.Duration(v.RetentionDays) * Day,
+	}
+	return nil
+}
+
+// FormatTTL describes a disappearing-message timer in the largest whole
+// unit that fits, such as "1 day" or "90 minutes".
+func FormatTTL(ttl time.Duration) string {
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"week", 7 * Day},
+		{"day", Day},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+		{"second", time.Second},
+	}
+	for _, u := range units {
+		if ttl >= u.size && ttl%u.size == 0 {
+			n := int64(ttl / u.size)
+			if n == 1 {
+				return "1 " + u.name
+			}
+			return strconv.FormatInt(n, 10) + " " + u.name + "s"
+		}
+	}
+	return ttl.String()
 }
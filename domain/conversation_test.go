@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -20,3 +21,25 @@ func TestConversationFields(t *testing.T) {
 	assert.ElementsMatch(t, []string{"alice", "bob"}, conv.ParticipantIDs)
 	assert.True(t, conv.CreatedAt.Equal(now))
 }
+
+func TestConversationJSON_MessageTTL(t *testing.T) {
+	conv := Conversation{ID: uuid.New(), ParticipantIDs: []string{"alice", "bob"}, MessageTTL: 24 * time.Hour}
+	data, err := json.Marshal(conv)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"message_ttl_seconds":86400`)
+
+	var decoded Conversation
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, 24*time.Hour, decoded.MessageTTL)
+
+	data, err = json.Marshal(Conversation{ID: uuid.New()})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "message_ttl_seconds")
+}
+
+func TestFormatTTL(t *testing.T) {
+	assert.Equal(t, "1 hour", FormatTTL(time.Hour))
+	assert.Equal(t, "1 day", FormatTTL(24*time.Hour))
+	assert.Equal(t, "2 weeks", FormatTTL(14*24*time.Hour))
+	assert.Equal(t, "90 minutes", FormatTTL(90*time.Minute))
+}
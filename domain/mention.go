@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Mention is an @username reference inside a message's content. Offset
+// and Length are byte positions of the whole "@username" token.
+type Mention struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+}
+
+// mentionPattern matches "@name" not preceded by a word character, so
+// email addresses aren't picked up.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])(@[\w.-]{1,64})`)
+
+// ParseMentions finds @username tokens in content. UserID is left empty
+// for the caller to resolve. A username is mentioned at most once.
+func ParseMentions(content string) []Mention {
+	var out []Mention
+	seen := make(map[string]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatchIndex(content, -1) {
+		start, end := m[2], m[3]
+		// Sentence punctuation isn't part of the name: "thanks @bob."
+		token := strings.TrimRight(content[start:end], ".-")
+		name := token[1:]
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, Mention{Username: name, Offset: start, Length: len(token)})
+	}
+	return out
+}
+
+// Mentioned reports whether m mentions userID.
+func (m *Message) Mentioned(userID string) bool {
+	for _, mention := range m.Mentions {
+		if mention.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	content := "@alice can you ask @bob.smith? cc @alice, mail carol@example.com, thanks @dave."
+
+	assert.Equal(t, []Mention{
+		{Username: "alice", Offset: 0, Length: 6},
+		{Username: "bob.smith", Offset: 19, Length: 10},
+		{Username: "dave", Offset: 73, Length: 5},
+	}, ParseMentions(content))
+
+	assert.Equal(t, "@dave", content[73:78])
+	assert.Empty(t, ParseMentions("no mentions @ all"))
+}
@@ -55,4 +55,82 @@ type Message struct {
 	ConversationID uuid.UUID     `json:"conversation_id"`
 	CreatedAt      time.Time     `json:"created_at"`
 	Status         MessageStatus `json:"status"`
+	EditedAt       *time.Time    `json:"edited_at,omitempty"`
+	// DeletedAt marks a tombstone: the sender deleted the message for
+	// everyone, so Content is empty but the message keeps its place.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// ReplyToID is the message this one answers; ThreadRootID is the
+	// first message of the thread. Both are nil outside threads.
+	ReplyToID    *uuid.UUID `json:"reply_to,omitempty"`
+	ThreadRootID *uuid.UUID `json:"thread_root_id,omitempty"`
+	// Mentions are the resolved @username references in Content.
+	Mentions []Mention `json:"mentions,omitempty"`
+	// Format is how the sender wrote the message. Markdown is stored as
+	// its plain text in Content with Entities marking the formatting.
+	Format   MessageFormat `json:"format,omitempty"`
+	Entities []Entity      `json:"entities,omitempty"`
+	// ExpiresAt is when a disappearing message stops being readable.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// System marks a notice generated by the server, such as a changed
+	// disappearing-message timer. SenderID is the user who caused it.
+	System bool `json:"system,omitempty"`
+	// ForwardedFrom credits the original of a forwarded message.
+	ForwardedFrom *Forward `json:"forwarded_from,omitempty"`
+	// Quote is the message quoted inline, as it read when quoted.
+	Quote *Quote `json:"quote,omitempty"`
+
+	// Attachments, Reactions, Thread and HTML are filled in by
+	// MessageService; they aren't stored with the message.
+	Attachments []Attachment      `json:"attachments,omitempty"`
+	Reactions   []ReactionSummary `json:"reactions,omitempty"`
+	Thread      *ThreadSummary    `json:"thread,omitempty"`
+	HTML        string            `json:"html,omitempty"`
+}
+
+// Deleted reports whether the message was deleted for everyone.
+func (m *Message) Deleted() bool {
+	return m.DeletedAt != nil
+}
+
+// Expired reports whether a disappearing message has run out at now.
+func (m *Message) Expired(now time.Time) bool {
+	return m.ExpiresAt != nil && !m.ExpiresAt.After(now)
+}
+
+// Forward credits the original sender of a forwarded message. Forwarding
+// a forward keeps the first attribution.
+type Forward struct {
+	MessageID uuid.UUID `json:"message_id"`
+	SenderID  string    `json:"sender_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Quote is a snapshot of a quoted message, taken when the quote was sent
+// so later edits to the original don't change it.
+type Quote struct {
+	MessageID uuid.UUID `json:"message_id"`
+	SenderID  string    `json:"sender_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	// Unavailable is set on reads once the original has been deleted or
+	// has expired; Content is withheld then.
+	Unavailable bool `json:"unavailable,omitempty"`
+}
+
+// ThreadSummary describes the replies to a thread root as seen by one
+// user.
+type ThreadSummary struct {
+	ReplyCount     int       `json:"reply_count"`
+	LastReplyID    uuid.UUID `json:"last_reply_id"`
+	LastReplyAt    time.Time `json:"last_reply_at"`
+	ParticipantIDs []string  `json:"participant_ids"`
+	UnreadCount    int       `json:"unread_count"`
+}
+
+// MessageRevision is a superseded version of a message's content.
+// CreatedAt is when that version was written.
+type MessageRevision struct {
+	MessageID uuid.UUID `json:"message_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
 }
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationType identifies the event a Notification describes.
+type NotificationType string
+
+const (
+	NotificationNewMessage      NotificationType = "message.new"
+	NotificationMessageEdited   NotificationType = "message.edited"
+	NotificationMessageDeleted  NotificationType = "message.deleted"
+	NotificationMention         NotificationType = "message.mention"
+	NotificationMessagePinned   NotificationType = "message.pinned"
+	NotificationMessageUnpinned NotificationType = "message.unpinned"
+	NotificationTyping          NotificationType = "conversation.typing"
+	NotificationTypingStopped   NotificationType = "conversation.typing_stopped"
+	NotificationContactRequest  NotificationType = "contact.request"
+)
+
+// Alerting reports whether events of type t are meant to get the user's
+// attention. Only these are subject to notification preferences; the
+// others keep clients in sync and are always delivered.
+func (t NotificationType) Alerting() bool {
+	switch t {
+	case NotificationNewMessage, NotificationMention, NotificationContactRequest:
+		return true
+	}
+	return false
+}
+
+// NotificationPriority lets notifiers treat some events as more urgent.
+type NotificationPriority string
+
+const (
+	// PriorityLow marks ephemeral events, such as typing indicators, that
+	// are worthless once late and need not be stored or retried.
+	PriorityLow    NotificationPriority = "low"
+	PriorityNormal NotificationPriority = "normal"
+	PriorityHigh   NotificationPriority = "high"
+)
+
+// Notification is an event pushed to a single user through
+// ports.NotificationService.
+type Notification struct {
+	Type           NotificationType     `json:"type"`
+	Priority       NotificationPriority `json:"priority"`
+	UserID         string               `json:"user_id"`
+	ActorID        string               `json:"actor_id,omitempty"`
+	ConversationID uuid.UUID            `json:"conversation_id,omitempty"`
+	MessageID      uuid.UUID            `json:"message_id,omitempty"`
+	Body           string               `json:"body"`
+	CreatedAt      time.Time            `json:"created_at"`
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationLevel is how much of the conversation activity a user wants
+// to be notified about.
+type NotificationLevel string
+
+const (
+	NotifyAll      NotificationLevel = "all"
+	NotifyMentions NotificationLevel = "mentions"
+	NotifyNone     NotificationLevel = "none"
+)
+
+// Valid reports whether l is a known level.
+func (l NotificationLevel) Valid() bool {
+	return l == NotifyAll || l == NotifyMentions || l == NotifyNone
+}
+
+// clockLayout is how do-not-disturb times are written, e.g. "22:30".
+const clockLayout = "15:04"
+
+// DoNotDisturb is a daily window, in the user's time zone, during which
+// they get no notifications. A window whose End is before its Start runs
+// past midnight.
+type DoNotDisturb struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Valid reports whether Start and End are distinct "HH:MM" times.
+func (d DoNotDisturb) Valid() bool {
+	start, err := time.Parse(clockLayout, d.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(clockLayout, d.End)
+	return err == nil && !start.Equal(end)
+}
+
+// Covers reports whether the wall-clock time of t falls in the window.
+// Start is inclusive and End exclusive.
+func (d DoNotDisturb) Covers(t time.Time) bool {
+	start, err1 := time.Parse(clockLayout, d.Start)
+	end, err2 := time.Parse(clockLayout, d.End)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	minute := func(c time.Time) int { return c.Hour()*60 + c.Minute() }
+	now, from, to := minute(t), minute(start), minute(end)
+	if from <= to {
+		return now >= from && now < to
+	}
+	return now >= from || now < to
+}
+
+// NotificationSettings are a user's notification preferences. The zero
+// value notifies about everything, at any hour.
+type NotificationSettings struct {
+	UserID string            `json:"-"`
+	Level  NotificationLevel `json:"level"`
+	// TimeZone is the IANA name do-not-disturb hours are read in; empty
+	// means UTC.
+	TimeZone     string        `json:"time_zone,omitempty"`
+	DoNotDisturb *DoNotDisturb `json:"do_not_disturb,omitempty"`
+}
+
+// Allows reports whether n should reach the user at now, leaving aside
+// conversation mutes. Events that aren't alerting are always allowed.
+// Events that aren't about conversation activity, such as contact
+// requests, are only held back by NotifyNone and do-not-disturb hours.
+func (s NotificationSettings) Allows(n *Notification, now time.Time) bool {
+	if !n.Type.Alerting() {
+		return true
+	}
+	if s.Level == NotifyNone {
+		return false
+	}
+	if s.DoNotDisturb != nil {
+		loc, err := time.LoadLocation(s.TimeZone)
+		if err != nil {
+			loc = time.UTC
+		}
+		if s.DoNotDisturb.Covers(now.In(loc)) {
+			return false
+		}
+	}
+	conversational := n.ConversationID != uuid.Nil || n.MessageID != uuid.Nil
+	if s.Level == NotifyMentions && conversational {
+		return n.Type == NotificationMention
+	}
+	return true
+}
+
+// ConversationMute silences a conversation for one user. A nil Until mutes
+// it until the user unmutes it.
+type ConversationMute struct {
+	UserID         string     `json:"-"`
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	Until          *time.Time `json:"until,omitempty"`
+}
+
+// Active reports whether the mute is still in force at now.
+func (m ConversationMute) Active(now time.Time) bool {
+	return m.Until == nil || now.Before(*m.Until)
+}
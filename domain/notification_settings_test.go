@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoNotDisturb(t *testing.T) {
+	assert.True(t, DoNotDisturb{Start: "22:00", End: "07:00"}.Valid())
+	assert.False(t, DoNotDisturb{Start: "22:00", End: "22:00"}.Valid())
+	assert.False(t, DoNotDisturb{Start: "25:00", End: "07:00"}.Valid())
+	assert.False(t, DoNotDisturb{Start: "10pm", End: "07:00"}.Valid())
+
+	at := func(clock string) time.Time {
+		c, _ := time.Parse(clockLayout, clock)
+		return time.Date(2024, 3, 1, c.Hour(), c.Minute(), 0, 0, time.UTC)
+	}
+	overnight := DoNotDisturb{Start: "22:00", End: "07:00"}
+	daytime := DoNotDisturb{Start: "09:00", End: "17:30"}
+	tests := []struct {
+		window DoNotDisturb
+		clock  string
+		want   bool
+	}{
+		{overnight, "21:59", false},
+		{overnight, "22:00", true},
+		{overnight, "03:00", true},
+		{overnight, "07:00", false},
+		{daytime, "08:59", false},
+		{daytime, "12:00", true},
+		{daytime, "17:30", false},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, tc.window.Covers(at(tc.clock)), "%v at %s", tc.window, tc.clock)
+	}
+}
+
+func TestNotificationSettings_Allows(t *testing.T) {
+	noon := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	edit := &Notification{Type: NotificationMessageEdited, MessageID: uuid.New()}
+	message := &Notification{Type: NotificationNewMessage, MessageID: uuid.New()}
+	mention := &Notification{Type: NotificationMention, MessageID: uuid.New()}
+	request := &Notification{Type: NotificationContactRequest}
+
+	all := NotificationSettings{Level: NotifyAll}
+	assert.True(t, all.Allows(message, noon))
+	assert.True(t, NotificationSettings{}.Allows(message, noon), "the zero value allows everything")
+
+	mentions := NotificationSettings{Level: NotifyMentions}
+	assert.False(t, mentions.Allows(message, noon))
+	assert.True(t, mentions.Allows(mention, noon))
+	assert.True(t, mentions.Allows(request, noon))
+
+	none := NotificationSettings{Level: NotifyNone}
+	assert.False(t, none.Allows(mention, noon))
+	assert.False(t, none.Allows(request, noon))
+	assert.True(t, none.Allows(edit, noon), "events that don't alert are never held back")
+
+	// 12:00 UTC is 14:00 in Athens in winter.
+	dnd := NotificationSettings{Level: NotifyAll, TimeZone: "Europe/Athens", DoNotDisturb: &DoNotDisturb{Start: "13:00", End: "15:00"}}
+	assert.False(t, dnd.Allows(mention, noon))
+	assert.True(t, dnd.Allows(mention, noon.Add(time.Hour)))
+	dnd.TimeZone = ""
+	assert.True(t, dnd.Allows(mention, noon), "no time zone means UTC")
+}
+
+func TestConversationMute_Active(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	assert.True(t, ConversationMute{}.Active(now))
+	assert.True(t, ConversationMute{Until: &later}.Active(now))
+	assert.False(t, ConversationMute{Until: &later}.Active(later))
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pin marks a message as pinned in its conversation. A message is pinned
+// at most once.
+type Pin struct {
+	ConversationID uuid.UUID `json:"conversation_id"`
+	MessageID      uuid.UUID `json:"message_id"`
+	PinnedBy       string    `json:"pinned_by"`
+	PinnedAt       time.Time `json:"pinned_at"`
+
+	// Message is filled in by MessageService; it isn't stored with the
+	// pin.
+	Message *Message `json:"message,omitempty"`
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// PresenceStatus is whether a user is around.
+type PresenceStatus string
+
+const (
+	PresenceOnline  PresenceStatus = "online"
+	PresenceAway    PresenceStatus = "away"
+	PresenceOffline PresenceStatus = "offline"
+)
+
+// Activity is what the server last heard from a user: any authenticated
+// request counts, and heartbeats also say whether the user is away.
+type Activity struct {
+	LastSeenAt time.Time
+	Away       bool
+}
+
+// Status derives a user's status at now. Users seen within idle are
+// online unless they said they are away; after idle they are away, and
+// after timeout offline.
+func (a Activity) Status(now time.Time, idle, timeout time.Duration) PresenceStatus {
+	age := now.Sub(a.LastSeenAt)
+	switch {
+	case a.LastSeenAt.IsZero() || age > timeout:
+		return PresenceOffline
+	case a.Away || age > idle:
+		return PresenceAway
+	}
+	return PresenceOnline
+}
+
+// Presence is a user's status as shown to someone else. LastSeenAt is
+// left out when the user hides it or has never been seen.
+type Presence struct {
+	UserID     string         `json:"user_id"`
+	Status     PresenceStatus `json:"status"`
+	LastSeenAt *time.Time     `json:"last_seen_at,omitempty"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivity_Status(t *testing.T) {
+	now := time.Now()
+	idle, timeout := 2*time.Minute, 5*time.Minute
+
+	tests := []struct {
+		name     string
+		activity Activity
+		want     PresenceStatus
+	}{
+		{"never seen", Activity{}, PresenceOffline},
+		{"just seen", Activity{LastSeenAt: now.Add(-10 * time.Second)}, PresenceOnline},
+		{"said away", Activity{LastSeenAt: now.Add(-10 * time.Second), Away: true}, PresenceAway},
+		{"idle", Activity{LastSeenAt: now.Add(-3 * time.Minute)}, PresenceAway},
+		{"gone", Activity{LastSeenAt: now.Add(-6 * time.Minute)}, PresenceOffline},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.activity.Status(now, idle, timeout))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Reaction is one user's emoji reaction to a message. A user holds at most
+// one reaction per emoji per message.
+type Reaction struct {
+	MessageID uuid.UUID `json:"message_id"`
+	UserID    string    `json:"user_id"`
+	Emoji     string    `json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReactionSummary aggregates the reactions with one emoji on a message.
+type ReactionSummary struct {
+	Emoji   string   `json:"emoji"`
+	Count   int      `json:"count"`
+	UserIDs []string `json:"user_ids"`
+}
+
+var shortcodePattern = regexp.MustCompile(`^:[a-z0-9_+-]{1,32}:$`)
+
+// maxEmojiRunes allows for ZWJ sequences such as family emoji.
+const maxEmojiRunes = 10
+
+// ValidReaction reports whether s is a shortcode like ":thumbsup:" or a
+// single Unicode emoji, including modifier, keycap and ZWJ sequences.
+func ValidReaction(s string) bool {
+	if shortcodePattern.MatchString(s) {
+		return true
+	}
+	if s == "" || utf8.RuneCountInString(s) > maxEmojiRunes {
+		return false
+	}
+
+	symbol, keycap := false, false
+	for _, r := range s {
+		switch {
+		case r == '\u20e3': // combining keycap
+			keycap = true
+		case unicode.Is(unicode.So, r):
+			symbol = true
+		case r == '\u200d', r == '\ufe0f', // ZWJ, emoji presentation
+			unicode.Is(unicode.Sk, r),    // skin tone modifiers
+			r >= 0xe0020 && r <= 0xe007f, // tag sequences (subdivision flags)
+			r == '#', r == '*', r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return symbol || keycap
+}
+
+// SummarizeReactions groups reactions by emoji in order of first use.
+func SummarizeReactions(reactions []*Reaction) []ReactionSummary {
+	var out []ReactionSummary
+	index := make(map[string]int)
+	for _, r := range reactions {
+		i, ok := index[r.Emoji]
+		if !ok {
+			i = len(out)
+			index[r.Emoji] = i
+			out = append(out, ReactionSummary{Emoji: r.Emoji})
+		}
+		out[i].Count++
+		out[i].UserIDs = append(out[i].UserIDs, r.UserID)
+	}
+	return out
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidReaction(t *testing.T) {
+	valid := []string{
+		":thumbsup:", ":+1:", ":party_parrot:",
+		"👍", "👍🏽", "❤️", "🇬🇷", "1️⃣", "👩‍👩‍👧‍👦", "🏴󠁧󠁢󠁳󠁣󠁴󠁿",
+	}
+	for _, s := range valid {
+		assert.True(t, ValidReaction(s), s)
+	}
+
+	invalid := []string{
+		"", "a", "1", "hello", ":Thumbs Up:", "::", "<b>", "👍 ", "👍👍👍👍👍👍👍👍👍👍👍",
+	}
+	for _, s := range invalid {
+		assert.False(t, ValidReaction(s), s)
+	}
+}
+
+func TestSummarizeReactions(t *testing.T) {
+	summary := SummarizeReactions([]*Reaction{
+		{UserID: "a", Emoji: "👍"},
+		{UserID: "b", Emoji: ":tada:"},
+		{UserID: "b", Emoji: "👍"},
+	})
+
+	assert.Equal(t, []ReactionSummary{
+		{Emoji: "👍", Count: 2, UserIDs: []string{"a", "b"}},
+		{Emoji: ":tada:", Count: 1, UserIDs: []string{"b"}},
+	}, summary)
+	assert.Empty(t, SummarizeReactions(nil))
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PurgeReport describes one run of the retention purge. In a dry run the
+// counts are what would have been removed.
+type PurgeReport struct {
+	DryRun     bool          `json:"dry_run"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Policies   []PolicyPurge `json:"policies"`
+	PurgeCounts
+}
+
+// PolicyPurge is what one retention policy removed. ConversationID is
+// zero for the server-wide policy.
+type PolicyPurge struct {
+	ConversationID uuid.UUID `json:"conversation_id,omitempty"`
+	RetentionDays  int       `json:"retention_days"`
+	Cutoff         time.Time `json:"cutoff"`
+	PurgeCounts
+}
+
+// PurgeCounts tallies removed data.
+type PurgeCounts struct {
+	Messages    int   `json:"messages"`
+	Attachments int   `json:"attachments"`
+	Bytes       int64 `json:"bytes"`
+}
+
+// Add accumulates other into c.
+func (c *PurgeCounts) Add(other PurgeCounts) {
+	c.Messages += other.Messages
+	c.Attachments += other.Attachments
+	c.Bytes += other.Bytes
+}
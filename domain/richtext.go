@@ -0,0 +1,435 @@
+package domain
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MessageFormat is how the sender wrote a message's content.
+type MessageFormat string
+
+const (
+	FormatPlain    MessageFormat = "plain"
+	FormatMarkdown MessageFormat = "markdown"
+)
+
+// Valid reports whether f is a known format.
+func (f MessageFormat) Valid() bool {
+	return f == FormatPlain || f == FormatMarkdown
+}
+
+// EntityType is the kind of formatting an Entity applies.
+type EntityType string
+
+const (
+	EntityBold        EntityType = "bold"
+	EntityItalic      EntityType = "italic"
+	EntityCode        EntityType = "code"
+	EntityPre         EntityType = "pre"
+	EntityLink        EntityType = "link"
+	EntityBulletList  EntityType = "bullet_list"
+	EntityOrderedList EntityType = "ordered_list"
+	EntityListItem    EntityType = "list_item"
+)
+
+// Entity formats a span of a message's plain-text content. Offset and
+// Length are byte positions. Entities may nest but never partially
+// overlap.
+type Entity struct {
+	Type   EntityType `json:"type"`
+	Offset int        `json:"offset"`
+	Length int        `json:"length"`
+	// URL is the target of a link.
+	URL string `json:"url,omitempty"`
+	// Language is the optional language of a code block.
+	Language string `json:"language,omitempty"`
+}
+
+func (e Entity) end() int {
+	return e.Offset + e.Length
+}
+
+var (
+	listItemPattern = regexp.MustCompile(`^ {0,3}(?:[-*+]|(\d{1,9})[.)])[ \t]+(.*)$`)
+	languagePattern = regexp.MustCompile(`^[\w+#.-]{1,32}$`)
+)
+
+// markdownEscapable are the characters a backslash makes literal.
+const markdownEscapable = "\\`*_[]()#+-.!"
+
+// maxInlineNesting caps how deeply emphasis and links nest. Deeper
+// markup is left as written.
+const maxInlineNesting = 8
+
+// ParseMarkdown reduces a subset of Markdown to plain text and the
+// entities that format it: **bold**, *italics*, `code`, fenced code
+// blocks, [links](https://example.com) and bulleted or numbered lists.
+// Links to anything but http, https and mailto URLs keep their text and
+// lose the link.
+func ParseMarkdown(src string) (string, []Entity) {
+	p := &markdownParser{}
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); {
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(lines[i]), "```"):
+			i = p.codeBlock(lines, i)
+		case listItemPattern.MatchString(lines[i]):
+			i = p.list(lines, i)
+		default:
+			p.startLine()
+			p.inline(lines[i])
+			i++
+		}
+	}
+	return p.out.String(), p.entities
+}
+
+type markdownParser struct {
+	out      strings.Builder
+	entities []Entity
+	started  bool
+	depth    int
+}
+
+func (p *markdownParser) startLine() {
+	if p.started {
+		p.out.WriteByte('\n')
+	}
+	p.started = true
+}
+
+// span records an entity around whatever write adds, unless it adds
+// nothing.
+func (p *markdownParser) span(e Entity, write func()) {
+	e.Offset = p.out.Len()
+	write()
+	if e.Length = p.out.Len() - e.Offset; e.Length > 0 {
+		p.entities = append(p.entities, e)
+	}
+}
+
+// codeBlock copies a fenced block starting at lines[i] verbatim. A block
+// left open runs to the end of the message.
+func (p *markdownParser) codeBlock(lines []string, i int) int {
+	block := Entity{Type: EntityPre}
+	if lang := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), "```")); languagePattern.MatchString(lang) {
+		block.Language = lang
+	}
+	end := i + 1
+	for end < len(lines) && strings.TrimSpace(lines[end]) != "```" {
+		end++
+	}
+	p.startLine()
+	p.span(block, func() { p.out.WriteString(strings.Join(lines[i+1:end], "\n")) })
+	return end + 1
+}
+
+// list parses the run of list items of one kind starting at lines[i].
+// Items keep a "•" or their number in the plain text.
+func (p *markdownParser) list(lines []string, i int) int {
+	ordered := listItemPattern.FindStringSubmatch(lines[i])[1] != ""
+	kind := EntityBulletList
+	if ordered {
+		kind = EntityOrderedList
+	}
+	p.startLine()
+	p.span(Entity{Type: kind}, func() {
+		for first := true; i < len(lines); i, first = i+1, false {
+			m := listItemPattern.FindStringSubmatch(lines[i])
+			if m == nil || (m[1] != "") != ordered {
+				return
+			}
+			if !first {
+				p.out.WriteByte('\n')
+			}
+			if ordered {
+				p.out.WriteString(m[1] + ". ")
+			} else {
+				p.out.WriteString("• ")
+			}
+			p.span(Entity{Type: EntityListItem}, func() { p.inline(m[2]) })
+		}
+	})
+	return i
+}
+
+// inline parses the spans within one line.
+func (p *markdownParser) inline(s string) {
+	if p.depth > maxInlineNesting {
+		p.out.WriteString(s)
+		return
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+
+	scan := &lineScan{s: s, next: make(map[string]int), from: make(map[string]int)}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(markdownEscapable, s[i+1]) >= 0:
+			p.out.WriteByte(s[i+1])
+			i += 2
+			continue
+		case c == '`':
+			if j := scan.find("`", i+1, scan.literal("`")); j > i+1 {
+				code := s[i+1 : j]
+				p.span(Entity{Type: EntityCode}, func() { p.out.WriteString(code) })
+				i = j + 1
+				continue
+			}
+		case c == '*' || c == '_':
+			if next, ok := p.emphasis(scan, i); ok {
+				i = next
+				continue
+			}
+		case c == '[':
+			if next, ok := p.link(scan, i); ok {
+				i = next
+				continue
+			}
+		}
+		p.out.WriteByte(c)
+		i++
+	}
+}
+
+// lineScan finds the next match of each kind of closing markup in one
+// line. Openers are tried left to right, so a match found for one opener
+// also answers every later opener up to it, and the line is only walked
+// once per kind however many openers it has.
+type lineScan struct {
+	s    string
+	next map[string]int // where the last search found a match, or -1
+	from map[string]int // where the last search started
+}
+
+// find returns the first position at or after i where match holds, or -1.
+// Calls for a kind must not move backwards.
+func (l *lineScan) find(kind string, i int, match func(j int) bool) int {
+	if at, ok := l.next[kind]; ok && l.from[kind] <= i && (at < 0 || at >= i) {
+		return at
+	}
+	at := -1
+	for j := i; j < len(l.s); j++ {
+		if match(j) {
+			at = j
+			break
+		}
+	}
+	l.next[kind], l.from[kind] = at, i
+	return at
+}
+
+func (l *lineScan) literal(sub string) func(int) bool {
+	return func(j int) bool { return strings.HasPrefix(l.s[j:], sub) }
+}
+
+// emphasis parses *italics* or **bold** (or the underscore forms)
+// opening at s[i], returning where parsing resumes.
+func (p *markdownParser) emphasis(scan *lineScan, i int) (int, bool) {
+	s := scan.s
+	delim, kind := s[i:i+1], EntityItalic
+	if strings.HasPrefix(s[i+1:], delim) {
+		delim, kind = delim+delim, EntityBold
+	}
+	open := i + len(delim)
+	if open >= len(s) || s[open] == ' ' {
+		return i, false
+	}
+	// Underscores inside words, as in snake_case, are literal.
+	underscore := delim[0] == '_'
+	if underscore && i > 0 && isWordByte(s[i-1]) {
+		return i, false
+	}
+	// Whether a delimiter can close depends only on where it is, so the
+	// closers are found with one scan of the line.
+	j := scan.find(delim, open+1, func(j int) bool {
+		if !strings.HasPrefix(s[j:], delim) || s[j-1] == ' ' || s[j-1] == '\\' {
+			return false
+		}
+		after := j + len(delim)
+		// A single delimiter doesn't close on half of a double one.
+		if len(delim) == 1 && (s[j-1] == delim[0] || (after < len(s) && s[after] == delim[0])) {
+			return false
+		}
+		return !underscore || after >= len(s) || !isWordByte(s[after])
+	})
+	if j < 0 {
+		return i, false
+	}
+	inner := s[open:j]
+	p.span(Entity{Type: kind}, func() { p.inline(inner) })
+	return j + len(delim), true
+}
+
+// link parses [text](url) opening at s[i], returning where parsing
+// resumes.
+func (p *markdownParser) link(scan *lineScan, i int) (int, bool) {
+	s := scan.s
+	n := scan.find("](", i, scan.literal("](")) - i
+	if n < 2 {
+		return i, false
+	}
+	target := i + n + 2
+	m := scan.find(")", target, scan.literal(")")) - target
+	if m < 0 {
+		return i, false
+	}
+	text, href := s[i+1:i+n], strings.TrimSpace(s[target:target+m])
+	if strings.ContainsAny(text, "[]") {
+		return i, false
+	}
+	if SafeURL(href) {
+		p.span(Entity{Type: EntityLink, URL: href}, func() { p.inline(text) })
+	} else {
+		p.inline(text)
+	}
+	return target + m + 1, true
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// SafeURL reports whether raw is an absolute http, https or mailto URL,
+// the only links rendered.
+func SafeURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto":
+		return u.Opaque != ""
+	}
+	return false
+}
+
+// RenderHTML renders text formatted by entities as HTML. All of text is
+// escaped and only the tags the entities call for are produced, so the
+// result is safe to embed whatever the message says. Entities that are
+// out of range, overlap others or sit inside code are ignored.
+func RenderHTML(text string, entities []Entity) string {
+	root := &entityNode{Entity: Entity{Length: len(text)}}
+	sorted := slices.Clone(entities)
+	slices.SortStableFunc(sorted, func(a, b Entity) int {
+		if a.Offset != b.Offset {
+			return a.Offset - b.Offset
+		}
+		if a.Length != b.Length {
+			return b.Length - a.Length
+		}
+		return slices.Index(entityNesting, a.Type) - slices.Index(entityNesting, b.Type)
+	})
+	stack := []*entityNode{root}
+	for _, e := range sorted {
+		if e.Offset < 0 || e.Length <= 0 || e.end() > len(text) {
+			continue
+		}
+		for e.end() > stack[len(stack)-1].end() {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		if parent.Type == EntityCode || parent.Type == EntityPre {
+			continue
+		}
+		if n := len(parent.children); n > 0 && e.Offset < parent.children[n-1].end() {
+			continue
+		}
+		node := &entityNode{Entity: e}
+		parent.children = append(parent.children, node)
+		stack = append(stack, node)
+	}
+
+	var b strings.Builder
+	root.render(&b, text, "")
+	return b.String()
+}
+
+// entityNesting orders entities covering the same span from outermost to
+// innermost.
+var entityNesting = []EntityType{
+	EntityBulletList, EntityOrderedList, EntityListItem, EntityPre, EntityLink, EntityBold, EntityItalic, EntityCode,
+}
+
+type entityNode struct {
+	Entity
+	children []*entityNode
+}
+
+func (n *entityNode) render(b *strings.Builder, text string, parent EntityType) {
+	open, close := n.tags(parent)
+	b.WriteString(open)
+	list := n.Type == EntityBulletList || n.Type == EntityOrderedList
+	pos := n.Offset
+	for i, c := range n.children {
+		// Inside a list only the items are rendered; markers and line
+		// breaks between them are left to the list tags.
+		if !list {
+			gap := text[pos:c.Offset]
+			if c.block() {
+				gap = strings.TrimSuffix(gap, "\n")
+			}
+			if i > 0 && n.children[i-1].block() {
+				gap = strings.TrimPrefix(gap, "\n")
+			}
+			n.writeText(b, gap)
+		}
+		c.render(b, text, n.Type)
+		pos = c.end()
+	}
+	if !list {
+		gap := text[pos:n.end()]
+		if k := len(n.children); k > 0 && n.children[k-1].block() {
+			gap = strings.TrimPrefix(gap, "\n")
+		}
+		n.writeText(b, gap)
+	}
+	b.WriteString(close)
+}
+
+func (n *entityNode) block() bool {
+	return n.Type == EntityPre || n.Type == EntityBulletList || n.Type == EntityOrderedList
+}
+
+func (n *entityNode) writeText(b *strings.Builder, s string) {
+	s = html.EscapeString(s)
+	if n.Type != EntityPre {
+		s = strings.ReplaceAll(s, "\n", "<br>\n")
+	}
+	b.WriteString(s)
+}
+
+func (n *entityNode) tags(parent EntityType) (string, string) {
+	switch n.Type {
+	case EntityBold:
+		return "<strong>", "</strong>"
+	case EntityItalic:
+		return "<em>", "</em>"
+	case EntityCode:
+		return "<code>", "</code>"
+	case EntityPre:
+		if languagePattern.MatchString(n.Language) {
+			return `<pre><code class="language-` + html.EscapeString(n.Language) + `">`, "</code></pre>"
+		}
+		return "<pre><code>", "</code></pre>"
+	case EntityLink:
+		if SafeURL(n.URL) {
+			return `<a href="` + html.EscapeString(n.URL) + `" rel="nofollow noopener noreferrer">`, "</a>"
+		}
+	case EntityBulletList:
+		return "<ul>", "</ul>"
+	case EntityOrderedList:
+		return "<ol>", "</ol>"
+	case EntityListItem:
+		if parent == EntityBulletList || parent == EntityOrderedList {
+			return "<li>", "</li>"
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		text     string
+		entities []Entity
+	}{
+		{
+			name:     "emphasis",
+			src:      "**bold** and *italic* and __more__",
+			text:     "bold and italic and more",
+			entities: []Entity{{Type: EntityBold, Offset: 0, Length: 4}, {Type: EntityItalic, Offset: 9, Length: 6}, {Type: EntityBold, Offset: 20, Length: 4}},
+		},
+		{
+			name:     "nested",
+			src:      "*a **b** c*",
+			text:     "a b c",
+			entities: []Entity{{Type: EntityBold, Offset: 2, Length: 1}, {Type: EntityItalic, Offset: 0, Length: 5}},
+		},
+		{
+			name: "literal",
+			src:  "2 * 3 * 4, snake_case_name, \\*not italic\\*",
+			text: "2 * 3 * 4, snake_case_name, *not italic*",
+		},
+		{
+			name:     "inline code keeps markup",
+			src:      "run `**x**` now",
+			text:     "run **x** now",
+			entities: []Entity{{Type: EntityCode, Offset: 4, Length: 5}},
+		},
+		{
+			name:     "code block",
+			src:      "look:\n```go\nfmt.Println(\"*hi*\")\n```\ndone",
+			text:     "look:\nfmt.Println(\"*hi*\")\ndone",
+			entities: []Entity{{Type: EntityPre, Offset: 6, Length: 19, Language: "go"}},
+		},
+		{
+			name:     "link",
+			src:      "see [the **docs**](https://example.com/a?b=1)",
+			text:     "see the docs",
+			entities: []Entity{{Type: EntityBold, Offset: 8, Length: 4}, {Type: EntityLink, Offset: 4, Length: 8, URL: "https://example.com/a?b=1"}},
+		},
+		{
+			name: "unsafe link keeps its text",
+			src:  "[click](javascript:alert(1))",
+			text: "click)",
+		},
+		{
+			name: "lists",
+			src:  "- one\n- *two*\n1. first\n2. second",
+			text: "• one\n• two\n1. first\n2. second",
+			entities: []Entity{
+				{Type: EntityListItem, Offset: 4, Length: 3},
+				{Type: EntityItalic, Offset: 12, Length: 3},
+				{Type: EntityListItem, Offset: 12, Length: 3},
+				{Type: EntityBulletList, Offset: 0, Length: 15},
+				{Type: EntityListItem, Offset: 19, Length: 5},
+				{Type: EntityListItem, Offset: 28, Length: 6},
+				{Type: EntityOrderedList, Offset: 16, Length: 18},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			text, entities := ParseMarkdown(tc.src)
+			assert.Equal(t, tc.text, text)
+			assert.Equal(t, tc.entities, entities)
+		})
+	}
+}
+
+func TestParseMarkdown_UnclosedMarkupIsLinear(t *testing.T) {
+	for _, src := range []string{
+		strings.Repeat("*a ", 32000),
+		strings.Repeat("__a ", 24000),
+		strings.Repeat("[a", 48000),
+		strings.Repeat("`", 96000),
+		strings.Repeat("*_", 48000) + strings.Repeat("_*", 48000),
+	} {
+		start := time.Now()
+		ParseMarkdown(src)
+		assert.Less(t, time.Since(start), time.Second, src[:8])
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	render := func(src string) string { return RenderHTML(ParseMarkdown(src)) }
+
+	assert.Equal(t, "<strong>hi</strong> &lt;script&gt;alert(1)&lt;/script&gt;", render("**hi** <script>alert(1)</script>"))
+	assert.Equal(t, `<a href="https://example.com/?a=1&amp;b=%22" rel="nofollow noopener noreferrer">x</a>`,
+		render(`[x](https://example.com/?a=1&b=%22)`))
+	assert.Equal(t, "click)", render("[click](javascript:alert(1))"))
+	assert.Equal(t, "items:<ul><li>a</li><li><em>b</em></li></ul>after", render("items:\n- a\n- *b*\nafter"))
+	assert.Equal(t, "<pre><code class=\"language-go\">x &lt; 1\ny</code></pre>", render("```go\nx < 1\ny\n```"))
+	assert.Equal(t, "one<br>\ntwo", render("one\ntwo"))
+
+	// Stored entities aren't trusted either.
+	assert.Equal(t, "<pre><code>&lt;b&gt;</code></pre>", RenderHTML("<b>", []Entity{
+		{Type: EntityLink, Offset: 0, Length: 3, URL: "javascript:alert(1)"},
+		{Type: EntityBold, Offset: 2, Length: 9},
+		{Type: EntityPre, Offset: 0, Length: 3, Language: `"><script>`},
+	}))
+	assert.Equal(t, "<strong>ab</strong>c", RenderHTML("abc", []Entity{
+		{Type: EntityBold, Offset: 0, Length: 2},
+		{Type: EntityItalic, Offset: 1, Length: 2},
+	}))
+}
@@ -0,0 +1,33 @@
+package domain
+
+// Role is a user's global permission level. Roles are ordered: every
+// role includes the rights of the ones below it.
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+var roleRank = map[Role]int{
+	RoleUser:      1,
+	RoleModerator: 2,
+	RoleAdmin:     3,
+}
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+// AtLeast reports whether r grants everything min does. Unknown roles,
+// including the empty one, grant nothing beyond RoleUser.
+func (r Role) AtLeast(min Role) bool {
+	rank, ok := roleRank[r]
+	if !ok {
+		rank = roleRank[RoleUser]
+	}
+	return rank >= roleRank[min]
+}
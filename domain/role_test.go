@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleAtLeast(t *testing.T) {
+	cases := []struct {
+		role, min Role
+		expected  bool
+	}{
+		{RoleAdmin, RoleAdmin, true},
+		{RoleAdmin, RoleModerator, true},
+		{RoleAdmin, RoleUser, true},
+		{RoleModerator, RoleAdmin, false},
+		{RoleModerator, RoleModerator, true},
+		{RoleUser, RoleModerator, false},
+		{RoleUser, RoleUser, true},
+		{"", RoleUser, true},
+		{"", RoleModerator, false},
+		{"superuser", RoleAdmin, false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, tc.role.AtLeast(tc.min), "%q at least %q", tc.role, tc.min)
+	}
+
+	assert.True(t, RoleModerator.Valid())
+	assert.False(t, Role("").Valid())
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleStatus is where a scheduled message is in its lifecycle.
+type ScheduleStatus string
+
+const (
+	SchedulePending  ScheduleStatus = "pending"
+	ScheduleSent     ScheduleStatus = "sent"
+	ScheduleCanceled ScheduleStatus = "canceled"
+	// ScheduleFailed means the message could no longer be sent when it
+	// fell due, for example because the sender left the conversation.
+	ScheduleFailed ScheduleStatus = "failed"
+)
+
+// ScheduledMessage is a message composed now to be sent at SendAt. When
+// it is sent, the message keeps the scheduled message's ID.
+type ScheduledMessage struct {
+	ID             uuid.UUID      `json:"id"`
+	SenderID       string         `json:"sender_id"`
+	ReceiverID     string         `json:"receiver_id,omitempty"`
+	ConversationID uuid.UUID      `json:"conversation_id,omitempty"`
+	ReplyToID      *uuid.UUID     `json:"reply_to,omitempty"`
+	QuoteID        *uuid.UUID     `json:"quote_id,omitempty"`
+	Content        string         `json:"content"`
+	Format         MessageFormat  `json:"format,omitempty"`
+	AttachmentIDs  []uuid.UUID    `json:"attachment_ids,omitempty"`
+	SendAt         time.Time      `json:"send_at"`
+	Status         ScheduleStatus `json:"status"`
+	// Failure explains a ScheduleFailed status.
+	Failure   string    `json:"failure,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// ClaimedUntil is when a scheduler's claim on a due message lapses.
+	ClaimedUntil *time.Time `json:"-"`
+}
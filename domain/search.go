@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SearchTerm is one clause of a search query. A term with several words
+// is a phrase; a Prefix term matches any word starting with its last
+// word.
+type SearchTerm struct {
+	Words  []string
+	Prefix bool
+}
+
+// Phrase reports whether the term must match consecutive words.
+func (t SearchTerm) Phrase() bool {
+	return len(t.Words) > 1
+}
+
+// ParseSearchQuery splits a query into terms that must all match.
+// "Double quotes" group a phrase and a trailing * makes a word a prefix,
+// so `"release notes" deploy*` finds messages with that phrase and any
+// word starting with "deploy". Punctuation splits words, so an unquoted
+// "e-mail" is the phrase "e mail".
+func ParseSearchQuery(q string) []SearchTerm {
+	var terms []SearchTerm
+	for q != "" {
+		q = strings.TrimLeftFunc(q, unicode.IsSpace)
+		if q == "" {
+			break
+		}
+		var chunk string
+		if q[0] == '"' {
+			end := strings.IndexByte(q[1:], '"')
+			if end < 0 {
+				chunk, q = q[1:], ""
+			} else {
+				chunk, q = q[1:end+1], q[end+2:]
+			}
+		} else {
+			end := strings.IndexFunc(q, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+			if end < 0 {
+				end = len(q)
+			}
+			chunk, q = q[:end], q[end:]
+		}
+
+		prefix := strings.HasSuffix(chunk, "*")
+		words := Tokenize(chunk)
+		if len(words) == 0 {
+			continue
+		}
+		terms = append(terms, SearchTerm{Words: words, Prefix: prefix})
+	}
+	return terms
+}
+
+// Tokenize lowercases text and splits it into runs of letters and digits,
+// the unit both search and indexing work on.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
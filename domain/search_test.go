@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+		want []SearchTerm
+	}{
+		{"empty", "   ", nil},
+		{"words", "Hello  World", []SearchTerm{{Words: []string{"hello"}}, {Words: []string{"world"}}}},
+		{"phrase", `"release notes" ship`, []SearchTerm{
+			{Words: []string{"release", "notes"}},
+			{Words: []string{"ship"}},
+		}},
+		{"prefix", "deploy*", []SearchTerm{{Words: []string{"deploy"}, Prefix: true}}},
+		{"phrase prefix", `"new rel*"`, []SearchTerm{{Words: []string{"new", "rel"}, Prefix: true}}},
+		{"unterminated quote", `"big plans`, []SearchTerm{{Words: []string{"big", "plans"}}}},
+		{"punctuation splits", "e-mail", []SearchTerm{{Words: []string{"e", "mail"}}}},
+		{"only punctuation", `"" * !!`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseSearchQuery(tt.q))
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"café", "at", "9am", "ok"}, Tokenize("Café at 9am, OK?"))
+}
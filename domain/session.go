@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is created by every successful login and referenced from the
+// access token, so revoking it invalidates the token server-side.
+type Session struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     string     `json:"user_id"`
+	DeviceName string     `json:"device_name"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the session has not been revoked.
+func (s *Session) Active() bool {
+	return s.RevokedAt == nil
+}
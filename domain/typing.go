@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// Typer is a participant typing in a conversation. The indicator lapses
+// at ExpiresAt unless the client refreshes it.
+type Typer struct {
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
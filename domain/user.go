@@ -6,4 +6,36 @@ type User struct {
 	ID           uuid.UUID
 	Username     string
 	PasswordHash string
+	Role         Role
+
+	// Disabled accounts can't log in and their sessions and keys stop
+	// working. PasswordResetRequired blocks login until the password is
+	// changed.
+	Disabled              bool
+	PasswordResetRequired bool
+
+	// IsBot marks accounts that can only authenticate with API keys.
+	// OwnerID is the user who created the bot and may manage its keys.
+	IsBot   bool
+	OwnerID string
+
+	// TOTPSecret is set on enrollment but only enforced at login once
+	// TOTPEnabled is true, i.e. after the user confirmed a first code.
+	TOTPSecret  string
+	TOTPEnabled bool
+	// TOTPLastCounter is the time step of the last accepted code, so the
+	// same code can't be replayed while it is still valid.
+	TOTPLastCounter    int64
+	RecoveryCodeHashes []string
+
+	// HideLastSeen keeps others from seeing when the user was last
+	// around; their online status still shows.
+	HideLastSeen bool
+	// ContactsOnly refuses direct messages from anyone who isn't one of
+	// the user's contacts.
+	ContactsOnly bool
+
+	// Email is where email notifications go; empty turns them off. It is
+	// never shown to other users.
+	Email string
 }
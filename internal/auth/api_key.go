@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+
+	"github.com/chrikar/chatheon/domain"
+)
+
+// APIKeyPrefix starts every API key, so the middleware can tell keys and
+// JWTs apart and leaked keys are easy to grep for.
+const APIKeyPrefix = "chk_"
+
+const (
+	apiKeyPrefixBytes = 5
+	apiKeySecretBytes = 20
+)
+
+var apiKeyEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Principal is the identity an API key authenticates as.
+type Principal struct {
+	UserID   string
+	Username string
+	Role     domain.Role
+	Scopes   []domain.Scope
+}
+
+// APIKeyAuthenticator resolves a presented API key to a principal.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(key string) (*Principal, error)
+}
+
+// GenerateAPIKey returns a new key in the form chk_<prefix>_<secret>,
+// along with its prefix and the hash to store.
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	p := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(p); err != nil {
+		return "", "", "", err
+	}
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = strings.ToLower(apiKeyEncoding.EncodeToString(p))
+	key = APIKeyPrefix + prefix + "_" + strings.ToLower(apiKeyEncoding.EncodeToString(secret))
+	return key, prefix, HashAPIKey(key), nil
+}
+
+// ParseAPIKey extracts the lookup prefix from a presented key.
+func ParseAPIKey(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, APIKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || prefix == "" || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// HashAPIKey returns the stored form of a key. Keys are random and long,
+// so an unsalted SHA-256 is sufficient.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
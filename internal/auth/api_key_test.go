@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAndParseAPIKey(t *testing.T) {
+	t.Parallel()
+
+	key, prefix, hash, err := GenerateAPIKey()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, APIKeyPrefix+prefix+"_"))
+	assert.Equal(t, HashAPIKey(key), hash)
+	assert.NotContains(t, hash, prefix)
+
+	parsed, ok := ParseAPIKey(key)
+	assert.True(t, ok)
+	assert.Equal(t, prefix, parsed)
+
+	other, otherPrefix, _, err := GenerateAPIKey()
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, other)
+	assert.NotEqual(t, prefix, otherPrefix)
+
+	for _, bad := range []string{"", "chk_", "chk_abc", "chk__secret", "chk_abc_", "xyz_abc_def"} {
+		_, ok := ParseAPIKey(bad)
+		assert.False(t, ok, bad)
+	}
+}
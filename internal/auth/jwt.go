@@ -4,8 +4,18 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/chrikar/chatheon/domain"
 )
 
+// challengeDuration bounds how long a user has to enter their second
+// factor after a successful password check.
+const challengeDuration = 5 * time.Minute
+
+// purposeTOTPChallenge marks tokens that only prove the password step of
+// a two-step login. They must never be accepted as access tokens.
+const purposeTOTPChallenge = "totp_challenge"
+
 type JWTManager struct {
 	secretKey     string
 	tokenDuration time.Duration
@@ -13,31 +23,72 @@ type JWTManager struct {
 
 type UserClaims struct {
 	jwt.RegisteredClaims
-	Username string
-	UserID   string
+	Username  string
+	UserID    string
+	Role      domain.Role `json:",omitempty"`
+	SessionID string      `json:",omitempty"`
+	Purpose   string      `json:",omitempty"`
 }
 
 func NewJWTManager(secretKey string, tokenDuration time.Duration) *JWTManager {
 	return &JWTManager{secretKey, tokenDuration}
 }
 
-func (j *JWTManager) Generate(username, userID string) (string, error) {
-	claims := UserClaims{
-		Username: username,
-		UserID:   userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.tokenDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+func (j *JWTManager) Generate(username, userID, sessionID string, role domain.Role) (string, error) {
+	return j.sign(UserClaims{
+		Username:  username,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
+	}, j.tokenDuration)
+}
+
+// GenerateChallenge issues a short-lived token that can only be redeemed
+// for an access token together with a valid second factor.
+func (j *JWTManager) GenerateChallenge(userID string) (string, error) {
+	return j.sign(UserClaims{
+		UserID:  userID,
+		Purpose: purposeTOTPChallenge,
+	}, challengeDuration)
+}
+
+func (j *JWTManager) Verify(accessToken string) (*UserClaims, error) {
+	claims, err := j.parse(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	return claims, nil
+}
+
+// VerifyChallenge validates a token from GenerateChallenge and returns
+// the user ID it was issued for.
+func (j *JWTManager) VerifyChallenge(challenge string) (string, error) {
+	claims, err := j.parse(challenge)
+	if err != nil {
+		return "", err
+	}
+	if claims.Purpose != purposeTOTPChallenge {
+		return "", jwt.ErrTokenInvalidClaims
+	}
+	return claims.UserID, nil
+}
+
+func (j *JWTManager) sign(claims UserClaims, ttl time.Duration) (string, error) {
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(j.secretKey))
 }
 
-func (j *JWTManager) Verify(accessToken string) (*UserClaims, error) {
+func (j *JWTManager) parse(tokenString string) (*UserClaims, error) {
 	token, err := jwt.ParseWithClaims(
-		accessToken,
+		tokenString,
 		&UserClaims{},
 		func(token *jwt.Token) (interface{}, error) {
 			return []byte(j.secretKey), nil
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/domain"
 )
 
 func TestJWTManager_GenerateAndVerify(t *testing.T) {
@@ -12,7 +14,7 @@ func TestJWTManager_GenerateAndVerify(t *testing.T) {
 	mgr := NewJWTManager("test-secret", time.Second)
 
 	// Generate a token for user “alice”
-	token, err := mgr.Generate("alice", "user-123")
+	token, err := mgr.Generate("alice", "user-123", "session-1", domain.RoleModerator)
 	assert.NoError(t, err, "Generate should not error")
 
 	// Immediately verify: should be valid
@@ -20,6 +22,7 @@ func TestJWTManager_GenerateAndVerify(t *testing.T) {
 	assert.NoError(t, err, "Verify should accept fresh token")
 	assert.Equal(t, "alice", claims.Username, "Claims.Username should match")
 	assert.Equal(t, "user-123", claims.UserID, "Claims.UserID should match")
+	assert.Equal(t, domain.RoleModerator, claims.Role, "Claims.Role should match")
 	assert.NotEmpty(t, claims.ExpiresAt, "Claims.ExpiresAt should not be empty")
 
 	// Tamper: invalid token should error
@@ -38,7 +41,7 @@ func TestJWTManager_Verify_InvalidCases(t *testing.T) {
 	manager := NewJWTManager("test-secret", time.Minute)
 
 	// Generate valid token for tampering
-	validToken, err := manager.Generate("testuser", "user-123")
+	validToken, err := manager.Generate("testuser", "user-123", "session-1", domain.RoleUser)
 	assert.NoError(t, err)
 
 	// Tampered token
@@ -46,7 +49,7 @@ func TestJWTManager_Verify_InvalidCases(t *testing.T) {
 
 	// Expired token
 	expiredManager := NewJWTManager("test-secret", -time.Minute) // already expired
-	expiredToken, err := expiredManager.Generate("testuser", "user-123")
+	expiredToken, err := expiredManager.Generate("testuser", "user-123", "session-1", domain.RoleUser)
 	assert.NoError(t, err)
 
 	cases := []struct {
@@ -78,3 +81,26 @@ func TestJWTManager_Verify_InvalidCases(t *testing.T) {
 		})
 	}
 }
+
+func TestJWTManager_Challenge(t *testing.T) {
+	t.Parallel()
+
+	manager := NewJWTManager("test-secret", time.Minute)
+
+	challenge, err := manager.GenerateChallenge("user-123")
+	assert.NoError(t, err)
+
+	userID, err := manager.VerifyChallenge(challenge)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-123", userID)
+
+	// a challenge must never work as an access token
+	_, err = manager.Verify(challenge)
+	assert.Error(t, err)
+
+	// and an access token is not a challenge
+	token, err := manager.Generate("testuser", "user-123", "session-1", domain.RoleUser)
+	assert.NoError(t, err)
+	_, err = manager.VerifyChallenge(token)
+	assert.Error(t, err)
+}
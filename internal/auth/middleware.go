@@ -3,26 +3,113 @@ package auth
 import (
 	"context"
 	"net/http"
+	"slices"
 	"strings"
+
+	"github.com/chrikar/chatheon/domain"
 )
 
 type contextKey string
 
 const (
-	ContextUserIDKey   contextKey = "userID"
-	ContextUsernameKey contextKey = "username"
+	ContextUserIDKey    contextKey = "userID"
+	ContextUsernameKey  contextKey = "username"
+	ContextSessionIDKey contextKey = "sessionID"
+	ContextRoleKey      contextKey = "role"
+	// ContextScopesKey holds the []domain.Scope of an API key. It is absent
+	// for JWT logins, which carry the full rights of the user.
+	ContextScopesKey contextKey = "scopes"
 )
 
-func JWTMiddleware(jwtManager *JWTManager) func(http.Handler) http.Handler {
+// SessionValidator decides whether the session a token was issued for is
+// still usable.
+type SessionValidator interface {
+	ValidateSession(sessionID string) error
+}
+
+// ActivityTracker is told about every authenticated request.
+type ActivityTracker interface {
+	TrackActivity(userID string)
+}
+
+// MiddlewareOption configures JWTMiddleware.
+type MiddlewareOption func(*middlewareConfig)
+
+type middlewareConfig struct {
+	sessions    SessionValidator
+	apiKeys     APIKeyAuthenticator
+	apiKeyRoute func(*http.Request) bool
+	activity    ActivityTracker
+}
+
+// WithSessionValidator makes the middleware reject tokens whose session
+// is unknown or has been revoked.
+func WithSessionValidator(v SessionValidator) MiddlewareOption {
+	return func(c *middlewareConfig) { c.sessions = v }
+}
+
+// WithAPIKeys makes the middleware accept API keys, either as a Bearer
+// token or in the X-API-Key header.
+func WithAPIKeys(a APIKeyAuthenticator) MiddlewareOption {
+	return func(c *middlewareConfig) { c.apiKeys = a }
+}
+
+// WithAPIKeyRoutes limits API keys to the requests allowed reports true
+// for, typically the routes that declare a scope with RequireScope. The
+// check runs before any handler, so it doesn't matter how the routes
+// are wrapped.
+func WithAPIKeyRoutes(allowed func(r *http.Request) bool) MiddlewareOption {
+	return func(c *middlewareConfig) { c.apiKeyRoute = allowed }
+}
+
+// WithActivityTracker reports each authenticated caller to t, which is
+// how presence learns who is online.
+func WithActivityTracker(t ActivityTracker) MiddlewareOption {
+	return func(c *middlewareConfig) { c.activity = t }
+}
+
+func JWTMiddleware(jwtManager *JWTManager, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	var cfg middlewareConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-				http.Error(w, "missing or invalid Authorization header", http.StatusUnauthorized)
-				return
+			token := r.Header.Get("X-API-Key")
+			if token == "" {
+				authHeader := r.Header.Get("Authorization")
+				if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+					http.Error(w, "missing or invalid Authorization header", http.StatusUnauthorized)
+					return
+				}
+				token = strings.TrimPrefix(authHeader, "Bearer ")
 			}
 
-			token := strings.TrimPrefix(authHeader, "Bearer ")
+			if strings.HasPrefix(token, APIKeyPrefix) {
+				if cfg.apiKeys == nil {
+					http.Error(w, "API keys are not accepted", http.StatusUnauthorized)
+					return
+				}
+				principal, err := cfg.apiKeys.AuthenticateAPIKey(token)
+				if err != nil {
+					http.Error(w, "invalid API key: "+err.Error(), http.StatusUnauthorized)
+					return
+				}
+				if cfg.apiKeyRoute != nil && !cfg.apiKeyRoute(r) {
+					http.Error(w, "this endpoint does not accept API keys", http.StatusForbidden)
+					return
+				}
+
+				ctx := context.WithValue(r.Context(), ContextUserIDKey, principal.UserID)
+				ctx = context.WithValue(ctx, ContextUsernameKey, principal.Username)
+				ctx = context.WithValue(ctx, ContextRoleKey, principal.Role)
+				ctx = context.WithValue(ctx, ContextScopesKey, principal.Scopes)
+
+				cfg.track(principal.UserID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
 
 			claims, err := jwtManager.Verify(token)
 			if err != nil {
@@ -30,11 +117,80 @@ func JWTMiddleware(jwtManager *JWTManager) func(http.Handler) http.Handler {
 				return
 			}
 
+			if cfg.sessions != nil {
+				if claims.SessionID == "" {
+					http.Error(w, "invalid token: no session", http.StatusUnauthorized)
+					return
+				}
+				if err := cfg.sessions.ValidateSession(claims.SessionID); err != nil {
+					http.Error(w, "invalid session: "+err.Error(), http.StatusUnauthorized)
+					return
+				}
+			}
+
 			// Inject claims into context
 			ctx := context.WithValue(r.Context(), ContextUserIDKey, claims.UserID)
 			ctx = context.WithValue(ctx, ContextUsernameKey, claims.Username)
+			ctx = context.WithValue(ctx, ContextRoleKey, claims.Role)
+			ctx = context.WithValue(ctx, ContextSessionIDKey, claims.SessionID)
 
+			cfg.track(claims.UserID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+func (c *middlewareConfig) track(userID string) {
+	if c.activity != nil {
+		c.activity.TrackActivity(userID)
+	}
+}
+
+// RoleFromContext returns the caller's role as set by JWTMiddleware.
+func RoleFromContext(ctx context.Context) domain.Role {
+	role, _ := ctx.Value(ContextRoleKey).(domain.Role)
+	if role == "" {
+		return domain.RoleUser
+	}
+	return role
+}
+
+// RequireRole rejects callers whose role is below min.
+func RequireRole(min domain.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !RoleFromContext(r.Context()).AtLeast(min) {
+				http.Error(w, "requires role "+string(min), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireScope rejects API-key requests whose key lacks scope. Requests
+// authenticated with a login token are let through.
+func RequireScope(scope domain.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, restricted := r.Context().Value(ContextScopesKey).([]domain.Scope)
+			if restricted && !slices.Contains(scopes, scope) {
+				http.Error(w, "API key lacks scope "+string(scope), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession only lets through requests made with a login token, for
+// account management that API keys must never reach.
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sid, _ := r.Context().Value(ContextSessionIDKey).(string); sid == "" {
+			http.Error(w, "this endpoint requires a login token", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
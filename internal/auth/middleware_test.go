@@ -1,19 +1,22 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/chrikar/chatheon/domain"
 )
 
 func TestJWTMiddleware(t *testing.T) {
 	jwtManager := NewJWTManager("test-secret", time.Minute)
 
 	// Generate valid token
-	validToken, err := jwtManager.Generate("testuser", "user-123")
+	validToken, err := jwtManager.Generate("testuser", "user-123", "session-1", domain.RoleUser)
 	assert.NoError(t, err)
 
 	// Table-driven tests
@@ -77,3 +80,234 @@ func TestJWTMiddleware(t *testing.T) {
 		})
 	}
 }
+
+// stubSessions accepts every session except the ones listed as revoked.
+type stubSessions map[string]bool
+
+func (s stubSessions) ValidateSession(sessionID string) error {
+	if s[sessionID] {
+		return errors.New("session has been revoked")
+	}
+	return nil
+}
+
+func TestJWTMiddleware_SessionValidation(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret", time.Minute)
+	sessions := stubSessions{"revoked-session": true}
+
+	active, err := jwtManager.Generate("testuser", "user-123", "active-session", domain.RoleUser)
+	assert.NoError(t, err)
+	revoked, err := jwtManager.Generate("testuser", "user-123", "revoked-session", domain.RoleUser)
+	assert.NoError(t, err)
+	sessionless, err := jwtManager.Generate("testuser", "user-123", "", domain.RoleUser)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{"active session", active, http.StatusOK},
+		{"revoked session", revoked, http.StatusUnauthorized},
+		{"token without session", sessionless, http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "active-session", r.Context().Value(ContextSessionIDKey))
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			rr := httptest.NewRecorder()
+
+			JWTMiddleware(jwtManager, WithSessionValidator(sessions))(nextHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
+
+// stubAPIKeys knows exactly one key.
+type stubAPIKeys struct{}
+
+func (stubAPIKeys) AuthenticateAPIKey(key string) (*Principal, error) {
+	if key != "chk_good_secret" {
+		return nil, errors.New("invalid or revoked API key")
+	}
+	return &Principal{UserID: "bot-1", Username: "ci-bot", Scopes: []domain.Scope{domain.ScopeMessagesRead}}, nil
+}
+
+func TestJWTMiddleware_APIKeys(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret", time.Minute)
+
+	cases := []struct {
+		name           string
+		opts           []MiddlewareOption
+		header         string
+		value          string
+		expectedStatus int
+	}{
+		{"bearer key", []MiddlewareOption{WithAPIKeys(stubAPIKeys{})}, "Authorization", "Bearer chk_good_secret", http.StatusOK},
+		{"X-API-Key header", []MiddlewareOption{WithAPIKeys(stubAPIKeys{})}, "X-API-Key", "chk_good_secret", http.StatusOK},
+		{"unknown key", []MiddlewareOption{WithAPIKeys(stubAPIKeys{})}, "X-API-Key", "chk_bad_secret", http.StatusUnauthorized},
+		{"keys not enabled", nil, "X-API-Key", "chk_good_secret", http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "bot-1", r.Context().Value(ContextUserIDKey))
+				assert.Equal(t, "ci-bot", r.Context().Value(ContextUsernameKey))
+				assert.Equal(t, []domain.Scope{domain.ScopeMessagesRead}, r.Context().Value(ContextScopesKey))
+				assert.Nil(t, r.Context().Value(ContextSessionIDKey))
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(tc.header, tc.value)
+			rr := httptest.NewRecorder()
+
+			JWTMiddleware(jwtManager, tc.opts...)(nextHandler).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
+
+// recordingTracker remembers who it was told about.
+type recordingTracker []string
+
+func (r *recordingTracker) TrackActivity(userID string) {
+	*r = append(*r, userID)
+}
+
+func TestJWTMiddleware_ActivityTracking(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret", time.Minute)
+	token, err := jwtManager.Generate("testuser", "user-123", "", domain.RoleUser)
+	assert.NoError(t, err)
+
+	var tracker recordingTracker
+	middleware := JWTMiddleware(jwtManager, WithAPIKeys(stubAPIKeys{}), WithActivityTracker(&tracker))
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, header := range []string{"Bearer " + token, "Bearer chk_good_secret", "Bearer invalid"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", header)
+		middleware(nextHandler).ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, recordingTracker{"user-123", "bot-1"}, tracker)
+}
+
+func TestJWTMiddleware_APIKeyRoutes(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret", time.Minute)
+	token, err := jwtManager.Generate("testuser", "user-123", "", domain.RoleUser)
+	assert.NoError(t, err)
+
+	middleware := JWTMiddleware(jwtManager, WithAPIKeys(stubAPIKeys{}),
+		WithAPIKeyRoutes(func(r *http.Request) bool { return r.URL.Path == "/messages" }))
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cases := []struct {
+		name           string
+		path           string
+		header         string
+		value          string
+		expectedStatus int
+	}{
+		{"key on an allowed route", "/messages", "X-API-Key", "chk_good_secret", http.StatusOK},
+		{"key on any other route", "/users", "X-API-Key", "chk_good_secret", http.StatusForbidden},
+		{"login token on any route", "/users", "Authorization", "Bearer " + token, http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set(tc.header, tc.value)
+			rr := httptest.NewRecorder()
+
+			middleware(ok).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
+
+func TestRequireScopeAndSession(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret", time.Minute)
+	token, err := jwtManager.Generate("testuser", "user-123", "session-1", domain.RoleUser)
+	assert.NoError(t, err)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cases := []struct {
+		name           string
+		header         string
+		value          string
+		guard          func(http.Handler) http.Handler
+		expectedStatus int
+	}{
+		{"key with scope", "X-API-Key", "chk_good_secret", RequireScope(domain.ScopeMessagesRead), http.StatusOK},
+		{"key without scope", "X-API-Key", "chk_good_secret", RequireScope(domain.ScopeMessagesWrite), http.StatusForbidden},
+		{"login token has every scope", "Authorization", "Bearer " + token, RequireScope(domain.ScopeMessagesWrite), http.StatusOK},
+		{"key can't manage account", "X-API-Key", "chk_good_secret", RequireSession, http.StatusForbidden},
+		{"login token can manage account", "Authorization", "Bearer " + token, RequireSession, http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(tc.header, tc.value)
+			rr := httptest.NewRecorder()
+
+			JWTMiddleware(jwtManager, WithAPIKeys(stubAPIKeys{}))(tc.guard(ok)).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	jwtManager := NewJWTManager("test-secret", time.Minute)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cases := []struct {
+		name           string
+		role           domain.Role
+		required       domain.Role
+		expectedStatus int
+	}{
+		{"admin on admin route", domain.RoleAdmin, domain.RoleAdmin, http.StatusOK},
+		{"admin on moderator route", domain.RoleAdmin, domain.RoleModerator, http.StatusOK},
+		{"moderator on admin route", domain.RoleModerator, domain.RoleAdmin, http.StatusForbidden},
+		{"user on moderator route", domain.RoleUser, domain.RoleModerator, http.StatusForbidden},
+		{"legacy token without role", "", domain.RoleUser, http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := jwtManager.Generate("testuser", "user-123", "session-1", tc.role)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rr := httptest.NewRecorder()
+
+			JWTMiddleware(jwtManager)(RequireRole(tc.required)(ok)).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
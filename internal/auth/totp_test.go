@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	t.Parallel()
+
+	// RFC 6238 appendix B uses the ASCII key "12345678901234567890" with
+	// 8 digits; the 6-digit codes are the last six of those.
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tc := range cases {
+		code, err := TOTPCode(secret, time.Unix(tc.unix, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, tc.code, code, "at %d", tc.unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	t.Parallel()
+
+	secret, err := GenerateTOTPSecret()
+	assert.NoError(t, err)
+
+	now := time.Unix(1_700_000_000, 0)
+	code, err := TOTPCode(secret, now)
+	assert.NoError(t, err)
+
+	counter, ok := ValidateTOTP(secret, code, now)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/30, counter)
+
+	// one step of drift either way is tolerated
+	_, ok = ValidateTOTP(secret, code, now.Add(30*time.Second))
+	assert.True(t, ok)
+	_, ok = ValidateTOTP(secret, code, now.Add(-30*time.Second))
+	assert.True(t, ok)
+
+	// but not more
+	_, ok = ValidateTOTP(secret, code, now.Add(90*time.Second))
+	assert.False(t, ok)
+
+	_, ok = ValidateTOTP(secret, "12345", now)
+	assert.False(t, ok)
+	_, ok = ValidateTOTP("not base32!", code, now)
+	assert.False(t, ok)
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	t.Parallel()
+
+	uri := TOTPProvisioningURI("Chatheon", "alice", "JBSWY3DPEHPK3PXP")
+
+	u, err := url.Parse(uri)
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/Chatheon:alice", u.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+	assert.Equal(t, "Chatheon", u.Query().Get("issuer"))
+	assert.Equal(t, "6", u.Query().Get("digits"))
+	assert.Equal(t, "30", u.Query().Get("period"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	codes, err := GenerateRecoveryCodes(10)
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+
+	seen := map[string]bool{}
+	for _, c := range codes {
+		assert.Len(t, c, recoveryCodeLength+1)
+		assert.Equal(t, byte('-'), c[recoveryCodeLength/2])
+		assert.False(t, seen[c], "duplicate recovery code")
+		seen[c] = true
+	}
+
+	// hashing ignores case, dashes and surrounding whitespace
+	assert.Equal(t, HashRecoveryCode(codes[0]), HashRecoveryCode("  "+codes[0]+" "))
+	assert.NotEqual(t, HashRecoveryCode(codes[0]), HashRecoveryCode(codes[1]))
+}
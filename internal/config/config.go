@@ -1,6 +1,11 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Config struct {
 	DBHost     string
@@ -8,6 +13,66 @@ type Config struct {
 	DBUser     string
 	DBPassword string
 	DBName     string
+
+	// AdminUsernames get the admin role when they register.
+	AdminUsernames []string
+
+	// MessageEditWindow is how long senders may edit a message.
+	MessageEditWindow time.Duration
+	// MessageDeleteWindow is how long senders may delete a message for
+	// everyone.
+	MessageDeleteWindow time.Duration
+
+	// BlobDir is where uploaded attachments are stored.
+	BlobDir string
+	// MaxAttachmentSize is the largest accepted upload, in bytes.
+	MaxAttachmentSize int64
+	// UnsentAttachmentTTL is how long an upload may wait to be sent
+	// before it is deleted.
+	UnsentAttachmentTTL time.Duration
+
+	// SchedulerInterval is how often due scheduled messages are sent.
+	SchedulerInterval time.Duration
+	// ReaperInterval is how often expired disappearing messages are
+	// purged.
+	ReaperInterval time.Duration
+
+	// PinRole is the lowest role allowed to pin messages.
+	PinRole string
+
+	// TypingTTL is how long a typing indicator lasts without a refresh.
+	TypingTTL time.Duration
+	// PresenceIdle and PresenceTimeout are how long after their last
+	// activity users show as away and as offline.
+	PresenceIdle    time.Duration
+	PresenceTimeout time.Duration
+
+	// MessageRetention is the server-wide retention policy; zero keeps
+	// messages forever.
+	MessageRetention time.Duration
+	// RetentionInterval is how often the retention purge runs.
+	RetentionInterval time.Duration
+	// RetentionDryRun makes the scheduled purge only report what it
+	// would delete.
+	RetentionDryRun bool
+
+	// WebhookURLs receive every notification as a signed POST, using
+	// WebhookSecret as the HMAC key. WebhookTimeout bounds each attempt.
+	WebhookURLs    []string
+	WebhookSecret  string
+	WebhookTimeout time.Duration
+
+	// SMTPAddr is the host:port of the mail relay; email notifications
+	// are off when it is empty. SMTPUsername enables AUTH PLAIN.
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	// SMTPAllowPlaintext allows relays that don't offer STARTTLS.
+	SMTPAllowPlaintext bool
+	// EmailDigestInterval is how often unread messages are emailed as a
+	// digest.
+	EmailDigestInterval time.Duration
 }
 
 func Load() Config {
@@ -17,5 +82,87 @@ func Load() Config {
 		DBUser:     os.Getenv("DB_USER"),
 		DBPassword: os.Getenv("DB_PASSWORD"),
 		DBName:     os.Getenv("DB_NAME"),
+
+		AdminUsernames: splitList(os.Getenv("ADMIN_USERNAMES")),
+
+		MessageEditWindow:   duration(os.Getenv("MESSAGE_EDIT_WINDOW"), 15*time.Minute),
+		MessageDeleteWindow: duration(os.Getenv("MESSAGE_DELETE_WINDOW"), time.Hour),
+
+		BlobDir:             stringOr(os.Getenv("BLOB_DIR"), "data/blobs"),
+		MaxAttachmentSize:   size(os.Getenv("MAX_ATTACHMENT_SIZE"), 10<<20),
+		UnsentAttachmentTTL: duration(os.Getenv("UNSENT_ATTACHMENT_TTL"), 24*time.Hour),
+
+		SchedulerInterval: duration(os.Getenv("SCHEDULER_INTERVAL"), 5*time.Second),
+		ReaperInterval:    duration(os.Getenv("REAPER_INTERVAL"), time.Minute),
+
+		PinRole: stringOr(os.Getenv("PIN_ROLE"), "user"),
+
+		TypingTTL:       duration(os.Getenv("TYPING_TTL"), 6*time.Second),
+		PresenceIdle:    duration(os.Getenv("PRESENCE_IDLE"), 2*time.Minute),
+		PresenceTimeout: duration(os.Getenv("PRESENCE_TIMEOUT"), 5*time.Minute),
+
+		MessageRetention:  days(os.Getenv("RETENTION_DAYS")),
+		RetentionInterval: duration(os.Getenv("RETENTION_INTERVAL"), time.Hour),
+		RetentionDryRun:   os.Getenv("RETENTION_DRY_RUN") == "true",
+
+		WebhookURLs:    splitList(os.Getenv("WEBHOOK_URLS")),
+		WebhookSecret:  os.Getenv("WEBHOOK_SECRET"),
+		WebhookTimeout: duration(os.Getenv("WEBHOOK_TIMEOUT"), 10*time.Second),
+
+		SMTPAddr:            os.Getenv("SMTP_ADDR"),
+		SMTPUsername:        os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:        os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:            stringOr(os.Getenv("SMTP_FROM"), "Chatheon <no-reply@localhost>"),
+		SMTPAllowPlaintext:  os.Getenv("SMTP_ALLOW_PLAINTEXT") == "true",
+		EmailDigestInterval: duration(os.Getenv("EMAIL_DIGEST_INTERVAL"), time.Hour),
+	}
+}
+
+// duration parses a Go duration string such as "15m", falling back to def
+// when v is empty or malformed.
+func duration(v string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
+
+// size parses a positive byte count, falling back to def when v is empty
+// or malformed.
+func size(v string, def int64) int64 {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
+}
+
+// days parses a whole number of days, returning zero when v is empty or
+// malformed.
+func days(v string) time.Duration {
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// stringOr returns v, or def when v is empty.
+func stringOr(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+// splitList parses a comma-separated environment value.
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
 	}
+	return out
 }
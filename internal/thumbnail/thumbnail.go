@@ -0,0 +1,91 @@
+// Package thumbnail renders small PNG previews of uploaded images using
+// only the standard library decoders.
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"  // register decoder
+	_ "image/jpeg" // register decoder
+	"image/png"
+	"io"
+)
+
+// MaxPixels bounds the decoded size of a source image so a tiny file
+// can't expand into gigabytes of pixels.
+const MaxPixels = 40_000_000
+
+// ErrTooLarge is returned for images with more than MaxPixels pixels.
+var ErrTooLarge = errors.New("image dimensions too large")
+
+// Config reads an image's dimensions without decoding its pixels.
+func Config(r io.Reader) (width, height int, err error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// Generate decodes a GIF, JPEG or PNG image from src and writes a PNG to
+// dst that fits within maxSide×maxSide, keeping the aspect ratio. Images
+// that already fit are re-encoded unscaled.
+func Generate(dst io.Writer, src io.Reader, maxSide int) error {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+	// Check the header before allocating the pixel buffer.
+	w, h, err := Config(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if w*h > MaxPixels {
+		return ErrTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	return png.Encode(dst, scale(img, maxSide))
+}
+
+// scale box-filters img down so its longer side is at most maxSide.
+func scale(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+	tw, th := maxSide, h*maxSide/w
+	if h > w {
+		tw, th = w*maxSide/h, maxSide
+	}
+	tw, th = max(tw, 1), max(th, 1)
+
+	out := image.NewNRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+(x+1)*w/tw
+			out.Set(x, y, average(img, x0, y0, max(x1, x0+1), max(y1, y0+1)))
+		}
+	}
+	return out
+}
+
+// average blends the pixels in [x0,x1)×[y0,y1).
+func average(img image.Image, x0, y0, x1, y1 int) color.Color {
+	var r, g, b, a, n uint64
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			pr, pg, pb, pa := img.At(x, y).RGBA()
+			r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+			n++
+		}
+	}
+	// RGBA returns alpha-premultiplied values, which is what RGBA64 holds.
+	return color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)}
+}
@@ -0,0 +1,63 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestGenerate_ScalesKeepingAspectRatio(t *testing.T) {
+	tests := []struct {
+		name         string
+		w, h         int
+		wantW, wantH int
+	}{
+		{"landscape", 800, 400, 128, 64},
+		{"portrait", 300, 600, 64, 128},
+		{"already small", 50, 20, 50, 20},
+		{"very thin", 1000, 2, 128, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.NoError(t, Generate(&out, bytes.NewReader(encodePNG(t, tt.w, tt.h)), 128))
+
+			thumb, err := png.Decode(&out)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantW, thumb.Bounds().Dx())
+			assert.Equal(t, tt.wantH, thumb.Bounds().Dy())
+			r, _, _, a := thumb.At(0, 0).RGBA()
+			assert.Equal(t, uint32(200)*0x101, r)
+			assert.Equal(t, uint32(0xffff), a)
+		})
+	}
+}
+
+func TestGenerate_RejectsNonImages(t *testing.T) {
+	assert.Error(t, Generate(&bytes.Buffer{}, strings.NewReader("not an image"), 128))
+}
+
+func TestConfig(t *testing.T) {
+	w, h, err := Config(bytes.NewReader(encodePNG(t, 30, 20)))
+	assert.NoError(t, err)
+	assert.Equal(t, 30, w)
+	assert.Equal(t, 20, h)
+}
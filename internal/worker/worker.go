@@ -0,0 +1,26 @@
+// Package worker runs the server's periodic background jobs.
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every runs job once per interval until ctx is done. A failing run is
+// logged and retried on the next tick, so one bad run never stops the
+// job.
+func Every(ctx context.Context, name string, interval time.Duration, job func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(); err != nil {
+				log.Printf("%s: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery_RunsUntilCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int32
+	done := make(chan struct{})
+
+	go func() {
+		Every(ctx, "test job", time.Millisecond, func() error {
+			if runs.Add(1) == 3 {
+				cancel()
+			}
+			return errors.New("keeps going")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not stop after cancel")
+	}
+	assert.GreaterOrEqual(t, runs.Load(), int32(3))
+}
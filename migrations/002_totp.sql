@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN totp_secret TEXT NOT NULL DEFAULT '',
+    ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN totp_last_counter BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN recovery_code_hashes TEXT[] NOT NULL DEFAULT '{}';
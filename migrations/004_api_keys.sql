@@ -0,0 +1,19 @@
+ALTER TABLE users
+    ADD COLUMN is_bot BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN owner_id TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE users ALTER COLUMN password_hash SET DEFAULT '';
+
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    prefix TEXT UNIQUE NOT NULL,
+    hash TEXT NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX api_keys_user_id_idx ON api_keys (user_id);
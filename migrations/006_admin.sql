@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,30 @@
+CREATE TABLE messages (
+    id UUID PRIMARY KEY,
+    sender_id TEXT NOT NULL,
+    receiver_id TEXT NOT NULL DEFAULT '',
+    conversation_id UUID,
+    content TEXT NOT NULL,
+    status SMALLINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL,
+    edited_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX messages_sender_id_idx ON messages (sender_id, created_at);
+CREATE INDEX messages_receiver_id_idx ON messages (receiver_id, created_at);
+CREATE INDEX messages_conversation_id_idx ON messages (conversation_id, created_at);
+
+CREATE TABLE message_revisions (
+    message_id UUID NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX message_revisions_message_id_idx ON message_revisions (message_id, created_at);
+
+-- Messages a user deleted "for me".
+CREATE TABLE message_hidden (
+    message_id UUID NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    PRIMARY KEY (message_id, user_id)
+);
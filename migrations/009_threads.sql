@@ -0,0 +1,12 @@
+ALTER TABLE messages
+    ADD COLUMN reply_to_id UUID REFERENCES messages (id) ON DELETE SET NULL,
+    ADD COLUMN thread_root_id UUID REFERENCES messages (id) ON DELETE CASCADE;
+
+CREATE INDEX messages_thread_root_id_idx ON messages (thread_root_id, created_at);
+
+CREATE TABLE thread_reads (
+    root_id UUID NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    read_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (root_id, user_id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE messages ADD COLUMN mentions JSONB NOT NULL DEFAULT '[]';
+
+-- Serves the mentions inbox: mentions @> '[{"user_id": ...}]'.
+CREATE INDEX messages_mentions_idx ON messages USING GIN (mentions jsonb_path_ops);
@@ -0,0 +1,15 @@
+CREATE TABLE attachments (
+    id UUID PRIMARY KEY,
+    uploader_id TEXT NOT NULL,
+    message_id UUID REFERENCES messages (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    content_type TEXT NOT NULL,
+    checksum TEXT NOT NULL,
+    width INTEGER NOT NULL DEFAULT 0,
+    height INTEGER NOT NULL DEFAULT 0,
+    has_thumbnail BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX attachments_message_id_idx ON attachments (message_id);
@@ -0,0 +1,14 @@
+-- Full-text index over message content. Content is tokenized by the
+-- application before to_tsvector('simple', ...) so both search adapters
+-- agree on what a word is.
+CREATE TABLE message_search (
+    message_id UUID PRIMARY KEY REFERENCES messages (id) ON DELETE CASCADE,
+    conversation_id UUID,
+    sender_id TEXT NOT NULL,
+    receiver_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    document TSVECTOR NOT NULL
+);
+
+CREATE INDEX message_search_document_idx ON message_search USING GIN (document);
+CREATE INDEX message_search_conversation_idx ON message_search (conversation_id, created_at);
@@ -0,0 +1,17 @@
+CREATE TABLE scheduled_messages (
+    id UUID PRIMARY KEY,
+    sender_id TEXT NOT NULL,
+    receiver_id TEXT NOT NULL DEFAULT '',
+    conversation_id UUID,
+    reply_to_id UUID,
+    content TEXT NOT NULL,
+    attachment_ids UUID[] NOT NULL DEFAULT '{}',
+    send_at TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    failure TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    claimed_until TIMESTAMPTZ
+);
+
+CREATE INDEX scheduled_messages_due_idx ON scheduled_messages (send_at) WHERE status = 'pending';
+CREATE INDEX scheduled_messages_sender_idx ON scheduled_messages (sender_id, send_at);
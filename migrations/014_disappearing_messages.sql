@@ -0,0 +1,5 @@
+ALTER TABLE messages
+    ADD COLUMN expires_at TIMESTAMPTZ,
+    ADD COLUMN system BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX messages_expires_at_idx ON messages (expires_at) WHERE expires_at IS NOT NULL;
@@ -0,0 +1,9 @@
+CREATE TABLE message_pins (
+    conversation_id UUID NOT NULL,
+    message_id UUID NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+    pinned_by TEXT NOT NULL,
+    pinned_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (conversation_id, message_id)
+);
+
+CREATE INDEX message_pins_conversation_idx ON message_pins (conversation_id, pinned_at);
@@ -0,0 +1,5 @@
+ALTER TABLE messages
+    ADD COLUMN forwarded_from JSONB,
+    ADD COLUMN quote JSONB;
+
+ALTER TABLE scheduled_messages ADD COLUMN quote_id UUID;
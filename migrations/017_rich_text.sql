@@ -0,0 +1,5 @@
+ALTER TABLE messages
+    ADD COLUMN format TEXT NOT NULL DEFAULT 'plain',
+    ADD COLUMN entities JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE scheduled_messages ADD COLUMN format TEXT NOT NULL DEFAULT 'plain';
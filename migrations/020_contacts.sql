@@ -0,0 +1,21 @@
+ALTER TABLE users ADD COLUMN contacts_only BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE contact_requests (
+    id UUID PRIMARY KEY,
+    from_id TEXT NOT NULL,
+    to_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ
+);
+
+-- At most one pending request between two users, whichever way it goes.
+CREATE UNIQUE INDEX contact_requests_pending_idx ON contact_requests
+    (LEAST(from_id, to_id), GREATEST(from_id, to_id)) WHERE status = 'pending';
+
+CREATE TABLE contacts (
+    user_id TEXT NOT NULL,
+    contact_id TEXT NOT NULL,
+    since TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, contact_id)
+);
@@ -0,0 +1,16 @@
+CREATE TABLE notification_settings (
+    user_id TEXT PRIMARY KEY,
+    level TEXT NOT NULL DEFAULT 'all',
+    time_zone TEXT NOT NULL DEFAULT '',
+    -- Do-not-disturb hours as "HH:MM" in time_zone; both or neither set.
+    dnd_start TEXT,
+    dnd_end TEXT
+);
+
+CREATE TABLE conversation_mutes (
+    user_id TEXT NOT NULL,
+    conversation_id UUID NOT NULL,
+    -- NULL mutes until the user unmutes.
+    until TIMESTAMPTZ,
+    PRIMARY KEY (user_id, conversation_id)
+);